@@ -0,0 +1,50 @@
+
+-- ============================================================================
+-- Conversation Memory: branched conversation history for memoryx.StoredMemory
+-- ============================================================================
+
+-- ============================================================================
+-- CONVERSATIONS
+-- ============================================================================
+
+CREATE TABLE conversations (
+    id VARCHAR(255) PRIMARY KEY,
+    active_branch_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ============================================================================
+-- CONVERSATION BRANCHES
+-- ============================================================================
+
+CREATE TABLE conversation_branches (
+    conversation_id VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    parent_id VARCHAR(255) NOT NULL DEFAULT '',
+    forked_at VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (conversation_id, id)
+);
+
+-- ============================================================================
+-- CONVERSATION MESSAGES
+-- ============================================================================
+
+CREATE TABLE conversation_messages (
+    conversation_id VARCHAR(255) NOT NULL,
+    branch_id VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL,
+    message_id VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    message JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (conversation_id, branch_id, position),
+    CONSTRAINT fk_conversation_messages_branch FOREIGN KEY (conversation_id, branch_id)
+        REFERENCES conversation_branches(conversation_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_conversation_messages_message_id ON conversation_messages(conversation_id, message_id);
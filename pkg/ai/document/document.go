@@ -41,6 +41,28 @@ const (
 	MetadataTags         = "tags"          // Tags array
 	MetadataUserID       = "user_id"       // Owner user ID
 	MetadataFileSize     = "file_size"     // File size in bytes
+	MetadataPageCount    = "page_count"    // Total pages
+	MetadataDescription  = "description"   // Summary or meta description
+	MetadataHeadings     = "headings"      // Heading path of a section, outermost first
+	MetadataConfidence   = "confidence"    // Extraction confidence (0-1)
+	MetadataTables       = "tables"        // Tables found on the page
+	MetadataRegions      = "regions"       // Bounding boxes of page elements
+	MetadataAnnotation   = "annotation"    // Structured data extracted by an annotation schema
+	MetadataContentType  = "content_type"  // MIME type of the source file
+	MetadataETag         = "etag"          // Version tag of the source file
+	MetadataFileMetadata = "file_metadata" // User metadata of the source file (e.g. S3 object metadata)
+	MetadataContentHash  = "content_hash"  // SHA-256 of the chunk content
+	MetadataCodeLanguage = "code_language" // Programming language of a code chunk
+	MetadataSymbols      = "symbols"       // Functions, types and classes defined in a code chunk
+	MetadataStartLine    = "start_line"    // First line of the chunk (1-based)
+	MetadataEndLine      = "end_line"      // Last line of the chunk (1-based)
+	MetadataChunkRole    = "chunk_role"    // ChunkRoleParent or ChunkRoleChild
+	MetadataParentID     = "parent_id"     // ID of the parent section of a child chunk
+	MetadataPrevChunkID  = "prev_chunk_id" // ID of the previous chunk of the same source
+	MetadataNextChunkID  = "next_chunk_id" // ID of the next chunk of the same source
+	MetadataChunkContext = "chunk_context" // Situating context prepended to the chunk
+	MetadataRerankScore  = "rerank_score"  // Relevance score given by a reranker (0-1)
+	MetadataSubQueries   = "sub_queries"   // Sub-queries of a transformed query that retrieved the document
 )
 
 // NewDocument creates a new document
@@ -0,0 +1,276 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// DOCX Loader - loads Word documents
+// ============================================================================
+
+// DOCXLoader extracts text from Word (.docx) files. Heading styles become
+// Markdown headings, list items are prefixed with "- " and table rows are
+// written as tab-separated lines, so the Markdown-aware splitters and
+// WithSections can use the structure. Title and author come from the
+// document properties.
+type DOCXLoader struct {
+	source   Source
+	splitter Splitter
+	metadata map[string]any
+	sections bool
+}
+
+// NewDOCXLoader creates a new DOCX loader
+func NewDOCXLoader(source Source) *DOCXLoader {
+	return &DOCXLoader{
+		source:   source,
+		metadata: make(map[string]any),
+	}
+}
+
+// WithSplitter sets the splitter
+func (l *DOCXLoader) WithSplitter(splitter Splitter) *DOCXLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata
+func (l *DOCXLoader) WithMetadata(key string, value any) *DOCXLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithSections emits one document per heading section instead of one per
+// file, with the heading path in MetadataHeadings
+func (l *DOCXLoader) WithSections(sections bool) *DOCXLoader {
+	l.sections = sections
+	return l
+}
+
+// Load loads the document
+func (l *DOCXLoader) Load(ctx context.Context) ([]*Document, error) {
+	data, err := readSource(ctx, l.source)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx: %w", err)
+	}
+
+	body, err := readZipFile(archive, "word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docx body: %w", err)
+	}
+	text, err := docxText(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse docx body: %w", err)
+	}
+
+	metadata := map[string]any{MetadataDocumentType: "docx"}
+	if core, err := readZipFile(archive, "docProps/core.xml"); err == nil {
+		var props docxCoreProperties
+		if xml.Unmarshal(core, &props) == nil {
+			props.addTo(metadata)
+		}
+	}
+	if _, ok := metadata[MetadataTitle]; !ok {
+		if title := firstHeading(text); title != "" {
+			metadata[MetadataTitle] = title
+		}
+	}
+	for k, v := range l.metadata {
+		metadata[k] = v
+	}
+
+	source := sourceName(l.source)
+	if l.sections {
+		return splitDocuments(ctx, sectionDocuments(text, source, metadata), l.splitter)
+	}
+
+	doc := NewDocument(text).
+		WithMetadataMap(metadata).
+		WithMetadata(MetadataSource, source)
+	return splitDocuments(ctx, []*Document{doc}, l.splitter)
+}
+
+// LoadStream loads the document and streams the results
+func (l *DOCXLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(docs), nil
+}
+
+func readZipFile(archive *zip.Reader, name string) ([]byte, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// docxCoreProperties is the Dublin Core part of docProps/core.xml
+type docxCoreProperties struct {
+	Title       string `xml:"title"`
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+	Language    string `xml:"language"`
+}
+
+func (p docxCoreProperties) addTo(metadata map[string]any) {
+	for key, value := range map[string]string{
+		MetadataTitle:       p.Title,
+		MetadataAuthor:      p.Creator,
+		MetadataDescription: p.Description,
+		MetadataLanguage:    p.Language,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			metadata[key] = value
+		}
+	}
+}
+
+// docxText renders word/document.xml as Markdown-flavoured text
+func docxText(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	var out, paragraph, cell strings.Builder
+	var row []string
+	var style string
+	var listItem, inText bool
+	tables := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paragraph.Reset()
+				style, listItem = "", false
+			case "pStyle":
+				style = xmlAttr(t, "val")
+			case "numPr":
+				listItem = true
+			case "t":
+				inText = true
+			case "tab":
+				paragraph.WriteByte('\t')
+			case "br", "cr":
+				paragraph.WriteByte('\n')
+			case "tbl":
+				if tables++; tables == 1 {
+					endBlock(&out)
+				}
+			case "tr":
+				if tables == 1 {
+					row = row[:0]
+				}
+			case "tc":
+				if tables == 1 {
+					cell.Reset()
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				switch {
+				case text == "":
+				case tables > 0:
+					if cell.Len() > 0 {
+						cell.WriteByte(' ')
+					}
+					cell.WriteString(text)
+				case docxHeadingLevel(style) > 0:
+					endBlock(&out)
+					out.WriteString(strings.Repeat("#", docxHeadingLevel(style)) + " " + text + "\n\n")
+				case listItem:
+					out.WriteString("- " + text + "\n")
+				default:
+					endBlock(&out)
+					out.WriteString(text + "\n\n")
+				}
+			case "tc":
+				if tables == 1 {
+					row = append(row, strings.TrimSpace(cell.String()))
+				}
+			case "tr":
+				if tables == 1 {
+					out.WriteString(strings.Join(row, "\t") + "\n")
+				}
+			case "tbl":
+				if tables--; tables == 0 {
+					out.WriteString("\n")
+				}
+			}
+
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		}
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// endBlock separates a list or table from the block that follows it
+func endBlock(out *strings.Builder) {
+	if text := out.String(); text != "" && !strings.HasSuffix(text, "\n\n") {
+		out.WriteByte('\n')
+	}
+}
+
+// docxHeadingLevel maps the built-in Title and HeadingN styles to a level
+func docxHeadingLevel(style string) int {
+	style = strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	if style == "title" {
+		return 1
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(style, "heading"))
+	if !strings.HasPrefix(style, "heading") || err != nil || level < 1 {
+		return 0
+	}
+	return min(level, 6)
+}
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// firstHeading returns the text of the first Markdown heading
+func firstHeading(text string) string {
+	for _, sec := range splitSections(text) {
+		if len(sec.headings) > 0 {
+			return sec.headings[0]
+		}
+	}
+	return ""
+}
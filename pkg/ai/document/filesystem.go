@@ -0,0 +1,516 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/fsx"
+)
+
+// ============================================================================
+// File System Loader - loads documents from local disk or object storage
+// ============================================================================
+
+// FileSystemLoader loads the files under a prefix of an fsx file system, so
+// the same ingestion code reads from fsxlocal during development and fsxs3
+// in production. Each file is read with the loader registered for its
+// extension or content type, and its documents carry the file's size,
+// modification time, content type, ETag and user metadata.
+//
+// With WithStateStore the loader skips files whose ETag, or size and
+// modification time, match the last successful run. Call Commit once the
+// loaded documents are stored so the next run picks up only what changed;
+// IngestionPipeline does this itself after a run without errors:
+//
+//	loader := document.NewFileSystemLoader(s3fs, "manuals").
+//	    WithRecursive(true).
+//	    WithStateStore(states)
+//	docs, err := loader.Load(ctx)
+//	...
+//	if err := store.AddDocuments(ctx, docs); err == nil {
+//	    loader.Commit(ctx)
+//	}
+type FileSystemLoader struct {
+	fs        fsx.PathReader
+	prefix    string
+	pattern   string
+	recursive bool
+	splitter  Splitter
+	metadata  map[string]any
+	loaders   FormatLoaders
+	states    FileStateStore
+
+	mu      sync.Mutex
+	pending []FileState
+}
+
+// NewFileSystemLoader creates a loader for the files under prefix. An empty
+// prefix reads from the root of the file system.
+func NewFileSystemLoader(fs fsx.PathReader, prefix string) *FileSystemLoader {
+	return &FileSystemLoader{
+		fs:       fs,
+		prefix:   strings.Trim(prefix, "/"),
+		pattern:  "*",
+		metadata: make(map[string]any),
+		loaders:  DefaultFormatLoaders(),
+	}
+}
+
+// WithPattern sets the file pattern, matched against file names, or against
+// slash-separated paths relative to the prefix when it contains a "/"
+func (l *FileSystemLoader) WithPattern(pattern string) *FileSystemLoader {
+	l.pattern = pattern
+	return l
+}
+
+// WithRecursive enables loading files in nested directories
+func (l *FileSystemLoader) WithRecursive(recursive bool) *FileSystemLoader {
+	l.recursive = recursive
+	return l
+}
+
+// WithSplitter sets the splitter
+func (l *FileSystemLoader) WithSplitter(splitter Splitter) *FileSystemLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata to every document, unless the file's loader
+// set the same key
+func (l *FileSystemLoader) WithMetadata(key string, value any) *FileSystemLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithLoader registers the loader for a file extension (".csv") or MIME
+// type, replacing the built-in one
+func (l *FileSystemLoader) WithLoader(extOrMIME string, factory LoaderFactory) *FileSystemLoader {
+	l.loaders[strings.ToLower(extOrMIME)] = factory
+	return l
+}
+
+// WithStateStore enables incremental loading: files that haven't changed
+// since the last Commit are skipped
+func (l *FileSystemLoader) WithStateStore(states FileStateStore) *FileSystemLoader {
+	l.states = states
+	return l
+}
+
+// Load loads the documents of every new or changed file
+func (l *FileSystemLoader) Load(ctx context.Context) ([]*Document, error) {
+	files, err := l.changedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var allDocs []*Document
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		loader, info, ok := l.loaderFor(ctx, file)
+		if !ok {
+			continue
+		}
+
+		docs, err := loader.Load(ctx)
+		if err != nil {
+			// Skip this file; it stays pending for the next run
+			continue
+		}
+
+		for _, doc := range docs {
+			allDocs = append(allDocs, l.addMetadata(doc, file.path, info))
+		}
+		l.loaded(file)
+	}
+
+	return allDocs, nil
+}
+
+// LoadStream streams the documents of every new or changed file. A file that
+// fails to load is reported by Next, and the following call moves on to the
+// next file.
+func (l *FileSystemLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	files, err := l.changedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSystemStream{
+		files:  files,
+		loader: l,
+		ctx:    ctx,
+	}, nil
+}
+
+// Commit saves the state of the files loaded since the last Load or
+// LoadStream, so the next run skips them. Call it only after their
+// documents were stored; files that failed to load are not recorded.
+func (l *FileSystemLoader) Commit(ctx context.Context) error {
+	if l.states == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	for i, state := range pending {
+		if err := l.states.SaveFileState(ctx, state); err != nil {
+			l.mu.Lock()
+			l.pending = append(pending[i:], l.pending...)
+			l.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSources implements SourceLister. It returns every matching file,
+// including the unchanged ones Load skips, so IngestionPipeline doesn't
+// treat skipped files as deleted.
+func (l *FileSystemLoader) ListSources(ctx context.Context) ([]string, error) {
+	files, err := l.listFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]string, len(files))
+	for i, file := range files {
+		sources[i] = file.path
+	}
+	return sources, nil
+}
+
+// fileEntry is a file found under the prefix, with its listing information
+type fileEntry struct {
+	path string
+	info fsx.FileInfo
+}
+
+// changedFiles lists the matching files, sorted by path, leaving out the
+// ones whose state is unchanged
+func (l *FileSystemLoader) changedFiles(ctx context.Context) ([]fileEntry, error) {
+	l.mu.Lock()
+	l.pending = nil
+	l.mu.Unlock()
+
+	files, err := l.listFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.states == nil {
+		return files, nil
+	}
+
+	changed := files[:0]
+	for _, file := range files {
+		state, err := l.states.LoadFileState(ctx, file.path)
+		if err != nil {
+			return nil, err
+		}
+		if state == nil || !state.Matches(file.info) {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+func (l *FileSystemLoader) listFiles(ctx context.Context) ([]fileEntry, error) {
+	var files []fileEntry
+	dirs := []string{l.prefix}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		entries, err := l.listDir(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			file := l.fs.Join(dir, entry.Name)
+			if entry.IsDir {
+				if l.recursive {
+					dirs = append(dirs, file)
+				}
+				continue
+			}
+
+			name := entry.Name
+			if strings.Contains(l.pattern, "/") {
+				name = strings.TrimPrefix(strings.TrimPrefix(toSlash(file), l.prefix), "/")
+			}
+			matched, err := path.Match(l.pattern, name)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				files = append(files, fileEntry{path: file, info: entry})
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// listDir lists a single level, using ListDir on file systems whose List
+// may return nested files
+func (l *FileSystemLoader) listDir(ctx context.Context, dir string) ([]fsx.FileInfo, error) {
+	if lister, ok := l.fs.(fsx.DirLister); ok {
+		return lister.ListDir(ctx, dir)
+	}
+	return l.fs.List(ctx, dir)
+}
+
+// loaderFor picks the loader of a file from its extension, then from the
+// content type reported by the file system, then by sniffing its content.
+// Listings don't always carry the content type or user metadata, so the
+// file is stat'ed first.
+func (l *FileSystemLoader) loaderFor(ctx context.Context, file fileEntry) (Loader, fsx.FileInfo, bool) {
+	info := file.info
+	if stat, err := l.fs.Stat(ctx, file.path); err == nil {
+		info = mergeFileInfo(info, stat)
+	}
+
+	if factory, ok := l.loaders.Lookup(file.path, nil); ok {
+		return factory(l.streamSource(ctx, file.path), l.splitter), info, true
+	}
+	if factory, ok := l.loaders.LookupType(info.ContentType); ok {
+		return factory(l.streamSource(ctx, file.path), l.splitter), info, true
+	}
+
+	data, err := l.fs.ReadFile(ctx, file.path)
+	if err != nil {
+		return nil, info, false
+	}
+	factory, ok := l.loaders.Lookup(file.path, data[:min(len(data), 512)])
+	if !ok {
+		return nil, info, false
+	}
+	return factory(Source{Type: SourceTypeBytes, Data: data, Path: file.path}, l.splitter), info, true
+}
+
+// streamSource returns a reader source that opens the file on first read,
+// so files are only fetched by the loader that uses them
+func (l *FileSystemLoader) streamSource(ctx context.Context, file string) Source {
+	return Source{
+		Type:   SourceTypeReader,
+		Reader: &lazyFileReader{ctx: ctx, fs: l.fs, path: file},
+		Path:   file,
+	}
+}
+
+// addMetadata adds the file information and the loader's metadata
+func (l *FileSystemLoader) addMetadata(doc *Document, file string, info fsx.FileInfo) *Document {
+	doc.Metadata[MetadataSource] = file
+	doc.Metadata[MetadataFileSize] = info.Size
+	if !info.ModTime.IsZero() {
+		doc.Metadata[MetadataUpdatedAt] = info.ModTime.UTC().Format(time.RFC3339)
+	}
+	if info.ContentType != "" && info.ContentType != "application/octet-stream" {
+		doc.Metadata[MetadataContentType] = info.ContentType
+	}
+	if info.ETag != "" {
+		doc.Metadata[MetadataETag] = info.ETag
+	}
+	if len(info.Metadata) > 0 {
+		fileMetadata := make(map[string]any, len(info.Metadata))
+		for k, v := range info.Metadata {
+			fileMetadata[k] = v
+		}
+		doc.Metadata[MetadataFileMetadata] = fileMetadata
+	}
+
+	for k, v := range l.metadata {
+		if _, ok := doc.Metadata[k]; !ok {
+			doc.Metadata[k] = v
+		}
+	}
+	return doc
+}
+
+// loaded marks a file for the next Commit
+func (l *FileSystemLoader) loaded(file fileEntry) {
+	if l.states == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, FileState{
+		Path:    file.path,
+		Size:    file.info.Size,
+		ModTime: file.info.ModTime,
+		ETag:    file.info.ETag,
+	})
+}
+
+// mergeFileInfo fills the fields a listing left empty from a Stat result
+func mergeFileInfo(listed, stat fsx.FileInfo) fsx.FileInfo {
+	if listed.ContentType == "" || listed.ContentType == "application/octet-stream" {
+		listed.ContentType = stat.ContentType
+	}
+	if listed.ETag == "" {
+		listed.ETag = stat.ETag
+	}
+	if len(listed.Metadata) == 0 {
+		listed.Metadata = stat.Metadata
+	}
+	return listed
+}
+
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
+
+// lazyFileReader opens a file on the first Read
+type lazyFileReader struct {
+	ctx    context.Context
+	fs     fsx.FileReader
+	path   string
+	reader io.ReadCloser
+}
+
+func (r *lazyFileReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		reader, err := r.fs.ReadFileStream(r.ctx, r.path)
+		if err != nil {
+			return 0, err
+		}
+		r.reader = reader
+	}
+	return r.reader.Read(p)
+}
+
+func (r *lazyFileReader) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	return r.reader.Close()
+}
+
+// fileSystemStream implements DocumentStream for file systems
+type fileSystemStream struct {
+	files       []fileEntry
+	fileIndex   int
+	current     fileEntry
+	currentInfo fsx.FileInfo
+	currentFile DocumentStream
+	loader      *FileSystemLoader
+	ctx         context.Context
+}
+
+func (s *fileSystemStream) Next() (*Document, error) {
+	for {
+		// Try current file stream
+		if s.currentFile != nil {
+			doc, err := s.currentFile.Next()
+			if err == nil {
+				return s.loader.addMetadata(doc, s.current.path, s.currentInfo), nil
+			}
+			if err != io.EOF {
+				return nil, err
+			}
+			// EOF - the file is done, close and move to the next one
+			s.currentFile.Close()
+			s.currentFile = nil
+			s.loader.loaded(s.current)
+		}
+
+		if s.fileIndex >= len(s.files) {
+			return nil, io.EOF
+		}
+		if err := s.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		file := s.files[s.fileIndex]
+		s.fileIndex++
+
+		loader, info, ok := s.loader.loaderFor(s.ctx, file)
+		if !ok {
+			continue
+		}
+		stream, err := loader.LoadStream(s.ctx)
+		if err != nil {
+			// Report the file and move on; it stays pending for the next run
+			return nil, fmt.Errorf("failed to load %s: %w", file.path, err)
+		}
+
+		s.current, s.currentInfo, s.currentFile = file, info, stream
+	}
+}
+
+func (s *fileSystemStream) Close() error {
+	if s.currentFile != nil {
+		return s.currentFile.Close()
+	}
+	return nil
+}
+
+// ============================================================================
+// File State
+// ============================================================================
+
+// FileState records the version of a file at its last successful load
+type FileState struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	ETag    string    `json:"etag,omitempty"`
+}
+
+// Matches reports whether info describes the same version of the file. The
+// ETag is compared when both sides have one, otherwise size and
+// modification time.
+func (s FileState) Matches(info fsx.FileInfo) bool {
+	if s.ETag != "" && info.ETag != "" {
+		return s.ETag == info.ETag
+	}
+	return s.Size == info.Size && s.ModTime.Equal(info.ModTime)
+}
+
+// FileStateStore persists file states between ingestion runs
+type FileStateStore interface {
+	// LoadFileState returns nil, nil when the file was never loaded
+	LoadFileState(ctx context.Context, path string) (*FileState, error)
+	SaveFileState(ctx context.Context, state FileState) error
+}
+
+// MemoryFileStateStore keeps file states in process memory
+type MemoryFileStateStore struct {
+	mu     sync.RWMutex
+	states map[string]FileState
+}
+
+// NewMemoryFileStateStore creates an in-memory file state store
+func NewMemoryFileStateStore() *MemoryFileStateStore {
+	return &MemoryFileStateStore{states: make(map[string]FileState)}
+}
+
+// LoadFileState implements FileStateStore
+func (s *MemoryFileStateStore) LoadFileState(ctx context.Context, path string) (*FileState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.states[path]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SaveFileState implements FileStateStore
+func (s *MemoryFileStateStore) SaveFileState(ctx context.Context, state FileState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.Path] = state
+	return nil
+}
@@ -0,0 +1,79 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/fsx/fsxlocal"
+)
+
+// failingLoader fails every load
+type failingLoader struct{}
+
+func (failingLoader) Load(ctx context.Context) ([]*Document, error) {
+	return nil, errors.New("broken file")
+}
+
+func (failingLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	return nil, errors.New("broken file")
+}
+
+func TestFileSystemLoaderStreamReportsLoadErrors(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "first", "b.bad": "broken", "c.txt": "third"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs, err := fsxlocal.NewLocalFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewLocalFileSystem: %v", err)
+	}
+
+	states := NewMemoryFileStateStore()
+	loader := NewFileSystemLoader(fs, "").
+		WithStateStore(states).
+		WithLoader(".bad", func(Source, Splitter) Loader { return failingLoader{} })
+	stream, err := loader.LoadStream(ctx)
+	if err != nil {
+		t.Fatalf("LoadStream: %v", err)
+	}
+	defer stream.Close()
+
+	var contents []string
+	var errs []error
+	for {
+		doc, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		contents = append(contents, strings.TrimSpace(doc.Content))
+	}
+
+	if strings.Join(contents, ",") != "first,third" {
+		t.Errorf("streamed %v, want the two text files", contents)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "b.bad") {
+		t.Fatalf("errors = %v, want one error naming b.bad", errs)
+	}
+
+	if err := loader.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if state, _ := states.LoadFileState(ctx, "a.txt"); state == nil {
+		t.Error("Commit didn't record a loaded file")
+	}
+	if state, _ := states.LoadFileState(ctx, "b.bad"); state != nil {
+		t.Error("Commit recorded the file that failed to load")
+	}
+}
@@ -0,0 +1,330 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ============================================================================
+// HTML Loader - loads web pages without boilerplate
+// ============================================================================
+
+// HTMLLoader extracts the readable text of an HTML page. Scripts, styles,
+// navigation, forms and page chrome are dropped, and when the page has a
+// <main> or <article> element only that element is read. Headings are kept
+// as Markdown headings so WithSections can split the page along its heading
+// hierarchy. Title, description and language come from the document head.
+type HTMLLoader struct {
+	source   Source
+	splitter Splitter
+	metadata map[string]any
+	sections bool
+}
+
+// NewHTMLLoader creates a new HTML loader
+func NewHTMLLoader(source Source) *HTMLLoader {
+	return &HTMLLoader{
+		source:   source,
+		metadata: make(map[string]any),
+	}
+}
+
+// WithSplitter sets the splitter
+func (l *HTMLLoader) WithSplitter(splitter Splitter) *HTMLLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata
+func (l *HTMLLoader) WithMetadata(key string, value any) *HTMLLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithSections emits one document per heading section instead of one per
+// page, with the heading path in MetadataHeadings
+func (l *HTMLLoader) WithSections(sections bool) *HTMLLoader {
+	l.sections = sections
+	return l
+}
+
+// Load loads the page
+func (l *HTMLLoader) Load(ctx context.Context) ([]*Document, error) {
+	data, err := readSource(ctx, l.source)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	metadata := map[string]any{MetadataDocumentType: "html"}
+	for key, value := range htmlHead(root) {
+		metadata[key] = value
+	}
+
+	text := renderHTML(root)
+	if _, ok := metadata[MetadataTitle]; !ok {
+		if title := firstHeading(text); title != "" {
+			metadata[MetadataTitle] = title
+		}
+	}
+	for k, v := range l.metadata {
+		metadata[k] = v
+	}
+
+	source := sourceName(l.source)
+	if l.sections {
+		return splitDocuments(ctx, sectionDocuments(text, source, metadata), l.splitter)
+	}
+
+	doc := NewDocument(text).
+		WithMetadataMap(metadata).
+		WithMetadata(MetadataSource, source)
+	return splitDocuments(ctx, []*Document{doc}, l.splitter)
+}
+
+// LoadStream loads the page and streams the results
+func (l *HTMLLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(docs), nil
+}
+
+// htmlHead reads the title, meta description and language of a page
+func htmlHead(root *html.Node) map[string]string {
+	out := make(map[string]string)
+	for n := range root.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		switch n.DataAtom {
+		case atom.Html:
+			if lang := htmlAttr(n, "lang"); lang != "" {
+				out[MetadataLanguage] = lang
+			}
+		case atom.Title:
+			if title := collapseSpace(htmlText(n)); title != "" && out[MetadataTitle] == "" {
+				out[MetadataTitle] = title
+			}
+		case atom.Meta:
+			name := htmlAttr(n, "name")
+			if name == "" {
+				name = htmlAttr(n, "property")
+			}
+			content := strings.TrimSpace(htmlAttr(n, "content"))
+			switch name = strings.ToLower(name); {
+			case content == "":
+			case name == "description", name == "og:description" && out[MetadataDescription] == "":
+				out[MetadataDescription] = content
+			case name == "author":
+				out[MetadataAuthor] = content
+			}
+		}
+	}
+	return out
+}
+
+// htmlSkipped holds elements that never carry page content
+var htmlSkipped = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true,
+	atom.Template: true, atom.Nav: true, atom.Aside: true, atom.Form: true,
+	atom.Iframe: true, atom.Svg: true, atom.Canvas: true, atom.Button: true,
+	atom.Select: true, atom.Dialog: true,
+}
+
+// htmlBlocks holds elements that start a new paragraph
+var htmlBlocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Blockquote: true, atom.Figure: true,
+	atom.Figcaption: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Address: true, atom.Details: true, atom.Summary: true, atom.Hr: true,
+	atom.Header: true, atom.Footer: true, atom.Table: true, atom.Body: true,
+}
+
+// renderHTML renders the main content of a page as Markdown-flavoured text
+func renderHTML(root *html.Node) string {
+	content := findElement(root, atom.Main)
+	if content == nil {
+		content = findElement(root, atom.Article)
+	}
+	chrome := content == nil // page-level header and footer are boilerplate
+	if content == nil {
+		if content = findElement(root, atom.Body); content == nil {
+			content = root
+		}
+	}
+
+	r := &htmlRenderer{chrome: chrome}
+	r.render(content)
+	return strings.TrimSpace(string(r.out))
+}
+
+type htmlRenderer struct {
+	out    []byte
+	chrome bool
+	lists  int
+}
+
+func (r *htmlRenderer) render(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		r.children(n)
+		return
+	default:
+		return
+	}
+
+	if htmlSkipped[n.DataAtom] || htmlAttr(n, "hidden") != "" || htmlAttr(n, "aria-hidden") == "true" ||
+		htmlAttr(n, "role") == "navigation" {
+		return
+	}
+	if r.chrome && (n.DataAtom == atom.Header || n.DataAtom == atom.Footer) {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if title := collapseSpace(htmlText(n)); title != "" {
+			r.block()
+			r.out = append(r.out, strings.Repeat("#", int(n.Data[1]-'0'))+" "+title...)
+			r.block()
+		}
+	case atom.Ul, atom.Ol:
+		r.newline()
+		r.lists++
+		r.children(n)
+		r.lists--
+		if r.lists == 0 {
+			r.block()
+		}
+	case atom.Li:
+		r.newline()
+		r.out = append(r.out, strings.Repeat("  ", max(r.lists-1, 0))+"- "...)
+		r.children(n)
+		r.newline()
+	case atom.Tr:
+		r.newline()
+		for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+				if !r.atLineStart() {
+					r.out = append(r.out, '\t')
+				}
+				r.out = append(r.out, collapseSpace(htmlText(cell))...)
+			}
+		}
+		r.newline()
+	case atom.Pre:
+		r.block()
+		r.out = append(r.out, "```\n"+strings.Trim(htmlText(n), "\n")+"\n```"...)
+		r.block()
+	case atom.Br:
+		r.newline()
+	default:
+		if htmlBlocks[n.DataAtom] {
+			r.block()
+			r.children(n)
+			r.block()
+			return
+		}
+		r.children(n)
+	}
+}
+
+func (r *htmlRenderer) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		r.render(child)
+	}
+}
+
+// text appends inline text, collapsing whitespace
+func (r *htmlRenderer) text(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" && !r.atLineStart() && r.out[len(r.out)-1] != ' ' {
+			r.out = append(r.out, ' ')
+		}
+		return
+	}
+	leading := s[0] == ' ' || s[0] == '\n' || s[0] == '\t' || s[0] == '\r'
+	if leading && !r.atLineStart() && r.out[len(r.out)-1] != ' ' {
+		r.out = append(r.out, ' ')
+	}
+	r.out = append(r.out, strings.Join(words, " ")...)
+	if last := s[len(s)-1]; last == ' ' || last == '\n' || last == '\t' || last == '\r' {
+		r.out = append(r.out, ' ')
+	}
+}
+
+func (r *htmlRenderer) atLineStart() bool {
+	return len(r.out) == 0 || r.out[len(r.out)-1] == '\n'
+}
+
+func (r *htmlRenderer) trimSpace() {
+	r.out = bytes.TrimRight(r.out, " \t")
+}
+
+// newline ends the current line
+func (r *htmlRenderer) newline() {
+	r.trimSpace()
+	if !r.atLineStart() {
+		r.out = append(r.out, '\n')
+	}
+}
+
+// block ends the current paragraph with a blank line
+func (r *htmlRenderer) block() {
+	r.newline()
+	if len(r.out) > 0 && !bytes.HasSuffix(r.out, []byte("\n\n")) {
+		r.out = append(r.out, '\n')
+	}
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	for d := range n.Descendants() {
+		if d.Type == html.ElementNode && d.DataAtom == a {
+			return d
+		}
+	}
+	return nil
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			if attr.Val == "" {
+				return key // boolean attribute
+			}
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// htmlText returns the raw text below a node
+func htmlText(n *html.Node) string {
+	var b strings.Builder
+	for d := range n.Descendants() {
+		if d.Type == html.TextNode {
+			b.WriteString(d.Data)
+		}
+	}
+	return b.String()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -2,22 +2,39 @@ package document
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
+	"strconv"
 	"sync"
+	"time"
 )
 
 // ============================================================================
 // Ingestion Pipeline - processes and stores documents
 // ============================================================================
 
-// IngestionPipeline orchestrates document processing and storage
+// IngestionPipeline orchestrates document processing and storage.
+//
+// Chunk IDs are derived from the chunk's source, content hash and the
+// ordinal of that content among identical chunks of the source, so running
+// the pipeline again overwrites chunks instead of duplicating them. IDs set
+// on purpose, such as the page IDs of the PDF and OCR loaders or the links
+// of ParentDocumentSplitter, are kept; only missing IDs and the positional
+// "<document>_chunk_<n>" IDs of the splitters are replaced. With
+// WithRecordStore the pipeline also remembers which chunks each source
+// produced: unchanged chunks are not re-embedded, and chunks a source no
+// longer produces, or whose source disappeared, are deleted.
 type IngestionPipeline struct {
 	loader       Loader
 	splitter     Splitter
 	store        *DocumentStore
 	transformers []DocumentTransformer
 	filters      []DocumentFilter
+	records      SourceRecordStore
+	parentStore  *DocumentStore
 
 	// Performance settings
 	concurrency  int
@@ -67,6 +84,21 @@ func (p *IngestionPipeline) WithErrorHandler(handler ErrorHandler) *IngestionPip
 	return p
 }
 
+// WithParentStore stores the parent sections produced by a
+// ParentDocumentSplitter in a separate store, so similarity search only
+// sees the child chunks. Retriever.WithParentDocuments reads them back.
+func (p *IngestionPipeline) WithParentStore(store *DocumentStore) *IngestionPipeline {
+	p.parentStore = store
+	return p
+}
+
+// WithRecordStore enables change tracking. The store must be dedicated to
+// this pipeline: recorded sources the loader no longer returns are deleted.
+func (p *IngestionPipeline) WithRecordStore(records SourceRecordStore) *IngestionPipeline {
+	p.records = records
+	return p
+}
+
 // ============================================================================
 // Run Pipeline
 // ============================================================================
@@ -105,7 +137,18 @@ func (p *IngestionPipeline) Run(ctx context.Context) (*IngestionResult, error) {
 	}
 
 	// Ingest with concurrency
-	return p.ingestConcurrently(ctx, stream)
+	result, err := p.ingestConcurrently(ctx, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let incremental loaders record what was ingested
+	if committer, ok := p.loader.(loaderCommitter); ok && result.FailedCount == 0 && len(result.Errors) == 0 {
+		if err := committer.Commit(ctx); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to commit loader state: %w", err))
+		}
+	}
+	return result, nil
 }
 
 // ingestConcurrently ingests documents using concurrent workers
@@ -113,9 +156,38 @@ func (p *IngestionPipeline) ingestConcurrently(ctx context.Context, stream Docum
 	result := &IngestionResult{}
 	var mu sync.Mutex
 
+	var tracker *changeTracker
+	if p.records != nil {
+		tracker = newChangeTracker(p.records)
+	}
+
 	// Channel for documents
 	docChan := make(chan *Document, p.concurrency*2)
 	errorChan := make(chan error, p.concurrency)
+	var errs []error
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for err := range errorChan {
+			errs = append(errs, err)
+		}
+	}()
+
+	addBatch := func(batch []*Document) {
+		if err := p.addDocuments(ctx, batch); err != nil {
+			errorChan <- err
+			if tracker != nil {
+				tracker.fail(batch)
+			}
+			mu.Lock()
+			result.FailedCount += len(batch)
+			mu.Unlock()
+			return
+		}
+		mu.Lock()
+		result.ProcessedCount += len(batch)
+		mu.Unlock()
+	}
 
 	// Start workers
 	var wg sync.WaitGroup
@@ -131,38 +203,24 @@ func (p *IngestionPipeline) ingestConcurrently(ctx context.Context, stream Docum
 
 				// Process batch when full
 				if len(batch) >= p.store.batchSize {
-					if err := p.store.AddDocuments(ctx, batch); err != nil {
-						errorChan <- err
-						mu.Lock()
-						result.FailedCount += len(batch)
-						mu.Unlock()
-					} else {
-						mu.Lock()
-						result.ProcessedCount += len(batch)
-						mu.Unlock()
-					}
-					batch = batch[:0]
+					addBatch(batch)
+					batch = make([]*Document, 0, p.store.batchSize)
 				}
 			}
 
 			// Process remaining batch
 			if len(batch) > 0 {
-				if err := p.store.AddDocuments(ctx, batch); err != nil {
-					errorChan <- err
-					mu.Lock()
-					result.FailedCount += len(batch)
-					mu.Unlock()
-				} else {
-					mu.Lock()
-					result.ProcessedCount += len(batch)
-					mu.Unlock()
-				}
+				addBatch(batch)
 			}
 		}()
 	}
 
-	// Feed documents to workers
-	go func() {
+	// Feed documents to workers. complete stays false when the stream
+	// failed or was cancelled, so missing sources aren't mistaken for
+	// deleted ones.
+	complete := true
+	occurrences := make(map[string]int)
+	func() {
 		defer close(docChan)
 
 		for {
@@ -172,12 +230,27 @@ func (p *IngestionPipeline) ingestConcurrently(ctx context.Context, stream Docum
 			}
 			if err != nil {
 				errorChan <- err
+				complete = false
 				continue
 			}
 
+			assignChunkID(doc, occurrences)
+			if tracker != nil {
+				stored, err := tracker.track(ctx, doc)
+				if err != nil {
+					errorChan <- err
+					complete = false
+				}
+				if stored {
+					result.SkippedCount++
+					continue
+				}
+			}
+
 			select {
 			case docChan <- doc:
 			case <-ctx.Done():
+				complete = false
 				return
 			}
 		}
@@ -185,21 +258,64 @@ func (p *IngestionPipeline) ingestConcurrently(ctx context.Context, stream Docum
 
 	// Wait for workers
 	wg.Wait()
-	close(errorChan)
 
-	// Collect errors
-	for err := range errorChan {
-		result.Errors = append(result.Errors, err)
+	if tracker != nil {
+		tracker.finish(ctx, p, result, errorChan, complete)
 	}
 
+	close(errorChan)
+	<-collected
+	result.Errors = append(result.Errors, errs...)
+
 	return result, nil
 }
 
-// IngestionResult contains the results of an ingestion run
+// IngestionResult contains the results of an ingestion run. Chunk counts
+// are always set; source counts need a record store.
 type IngestionResult struct {
-	ProcessedCount int
-	FailedCount    int
-	Errors         []error
+	ProcessedCount int // Chunks written to the store
+	FailedCount    int // Chunks that failed to write
+	SkippedCount   int // Chunks already stored with the same content
+	StaleCount     int // Chunks deleted because their source changed or disappeared
+
+	AddedCount     int // Sources ingested for the first time
+	UpdatedCount   int // Sources whose chunks changed
+	UnchangedCount int // Sources whose chunks were all stored already
+	DeletedCount   int // Sources that disappeared, with their chunks
+
+	Errors []error
+}
+
+// addDocuments writes a batch, sending parent sections to the parent store
+// when there is one
+func (p *IngestionPipeline) addDocuments(ctx context.Context, batch []*Document) error {
+	if p.parentStore == nil {
+		return p.store.AddDocuments(ctx, batch)
+	}
+
+	var parents, chunks []*Document
+	for _, doc := range batch {
+		if role, _ := doc.Metadata[MetadataChunkRole].(string); role == ChunkRoleParent {
+			parents = append(parents, doc)
+		} else {
+			chunks = append(chunks, doc)
+		}
+	}
+	if err := p.parentStore.AddDocuments(ctx, parents); err != nil {
+		return err
+	}
+	return p.store.AddDocuments(ctx, chunks)
+}
+
+// deleteDocuments deletes chunks from the store and the parent store
+func (p *IngestionPipeline) deleteDocuments(ctx context.Context, ids []string) error {
+	if err := p.store.DeleteDocuments(ctx, ids); err != nil {
+		return err
+	}
+	if p.parentStore != nil {
+		return p.parentStore.DeleteDocuments(ctx, ids)
+	}
+	return nil
 }
 
 func defaultErrorHandler(doc *Document, err error) error {
@@ -239,3 +355,349 @@ func (s *filteredStream) Next() (*Document, error) {
 func (s *filteredStream) Close() error {
 	return s.stream.Close()
 }
+
+// ============================================================================
+// Change Tracking
+// ============================================================================
+
+// ContentHash returns the hex SHA-256 of a chunk's content
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ChunkID returns the deterministic ID of a chunk from its scope (usually
+// its source), its content hash and its ordinal among the chunks of the
+// scope with the same content. The first occurrence has ordinal 0, so a
+// repeated header or footer gets a distinct ID on every page.
+func ChunkID(scope, contentHash string, ordinal int) string {
+	key := scope + "\x00" + contentHash
+	if ordinal > 0 {
+		key += "\x00" + strconv.Itoa(ordinal)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// assignChunkID sets the content hash of a chunk and, unless its ID was set
+// on purpose, its deterministic ID. occurrences counts the chunks seen so
+// far per source and content hash. A content hash set by the splitter is
+// kept: ContextualSplitter hashes chunks before their content is rewritten.
+func assignChunkID(doc *Document, occurrences map[string]int) {
+	if doc.Metadata == nil {
+		doc.Metadata = make(Metadata)
+	}
+	hash, _ := doc.Metadata[MetadataContentHash].(string)
+	if hash == "" {
+		hash = ContentHash(doc.Content)
+		doc.Metadata[MetadataContentHash] = hash
+	}
+	if !hasGeneratedID(doc) {
+		return
+	}
+
+	source, _ := doc.Metadata[MetadataSource].(string)
+	key := source + "\x00" + hash
+	doc.ID = ChunkID(source, hash, occurrences[key])
+	occurrences[key]++
+}
+
+// hasGeneratedID reports whether a chunk has no ID or the positional
+// "<document>_chunk_<n>" ID the splitters give their chunks
+func hasGeneratedID(doc *Document) bool {
+	if doc.ID == "" {
+		return true
+	}
+	parent, ok := doc.GetMetadataString(MetadataDocumentID)
+	if !ok {
+		return false
+	}
+	index, ok := doc.GetMetadataInt(MetadataChunkIndex)
+	return ok && doc.ID == fmt.Sprintf("%s_chunk_%d", parent, index)
+}
+
+// SourceRecord lists the chunks a source produced in its last ingestion.
+// ContentHashes maps chunk IDs to their content hash, so a chunk that keeps
+// its ID while its content changes is written again.
+type SourceRecord struct {
+	Source        string            `json:"source"`
+	ChunkIDs      []string          `json:"chunk_ids"`
+	ContentHashes map[string]string `json:"content_hashes,omitempty"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// SourceRecordStore persists source records between ingestion runs
+type SourceRecordStore interface {
+	// LoadSourceRecord returns nil, nil when the source was never ingested
+	LoadSourceRecord(ctx context.Context, source string) (*SourceRecord, error)
+	SaveSourceRecord(ctx context.Context, record *SourceRecord) error
+	DeleteSourceRecord(ctx context.Context, source string) error
+
+	// ListSources returns every source with a record
+	ListSources(ctx context.Context) ([]string, error)
+}
+
+// SourceLister is implemented by loaders that know every source they cover,
+// including ones they skip because they haven't changed. The pipeline only
+// deletes recorded sources that are missing from this list.
+type SourceLister interface {
+	ListSources(ctx context.Context) ([]string, error)
+}
+
+// loaderCommitter is implemented by incremental loaders that record what
+// was loaded once the run has been stored
+type loaderCommitter interface {
+	Commit(ctx context.Context) error
+}
+
+// MemorySourceRecordStore keeps source records in process memory
+type MemorySourceRecordStore struct {
+	mu      sync.RWMutex
+	records map[string]SourceRecord
+}
+
+// NewMemorySourceRecordStore creates an in-memory source record store
+func NewMemorySourceRecordStore() *MemorySourceRecordStore {
+	return &MemorySourceRecordStore{records: make(map[string]SourceRecord)}
+}
+
+// LoadSourceRecord implements SourceRecordStore
+func (s *MemorySourceRecordStore) LoadSourceRecord(ctx context.Context, source string) (*SourceRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[source]
+	if !ok {
+		return nil, nil
+	}
+	record.ChunkIDs = append([]string(nil), record.ChunkIDs...)
+	record.ContentHashes = maps.Clone(record.ContentHashes)
+	return &record, nil
+}
+
+// SaveSourceRecord implements SourceRecordStore
+func (s *MemorySourceRecordStore) SaveSourceRecord(ctx context.Context, record *SourceRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *record
+	saved.ChunkIDs = append([]string(nil), record.ChunkIDs...)
+	saved.ContentHashes = maps.Clone(record.ContentHashes)
+	s.records[record.Source] = saved
+	return nil
+}
+
+// DeleteSourceRecord implements SourceRecordStore
+func (s *MemorySourceRecordStore) DeleteSourceRecord(ctx context.Context, source string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, source)
+	return nil
+}
+
+// ListSources implements SourceRecordStore
+func (s *MemorySourceRecordStore) ListSources(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sources := make([]string, 0, len(s.records))
+	for source := range s.records {
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// changeTracker compares the chunks of a run with the source records
+type changeTracker struct {
+	records SourceRecordStore
+
+	mu      sync.Mutex
+	sources map[string]*sourceChanges
+	order   []string
+}
+
+// sourceChanges holds the chunks one source produced in this run
+type sourceChanges struct {
+	previous map[string]string // Chunk ID to content hash; nil when the source is new
+	chunks   []string
+	hashes   map[string]string
+	seen     map[string]bool
+	written  int  // chunks sent to the store
+	failed   bool // some chunks could not be stored
+	unknown  bool // the previous record could not be loaded
+}
+
+func newChangeTracker(records SourceRecordStore) *changeTracker {
+	return &changeTracker{records: records, sources: make(map[string]*sourceChanges)}
+}
+
+// track records a chunk and reports whether the store already holds it with
+// the same content. A chunk whose ID was already seen in this run is
+// reported as stored. Chunks without a source are not tracked.
+func (t *changeTracker) track(ctx context.Context, doc *Document) (bool, error) {
+	source, _ := doc.Metadata[MetadataSource].(string)
+	if source == "" {
+		return false, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var loadErr error
+	changes, ok := t.sources[source]
+	if !ok {
+		changes = &sourceChanges{seen: make(map[string]bool), hashes: make(map[string]string)}
+		t.sources[source] = changes
+		t.order = append(t.order, source)
+
+		record, err := t.records.LoadSourceRecord(ctx, source)
+		switch {
+		case err != nil:
+			// Without the record the source can't be compared: write
+			// every chunk and leave the record alone
+			changes.unknown = true
+			loadErr = fmt.Errorf("failed to load record of %s: %w", source, err)
+		case record != nil:
+			changes.previous = make(map[string]string, len(record.ChunkIDs))
+			for _, id := range record.ChunkIDs {
+				changes.previous[id] = record.ContentHashes[id]
+			}
+		}
+	}
+
+	if changes.seen[doc.ID] {
+		return true, loadErr
+	}
+	hash, _ := doc.Metadata[MetadataContentHash].(string)
+	changes.seen[doc.ID] = true
+	changes.chunks = append(changes.chunks, doc.ID)
+	changes.hashes[doc.ID] = hash
+	// Records written before hashes were kept only hold content-derived IDs
+	if previous, ok := changes.previous[doc.ID]; ok && (previous == "" || previous == hash) {
+		return true, loadErr
+	}
+	changes.written++
+	return false, loadErr
+}
+
+// unwrittenHash is recorded for chunks that could not be stored. It matches
+// no content, so the next run writes them again.
+const unwrittenHash = "unwritten"
+
+// fail marks the sources of a batch that could not be stored
+func (t *changeTracker) fail(batch []*Document) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, doc := range batch {
+		source, _ := doc.Metadata[MetadataSource].(string)
+		if changes, ok := t.sources[source]; ok {
+			changes.failed = true
+			changes.hashes[doc.ID] = unwrittenHash
+		}
+	}
+}
+
+// finish deletes stale chunks, updates the records and counts the sources.
+// Sources with failed writes keep the chunks of both runs in their record,
+// so the next successful run cleans them up. Recorded sources missing from
+// the run are deleted only when the whole stream was read.
+func (t *changeTracker) finish(ctx context.Context, p *IngestionPipeline, result *IngestionResult, errs chan<- error, complete bool) {
+	now := time.Now()
+	for _, source := range t.order {
+		changes := t.sources[source]
+		if changes.unknown {
+			continue
+		}
+		record := &SourceRecord{
+			Source:        source,
+			ChunkIDs:      changes.chunks,
+			ContentHashes: changes.hashes,
+			UpdatedAt:     now,
+		}
+
+		var stale []string
+		for id := range changes.previous {
+			if !changes.seen[id] {
+				stale = append(stale, id)
+			}
+		}
+
+		if !changes.failed && len(stale) > 0 {
+			if err := p.deleteDocuments(ctx, stale); err != nil {
+				errs <- fmt.Errorf("failed to delete stale chunks of %s: %w", source, err)
+				changes.failed = true
+			} else {
+				result.StaleCount += len(stale)
+			}
+		}
+		if changes.failed {
+			record.ChunkIDs = append(append([]string(nil), changes.chunks...), stale...)
+			for _, id := range stale {
+				record.ContentHashes[id] = changes.previous[id]
+			}
+		}
+
+		if err := t.records.SaveSourceRecord(ctx, record); err != nil {
+			errs <- fmt.Errorf("failed to save record of %s: %w", source, err)
+			continue
+		}
+
+		switch {
+		case changes.failed:
+		case changes.previous == nil:
+			result.AddedCount++
+		case changes.written == 0 && len(stale) == 0:
+			result.UnchangedCount++
+		default:
+			result.UpdatedCount++
+		}
+	}
+
+	if !complete {
+		return
+	}
+	if err := t.deleteMissing(ctx, p, result); err != nil {
+		errs <- err
+	}
+}
+
+// deleteMissing deletes the chunks and records of sources the loader no
+// longer returns
+func (t *changeTracker) deleteMissing(ctx context.Context, p *IngestionPipeline, result *IngestionResult) error {
+	recorded, err := t.records.ListSources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list source records: %w", err)
+	}
+
+	present := make(map[string]bool, len(t.sources))
+	for source := range t.sources {
+		present[source] = true
+	}
+	if lister, ok := p.loader.(SourceLister); ok {
+		sources, err := lister.ListSources(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list loader sources: %w", err)
+		}
+		for _, source := range sources {
+			present[source] = true
+		}
+	}
+
+	for _, source := range recorded {
+		if present[source] {
+			continue
+		}
+		record, err := t.records.LoadSourceRecord(ctx, source)
+		if err != nil || record == nil {
+			continue
+		}
+		if len(record.ChunkIDs) > 0 {
+			if err := p.deleteDocuments(ctx, record.ChunkIDs); err != nil {
+				return fmt.Errorf("failed to delete chunks of %s: %w", source, err)
+			}
+		}
+		if err := t.records.DeleteSourceRecord(ctx, source); err != nil {
+			return fmt.Errorf("failed to delete record of %s: %w", source, err)
+		}
+		result.StaleCount += len(record.ChunkIDs)
+		result.DeletedCount++
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
+)
+
+// chunkLoader emits one chunk per text, tagged with its source
+type chunkLoader struct {
+	sources map[string][]string
+}
+
+func (l *chunkLoader) Load(ctx context.Context) ([]*Document, error) {
+	var docs []*Document
+	names := make([]string, 0, len(l.sources))
+	for source := range l.sources {
+		names = append(names, source)
+	}
+	sort.Strings(names)
+	for _, source := range names {
+		for _, text := range l.sources[source] {
+			docs = append(docs, NewDocument(text).WithMetadata(MetadataSource, source))
+		}
+	}
+	return docs, nil
+}
+
+func (l *chunkLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, _ := l.Load(ctx)
+	return DocumentStreamFunc(func() (*Document, error) {
+		if len(docs) == 0 {
+			return nil, io.EOF
+		}
+		doc := docs[0]
+		docs = docs[1:]
+		return doc, nil
+	}), nil
+}
+
+// flakyEmbedder fails every embedding while fail is set
+type flakyEmbedder struct {
+	lengthEmbedder
+	fail bool
+}
+
+func (e *flakyEmbedder) EmbedDocuments(ctx context.Context, texts []string, opts ...embedding.Option) ([]embedding.Embedding, error) {
+	if e.fail {
+		return nil, errors.New("embedding service unavailable")
+	}
+	return e.lengthEmbedder.EmbedDocuments(ctx, texts, opts...)
+}
+
+func chunkIDOf(source, text string) string {
+	return ChunkID(source, ContentHash(text), 0)
+}
+
+func recordedIDs(t *testing.T, records SourceRecordStore, source string) []string {
+	t.Helper()
+	record, err := records.LoadSourceRecord(context.Background(), source)
+	if err != nil {
+		t.Fatalf("LoadSourceRecord: %v", err)
+	}
+	if record == nil {
+		return nil
+	}
+	ids := slices.Clone(record.ChunkIDs)
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedIDs(ids ...string) []string {
+	sort.Strings(ids)
+	return ids
+}
+
+func TestIngestionPipelineTracksChanges(t *testing.T) {
+	ctx := context.Background()
+	index := newTestIndex(2)
+	embedder := &flakyEmbedder{lengthEmbedder: lengthEmbedder{dimensions: 2}}
+	store := NewDocumentStore(index.Store, embedder)
+	records := NewMemorySourceRecordStore()
+	loader := &chunkLoader{sources: map[string][]string{
+		"a.txt": {"alpha", "beta"},
+		"b.txt": {"gamma"},
+	}}
+	pipeline := NewIngestionPipeline(loader, store).WithRecordStore(records)
+
+	run := func(t *testing.T) *IngestionResult {
+		t.Helper()
+		result, err := pipeline.Run(ctx)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return result
+	}
+	assertStored := func(t *testing.T, want map[string]string) {
+		t.Helper()
+		got := storedIDs(t, index)
+		if len(got) != len(want) {
+			t.Fatalf("stored %v, want %v", got, want)
+		}
+		for id, content := range want {
+			if got[id] != content {
+				t.Errorf("stored %s = %q, want %q", id, got[id], content)
+			}
+		}
+	}
+
+	t.Run("first run", func(t *testing.T) {
+		result := run(t)
+		if result.ProcessedCount != 3 || result.AddedCount != 2 {
+			t.Errorf("result = %+v, want 3 processed chunks from 2 added sources", result)
+		}
+		assertStored(t, map[string]string{
+			chunkIDOf("a.txt", "alpha"): "alpha",
+			chunkIDOf("a.txt", "beta"):  "beta",
+			chunkIDOf("b.txt", "gamma"): "gamma",
+		})
+	})
+
+	t.Run("unchanged input", func(t *testing.T) {
+		calls := embedder.calls
+		result := run(t)
+		if result.ProcessedCount != 0 || result.SkippedCount != 3 || result.UnchangedCount != 2 {
+			t.Errorf("result = %+v, want 3 skipped chunks from 2 unchanged sources", result)
+		}
+		if embedder.calls != calls {
+			t.Error("unchanged chunks were embedded again")
+		}
+		if len(storedIDs(t, index)) != 3 {
+			t.Error("unchanged run duplicated chunks")
+		}
+	})
+
+	t.Run("changed chunk", func(t *testing.T) {
+		loader.sources["a.txt"] = []string{"alpha", "beta v2"}
+		result := run(t)
+		if result.ProcessedCount != 1 || result.StaleCount != 1 || result.UpdatedCount != 1 || result.UnchangedCount != 1 {
+			t.Errorf("result = %+v, want 1 written and 1 stale chunk", result)
+		}
+		assertStored(t, map[string]string{
+			chunkIDOf("a.txt", "alpha"):   "alpha",
+			chunkIDOf("a.txt", "beta v2"): "beta v2",
+			chunkIDOf("b.txt", "gamma"):   "gamma",
+		})
+		want := sortedIDs(chunkIDOf("a.txt", "alpha"), chunkIDOf("a.txt", "beta v2"))
+		if got := recordedIDs(t, records, "a.txt"); !slices.Equal(got, want) {
+			t.Errorf("record = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("failed batch", func(t *testing.T) {
+		loader.sources["a.txt"] = []string{"alpha", "beta v3"}
+		embedder.fail = true
+		result := run(t)
+		embedder.fail = false
+		if result.FailedCount != 1 || result.StaleCount != 0 || len(result.Errors) == 0 {
+			t.Errorf("result = %+v, want 1 failed chunk and nothing deleted", result)
+		}
+		if _, ok := storedIDs(t, index)[chunkIDOf("a.txt", "beta v2")]; !ok {
+			t.Error("previous chunk deleted although its replacement was not stored")
+		}
+		want := sortedIDs(chunkIDOf("a.txt", "alpha"), chunkIDOf("a.txt", "beta v2"), chunkIDOf("a.txt", "beta v3"))
+		if got := recordedIDs(t, records, "a.txt"); !slices.Equal(got, want) {
+			t.Errorf("record = %v, want the chunks of both runs %v", got, want)
+		}
+
+		// The next run stores the failed chunk and cleans up the old one
+		result = run(t)
+		if result.ProcessedCount != 1 || result.StaleCount != 1 || len(result.Errors) != 0 {
+			t.Errorf("retry result = %+v, want 1 written and 1 stale chunk", result)
+		}
+		assertStored(t, map[string]string{
+			chunkIDOf("a.txt", "alpha"):   "alpha",
+			chunkIDOf("a.txt", "beta v3"): "beta v3",
+			chunkIDOf("b.txt", "gamma"):   "gamma",
+		})
+	})
+
+	t.Run("removed source", func(t *testing.T) {
+		delete(loader.sources, "b.txt")
+		result := run(t)
+		if result.DeletedCount != 1 || result.StaleCount != 1 {
+			t.Errorf("result = %+v, want 1 deleted source with 1 chunk", result)
+		}
+		if _, ok := storedIDs(t, index)[chunkIDOf("b.txt", "gamma")]; ok {
+			t.Error("chunk of the removed source is still stored")
+		}
+		if got := recordedIDs(t, records, "b.txt"); got != nil {
+			t.Errorf("record of the removed source = %v, want none", got)
+		}
+	})
+}
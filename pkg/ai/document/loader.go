@@ -5,8 +5,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -110,14 +114,7 @@ func (l *TextLoader) getReader() (io.Reader, error) {
 }
 
 func (l *TextLoader) getSourceName() string {
-	switch l.source.Type {
-	case SourceTypeFile:
-		return l.source.Path
-	case SourceTypeURL:
-		return l.source.URL
-	default:
-		return "unknown"
-	}
+	return sourceName(l.source)
 }
 
 // textStream implements DocumentStream for text files
@@ -193,17 +190,104 @@ func (s *textStream) Close() error {
 	return nil
 }
 
+// ============================================================================
+// Format Dispatch - picks a loader by file extension or MIME type
+// ============================================================================
+
+// LoaderFactory creates the loader for a single file
+type LoaderFactory func(source Source, splitter Splitter) Loader
+
+// FormatLoaders maps file extensions (".pdf") and MIME types
+// ("application/pdf") to loader factories
+type FormatLoaders map[string]LoaderFactory
+
+// DefaultFormatLoaders returns the factories of the built-in loaders
+func DefaultFormatLoaders() FormatLoaders {
+	text := func(source Source, splitter Splitter) Loader {
+		return NewTextLoader(source).WithSplitter(splitter)
+	}
+	pdf := func(source Source, splitter Splitter) Loader {
+		return NewPDFLoader(source).WithSplitter(splitter)
+	}
+	docx := func(source Source, splitter Splitter) Loader {
+		return NewDOCXLoader(source).WithSplitter(splitter)
+	}
+	html := func(source Source, splitter Splitter) Loader {
+		return NewHTMLLoader(source).WithSplitter(splitter)
+	}
+	markdown := func(source Source, splitter Splitter) Loader {
+		return NewMarkdownLoader(source).WithSplitter(splitter)
+	}
+
+	return FormatLoaders{
+		".txt":       text,
+		"text/plain": text,
+		".pdf":       pdf,
+		".docx":      docx,
+		".html":      html,
+		".htm":       html,
+		".xhtml":     html,
+		".md":        markdown,
+		".markdown":  markdown,
+
+		"application/pdf": pdf,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": docx,
+		"text/html":             html,
+		"application/xhtml+xml": html,
+		"text/markdown":         markdown,
+		"text/x-markdown":       markdown,
+	}
+}
+
+// Lookup returns the factory for a file, trying its extension, the MIME type
+// registered for the extension, then the MIME type sniffed from head (the
+// first bytes of the file, may be nil). Other text/* files fall back to the
+// "text/plain" factory; unknown binary files have no loader.
+func (f FormatLoaders) Lookup(name string, head []byte) (LoaderFactory, bool) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if factory, ok := f[ext]; ok && ext != "" {
+		return factory, true
+	}
+
+	if factory, ok := f.LookupType(mime.TypeByExtension(ext)); ok {
+		return factory, true
+	}
+	if head != nil {
+		return f.LookupType(http.DetectContentType(head))
+	}
+	return nil, false
+}
+
+// LookupType returns the factory for a MIME type such as "text/html;
+// charset=utf-8". Other text/* types fall back to the "text/plain" factory.
+func (f FormatLoaders) LookupType(contentType string) (LoaderFactory, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	if factory, ok := f[mediaType]; ok {
+		return factory, true
+	}
+	if factory, ok := f["text/plain"]; ok && strings.HasPrefix(mediaType, "text/") {
+		return factory, true
+	}
+	return nil, false
+}
+
 // ============================================================================
 // Directory Loader - loads multiple files from a directory
 // ============================================================================
 
-// DirectoryLoader loads documents from a directory
+// DirectoryLoader loads documents from a directory, picking each file's
+// loader from its extension or MIME type. Files without a loader, such as
+// images, and files that fail to load are skipped.
 type DirectoryLoader struct {
 	path      string
 	pattern   string // Glob pattern
 	recursive bool
 	splitter  Splitter
 	metadata  map[string]any
+	loaders   FormatLoaders
 }
 
 // NewDirectoryLoader creates a new directory loader
@@ -212,10 +296,12 @@ func NewDirectoryLoader(path string) *DirectoryLoader {
 		path:     path,
 		pattern:  "*",
 		metadata: make(map[string]any),
+		loaders:  DefaultFormatLoaders(),
 	}
 }
 
-// WithPattern sets the file pattern
+// WithPattern sets the file pattern, matched against file names, or against
+// slash-separated paths relative to the directory when it contains a "/"
 func (l *DirectoryLoader) WithPattern(pattern string) *DirectoryLoader {
 	l.pattern = pattern
 	return l
@@ -233,6 +319,20 @@ func (l *DirectoryLoader) WithSplitter(splitter Splitter) *DirectoryLoader {
 	return l
 }
 
+// WithMetadata adds metadata to every document, unless the file's loader
+// set the same key
+func (l *DirectoryLoader) WithMetadata(key string, value any) *DirectoryLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithLoader registers the loader for a file extension (".csv") or MIME
+// type, replacing the built-in one
+func (l *DirectoryLoader) WithLoader(extOrMIME string, factory LoaderFactory) *DirectoryLoader {
+	l.loaders[strings.ToLower(extOrMIME)] = factory
+	return l
+}
+
 // Load loads all documents
 func (l *DirectoryLoader) Load(ctx context.Context) ([]*Document, error) {
 	files, err := l.listFiles()
@@ -242,9 +342,14 @@ func (l *DirectoryLoader) Load(ctx context.Context) ([]*Document, error) {
 
 	var allDocs []*Document
 	for _, file := range files {
-		loader := NewTextLoader(FromFile(file)).
-			WithSplitter(l.splitter).
-			WithMetadata(MetadataSource, file)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		loader, ok := l.loaderFor(file)
+		if !ok {
+			continue
+		}
 
 		docs, err := loader.Load(ctx)
 		if err != nil {
@@ -252,7 +357,9 @@ func (l *DirectoryLoader) Load(ctx context.Context) ([]*Document, error) {
 			continue
 		}
 
-		allDocs = append(allDocs, docs...)
+		for _, doc := range docs {
+			allDocs = append(allDocs, l.addMetadata(doc))
+		}
 	}
 
 	return allDocs, nil
@@ -266,28 +373,93 @@ func (l *DirectoryLoader) LoadStream(ctx context.Context) (DocumentStream, error
 	}
 
 	return &directoryStream{
-		files:    files,
-		splitter: l.splitter,
-		ctx:      ctx,
+		files:  files,
+		loader: l,
+		ctx:    ctx,
 	}, nil
 }
 
+// ListSources implements SourceLister
+func (l *DirectoryLoader) ListSources(ctx context.Context) ([]string, error) {
+	return l.listFiles()
+}
+
 func (l *DirectoryLoader) listFiles() ([]string, error) {
-	// Simple implementation - list files in directory
-	// In production, use filepath.Walk or filepath.Glob
-	entries, err := os.ReadDir(l.path)
+	var files []string
+	err := filepath.WalkDir(l.path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != l.path && !l.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := entry.Name()
+		if strings.Contains(l.pattern, "/") {
+			rel, err := filepath.Rel(l.path, path)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(rel)
+		}
+		matched, err := filepath.Match(l.pattern, name)
+		if err != nil {
+			return err
+		}
+		if matched {
+			files = append(files, path)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var files []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			files = append(files, filepath.Join(l.path, entry.Name()))
+	return files, nil
+}
+
+// loaderFor picks the loader of a file, sniffing its content when the
+// extension isn't conclusive
+func (l *DirectoryLoader) loaderFor(file string) (Loader, bool) {
+	factory, ok := l.loaders.Lookup(file, nil)
+	if !ok {
+		head, err := readHead(file)
+		if err != nil {
+			return nil, false
+		}
+		if factory, ok = l.loaders.Lookup(file, head); !ok {
+			return nil, false
 		}
 	}
+	return factory(FromFile(file), l.splitter), true
+}
 
-	return files, nil
+func (l *DirectoryLoader) addMetadata(doc *Document) *Document {
+	for k, v := range l.metadata {
+		if _, ok := doc.Metadata[k]; !ok {
+			doc.Metadata[k] = v
+		}
+	}
+	return doc
+}
+
+// readHead reads the bytes used for content type detection
+func readHead(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:n], nil
 }
 
 // directoryStream implements DocumentStream for directories
@@ -295,43 +467,49 @@ type directoryStream struct {
 	files       []string
 	fileIndex   int
 	currentFile DocumentStream
-	splitter    Splitter
+	loader      *DirectoryLoader
 	ctx         context.Context
 }
 
 func (s *directoryStream) Next() (*Document, error) {
-	// Try current file stream
-	if s.currentFile != nil {
-		doc, err := s.currentFile.Next()
-		if err == nil {
-			return doc, nil
+	for {
+		// Try current file stream
+		if s.currentFile != nil {
+			doc, err := s.currentFile.Next()
+			if err == nil {
+				return s.loader.addMetadata(doc), nil
+			}
+			if err != io.EOF {
+				return nil, err
+			}
+			// EOF - close and move to next file
+			s.currentFile.Close()
+			s.currentFile = nil
 		}
-		if err != io.EOF {
+
+		// Move to next file
+		if s.fileIndex >= len(s.files) {
+			return nil, io.EOF
+		}
+		if err := s.ctx.Err(); err != nil {
 			return nil, err
 		}
-		// EOF - close and move to next file
-		s.currentFile.Close()
-		s.currentFile = nil
-	}
 
-	// Move to next file
-	if s.fileIndex >= len(s.files) {
-		return nil, io.EOF
-	}
+		file := s.files[s.fileIndex]
+		s.fileIndex++
 
-	file := s.files[s.fileIndex]
-	s.fileIndex++
+		loader, ok := s.loader.loaderFor(file)
+		if !ok {
+			continue
+		}
+		stream, err := loader.LoadStream(s.ctx)
+		if err != nil {
+			// Skip this file
+			continue
+		}
 
-	// Create loader for this file
-	loader := NewTextLoader(FromFile(file)).WithSplitter(s.splitter)
-	stream, err := loader.LoadStream(s.ctx)
-	if err != nil {
-		// Skip this file
-		return s.Next()
+		s.currentFile = stream
 	}
-
-	s.currentFile = stream
-	return s.Next()
 }
 
 func (s *directoryStream) Close() error {
@@ -340,3 +518,177 @@ func (s *directoryStream) Close() error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Loader Helpers - shared by the format loaders
+// ============================================================================
+
+// readSource reads the whole source into memory. Format loaders need random
+// access to parse PDF, DOCX and HTML files.
+func readSource(ctx context.Context, source Source) ([]byte, error) {
+	switch source.Type {
+	case SourceTypeFile:
+		return os.ReadFile(source.Path)
+	case SourceTypeReader:
+		if closer, ok := source.Reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+		return io.ReadAll(source.Reader)
+	case SourceTypeBytes, SourceTypeString:
+		return source.Data, nil
+	case SourceTypeURL:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("failed to fetch %s: %s", source.URL, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported source type: %s", source.Type)
+	}
+}
+
+// sourceName returns the path or URL of a source. In-memory sources may set
+// Path to name their content.
+func sourceName(source Source) string {
+	switch {
+	case source.Type == SourceTypeURL:
+		return source.URL
+	case source.Path != "":
+		return source.Path
+	default:
+		return "unknown"
+	}
+}
+
+// sliceStream streams already loaded documents
+func sliceStream(docs []*Document) DocumentStream {
+	index := 0
+	return DocumentStreamFunc(func() (*Document, error) {
+		if index >= len(docs) {
+			return nil, io.EOF
+		}
+		doc := docs[index]
+		index++
+		return doc, nil
+	})
+}
+
+// splitDocuments applies an optional splitter to loaded documents
+func splitDocuments(ctx context.Context, docs []*Document, splitter Splitter) ([]*Document, error) {
+	if splitter == nil {
+		return docs, nil
+	}
+	var out []*Document
+	for _, doc := range docs {
+		chunks, err := splitter.Split(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunks...)
+	}
+	return out, nil
+}
+
+// section is a run of Markdown text under a heading path
+type section struct {
+	headings []string
+	content  string
+}
+
+// splitSections splits Markdown text at ATX headings ("# Title"), ignoring
+// headings inside fenced code blocks. Each section keeps its heading line
+// and records the path of enclosing headings.
+func splitSections(text string) []section {
+	return splitSectionsToLevel(text, 6)
+}
+
+// splitSectionsToLevel splits at headings up to maxLevel; deeper headings
+// stay inside their section
+func splitSectionsToLevel(text string, maxLevel int) []section {
+	var sections []section
+	var path []string
+	var levels []int
+	var current strings.Builder
+	fence := ""
+
+	flush := func() {
+		if content := strings.TrimSpace(current.String()); content != "" {
+			sections = append(sections, section{headings: slices.Clone(path), content: content})
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		default:
+			if level, title := parseHeading(line); level > 0 && level <= maxLevel {
+				flush()
+				for len(levels) > 0 && levels[len(levels)-1] >= level {
+					levels = levels[:len(levels)-1]
+					path = path[:len(path)-1]
+				}
+				levels = append(levels, level)
+				path = append(path, title)
+			}
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+	return sections
+}
+
+// parseHeading returns the level and text of an ATX heading line, or 0
+func parseHeading(line string) (int, string) {
+	if len(line) > 3 && strings.HasPrefix(line, "    ") {
+		return 0, "" // indented code
+	}
+	trimmed := strings.TrimLeft(line, " ")
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t') {
+		return 0, ""
+	}
+	title := strings.TrimSpace(trimmed[level:])
+	title = strings.TrimSpace(strings.TrimRight(title, "#"))
+	if title == "" {
+		return 0, ""
+	}
+	return level, title
+}
+
+// sectionDocuments turns Markdown text into one document per section
+func sectionDocuments(text, source string, metadata map[string]any) []*Document {
+	sections := splitSections(text)
+	docs := make([]*Document, 0, len(sections))
+	for i, sec := range sections {
+		doc := NewDocument(sec.content).
+			WithID(fmt.Sprintf("%s_section_%d", source, i)).
+			WithMetadataMap(metadata).
+			WithMetadata(MetadataSource, source).
+			WithMetadata(MetadataChunkIndex, i).
+			WithMetadata(MetadataChunkTotal, len(sections))
+		if len(sec.headings) > 0 {
+			doc.WithMetadata(MetadataHeadings, sec.headings)
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
@@ -0,0 +1,273 @@
+package document
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Markdown Loader - loads Markdown with front matter
+// ============================================================================
+
+// MarkdownLoader loads Markdown files. YAML ("---") or TOML ("+++") front
+// matter is removed from the content and its fields are added to the
+// metadata, so "title", "author" or "tags" land on the standard keys. When
+// there is no title field the first heading is used.
+//
+// Front matter is read with a small parser that understands scalars, quoted
+// strings, inline and block lists and one level of nested keys, which covers
+// what static site generators write.
+type MarkdownLoader struct {
+	source   Source
+	splitter Splitter
+	metadata map[string]any
+	sections bool
+}
+
+// NewMarkdownLoader creates a new Markdown loader
+func NewMarkdownLoader(source Source) *MarkdownLoader {
+	return &MarkdownLoader{
+		source:   source,
+		metadata: make(map[string]any),
+	}
+}
+
+// WithSplitter sets the splitter
+func (l *MarkdownLoader) WithSplitter(splitter Splitter) *MarkdownLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata
+func (l *MarkdownLoader) WithMetadata(key string, value any) *MarkdownLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithSections emits one document per heading section instead of one per
+// file, with the heading path in MetadataHeadings
+func (l *MarkdownLoader) WithSections(sections bool) *MarkdownLoader {
+	l.sections = sections
+	return l
+}
+
+// Load loads the file
+func (l *MarkdownLoader) Load(ctx context.Context) ([]*Document, error) {
+	data, err := readSource(ctx, l.source)
+	if err != nil {
+		return nil, err
+	}
+
+	frontMatter, body := ParseFrontMatter(string(data))
+	metadata := map[string]any{MetadataDocumentType: "markdown"}
+	for k, v := range frontMatter {
+		metadata[k] = v
+	}
+	if _, ok := metadata[MetadataTitle]; !ok {
+		if title := firstHeading(body); title != "" {
+			metadata[MetadataTitle] = title
+		}
+	}
+	for k, v := range l.metadata {
+		metadata[k] = v
+	}
+
+	source := sourceName(l.source)
+	if l.sections {
+		return splitDocuments(ctx, sectionDocuments(body, source, metadata), l.splitter)
+	}
+
+	doc := NewDocument(strings.TrimSpace(body)).
+		WithMetadataMap(metadata).
+		WithMetadata(MetadataSource, source)
+	return splitDocuments(ctx, []*Document{doc}, l.splitter)
+}
+
+// LoadStream loads the file and streams the results
+func (l *MarkdownLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(docs), nil
+}
+
+// ============================================================================
+// Front Matter
+// ============================================================================
+
+// ParseFrontMatter splits YAML or TOML front matter from a Markdown text. It
+// returns nil metadata and the unchanged text when there is none.
+func ParseFrontMatter(text string) (map[string]any, string) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+
+	var delimiter string
+	switch {
+	case strings.HasPrefix(normalized, "---\n"):
+		delimiter = "---"
+	case strings.HasPrefix(normalized, "+++\n"):
+		delimiter = "+++"
+	default:
+		return nil, text
+	}
+
+	lines := strings.Split(normalized, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		if line != delimiter && !(delimiter == "---" && line == "...") {
+			continue
+		}
+		body := strings.Join(lines[i+1:], "\n")
+		if delimiter == "+++" {
+			return parseTOMLFrontMatter(lines[1:i]), body
+		}
+		return parseYAMLFrontMatter(lines[1:i]), body
+	}
+	return nil, text
+}
+
+// parseYAMLFrontMatter reads "key: value" pairs, block lists ("- item") and
+// one level of indented mappings
+func parseYAMLFrontMatter(lines []string) map[string]any {
+	out := make(map[string]any)
+	var key string // key whose value continues on the indented lines below
+	var nested map[string]any
+
+	for _, raw := range lines {
+		line := stripComment(raw)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+		trimmed := strings.TrimSpace(line)
+
+		if indented && key != "" {
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok || trimmed == "-" {
+				list, _ := out[key].([]any)
+				out[key] = append(list, parseScalar(item))
+				continue
+			}
+			if k, v, ok := strings.Cut(trimmed, ":"); ok {
+				if nested == nil {
+					nested = make(map[string]any)
+					out[key] = nested
+				}
+				nested[strings.TrimSpace(k)] = parseScalar(v)
+			}
+			continue
+		}
+
+		k, v, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key, nested = strings.Trim(strings.TrimSpace(k), `"'`), nil
+		if v = strings.TrimSpace(v); v == "" {
+			out[key] = nil
+			continue
+		}
+		out[key] = parseScalar(v)
+		key = ""
+	}
+	return out
+}
+
+// parseTOMLFrontMatter reads "key = value" pairs and [table] headers
+func parseTOMLFrontMatter(lines []string) map[string]any {
+	out := make(map[string]any)
+	target := out
+	for _, raw := range lines {
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") && !strings.HasPrefix(line, "[[") {
+			table := make(map[string]any)
+			out[strings.Trim(line, "[] ")] = table
+			target = table
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			target[strings.Trim(strings.TrimSpace(k), `"'`)] = parseScalar(v)
+		}
+	}
+	return out
+}
+
+// parseScalar converts a front matter value: quoted strings, inline lists,
+// booleans, null, integers and floats; anything else is a plain string
+func parseScalar(value string) any {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "", value == "~", value == "null":
+		return nil
+	case value == "true":
+		return true
+	case value == "false":
+		return false
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+		return value[1 : len(value)-1]
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	case len(value) >= 2 && value[0] == '[' && value[len(value)-1] == ']':
+		items := []any{}
+		for _, item := range splitInlineList(value[1 : len(value)-1]) {
+			items = append(items, parseScalar(item))
+		}
+		return items
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// splitInlineList splits "a, 'b, c', d" at commas outside quotes
+func splitInlineList(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(items) > 0 {
+		items = append(items, s[start:])
+	}
+	return items
+}
+
+// stripComment removes a trailing "# comment" outside quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,373 @@
+package document
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/ocr"
+)
+
+// ============================================================================
+// OCR Loader - loads scanned documents and images through an OCR provider
+// ============================================================================
+
+// OCRLoader runs a document through an ocr.Client and emits one document per
+// page. Page text is the provider's Markdown when it has one, so headings and
+// tables survive for the splitters; tables returned separately are put back
+// in place of their placeholders.
+//
+// Besides MetadataPageNumber, pages carry the tables found on them
+// (MetadataTables) and the bounding boxes of images, tables and layout
+// blocks (MetadataRegions). With WithAnnotation the document is also run
+// through the provider's Annotator and the result is stored under
+// MetadataAnnotation, so fields such as "annotation.invoice_number" can be
+// used in vector store filters.
+type OCRLoader struct {
+	client     *ocr.Client
+	source     Source
+	splitter   Splitter
+	metadata   map[string]any
+	mimeType   string
+	options    []ocr.Option
+	docSchema  *ocr.AnnotationSchema
+	bboxSchema *ocr.AnnotationSchema
+}
+
+// NewOCRLoader creates a loader that reads source with an OCR client
+func NewOCRLoader(client *ocr.Client, source Source) *OCRLoader {
+	return &OCRLoader{
+		client:   client,
+		source:   source,
+		metadata: make(map[string]any),
+	}
+}
+
+// WithSplitter sets the splitter applied to every page
+func (l *OCRLoader) WithSplitter(splitter Splitter) *OCRLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata
+func (l *OCRLoader) WithMetadata(key string, value any) *OCRLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithMIMEType sets the content type of the source. By default it is
+// derived from the file extension or sniffed from the content.
+func (l *OCRLoader) WithMIMEType(mimeType string) *OCRLoader {
+	l.mimeType = mimeType
+	return l
+}
+
+// WithOCROptions sets the options passed to the OCR provider
+func (l *OCRLoader) WithOCROptions(opts ...ocr.Option) *OCRLoader {
+	l.options = opts
+	return l
+}
+
+// WithAnnotation extracts structured data from the whole document with
+// schema and stores it on every page under MetadataAnnotation
+func (l *OCRLoader) WithAnnotation(schema ocr.AnnotationSchema) *OCRLoader {
+	l.docSchema = &schema
+	return l
+}
+
+// WithBBoxAnnotation extracts structured data from every image with schema
+// and stores it on the image's region
+func (l *OCRLoader) WithBBoxAnnotation(schema ocr.AnnotationSchema) *OCRLoader {
+	l.bboxSchema = &schema
+	return l
+}
+
+// Load runs OCR and returns the pages with text
+func (l *OCRLoader) Load(ctx context.Context) ([]*Document, error) {
+	input, mimeType, err := l.input(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, annotated, err := l.process(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("ocr failed: %w", err)
+	}
+
+	metadata := map[string]any{MetadataDocumentType: documentTypeOf(mimeType)}
+	if language := result.Language(); language != "" {
+		metadata[MetadataLanguage] = language
+	}
+	var bboxAnnotations map[string]any
+	if annotated != nil {
+		if annotated.DocumentAnnotation != nil {
+			metadata[MetadataAnnotation] = annotated.DocumentAnnotation
+		}
+		bboxAnnotations = annotated.BBoxAnnotations
+	}
+	for k, v := range l.metadata {
+		metadata[k] = v
+	}
+
+	source := sourceName(l.source)
+	pages := result.Pages()
+	if len(pages) == 0 {
+		text := result.Markdown()
+		if text == "" {
+			text = result.Text()
+		}
+		if strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+		doc := NewDocument(strings.TrimSpace(text)).
+			WithMetadataMap(metadata).
+			WithMetadata(MetadataSource, source)
+		return splitDocuments(ctx, []*Document{doc}, l.splitter)
+	}
+
+	numbers := pageNumbers(pages)
+	var docs []*Document
+	for i, page := range pages {
+		text := pageContent(page)
+		if text == "" {
+			continue
+		}
+
+		doc := NewDocument(text).
+			WithID(fmt.Sprintf("%s_page_%d", source, numbers[i])).
+			WithMetadataMap(metadata).
+			WithMetadata(MetadataSource, source).
+			WithMetadata(MetadataPageNumber, numbers[i]).
+			WithMetadata(MetadataPageCount, len(pages))
+		if page.Confidence > 0 {
+			doc.WithMetadata(MetadataConfidence, float64(page.Confidence))
+		}
+		if tables := tableMetadata(page.Tables); len(tables) > 0 {
+			doc.WithMetadata(MetadataTables, tables)
+		}
+		if regions := pageRegions(page, bboxAnnotations); len(regions) > 0 {
+			doc.WithMetadata(MetadataRegions, regions)
+		}
+		docs = append(docs, doc)
+	}
+
+	return splitDocuments(ctx, docs, l.splitter)
+}
+
+// LoadStream runs OCR and streams the pages
+func (l *OCRLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(docs), nil
+}
+
+// OCRLoaderFactory returns a DirectoryLoader factory that reads files
+// through client, for registering image or scanned PDF extensions
+func OCRLoaderFactory(client *ocr.Client, opts ...ocr.Option) LoaderFactory {
+	return func(source Source, splitter Splitter) Loader {
+		return NewOCRLoader(client, source).WithSplitter(splitter).WithOCROptions(opts...)
+	}
+}
+
+// input converts the source to an OCR input. URLs are passed through so the
+// provider fetches them; other sources are sent inline as base64.
+func (l *OCRLoader) input(ctx context.Context) (ocr.Input, string, error) {
+	mimeType := l.mimeType
+	if mimeType == "" {
+		mimeType = mimeTypeByExtension(sourceName(l.source))
+	}
+
+	if l.source.Type == SourceTypeURL {
+		if strings.HasPrefix(mimeType, "image/") {
+			return ocr.Input{Type: ocr.InputTypeImageURL, URL: l.source.URL, MimeType: mimeType}, mimeType, nil
+		}
+		return ocr.Input{Type: ocr.InputTypeDocumentURL, URL: l.source.URL, MimeType: mimeType}, mimeType, nil
+	}
+
+	data, err := readSource(ctx, l.source)
+	if err != nil {
+		return ocr.Input{}, "", err
+	}
+	if mimeType == "" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return ocr.FromBase64([]byte(encoded), mimeType), mimeType, nil
+}
+
+// process runs recognition, or annotation when a schema is set
+func (l *OCRLoader) process(ctx context.Context, input ocr.Input) (*ocr.Result, *ocr.AnnotatedDocument, error) {
+	var annotated *ocr.AnnotatedDocument
+	var err error
+	switch {
+	case l.docSchema != nil && l.bboxSchema != nil:
+		annotated, err = l.client.AnnotateFull(ctx, input, *l.docSchema, *l.bboxSchema, l.options...)
+	case l.docSchema != nil:
+		annotated, err = l.client.Annotate(ctx, input, *l.docSchema, l.options...)
+	case l.bboxSchema != nil:
+		annotated, err = l.client.AnnotateBBoxes(ctx, input, *l.bboxSchema, l.options...)
+	default:
+		result, err := l.client.Process(ctx, input, l.options...)
+		return result, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return annotated.Result, annotated, nil
+}
+
+// pageNumbers returns 1-based page numbers. Providers that report 0-based
+// indexes are shifted, and ones that don't number pages get their order.
+func pageNumbers(pages []ocr.Page) []int {
+	numbers := make([]int, len(pages))
+	seen := make(map[int]bool, len(pages))
+	offset := 0
+	for _, page := range pages {
+		if seen[page.Number] || page.Number < 0 {
+			for i := range numbers {
+				numbers[i] = i + 1
+			}
+			return numbers
+		}
+		seen[page.Number] = true
+		if page.Number == 0 {
+			offset = 1
+		}
+	}
+	for i, page := range pages {
+		numbers[i] = page.Number + offset
+	}
+	return numbers
+}
+
+// pageContent returns the page text with separately returned tables put
+// back in place of their placeholders
+func pageContent(page ocr.Page) string {
+	text := page.Markdown
+	if text == "" {
+		text = page.Text
+	}
+	for _, table := range page.Tables {
+		if content := tableContent(table); table.Placeholder != "" && content != "" {
+			text = strings.ReplaceAll(text, table.Placeholder, content)
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+func tableContent(table ocr.Table) string {
+	switch {
+	case table.Markdown != "":
+		return table.Markdown
+	case table.HTML != "":
+		return table.HTML
+	default:
+		return table.CSV
+	}
+}
+
+// tableMetadata describes the tables of a page
+func tableMetadata(tables []ocr.Table) []map[string]any {
+	out := make([]map[string]any, 0, len(tables))
+	for _, table := range tables {
+		entry := map[string]any{"id": table.ID}
+		if table.Format != "" {
+			entry["format"] = string(table.Format)
+		}
+		if table.Rows > 0 {
+			entry["rows"] = table.Rows
+			entry["columns"] = table.Columns
+		}
+		if box, ok := boundingBoxMetadata(table.BoundingBox); ok {
+			entry["bbox"] = box
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// pageRegions lists the bounding boxes of the images, tables and layout
+// blocks of a page
+func pageRegions(page ocr.Page, annotations map[string]any) []map[string]any {
+	var out []map[string]any
+	add := func(kind, id string, bbox ocr.BoundingBox) map[string]any {
+		box, ok := boundingBoxMetadata(bbox)
+		if !ok {
+			return nil
+		}
+		region := map[string]any{"type": kind, "bbox": box}
+		if id != "" {
+			region["id"] = id
+		}
+		out = append(out, region)
+		return region
+	}
+
+	for _, image := range page.Images {
+		region := add("image", image.ID, image.BoundingBox)
+		if region == nil {
+			continue
+		}
+		if image.Caption != "" {
+			region["caption"] = image.Caption
+		}
+		if annotation, ok := annotations[image.ID]; ok {
+			region[MetadataAnnotation] = annotation
+		}
+	}
+	for _, table := range page.Tables {
+		add("table", table.ID, table.BoundingBox)
+	}
+	for _, block := range page.Blocks {
+		add(string(block.Type), "", block.BoundingBox)
+	}
+	return out
+}
+
+// boundingBoxMetadata converts a bounding box to plain values that survive
+// a round trip through a vector store
+func boundingBoxMetadata(bbox ocr.BoundingBox) (map[string]any, bool) {
+	if bbox.Width == 0 && bbox.Height == 0 && len(bbox.Vertices) == 0 {
+		return nil, false
+	}
+	box := map[string]any{
+		"x":      float64(bbox.X),
+		"y":      float64(bbox.Y),
+		"width":  float64(bbox.Width),
+		"height": float64(bbox.Height),
+	}
+	if bbox.Normalized {
+		box["normalized"] = true
+	}
+	return box, true
+}
+
+// mimeTypeByExtension returns the media type registered for a file name or
+// URL
+func mimeTypeByExtension(name string) string {
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	mediaType, _, _ := mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(name))))
+	return mediaType
+}
+
+// documentTypeOf maps a media type to a MetadataDocumentType value
+func documentTypeOf(mimeType string) string {
+	switch {
+	case mimeType == "application/pdf":
+		return "pdf"
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case mimeType == "":
+		return "unknown"
+	}
+	_, subtype, _ := strings.Cut(mimeType, "/")
+	return subtype
+}
@@ -0,0 +1,1101 @@
+package document
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"context"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ============================================================================
+// PDF Loader - one document per page
+// ============================================================================
+
+// PDFLoader extracts text from PDF files, producing one document per page
+// with the page number in MetadataPageNumber. Text is read from the content
+// streams using the fonts' ToUnicode maps; scanned pages without a text layer
+// come out empty and are skipped. Encrypted files are not supported.
+type PDFLoader struct {
+	source        Source
+	splitter      Splitter
+	metadata      map[string]any
+	maxStreamSize int64
+}
+
+// DefaultMaxPDFStreamSize caps the decompressed size of a single PDF stream
+const DefaultMaxPDFStreamSize = 64 << 20
+
+// NewPDFLoader creates a new PDF loader
+func NewPDFLoader(source Source) *PDFLoader {
+	return &PDFLoader{
+		source:        source,
+		metadata:      make(map[string]any),
+		maxStreamSize: DefaultMaxPDFStreamSize,
+	}
+}
+
+// WithSplitter sets the splitter applied to every page
+func (l *PDFLoader) WithSplitter(splitter Splitter) *PDFLoader {
+	l.splitter = splitter
+	return l
+}
+
+// WithMetadata adds metadata
+func (l *PDFLoader) WithMetadata(key string, value any) *PDFLoader {
+	l.metadata[key] = value
+	return l
+}
+
+// WithMaxStreamSize caps the decompressed size of a single stream, so a
+// small compressed file can't expand into gigabytes. Streams over the limit
+// are skipped like undecodable ones.
+func (l *PDFLoader) WithMaxStreamSize(size int64) *PDFLoader {
+	l.maxStreamSize = size
+	return l
+}
+
+// Load loads every page with text
+func (l *PDFLoader) Load(ctx context.Context) ([]*Document, error) {
+	data, err := readSource(ctx, l.source)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := parsePDF(data, l.maxStreamSize)
+	if err != nil {
+		return nil, err
+	}
+	pages := file.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found in PDF")
+	}
+
+	source := sourceName(l.source)
+	info := file.dict(file.trailer["Info"])
+	var docs []*Document
+	for i, page := range pages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		text := file.pageText(page)
+		if text == "" {
+			continue
+		}
+
+		doc := NewDocument(text).
+			WithID(fmt.Sprintf("%s_page_%d", source, i+1)).
+			WithMetadata(MetadataDocumentType, "pdf")
+		if title := file.text(info["Title"]); title != "" {
+			doc.WithMetadata(MetadataTitle, title)
+		}
+		if author := file.text(info["Author"]); author != "" {
+			doc.WithMetadata(MetadataAuthor, author)
+		}
+		doc.WithMetadataMap(l.metadata).
+			WithMetadata(MetadataSource, source).
+			WithMetadata(MetadataPageNumber, i+1).
+			WithMetadata(MetadataPageCount, len(pages))
+		docs = append(docs, doc)
+	}
+
+	return splitDocuments(ctx, docs, l.splitter)
+}
+
+// LoadStream loads the pages and streams them
+func (l *PDFLoader) LoadStream(ctx context.Context) (DocumentStream, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sliceStream(docs), nil
+}
+
+// ============================================================================
+// PDF Objects
+// ============================================================================
+
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfString  []byte
+	pdfArray   []any
+	pdfDict    map[pdfName]any
+)
+
+type pdfRef struct {
+	num, gen int
+}
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+// pdfLexer reads objects from a file body or operators from a content stream
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// next returns the next object, or a pdfKeyword for operators and keywords
+func (l *pdfLexer) next() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	switch c := l.data[l.pos]; c {
+	case '/':
+		return l.readName(), nil
+	case '(':
+		return l.readLiteral(), nil
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			return l.readDict()
+		}
+		return l.readHex(), nil
+	case '[':
+		return l.readArray()
+	case ')', '>', ']', '{', '}':
+		l.pos++
+		return pdfKeyword(c), nil
+	}
+
+	token := l.readToken()
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return pdfKeyword(token), nil
+	}
+	if ref, ok := l.readRef(n); ok {
+		return ref, nil
+	}
+	return n, nil
+}
+
+func (l *pdfLexer) readToken() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++ // unexpected delimiter
+	}
+	return string(l.data[start:l.pos])
+}
+
+// readRef completes an indirect reference "num gen R" after num was read
+func (l *pdfLexer) readRef(num float64) (pdfRef, bool) {
+	if num < 0 || num != float64(int(num)) {
+		return pdfRef{}, false
+	}
+	start := l.pos
+	l.skipSpace()
+	gen, err := strconv.Atoi(l.readToken())
+	if err == nil {
+		l.skipSpace()
+		if l.pos < len(l.data) && l.data[l.pos] == 'R' &&
+			(l.pos+1 == len(l.data) || isPDFSpace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			return pdfRef{num: int(num), gen: gen}, true
+		}
+	}
+	l.pos = start
+	return pdfRef{}, false
+}
+
+func (l *pdfLexer) readName() pdfName {
+	l.pos++ // '/'
+	var name []byte
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if b, err := hex.DecodeString(string(l.data[l.pos+1 : l.pos+3])); err == nil {
+				name = append(name, b[0])
+				l.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		l.pos++
+	}
+	return pdfName(name)
+}
+
+func (l *pdfLexer) readLiteral() pdfString {
+	l.pos++ // '('
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					value := int(c - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(value)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) readHex() pdfString {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	n, _ := hex.Decode(out, digits)
+	return out[:n]
+}
+
+func (l *pdfLexer) readArray() (pdfArray, error) {
+	l.pos++ // '['
+	var out pdfArray
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return out, io.ErrUnexpectedEOF
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return out, nil
+		}
+		obj, err := l.next()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, obj)
+	}
+}
+
+func (l *pdfLexer) readDict() (pdfDict, error) {
+	l.pos += 2 // '<<'
+	out := make(pdfDict)
+	for {
+		l.skipSpace()
+		if l.pos+1 >= len(l.data) {
+			return out, io.ErrUnexpectedEOF
+		}
+		if l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return out, nil
+		}
+		key, err := l.next()
+		if err != nil {
+			return out, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			continue
+		}
+		value, err := l.next()
+		if err != nil {
+			return out, err
+		}
+		out[name] = value
+	}
+}
+
+// readStream reads the stream data following a dictionary, if any
+func (l *pdfLexer) readStream(dict pdfDict) (pdfStream, bool) {
+	start := l.pos
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		l.pos = start
+		return pdfStream{}, false
+	}
+	l.pos += len("stream")
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	begin := l.pos
+
+	// Trust a direct Length only when endstream follows it
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && begin+int(n) <= len(l.data) {
+		end := begin + int(n)
+		rest := bytes.TrimLeft(l.data[end:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = len(l.data) - len(rest) + len("endstream")
+			return pdfStream{dict: dict, raw: l.data[begin:end]}, true
+		}
+	}
+
+	end := bytes.Index(l.data[begin:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return pdfStream{dict: dict, raw: l.data[begin:]}, true
+	}
+	l.pos = begin + end + len("endstream")
+	raw := bytes.TrimRight(l.data[begin:begin+end], "\r\n")
+	return pdfStream{dict: dict, raw: raw}, true
+}
+
+// ============================================================================
+// PDF File
+// ============================================================================
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// pdfFile holds the objects of a PDF. Objects are found by scanning the file
+// rather than trusting the xref table, which also recovers damaged files;
+// when an object is defined more than once the last definition wins, as it
+// does for incremental updates.
+type pdfFile struct {
+	objects       map[int]any
+	trailer       pdfDict
+	fonts         map[pdfRef]*pdfFont
+	maxStreamSize int64 // Decompressed bytes per stream (0 = no limit)
+}
+
+func parsePDF(data []byte, maxStreamSize int64) (*pdfFile, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+
+	file := &pdfFile{
+		objects:       make(map[int]any),
+		fonts:         make(map[pdfRef]*pdfFont),
+		maxStreamSize: maxStreamSize,
+	}
+
+	var xrefStreams []pdfDict
+	for pos := 0; pos < len(data); {
+		loc := pdfObjectHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		lexer := &pdfLexer{data: data, pos: pos + loc[1]}
+		obj, err := lexer.next()
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			pos += loc[1]
+			continue
+		}
+		if dict, ok := obj.(pdfDict); ok {
+			if stream, ok := lexer.readStream(dict); ok {
+				obj = stream
+				if dict["Type"] == pdfName("XRef") {
+					xrefStreams = append(xrefStreams, dict)
+				}
+			}
+		}
+		file.objects[num] = obj
+		pos = max(lexer.pos, pos+loc[1])
+	}
+
+	file.expandObjectStreams()
+
+	// The last trailer belongs to the newest revision
+	if i := bytes.LastIndex(data, []byte("trailer")); i >= 0 {
+		lexer := &pdfLexer{data: data, pos: i + len("trailer")}
+		if dict, ok := objectOf(lexer.next()).(pdfDict); ok {
+			file.trailer = dict
+		}
+	}
+	if file.trailer["Root"] == nil && len(xrefStreams) > 0 {
+		file.trailer = xrefStreams[len(xrefStreams)-1]
+	}
+	if file.trailer == nil {
+		file.trailer = make(pdfDict)
+	}
+	if file.trailer["Encrypt"] != nil {
+		return nil, fmt.Errorf("encrypted PDFs are not supported")
+	}
+	if file.dict(file.trailer["Root"]) == nil {
+		for num, obj := range file.objects {
+			if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				file.trailer["Root"] = pdfRef{num: num}
+				break
+			}
+		}
+	}
+	return file, nil
+}
+
+// objectOf drops the error of a lexer read; partial objects are still useful
+func objectOf(obj any, _ error) any {
+	return obj
+}
+
+// expandObjectStreams adds the objects compressed into object streams
+func (f *pdfFile) expandObjectStreams() {
+	var nums []int
+	for num, obj := range f.objects {
+		if stream, ok := obj.(pdfStream); ok && stream.dict["Type"] == pdfName("ObjStm") {
+			nums = append(nums, num)
+		}
+	}
+	slices.Sort(nums)
+
+	for _, num := range nums {
+		stream := f.objects[num].(pdfStream)
+		data, err := f.decode(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := stream.dict["N"].(float64)
+		first, _ := stream.dict["First"].(float64)
+
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(n); i++ {
+			objNum, ok1 := objectOf(header.next()).(float64)
+			offset, ok2 := objectOf(header.next()).(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, ok := f.objects[int(objNum)]; ok {
+				continue
+			}
+			lexer := &pdfLexer{data: data, pos: int(first) + int(offset)}
+			if lexer.pos < len(data) {
+				f.objects[int(objNum)] = objectOf(lexer.next())
+			}
+		}
+	}
+}
+
+// resolve follows indirect references
+func (f *pdfFile) resolve(obj any) any {
+	for range 32 {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = f.objects[ref.num]
+	}
+	return nil
+}
+
+// dict resolves obj to a dictionary, or the dictionary of a stream
+func (f *pdfFile) dict(obj any) pdfDict {
+	switch v := f.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// text resolves obj to a text string
+func (f *pdfFile) text(obj any) string {
+	s, ok := f.resolve(obj).(pdfString)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(decodePDFText(s))
+}
+
+// decode applies the stream filters
+func (f *pdfFile) decode(stream pdfStream) ([]byte, error) {
+	var filters []any
+	switch v := f.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{v}
+	case pdfArray:
+		filters = v
+	}
+
+	data := stream.raw
+	for _, filter := range filters {
+		var err error
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data, f.maxStreamSize)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data = (&pdfLexer{data: append(append([]byte{'<'}, data...), '>')}).readHex()
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			err = fmt.Errorf("unsupported PDF filter %v", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses zlib data, keeping what was read from truncated
+// streams. Output beyond limit bytes is an error (0 = no limit).
+func inflate(data []byte, limit int64) ([]byte, error) {
+	var reader io.ReadCloser
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		reader = flate.NewReader(bytes.NewReader(data))
+	}
+	defer reader.Close()
+
+	var r io.Reader = reader
+	if limit > 0 {
+		r = io.LimitReader(reader, limit+1)
+	}
+	out, err := io.ReadAll(r)
+	if limit > 0 && int64(len(out)) > limit {
+		return nil, fmt.Errorf("PDF stream exceeds %d bytes when decompressed", limit)
+	}
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// ============================================================================
+// Pages and Text
+// ============================================================================
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree in order, inheriting resources
+func (f *pdfFile) pages() []pdfPage {
+	root := f.dict(f.trailer["Root"])
+	if root == nil {
+		return nil
+	}
+
+	var pages []pdfPage
+	visited := make(map[pdfRef]bool)
+	var walk func(node any, resources pdfDict, depth int)
+	walk = func(node any, resources pdfDict, depth int) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		dict := f.dict(node)
+		if dict == nil || depth > 64 {
+			return
+		}
+		if own := f.dict(dict["Resources"]); own != nil {
+			resources = own
+		}
+		if kids, ok := f.resolve(dict["Kids"]).(pdfArray); ok && dict["Type"] != pdfName("Page") {
+			for _, kid := range kids {
+				walk(kid, resources, depth+1)
+			}
+			return
+		}
+		pages = append(pages, pdfPage{dict: dict, resources: resources})
+	}
+	walk(root["Pages"], nil, 0)
+	return pages
+}
+
+// pageText extracts the text of a page
+func (f *pdfFile) pageText(page pdfPage) string {
+	var content []byte
+	switch v := f.resolve(page.dict["Contents"]).(type) {
+	case pdfStream:
+		content, _ = f.decode(v)
+	case pdfArray:
+		for _, part := range v {
+			if stream, ok := f.resolve(part).(pdfStream); ok {
+				data, _ := f.decode(stream)
+				content = append(append(content, data...), '\n')
+			}
+		}
+	}
+
+	var w pdfTextWriter
+	f.interpret(content, page.resources, &w, 0)
+	return w.String()
+}
+
+// interpret runs the text operators of a content stream
+func (f *pdfFile) interpret(content []byte, resources pdfDict, w *pdfTextWriter, depth int) {
+	lexer := &pdfLexer{data: content}
+	var operands []any
+	var font *pdfFont
+
+	for {
+		obj, err := lexer.next()
+		if err != nil {
+			return
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) == 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = f.font(resources, name)
+				}
+			}
+		case "Tj", "'", `"`:
+			if op != "Tj" {
+				w.newline()
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					w.text(font.decode(s))
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				items, _ := operands[len(operands)-1].(pdfArray)
+				for _, item := range items {
+					switch v := item.(type) {
+					case pdfString:
+						w.text(font.decode(v))
+					case float64:
+						// Wide negative kerning separates words
+						if v < -200 {
+							w.space()
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) == 2 {
+				tx, _ := operands[0].(float64)
+				ty, _ := operands[1].(float64)
+				if ty != 0 {
+					w.newline()
+				} else if tx > 0 {
+					w.space()
+				}
+			}
+		case "T*":
+			w.newline()
+		case "Tm":
+			if len(operands) == 6 {
+				y, _ := operands[5].(float64)
+				if w.hasY && y != w.y {
+					w.newline()
+				} else {
+					w.space()
+				}
+				w.y, w.hasY = y, true
+			}
+		case "ET":
+			w.space()
+		case "Do":
+			if len(operands) == 1 && depth < 8 {
+				if name, ok := operands[0].(pdfName); ok {
+					f.interpretForm(resources, name, w, depth)
+				}
+			}
+		case "ID":
+			// Skip inline image data up to EI
+			lexer.pos = skipInlineImage(content, lexer.pos+1)
+		}
+		operands = operands[:0]
+	}
+}
+
+// interpretForm runs a form XObject drawn with Do
+func (f *pdfFile) interpretForm(resources pdfDict, name pdfName, w *pdfTextWriter, depth int) {
+	xobjects := f.dict(resources["XObject"])
+	stream, ok := f.resolve(xobjects[name]).(pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := f.decode(stream)
+	if err != nil {
+		return
+	}
+	if own := f.dict(stream.dict["Resources"]); own != nil {
+		resources = own
+	}
+	f.interpret(data, resources, w, depth+1)
+}
+
+func skipInlineImage(content []byte, pos int) int {
+	for pos < len(content) {
+		i := bytes.Index(content[pos:], []byte("EI"))
+		if i < 0 {
+			return len(content)
+		}
+		end := pos + i + 2
+		if pos+i > 0 && isPDFSpace(content[pos+i-1]) && (end == len(content) || isPDFSpace(content[end])) {
+			return end
+		}
+		pos = end
+	}
+	return len(content)
+}
+
+// pdfTextWriter collects page text, normalizing whitespace
+type pdfTextWriter struct {
+	b     strings.Builder
+	y     float64
+	hasY  bool
+	pause byte
+}
+
+func (w *pdfTextWriter) text(s string) {
+	if s == "" {
+		return
+	}
+	if w.pause != 0 && w.b.Len() > 0 {
+		w.b.WriteByte(w.pause)
+	}
+	w.pause = 0
+	w.b.WriteString(s)
+}
+
+func (w *pdfTextWriter) space() {
+	if w.pause == 0 {
+		w.pause = ' '
+	}
+}
+
+func (w *pdfTextWriter) newline() {
+	w.pause = '\n'
+}
+
+// String returns the text with runs of spaces and blank lines collapsed
+func (w *pdfTextWriter) String() string {
+	var lines []string
+	for _, line := range strings.Split(w.b.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ============================================================================
+// Fonts and Encodings
+// ============================================================================
+
+// pdfFont maps character codes to text
+type pdfFont struct {
+	twoByte     bool
+	toUnicode   map[uint32]string
+	differences map[byte]string
+}
+
+// font loads a font from page resources, caching indirect fonts
+func (f *pdfFile) font(resources pdfDict, name pdfName) *pdfFont {
+	entry := f.dict(resources["Font"])[name]
+	ref, isRef := entry.(pdfRef)
+	if isRef {
+		if font, ok := f.fonts[ref]; ok {
+			return font
+		}
+	}
+
+	dict := f.dict(entry)
+	if dict == nil {
+		return nil
+	}
+	font := &pdfFont{twoByte: dict["Subtype"] == pdfName("Type0")}
+	if stream, ok := f.resolve(dict["ToUnicode"]).(pdfStream); ok {
+		if data, err := f.decode(stream); err == nil {
+			font.parseCMap(data)
+		}
+	}
+	if encoding := f.dict(dict["Encoding"]); encoding != nil {
+		differences, _ := f.resolve(encoding["Differences"]).(pdfArray)
+		code := 0
+		for _, item := range differences {
+			switch v := item.(type) {
+			case float64:
+				code = int(v)
+			case pdfName:
+				if text, ok := glyphText(string(v)); ok && code < 256 {
+					if font.differences == nil {
+						font.differences = make(map[byte]string)
+					}
+					font.differences[byte(code)] = text
+				}
+				code++
+			}
+		}
+	}
+
+	if isRef {
+		f.fonts[ref] = font
+	}
+	return font
+}
+
+// parseCMap reads the code space and bfchar/bfrange mappings of a ToUnicode
+// CMap
+func (font *pdfFont) parseCMap(data []byte) {
+	font.toUnicode = make(map[uint32]string)
+	lexer := &pdfLexer{data: data}
+	var operands []any
+	for {
+		obj, err := lexer.next()
+		if err != nil {
+			return
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if low, ok := operands[0].(pdfString); ok {
+					font.twoByte = len(low) >= 2
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					font.toUnicode[pdfCode(src)] = decodeUTF16(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].(pdfString)
+				high, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := pdfCode(low), pdfCode(high)
+				for code := start; code <= end && code-start < 1<<16; code++ {
+					switch dst := operands[i+2].(type) {
+					case pdfString:
+						font.toUnicode[code] = decodeUTF16(offsetCode(dst, code-start))
+					case pdfArray:
+						if int(code-start) >= len(dst) {
+							break
+						}
+						if s, ok := dst[code-start].(pdfString); ok {
+							font.toUnicode[code] = decodeUTF16(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// decode maps the codes of a shown string to text
+func (font *pdfFont) decode(s pdfString) string {
+	if font == nil {
+		return decodeWinAnsi(s)
+	}
+
+	var b strings.Builder
+	if font.twoByte {
+		for i := 0; i+1 < len(s); i += 2 {
+			b.WriteString(font.toUnicode[uint32(s[i])<<8|uint32(s[i+1])])
+		}
+		return b.String()
+	}
+	for _, c := range []byte(s) {
+		if text, ok := font.toUnicode[uint32(c)]; ok {
+			b.WriteString(text)
+		} else if text, ok := font.differences[c]; ok {
+			b.WriteString(text)
+		} else {
+			b.WriteString(decodeWinAnsi([]byte{c}))
+		}
+	}
+	return b.String()
+}
+
+func pdfCode(s []byte) uint32 {
+	var code uint32
+	for _, c := range s {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// offsetCode adds delta to the last code unit of a bfrange destination
+func offsetCode(dst []byte, delta uint32) []byte {
+	out := slices.Clone(dst)
+	if len(out) >= 2 {
+		unit := uint32(out[len(out)-2])<<8 | uint32(out[len(out)-1]) + delta
+		out[len(out)-2], out[len(out)-1] = byte(unit>>8), byte(unit)
+	} else if len(out) == 1 {
+		out[0] += byte(delta)
+	}
+	return out
+}
+
+func decodeUTF16(s []byte) string {
+	if len(s)%2 == 1 {
+		return string(rune(s[0]))
+	}
+	units := make([]uint16, len(s)/2)
+	for i := range units {
+		units[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodePDFText decodes a text string such as a title: UTF-16BE with a byte
+// order mark, UTF-8 with a byte order mark, or PDFDocEncoding
+func decodePDFText(s []byte) string {
+	switch {
+	case bytes.HasPrefix(s, []byte{0xfe, 0xff}):
+		return decodeUTF16(s[2:])
+	case bytes.HasPrefix(s, []byte{0xef, 0xbb, 0xbf}):
+		return string(s[3:])
+	}
+	return decodeWinAnsi(s)
+}
+
+// winAnsi holds the WinAnsiEncoding codes that differ from Latin-1
+var winAnsi = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ', 0x8e: 'Ž', 0x91: '‘',
+	0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜',
+	0x99: '™', 0x9a: 'š', 0x9b: '›', 0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+}
+
+func decodeWinAnsi(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch r, ok := winAnsi[c]; {
+		case ok:
+			b.WriteRune(r)
+		case c == '\t' || c == '\n' || c == '\r' || c >= 0x20 && c != 0x7f:
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// glyphNames maps the glyph names common in Differences arrays
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "parenleft": "(",
+	"parenright": ")", "asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-",
+	"period": ".", "slash": "/", "zero": "0", "one": "1", "two": "2", "three": "3",
+	"four": "4", "five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"colon": ":", "semicolon": ";", "less": "<", "equal": "=", "greater": ">",
+	"question": "?", "at": "@", "bracketleft": "[", "backslash": "\\",
+	"bracketright": "]", "asciicircum": "^", "underscore": "_", "grave": "`",
+	"braceleft": "{", "bar": "|", "braceright": "}", "asciitilde": "~",
+	"quoteleft": "‘", "quoteright": "’", "quotedblleft": "“", "quotedblright": "”",
+	"bullet": "•", "endash": "–", "emdash": "—", "ellipsis": "…", "minus": "−",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+}
+
+func glyphText(name string) (string, bool) {
+	if text, ok := glyphNames[name]; ok {
+		return text, true
+	}
+	if len(name) == 1 {
+		return name, true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if code, err := strconv.ParseUint(name[3:], 16, 16); err == nil {
+			return string(rune(code)), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,111 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadPDF(t *testing.T, name string) ([]*Document, error) {
+	t.Helper()
+	source := Source{Type: SourceTypeFile, Path: filepath.Join("testdata", name)}
+	return NewPDFLoader(source).Load(context.Background())
+}
+
+func TestPDFLoader(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		pages []string
+	}{
+		{"plain", "plain.pdf", []string{"Hello from page one.", "Second page text."}},
+		{"object streams", "objstream.pdf", []string{"Packed in an object stream."}},
+		{"tounicode cmap", "tounicode.pdf", []string{"Héllo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := loadPDF(t, tt.file)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(docs) != len(tt.pages) {
+				t.Fatalf("loaded %d pages, want %d", len(docs), len(tt.pages))
+			}
+			for i, doc := range docs {
+				if got := strings.TrimSpace(doc.Content); got != tt.pages[i] {
+					t.Errorf("page %d = %q, want %q", i+1, got, tt.pages[i])
+				}
+				if doc.Metadata[MetadataPageNumber] != i+1 || doc.Metadata[MetadataPageCount] != len(tt.pages) {
+					t.Errorf("page %d metadata = %v", i+1, doc.Metadata)
+				}
+			}
+		})
+	}
+}
+
+func TestPDFLoaderInfo(t *testing.T) {
+	docs, err := loadPDF(t, "plain.pdf")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if docs[0].Metadata[MetadataTitle] != "Plain Fixture" || docs[0].Metadata[MetadataAuthor] != "Manifesto" {
+		t.Errorf("metadata = %v, want the Info title and author", docs[0].Metadata)
+	}
+}
+
+func TestPDFLoaderEncrypted(t *testing.T) {
+	if _, err := loadPDF(t, "encrypted.pdf"); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Fatalf("Load = %v, want an encrypted PDF error", err)
+	}
+}
+
+func TestPDFLoaderTruncatedStream(t *testing.T) {
+	docs, err := loadPDF(t, "truncated.pdf")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("loaded %d pages, want 1", len(docs))
+	}
+	// What was inflated before the cut is kept
+	if !strings.HasPrefix(docs[0].Content, "Line 0 of a page") || strings.Contains(docs[0].Content, "Line 39") {
+		t.Errorf("page = %q, want the text before the cut", docs[0].Content)
+	}
+}
+
+func TestPDFLoaderMaxStreamSize(t *testing.T) {
+	data, err := readSource(context.Background(), Source{Type: SourceTypeFile, Path: filepath.Join("testdata", "plain.pdf")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The compressed second page is 48 bytes once inflated
+	docs, err := NewPDFLoader(Source{Type: SourceTypeBytes, Data: data}).WithMaxStreamSize(16).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Metadata[MetadataPageNumber] != 1 {
+		t.Errorf("loaded %d pages, want only the uncompressed first page", len(docs))
+	}
+}
+
+func TestInflateLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(make([]byte, 1<<20))
+	w.Close()
+
+	if _, err := inflate(buf.Bytes(), 1<<10); err == nil {
+		t.Error("inflate exceeded the limit without an error")
+	}
+	out, err := inflate(buf.Bytes(), 1<<20)
+	if err != nil || len(out) != 1<<20 {
+		t.Errorf("inflate at the limit = %d bytes, %v", len(out), err)
+	}
+	if out, err := inflate(buf.Bytes(), 0); err != nil || len(out) != 1<<20 {
+		t.Errorf("inflate without a limit = %d bytes, %v", len(out), err)
+	}
+}
@@ -0,0 +1,270 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Query Transformers - rewrite the user's query before searching
+// ============================================================================
+
+// SubQuery is one search run on behalf of the user's query
+type SubQuery struct {
+	Query  string // Question the search answers, used for reranking and reported on hits
+	Search string // Text embedded for the search; Query when empty
+}
+
+// searchText returns the text to embed for the search
+func (q SubQuery) searchText() string {
+	if q.Search != "" {
+		return q.Search
+	}
+	return q.Query
+}
+
+// QueryTransformer turns a user query into the searches that answer it
+type QueryTransformer interface {
+	Transform(ctx context.Context, query string) ([]SubQuery, error)
+}
+
+// DefaultMultiQueryPrompt asks for paraphrases of a question. {{query}} and
+// {{count}} are replaced before the call.
+const DefaultMultiQueryPrompt = `You are helping search a document collection. Write {{count}} different versions of the question below, using other words and other angles, so that together they find relevant documents the original wording would miss.
+
+<question>
+{{query}}
+</question>
+
+Answer with one question per line, without numbering, and nothing else.`
+
+// MultiQueryTransformer searches with the query and Count paraphrases of it
+// written by an LLM. Embeddings are sensitive to wording, so the fused
+// results of several phrasings recall more than any one of them.
+type MultiQueryTransformer struct {
+	LLM             llm.LLM
+	Count           int          // Paraphrases to generate
+	IncludeOriginal bool         // Also search with the query as written
+	Prompt          string       // Defaults to DefaultMultiQueryPrompt
+	Options         []llm.Option // Passed to the LLM call
+}
+
+// NewMultiQueryTransformer creates a transformer that searches with the
+// query and three paraphrases
+func NewMultiQueryTransformer(model llm.LLM) *MultiQueryTransformer {
+	return &MultiQueryTransformer{
+		LLM:             model,
+		Count:           3,
+		IncludeOriginal: true,
+		Prompt:          DefaultMultiQueryPrompt,
+	}
+}
+
+// Transform implements QueryTransformer
+func (t *MultiQueryTransformer) Transform(ctx context.Context, query string) ([]SubQuery, error) {
+	count := max(t.Count, 1)
+	answer, err := askLLM(ctx, t.LLM, t.Prompt, DefaultMultiQueryPrompt, query, count, t.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query variants: %w", err)
+	}
+
+	var queries []SubQuery
+	if t.IncludeOriginal {
+		queries = append(queries, SubQuery{Query: query})
+	}
+	for _, line := range answerLines(answer, query, count) {
+		queries = append(queries, SubQuery{Query: line})
+	}
+	if len(queries) == 0 {
+		queries = append(queries, SubQuery{Query: query})
+	}
+	return queries, nil
+}
+
+// DefaultHyDEPrompt asks for a passage answering a question. {{query}} is
+// replaced before the call.
+const DefaultHyDEPrompt = `Write a short passage that answers the question below, as it would appear in a document on the topic. If you are not sure of the facts, write a plausible answer anyway; it is only used to find similar passages.
+
+<question>
+{{query}}
+</question>
+
+Answer only with the passage.`
+
+// HyDETransformer searches with a hypothetical answer written by an LLM
+// instead of the question (Hypothetical Document Embeddings). An answer,
+// even a wrong one, is usually closer in embedding space to the passages
+// that hold the real answer than the question is. Reranking still compares
+// hits with the question.
+type HyDETransformer struct {
+	LLM             llm.LLM
+	IncludeOriginal bool         // Also search with the query as written
+	Prompt          string       // Defaults to DefaultHyDEPrompt
+	Options         []llm.Option // Passed to the LLM call
+}
+
+// NewHyDETransformer creates a transformer that searches with a
+// hypothetical answer only
+func NewHyDETransformer(model llm.LLM) *HyDETransformer {
+	return &HyDETransformer{
+		LLM:    model,
+		Prompt: DefaultHyDEPrompt,
+	}
+}
+
+// Transform implements QueryTransformer
+func (t *HyDETransformer) Transform(ctx context.Context, query string) ([]SubQuery, error) {
+	answer, err := askLLM(ctx, t.LLM, t.Prompt, DefaultHyDEPrompt, query, 1, t.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate hypothetical answer: %w", err)
+	}
+
+	var queries []SubQuery
+	if t.IncludeOriginal {
+		queries = append(queries, SubQuery{Query: query})
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		queries = append(queries, SubQuery{Query: query, Search: answer})
+	}
+	if len(queries) == 0 {
+		queries = append(queries, SubQuery{Query: query})
+	}
+	return queries, nil
+}
+
+// DefaultDecompositionPrompt asks for the simple questions behind a
+// compound one. {{query}} and {{count}} are replaced before the call.
+const DefaultDecompositionPrompt = `Break the question below into the simpler questions that must be answered to answer it. Each question must make sense on its own, without the others. If the question is already simple, answer with it unchanged.
+
+<question>
+{{query}}
+</question>
+
+Answer with at most {{count}} questions, one per line, without numbering, and nothing else.`
+
+// DecompositionTransformer splits a compound question ("compare the refund
+// policies of plan A and plan B") into self-contained sub-questions that are
+// searched separately, so each part finds its own passages instead of the
+// search settling on whichever part dominates the embedding.
+type DecompositionTransformer struct {
+	LLM        llm.LLM
+	MaxQueries int          // Upper bound on sub-questions
+	Prompt     string       // Defaults to DefaultDecompositionPrompt
+	Options    []llm.Option // Passed to the LLM call
+}
+
+// NewDecompositionTransformer creates a transformer that splits a query in
+// up to four sub-questions
+func NewDecompositionTransformer(model llm.LLM) *DecompositionTransformer {
+	return &DecompositionTransformer{
+		LLM:        model,
+		MaxQueries: 4,
+		Prompt:     DefaultDecompositionPrompt,
+	}
+}
+
+// Transform implements QueryTransformer
+func (t *DecompositionTransformer) Transform(ctx context.Context, query string) ([]SubQuery, error) {
+	limit := max(t.MaxQueries, 1)
+	answer, err := askLLM(ctx, t.LLM, t.Prompt, DefaultDecompositionPrompt, query, limit, t.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompose query: %w", err)
+	}
+
+	// The query itself is a valid sub-question when the model keeps it
+	lines := answerLines(answer, "", limit)
+	if len(lines) == 0 {
+		return []SubQuery{{Query: query}}, nil
+	}
+	queries := make([]SubQuery, len(lines))
+	for i, line := range lines {
+		queries[i] = SubQuery{Query: line}
+	}
+	return queries, nil
+}
+
+// TransformerChain applies transformers in turn, each to every sub-query
+// of the one before, e.g. decomposition followed by HyDE searches with a
+// hypothetical answer to every sub-question. Only the last transformer's
+// search texts are kept, so put HyDE last.
+type TransformerChain struct {
+	Transformers []QueryTransformer
+}
+
+// NewTransformerChain creates a chain of transformers
+func NewTransformerChain(transformers ...QueryTransformer) *TransformerChain {
+	return &TransformerChain{Transformers: transformers}
+}
+
+// Transform implements QueryTransformer
+func (c *TransformerChain) Transform(ctx context.Context, query string) ([]SubQuery, error) {
+	queries := []SubQuery{{Query: query}}
+	for _, transformer := range c.Transformers {
+		var next []SubQuery
+		for _, q := range queries {
+			transformed, err := transformer.Transform(ctx, q.Query)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, transformed...)
+		}
+		queries = dedupeSubQueries(next)
+	}
+	return queries, nil
+}
+
+// askLLM fills a prompt and returns the text of the answer
+func askLLM(ctx context.Context, model llm.LLM, prompt, fallback, query string, count int, opts []llm.Option) (string, error) {
+	if prompt == "" {
+		prompt = fallback
+	}
+	message := strings.NewReplacer(
+		"{{query}}", query,
+		"{{count}}", strconv.Itoa(count),
+	).Replace(prompt)
+
+	resp, err := model.Chat(ctx, []llm.Message{llm.NewUserMessage(message)}, opts...)
+	if err != nil {
+		return "", err
+	}
+	return resp.Message.TextContent(), nil
+}
+
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)]|\(\d+\))\s*`)
+
+// answerLines returns up to limit distinct non-empty lines of an answer,
+// without list markers, skipping lines equal to exclude
+func answerLines(answer, exclude string, limit int) []string {
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(exclude)): true}
+	var lines []string
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.Trim(strings.TrimSpace(listMarker.ReplaceAllString(line, "")), `"`)
+		key := strings.ToLower(line)
+		if line == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		lines = append(lines, line)
+		if len(lines) == limit {
+			break
+		}
+	}
+	return lines
+}
+
+func dedupeSubQueries(queries []SubQuery) []SubQuery {
+	seen := make(map[SubQuery]bool, len(queries))
+	out := queries[:0]
+	for _, q := range queries {
+		if !seen[q] {
+			seen[q] = true
+			out = append(out, q)
+		}
+	}
+	return out
+}
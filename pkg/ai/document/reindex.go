@@ -0,0 +1,519 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/jobx"
+)
+
+// ============================================================================
+// Re-indexing - migrates documents to a new embedding model or dimension
+// ============================================================================
+
+// ReindexJobType is the jobx job type handled by Reindexer.Handler
+const ReindexJobType = "document.reindex"
+
+// ReindexRequest describes a migration between two registered indexes. It is
+// the JSON payload of a re-index job.
+type ReindexRequest struct {
+	// ID keys the checkpoint; defaults to "<source>-><target>"
+	ID string `json:"id,omitempty"`
+
+	// Source and Target are names passed to Reindexer.RegisterIndex
+	Source string `json:"source"`
+	Target string `json:"target"`
+
+	// Alias is repointed to Target in the reindexer's AliasStore once every
+	// document has been copied and caught up. DocumentStores following it
+	// with WithAlias switch on their next refresh; one registered under the
+	// name with RegisterAlias is swapped at once.
+	Alias string `json:"alias,omitempty"`
+
+	// BatchSize overrides the reindexer batch size
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// checkpointID returns the key progress is saved under
+func (r ReindexRequest) checkpointID() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Source + "->" + r.Target
+}
+
+// ReindexProgress is saved after every batch so an interrupted migration
+// resumes where it stopped
+type ReindexProgress struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+
+	// Cursor is the source List cursor of the next batch
+	Cursor string `json:"cursor"`
+
+	// Processed counts re-embedded documents; Skipped counts documents
+	// without content, which can't be re-embedded; Deleted counts target
+	// documents removed by the catch-up pass
+	Processed int `json:"processed"`
+	Skipped   int `json:"skipped"`
+	Deleted   int `json:"deleted"`
+
+	// Total is the source size when it could be determined, otherwise 0
+	Total int64 `json:"total"`
+
+	// Done is set once every document is copied; CaughtUp once writes made
+	// to the source during the copy are applied; Swapped once the alias
+	// store points at the target
+	Done     bool `json:"done"`
+	CaughtUp bool `json:"caught_up"`
+	Swapped  bool `json:"swapped"`
+
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore persists re-index progress. Workers on several hosts need
+// a shared implementation so a retried job resumes on any of them.
+type CheckpointStore interface {
+	// LoadCheckpoint returns nil, nil when no progress was saved
+	LoadCheckpoint(ctx context.Context, id string) (*ReindexProgress, error)
+	SaveCheckpoint(ctx context.Context, progress *ReindexProgress) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in process memory
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]ReindexProgress
+}
+
+// NewMemoryCheckpointStore creates an in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]ReindexProgress)}
+}
+
+// LoadCheckpoint implements CheckpointStore
+func (s *MemoryCheckpointStore) LoadCheckpoint(ctx context.Context, id string) (*ReindexProgress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	progress, ok := s.checkpoints[id]
+	if !ok {
+		return nil, nil
+	}
+	return &progress, nil
+}
+
+// SaveCheckpoint implements CheckpointStore
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, progress *ReindexProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[progress.ID] = *progress
+	return nil
+}
+
+// AliasStore persists which index an alias points at, so every process
+// serving the alias follows a swap. Like CheckpointStore it needs a shared
+// implementation when workers and servers run on several hosts.
+type AliasStore interface {
+	// LoadAlias returns "", nil when the alias was never set
+	LoadAlias(ctx context.Context, name string) (string, error)
+	SaveAlias(ctx context.Context, name, index string) error
+}
+
+// MemoryAliasStore keeps aliases in process memory
+type MemoryAliasStore struct {
+	mu      sync.RWMutex
+	aliases map[string]string
+}
+
+// NewMemoryAliasStore creates an in-memory alias store
+func NewMemoryAliasStore() *MemoryAliasStore {
+	return &MemoryAliasStore{aliases: make(map[string]string)}
+}
+
+// LoadAlias implements AliasStore
+func (s *MemoryAliasStore) LoadAlias(ctx context.Context, name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.aliases[name], nil
+}
+
+// SaveAlias implements AliasStore
+func (s *MemoryAliasStore) SaveAlias(ctx context.Context, name, index string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aliases[name] = index
+	return nil
+}
+
+// ============================================================================
+// Reindexer
+// ============================================================================
+
+// ProgressFunc is called after every batch and when a migration completes
+type ProgressFunc func(progress ReindexProgress)
+
+// Reindexer copies documents from one index to another, re-embedding their
+// content with the target embedder. Indexes and aliases are registered by
+// name so jobs can refer to them in their payload.
+//
+// A typical migration to a model with a new dimension creates a new
+// pgvector table for the target, then runs:
+//
+//	reindexer := document.NewReindexer(checkpoints).
+//	    WithAliasStore(aliases).
+//	    RegisterIndex("v1", store.Index()).
+//	    RegisterIndex("v2", document.Index{Store: newTable, Embedder: newEmbedder})
+//	reindexer.Register(jobs)
+//	document.EnqueueReindex(ctx, jobs, document.ReindexRequest{Source: "v1", Target: "v2", Alias: "docs"})
+//
+// while every process serving the documents follows the alias:
+//
+//	store.WithAlias(aliases, "docs", map[string]document.Index{"v1": v1, "v2": v2})
+//	go store.WatchAlias(ctx, 30*time.Second)
+//
+// Before swapping an alias, a catch-up pass lists the source again and
+// copies documents added or changed while the first pass ran, comparing
+// their content, then deletes target documents no longer in the source
+// when the target supports listing. Writes made after the catch-up and
+// before every process has refreshed its alias still go to the source
+// only; pause writers around the swap when they must not be lost.
+//
+// Vectors are written with Upsert, so a batch repeated after a crash
+// overwrites its earlier copy.
+type Reindexer struct {
+	mu          sync.RWMutex
+	indexes     map[string]Index
+	aliases     map[string]*DocumentStore
+	aliasStore  AliasStore
+	checkpoints CheckpointStore
+	batchSize   int
+	onProgress  ProgressFunc
+}
+
+// NewReindexer creates a reindexer that saves progress to checkpoints
+func NewReindexer(checkpoints CheckpointStore) *Reindexer {
+	return &Reindexer{
+		indexes:     make(map[string]Index),
+		aliases:     make(map[string]*DocumentStore),
+		checkpoints: checkpoints,
+		batchSize:   100,
+	}
+}
+
+// WithBatchSize sets how many documents are re-embedded per batch
+func (r *Reindexer) WithBatchSize(size int) *Reindexer {
+	r.batchSize = size
+	return r
+}
+
+// WithAliasStore sets where aliases are saved; requests with an Alias fail
+// without one
+func (r *Reindexer) WithAliasStore(aliases AliasStore) *Reindexer {
+	r.aliasStore = aliases
+	return r
+}
+
+// WithProgress sets a callback for progress updates
+func (r *Reindexer) WithProgress(fn ProgressFunc) *Reindexer {
+	r.onProgress = fn
+	return r
+}
+
+// RegisterIndex makes an index available to requests under name
+func (r *Reindexer) RegisterIndex(name string, index Index) *Reindexer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexes[name] = index
+	return r
+}
+
+// RegisterAlias swaps a document store of this process as soon as a
+// request repoints the alias name, without waiting for its next refresh
+func (r *Reindexer) RegisterAlias(name string, store *DocumentStore) *Reindexer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[name] = store
+	return r
+}
+
+// Register adds the re-index handler to a jobx client
+func (r *Reindexer) Register(client *jobx.Client) {
+	client.Register(ReindexJobType, r.Handler())
+}
+
+// Handler returns a jobx handler running the ReindexRequest in the job
+// payload. A failed job resumes from its last checkpoint when jobx retries
+// it.
+func (r *Reindexer) Handler() jobx.HandlerFunc {
+	return func(ctx context.Context, job *jobx.JobInfo) error {
+		var req ReindexRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return fmt.Errorf("invalid reindex payload: %w", err)
+		}
+		_, err := r.Run(ctx, req)
+		return err
+	}
+}
+
+// EnqueueReindex enqueues a re-index job
+func EnqueueReindex(ctx context.Context, client *jobx.Client, req ReindexRequest) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode reindex request: %w", err)
+	}
+	return client.Enqueue(ctx, jobx.Job{Type: ReindexJobType, Payload: payload})
+}
+
+// Progress returns the saved progress of a migration, or nil if it hasn't
+// started
+func (r *Reindexer) Progress(ctx context.Context, id string) (*ReindexProgress, error) {
+	return r.checkpoints.LoadCheckpoint(ctx, id)
+}
+
+// Run copies every document from the source index to the target, resuming
+// from a saved checkpoint, then catches up and swaps the alias if one was
+// requested. Running a completed migration again is a no-op.
+func (r *Reindexer) Run(ctx context.Context, req ReindexRequest) (*ReindexProgress, error) {
+	source, target, alias, err := r.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	if !source.Store.SupportsListing() {
+		return nil, fmt.Errorf("source index %q does not support listing", req.Source)
+	}
+
+	progress, err := r.checkpoints.LoadCheckpoint(ctx, req.checkpointID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if progress == nil {
+		progress = &ReindexProgress{
+			ID:        req.checkpointID(),
+			Source:    req.Source,
+			Target:    req.Target,
+			StartedAt: time.Now(),
+		}
+		if source.Store.SupportsStatistics() {
+			if stats, err := source.Store.GetStatistics(ctx, source.options()...); err == nil {
+				progress.Total = stats.TotalVectorCount
+			}
+		}
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = r.batchSize
+	}
+
+	for !progress.Done {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		page, err := source.Store.List(ctx, progress.Cursor, batchSize, source.options()...)
+		if err != nil {
+			return progress, fmt.Errorf("failed to list source documents: %w", err)
+		}
+
+		processed, err := r.copyBatch(ctx, page.Vectors, target)
+		if err != nil {
+			return progress, err
+		}
+		progress.Processed += processed
+		progress.Skipped += len(page.Vectors) - processed
+		progress.Cursor = page.NextCursor
+		progress.Done = page.NextCursor == ""
+
+		if err := r.save(ctx, progress); err != nil {
+			return progress, err
+		}
+	}
+
+	if req.Alias == "" || progress.Swapped {
+		return progress, nil
+	}
+
+	if !progress.CaughtUp {
+		if err := r.catchUp(ctx, source, target, batchSize, progress); err != nil {
+			return progress, err
+		}
+		progress.CaughtUp = true
+		if err := r.save(ctx, progress); err != nil {
+			return progress, err
+		}
+	}
+
+	if err := r.aliasStore.SaveAlias(ctx, req.Alias, req.Target); err != nil {
+		return progress, fmt.Errorf("failed to save alias %q: %w", req.Alias, err)
+	}
+	if alias != nil {
+		alias.SwapIndex(target)
+	}
+	progress.Swapped = true
+	if err := r.save(ctx, progress); err != nil {
+		return progress, err
+	}
+
+	return progress, nil
+}
+
+// catchUp applies the writes made to the source while it was copied:
+// documents missing from the target or whose content differs are copied
+// again, and target documents no longer in the source are deleted when the
+// target supports listing.
+func (r *Reindexer) catchUp(ctx context.Context, source, target Index, batchSize int, progress *ReindexProgress) error {
+	inSource := make(map[string]bool)
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := source.Store.List(ctx, cursor, batchSize, source.options()...)
+		if err != nil {
+			return fmt.Errorf("failed to list source documents: %w", err)
+		}
+
+		ids := make([]string, len(page.Vectors))
+		for i, v := range page.Vectors {
+			ids[i] = v.ID
+			inSource[v.ID] = true
+		}
+		copied, err := target.Store.Fetch(ctx, ids, target.options()...)
+		if err != nil {
+			return fmt.Errorf("failed to fetch target documents: %w", err)
+		}
+		contents := make(map[string]string, len(copied))
+		for _, v := range copied {
+			contents[v.ID] = getContentFromMetadata(v.Metadata)
+		}
+
+		var changed []vstore.Vector
+		for _, v := range page.Vectors {
+			if content, ok := contents[v.ID]; !ok || content != getContentFromMetadata(v.Metadata) {
+				changed = append(changed, v)
+			}
+		}
+		processed, err := r.copyBatch(ctx, changed, target)
+		if err != nil {
+			return err
+		}
+		progress.Processed += processed
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if !target.Store.SupportsListing() {
+		return nil
+	}
+
+	// Collect first: deleting while paging could shift the cursor
+	var deleted []string
+	cursor = ""
+	for {
+		page, err := target.Store.List(ctx, cursor, batchSize, target.options()...)
+		if err != nil {
+			return fmt.Errorf("failed to list target documents: %w", err)
+		}
+		for _, v := range page.Vectors {
+			if !inSource[v.ID] {
+				deleted = append(deleted, v.ID)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	if err := target.Store.Delete(ctx, deleted, target.options()...); err != nil {
+		return fmt.Errorf("failed to delete target documents: %w", err)
+	}
+	progress.Deleted += len(deleted)
+	return nil
+}
+
+// resolve looks up the indexes and alias named in a request
+func (r *Reindexer) resolve(req ReindexRequest) (source, target Index, alias *DocumentStore, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	source, ok := r.indexes[req.Source]
+	if !ok {
+		return Index{}, Index{}, nil, fmt.Errorf("unknown source index %q", req.Source)
+	}
+	target, ok = r.indexes[req.Target]
+	if !ok {
+		return Index{}, Index{}, nil, fmt.Errorf("unknown target index %q", req.Target)
+	}
+	if target.Embedder == nil {
+		return Index{}, Index{}, nil, fmt.Errorf("target index %q has no embedder", req.Target)
+	}
+	if req.Alias != "" && r.aliasStore == nil {
+		return Index{}, Index{}, nil, fmt.Errorf("alias %q requested without an alias store", req.Alias)
+	}
+	alias = r.aliases[req.Alias]
+	return source, target, alias, nil
+}
+
+// copyBatch re-embeds the content of vectors and writes them to target,
+// keeping IDs and metadata. It returns how many vectors had content.
+func (r *Reindexer) copyBatch(ctx context.Context, vectors []vstore.Vector, target Index) (int, error) {
+	texts := make([]string, 0, len(vectors))
+	copies := make([]vstore.Vector, 0, len(vectors))
+	for _, v := range vectors {
+		content := getContentFromMetadata(v.Metadata)
+		if content == "" {
+			continue
+		}
+		texts = append(texts, content)
+		copies = append(copies, vstore.Vector{ID: v.ID, Metadata: v.Metadata})
+	}
+	if len(copies) == 0 {
+		return 0, nil
+	}
+
+	embeddings, err := target.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	if len(embeddings) != len(copies) {
+		return 0, fmt.Errorf("embedder returned %d embeddings for %d documents", len(embeddings), len(copies))
+	}
+
+	dimensions := target.Embedder.Dimensions()
+	for i, emb := range embeddings {
+		if dimensions > 0 && len(emb.Vector) != dimensions {
+			return 0, fmt.Errorf("embedding for %s has %d dimensions, target expects %d",
+				copies[i].ID, len(emb.Vector), dimensions)
+		}
+		copies[i].Values = emb.Vector
+	}
+
+	if err := target.Store.Upsert(ctx, copies, target.options()...); err != nil {
+		return 0, fmt.Errorf("failed to write target batch: %w", err)
+	}
+	return len(copies), nil
+}
+
+// save checkpoints progress and reports it
+func (r *Reindexer) save(ctx context.Context, progress *ReindexProgress) error {
+	progress.UpdatedAt = time.Now()
+	if err := r.checkpoints.SaveCheckpoint(ctx, progress); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if r.onProgress != nil {
+		r.onProgress(*progress)
+	}
+	return nil
+}
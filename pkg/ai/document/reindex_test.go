@@ -0,0 +1,185 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstmemory"
+)
+
+// lengthEmbedder embeds a text as a vector derived from its length
+type lengthEmbedder struct {
+	dimensions int
+	calls      int
+}
+
+func (e *lengthEmbedder) EmbedDocuments(ctx context.Context, texts []string, opts ...embedding.Option) ([]embedding.Embedding, error) {
+	e.calls++
+	embeddings := make([]embedding.Embedding, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = e.EmbedQuery(ctx, text, opts...)
+	}
+	return embeddings, nil
+}
+
+func (e *lengthEmbedder) EmbedQuery(ctx context.Context, text string, opts ...embedding.Option) (embedding.Embedding, error) {
+	vector := make([]float32, e.dimensions)
+	for i := range vector {
+		vector[i] = float32(len(text) + i + 1)
+	}
+	return embedding.Embedding{Vector: vector}, nil
+}
+
+func (e *lengthEmbedder) Dimensions() int { return e.dimensions }
+
+func newTestIndex(dimensions int) Index {
+	return Index{
+		Store:    vstore.NewClient(vstmemory.NewMemoryVectorStore(dimensions, vstore.MetricCosine)),
+		Embedder: &lengthEmbedder{dimensions: dimensions},
+	}
+}
+
+func storedIDs(t *testing.T, index Index) map[string]string {
+	t.Helper()
+	ids := make(map[string]string)
+	cursor := ""
+	for {
+		page, err := index.Store.List(context.Background(), cursor, 100, index.options()...)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, v := range page.Vectors {
+			ids[v.ID] = getContentFromMetadata(v.Metadata)
+		}
+		if page.NextCursor == "" {
+			return ids
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func TestReindexCatchesUpAndSwapsAlias(t *testing.T) {
+	ctx := context.Background()
+	v1, v2 := newTestIndex(2), newTestIndex(3)
+	indexes := map[string]Index{"v1": v1, "v2": v2}
+
+	writer := NewDocumentStore(v1.Store, v1.Embedder)
+	var docs []*Document
+	for i := range 10 {
+		docs = append(docs, NewDocument(fmt.Sprintf("document %d", i)).WithID(fmt.Sprintf("d%d", i)))
+	}
+	if err := writer.AddDocuments(ctx, docs); err != nil {
+		t.Fatalf("AddDocuments: %v", err)
+	}
+
+	// Two processes serving the alias: one registered with the reindexer,
+	// one that only follows the alias store
+	aliases := NewMemoryAliasStore()
+	local := NewDocumentStore(v1.Store, v1.Embedder).WithAlias(aliases, "docs", indexes)
+	remote := NewDocumentStore(v1.Store, v1.Embedder).WithAlias(aliases, "docs", indexes)
+
+	// Writes made to the source while the copy runs
+	written := false
+	reindexer := NewReindexer(NewMemoryCheckpointStore()).
+		WithAliasStore(aliases).
+		WithBatchSize(4).
+		RegisterIndex("v1", v1).
+		RegisterIndex("v2", v2).
+		RegisterAlias("docs", local).
+		WithProgress(func(progress ReindexProgress) {
+			if written || progress.Processed == 0 {
+				return
+			}
+			written = true
+			added := []*Document{
+				NewDocument("document 0 edited").WithID("d0"),
+				NewDocument("late document").WithID("late"),
+			}
+			if err := writer.AddDocuments(ctx, added); err != nil {
+				t.Errorf("AddDocuments during copy: %v", err)
+			}
+			if err := writer.DeleteDocuments(ctx, []string{"d1"}); err != nil {
+				t.Errorf("DeleteDocuments during copy: %v", err)
+			}
+		})
+
+	progress, err := reindexer.Run(ctx, ReindexRequest{Source: "v1", Target: "v2", Alias: "docs"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !progress.Done || !progress.CaughtUp || !progress.Swapped {
+		t.Fatalf("progress = %+v, want done, caught up and swapped", progress)
+	}
+	if progress.Deleted != 1 {
+		t.Errorf("Deleted = %d, want 1", progress.Deleted)
+	}
+
+	want := storedIDs(t, v1)
+	got := storedIDs(t, v2)
+	if len(got) != len(want) {
+		t.Errorf("target has %d documents, want %d: %v", len(got), len(want), got)
+	}
+	for id, content := range want {
+		if got[id] != content {
+			t.Errorf("target %s = %q, want %q", id, got[id], content)
+		}
+	}
+
+	if target, _ := aliases.LoadAlias(ctx, "docs"); target != "v2" {
+		t.Errorf("alias = %q, want v2", target)
+	}
+	if local.Index().Store != v2.Store {
+		t.Error("registered store was not swapped")
+	}
+	if remote.Index().Store != v1.Store {
+		t.Fatal("following store swapped before its refresh")
+	}
+	if err := remote.RefreshAlias(ctx); err != nil {
+		t.Fatalf("RefreshAlias: %v", err)
+	}
+	if remote.Index().Store != v2.Store {
+		t.Error("following store did not switch on refresh")
+	}
+
+	// A completed migration is not repeated
+	calls := v2.Embedder.(*lengthEmbedder).calls
+	if _, err := reindexer.Run(ctx, ReindexRequest{Source: "v1", Target: "v2", Alias: "docs"}); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if v2.Embedder.(*lengthEmbedder).calls != calls {
+		t.Error("second Run embedded documents again")
+	}
+}
+
+func TestReindexAliasRequiresStore(t *testing.T) {
+	reindexer := NewReindexer(NewMemoryCheckpointStore()).
+		RegisterIndex("v1", newTestIndex(2)).
+		RegisterIndex("v2", newTestIndex(3))
+
+	if _, err := reindexer.Run(context.Background(), ReindexRequest{Source: "v1", Target: "v2", Alias: "docs"}); err == nil {
+		t.Fatal("Run swapped an alias without an alias store")
+	}
+}
+
+func TestRefreshAliasUnknownIndex(t *testing.T) {
+	ctx := context.Background()
+	v1 := newTestIndex(2)
+	aliases := NewMemoryAliasStore()
+	store := NewDocumentStore(v1.Store, v1.Embedder).WithAlias(aliases, "docs", map[string]Index{"v1": v1})
+
+	if err := store.RefreshAlias(ctx); err != nil {
+		t.Fatalf("RefreshAlias of an unset alias: %v", err)
+	}
+	if err := aliases.SaveAlias(ctx, "docs", "v9"); err != nil {
+		t.Fatalf("SaveAlias: %v", err)
+	}
+	if err := store.RefreshAlias(ctx); err == nil {
+		t.Fatal("RefreshAlias accepted an unknown index")
+	}
+	if store.Index().Store != v1.Store {
+		t.Error("failed refresh changed the index")
+	}
+}
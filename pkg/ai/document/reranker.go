@@ -0,0 +1,276 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/rerank"
+)
+
+// ============================================================================
+// Rerankers - reorder retrieved documents by relevance to the query
+// ============================================================================
+
+// Every reranker returns clones of the documents it keeps, most relevant
+// first, with a score between 0 and 1 in MetadataRerankScore.
+
+// ModelReranker reranks with a hosted rerank model, such as the Cohere
+// provider or the Bedrock provider's Cohere and Amazon rerank models
+type ModelReranker struct {
+	Model   rerank.Reranker
+	TopN    int // Documents kept (0 keeps all)
+	Options []rerank.Option
+}
+
+// NewModelReranker creates a reranker backed by a rerank model
+func NewModelReranker(model rerank.Reranker, opts ...rerank.Option) *ModelReranker {
+	return &ModelReranker{
+		Model:   model,
+		Options: opts,
+	}
+}
+
+// Rerank implements Reranker
+func (r *ModelReranker) Rerank(ctx context.Context, query string, docs []*Document) ([]*Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.Content
+	}
+	opts := r.Options
+	if r.TopN > 0 {
+		opts = append(opts[:len(opts):len(opts)], rerank.WithTopN(r.TopN))
+	}
+
+	results, err := r.Model.Rerank(ctx, query, texts, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rerank documents: %w", err)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	out := make([]*Document, 0, len(results))
+	seen := make(map[int]bool, len(results))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(docs) || seen[result.Index] {
+			continue
+		}
+		seen[result.Index] = true
+		out = append(out, scoredDocument(docs[result.Index], min(max(result.Score, 0), 1)))
+	}
+	return out, nil
+}
+
+// DefaultRerankPrompt asks for a listwise ranking of numbered passages.
+// {{query}}, {{count}} and {{passages}} are replaced before the call.
+const DefaultRerankPrompt = `I will give you {{count}} passages, each marked with a number in brackets. Rank them by how relevant they are to the search query.
+
+<query>
+{{query}}
+</query>
+
+{{passages}}
+
+Answer only with the passage numbers in descending order of relevance, using the format [2] > [1] > [3], and nothing else.`
+
+// LLMReranker reranks with any LLM by showing it the numbered passages and
+// asking for their order (listwise reranking). Lists longer than WindowSize
+// are ranked with a window that slides from the bottom of the list to the
+// top half a window at a time, which carries the best WindowSize/2 passages
+// to the top in about len(docs)/(WindowSize/2) calls; the order further
+// down is approximate. Rank the candidates that reach the retriever, not the
+// whole store.
+//
+// The score of a passage is its position in the final order: 1 for the
+// first of n passages down to 1/n for the last.
+type LLMReranker struct {
+	LLM              llm.LLM
+	Prompt           string       // Defaults to DefaultRerankPrompt
+	WindowSize       int          // Passages ranked per LLM call
+	MaxPassageLength int          // Longer passages are truncated in the prompt (0 = no limit)
+	Options          []llm.Option // Passed to every LLM call
+}
+
+// NewLLMReranker creates a listwise LLM reranker ranking 20 passages per
+// call
+func NewLLMReranker(model llm.LLM) *LLMReranker {
+	return &LLMReranker{
+		LLM:        model,
+		Prompt:     DefaultRerankPrompt,
+		WindowSize: 20,
+	}
+}
+
+// Rerank implements Reranker
+func (r *LLMReranker) Rerank(ctx context.Context, query string, docs []*Document) ([]*Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	ranked := append([]*Document(nil), docs...)
+	window := max(r.WindowSize, 2)
+	step := window / 2
+	for end := len(ranked); ; end -= step {
+		start := max(end-window, 0)
+		if err := r.rankWindow(ctx, query, ranked[start:end]); err != nil {
+			return nil, err
+		}
+		if start == 0 {
+			break
+		}
+	}
+
+	out := make([]*Document, len(ranked))
+	for i, doc := range ranked {
+		out[i] = scoredDocument(doc, float64(len(ranked)-i)/float64(len(ranked)))
+	}
+	return out, nil
+}
+
+// rankWindow asks the LLM for the order of docs and reorders them in place
+func (r *LLMReranker) rankWindow(ctx context.Context, query string, docs []*Document) error {
+	if len(docs) < 2 {
+		return nil
+	}
+
+	var passages strings.Builder
+	for i, doc := range docs {
+		text := truncateText(doc.Content, r.MaxPassageLength)
+		if i > 0 {
+			passages.WriteString("\n\n")
+		}
+		fmt.Fprintf(&passages, "[%d] %s", i+1, text)
+	}
+
+	prompt := r.Prompt
+	if prompt == "" {
+		prompt = DefaultRerankPrompt
+	}
+	message := strings.NewReplacer(
+		"{{query}}", query,
+		"{{count}}", strconv.Itoa(len(docs)),
+		"{{passages}}", passages.String(),
+	).Replace(prompt)
+
+	resp, err := r.LLM.Chat(ctx, []llm.Message{llm.NewUserMessage(message)}, r.Options...)
+	if err != nil {
+		return fmt.Errorf("failed to rank passages: %w", err)
+	}
+
+	order := parseRanking(resp.Message.TextContent(), len(docs))
+	reordered := make([]*Document, len(docs))
+	for i, index := range order {
+		reordered[i] = docs[index]
+	}
+	copy(docs, reordered)
+	return nil
+}
+
+var rankingNumber = regexp.MustCompile(`\d+`)
+
+// parseRanking reads passage numbers (1-based) from an answer such as
+// "[2] > [1] > [3]" and returns a permutation of 0..n-1. Numbers out of
+// range or repeated are ignored, and passages the answer leaves out keep
+// their relative order after the ranked ones.
+func parseRanking(answer string, n int) []int {
+	order := make([]int, 0, n)
+	seen := make([]bool, n)
+	for _, match := range rankingNumber.FindAllString(answer, -1) {
+		number, err := strconv.Atoi(match)
+		if err != nil || number < 1 || number > n || seen[number-1] {
+			continue
+		}
+		seen[number-1] = true
+		order = append(order, number-1)
+	}
+	for i := range n {
+		if !seen[i] {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// ReciprocalRankFusion merges several rankings of documents, such as the
+// results of different phrasings of a question, into one. A document scores
+// the sum of 1/(K+rank) over the rankings it appears in, so documents that
+// rank well for many queries rise above documents that rank first for one.
+// Scores are divided by the best possible score, which a document ranked
+// first everywhere reaches.
+//
+// Documents are matched by ID, or by content when they have none.
+type ReciprocalRankFusion struct {
+	K int // Damps the weight of the top ranks; 60 in the original paper
+}
+
+// NewReciprocalRankFusion creates a fusion with K = 60
+func NewReciprocalRankFusion() *ReciprocalRankFusion {
+	return &ReciprocalRankFusion{K: 60}
+}
+
+// Fuse merges rankings, best first
+func (f *ReciprocalRankFusion) Fuse(rankings ...[]*Document) []*Document {
+	k := float64(max(f.K, 0))
+
+	var order []string
+	docs := make(map[string]*Document)
+	scores := make(map[string]float64)
+	for _, ranking := range rankings {
+		for rank, doc := range ranking {
+			key := fusionKey(doc)
+			if _, ok := docs[key]; !ok {
+				docs[key] = doc
+				order = append(order, key)
+			}
+			scores[key] += 1 / (k + float64(rank+1))
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	best := float64(len(rankings)) / (k + 1)
+	out := make([]*Document, len(order))
+	for i, key := range order {
+		out[i] = scoredDocument(docs[key], scores[key]/best)
+	}
+	return out
+}
+
+// fusionKey identifies a document across rankings
+func fusionKey(doc *Document) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+	return ContentHash(doc.Content)
+}
+
+// scoredDocument returns a copy of doc carrying a rerank score
+func scoredDocument(doc *Document, score float64) *Document {
+	scored := doc.Clone()
+	scored.Metadata[MetadataRerankScore] = score
+	return scored
+}
+
+// truncateText cuts text to at most n bytes, backing off to a rune boundary.
+// n <= 0 means no limit.
+func truncateText(text string, n int) string {
+	if n <= 0 || len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
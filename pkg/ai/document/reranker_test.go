@@ -0,0 +1,35 @@
+package document
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"hello", 0, "hello"},
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+		{"日本語", 4, "日"},
+		{"日本語", 2, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateText(tt.text, tt.n); got != tt.want {
+			t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+
+	text := strings.Repeat("añ😀", 20)
+	for n := 1; n < len(text); n++ {
+		if got := truncateText(text, n); !utf8.ValidString(got) || len(got) > n {
+			t.Fatalf("truncateText(_, %d) = %q", n, got)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package document
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
 )
 
 // ============================================================================
@@ -19,6 +22,12 @@ type Retriever struct {
 	mmrLambda       float32 // For MMR (Maximal Marginal Relevance)
 	reranker        Reranker
 	compressionFunc CompressionFunc
+	transformer     QueryTransformer
+
+	// Context expansion around matched chunks
+	parentLookup bool
+	parents      *DocumentStore
+	neighbours   int
 }
 
 // SearchType defines the retrieval strategy
@@ -81,14 +90,92 @@ func (r *Retriever) WithCompression(fn CompressionFunc) *Retriever {
 	return r
 }
 
+// WithQueryTransformer rewrites every query into one or more sub-queries
+// before searching. Each sub-query is searched, filtered by MMR or reranked
+// on its own, and the results are fused with reciprocal rank fusion. Hits
+// carry the sub-queries that found them in MetadataSubQueries.
+func (r *Retriever) WithQueryTransformer(transformer QueryTransformer) *Retriever {
+	r.transformer = transformer
+	return r
+}
+
+// WithParentDocuments returns the parent sections of the matched chunks
+// instead of the chunks themselves, for stores filled through a
+// ParentDocumentSplitter. Parents are fetched from parents, or from the
+// retriever's own store when it is nil. Chunks matching the same parent
+// yield it once, at the rank of the best chunk.
+func (r *Retriever) WithParentDocuments(parents *DocumentStore) *Retriever {
+	r.parentLookup = true
+	r.parents = parents
+	return r
+}
+
+// WithNeighbours widens every matched chunk with up to window chunks before
+// and after it, following the links set by ParentDocumentSplitter. It is
+// ignored when WithParentDocuments is set.
+func (r *Retriever) WithNeighbours(window int) *Retriever {
+	r.neighbours = window
+	return r
+}
+
 // Retrieve retrieves relevant documents
 func (r *Retriever) Retrieve(ctx context.Context, query string) ([]*Document, error) {
+	// Parents are cut to topK after chunks of the same parent are merged
+	topK := r.topK
+	if r.parentLookup {
+		topK = r.topK * 2
+	}
+
+	var docs []*Document
+	var err error
+	if r.transformer != nil {
+		docs, err = r.searchTransformed(ctx, query, topK)
+	} else {
+		docs, err = r.search(ctx, SubQuery{Query: query}, topK)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Expand chunks into the context around them
+	switch {
+	case r.parentLookup:
+		if docs, err = r.parentDocuments(ctx, docs); err != nil {
+			return nil, err
+		}
+		if len(docs) > r.topK {
+			docs = docs[:r.topK]
+		}
+	case r.neighbours > 0:
+		if docs, err = r.withNeighbours(ctx, docs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply compression if set
+	if r.compressionFunc != nil {
+		for i, doc := range docs {
+			compressed := r.compressionFunc(ctx, query, doc)
+			docs[i] = doc.Clone()
+			docs[i].Content = compressed
+		}
+	}
+
+	return docs, nil
+}
+
+// search runs one sub-query and applies the search strategy
+func (r *Retriever) search(ctx context.Context, query SubQuery, topK int) ([]*Document, error) {
 	// Initial search
 	searchReq := SearchRequest{
-		Query:    query,
-		TopK:     r.topK * 2, // Fetch more for reranking/MMR
+		Query:    query.searchText(),
+		TopK:     topK * 2, // Fetch more for reranking/MMR
 		MinScore: r.minScore,
 	}
+	if r.parentLookup || r.neighbours > 0 {
+		// Parents stored alongside their chunks must not match themselves
+		searchReq.Filter = vstore.NewFilter().AddMustNot(MetadataChunkRole, vstore.OpEqual, ChunkRoleParent)
+	}
 
 	result, err := r.store.Search(ctx, searchReq)
 	if err != nil {
@@ -100,38 +187,226 @@ func (r *Retriever) Retrieve(ctx context.Context, query string) ([]*Document, er
 	// Apply search strategy
 	switch r.searchType {
 	case SearchTypeMMR:
-		docs = r.applyMMR(docs, result.Scores, r.topK)
+		docs = r.applyMMR(docs, result.Scores, topK)
 
 	case SearchTypeRerank:
 		if r.reranker != nil {
-			docs, err = r.reranker.Rerank(ctx, query, docs)
+			docs, err = r.reranker.Rerank(ctx, query.Query, docs)
 			if err != nil {
 				return nil, err
 			}
 		}
-		if len(docs) > r.topK {
-			docs = docs[:r.topK]
+		if len(docs) > topK {
+			docs = docs[:topK]
 		}
 
 	default:
 		// Simple similarity - just take topK
-		if len(docs) > r.topK {
-			docs = docs[:r.topK]
+		if len(docs) > topK {
+			docs = docs[:topK]
 		}
 	}
 
-	// Apply compression if set
-	if r.compressionFunc != nil {
-		for i, doc := range docs {
-			compressed := r.compressionFunc(ctx, query, doc)
-			docs[i] = doc.Clone()
-			docs[i].Content = compressed
+	return docs, nil
+}
+
+// searchTransformed runs every sub-query of a transformed query and fuses
+// their results
+func (r *Retriever) searchTransformed(ctx context.Context, query string, topK int) ([]*Document, error) {
+	queries, err := r.transformer.Transform(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		queries = []SubQuery{{Query: query}}
+	}
+
+	rankings := make([][]*Document, 0, len(queries))
+	found := make(map[string][]string)
+	for _, q := range queries {
+		docs, err := r.search(ctx, q, topK)
+		if err != nil {
+			return nil, err
 		}
+		for _, doc := range docs {
+			key := fusionKey(doc)
+			if !slices.Contains(found[key], q.Query) {
+				found[key] = append(found[key], q.Query)
+			}
+		}
+		rankings = append(rankings, docs)
+	}
+
+	docs := rankings[0]
+	if len(rankings) > 1 {
+		docs = NewReciprocalRankFusion().Fuse(rankings...)
+	}
+	if len(docs) > topK {
+		docs = docs[:topK]
+	}
+
+	out := make([]*Document, len(docs))
+	for i, doc := range docs {
+		out[i] = doc.Clone()
+		out[i].Metadata[MetadataSubQueries] = found[fusionKey(doc)]
+	}
+	return out, nil
+}
+
+// RetrieveMany retrieves documents for several phrasings of the same
+// question and merges the results with reciprocal rank fusion, keeping topK
+func (r *Retriever) RetrieveMany(ctx context.Context, queries []string) ([]*Document, error) {
+	rankings := make([][]*Document, 0, len(queries))
+	for _, query := range queries {
+		docs, err := r.Retrieve(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		rankings = append(rankings, docs)
 	}
 
+	docs := NewReciprocalRankFusion().Fuse(rankings...)
+	if len(docs) > r.topK {
+		docs = docs[:r.topK]
+	}
 	return docs, nil
 }
 
+// parentDocuments replaces chunks with their parents, keeping the order of
+// the best matching chunk. Chunks without a parent are kept.
+func (r *Retriever) parentDocuments(ctx context.Context, docs []*Document) ([]*Document, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		if id, ok := doc.GetMetadataString(MetadataParentID); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	store := r.parents
+	if store == nil {
+		store = r.store
+	}
+	fetched, err := store.GetDocuments(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch parent documents: %w", err)
+	}
+	parents := make(map[string]*Document, len(fetched))
+	for _, parent := range fetched {
+		parents[parent.ID] = parent
+	}
+
+	out := make([]*Document, 0, len(docs))
+	added := make(map[string]bool)
+	for _, doc := range docs {
+		id, ok := doc.GetMetadataString(MetadataParentID)
+		parent := parents[id]
+		switch {
+		case !ok || parent == nil:
+			out = append(out, doc)
+		case !added[id]:
+			added[id] = true
+			out = append(out, withHitMetadata(parent, doc))
+		}
+	}
+	return out, nil
+}
+
+// withHitMetadata returns parent carrying the scores and sub-queries of
+// the chunk that matched
+func withHitMetadata(parent, chunk *Document) *Document {
+	var out *Document
+	for _, key := range []string{MetadataRerankScore, MetadataSubQueries} {
+		if value, ok := chunk.Metadata[key]; ok {
+			if out == nil {
+				out = parent.Clone()
+			}
+			out.Metadata[key] = value
+		}
+	}
+	if out == nil {
+		return parent
+	}
+	return out
+}
+
+// withNeighbours joins every chunk with the chunks around it
+func (r *Retriever) withNeighbours(ctx context.Context, docs []*Document) ([]*Document, error) {
+	before := make([][]*Document, len(docs))
+	after := make([][]*Document, len(docs))
+	prev := make([]*Document, len(docs))
+	next := make([]*Document, len(docs))
+	copy(prev, docs)
+	copy(next, docs)
+
+	// Walk the links one step at a time, fetching a step for all chunks at once
+	for step := 0; step < r.neighbours; step++ {
+		var ids []string
+		for i := range docs {
+			if prev[i] != nil {
+				if id, ok := prev[i].GetMetadataString(MetadataPrevChunkID); ok {
+					ids = append(ids, id)
+				}
+			}
+			if next[i] != nil {
+				if id, ok := next[i].GetMetadataString(MetadataNextChunkID); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		fetched, err := r.store.GetDocuments(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch neighbouring chunks: %w", err)
+		}
+		byID := make(map[string]*Document, len(fetched))
+		for _, doc := range fetched {
+			byID[doc.ID] = doc
+		}
+
+		for i := range docs {
+			if prev[i] != nil {
+				id, _ := prev[i].GetMetadataString(MetadataPrevChunkID)
+				if prev[i] = byID[id]; prev[i] != nil {
+					before[i] = append([]*Document{prev[i]}, before[i]...)
+				}
+			}
+			if next[i] != nil {
+				id, _ := next[i].GetMetadataString(MetadataNextChunkID)
+				if next[i] = byID[id]; next[i] != nil {
+					after[i] = append(after[i], next[i])
+				}
+			}
+		}
+	}
+
+	out := make([]*Document, len(docs))
+	for i, doc := range docs {
+		if len(before[i]) == 0 && len(after[i]) == 0 {
+			out[i] = doc
+			continue
+		}
+		parts := make([]string, 0, len(before[i])+len(after[i])+1)
+		for _, chunk := range before[i] {
+			parts = append(parts, chunkText(chunk))
+		}
+		parts = append(parts, chunkText(doc))
+		for _, chunk := range after[i] {
+			parts = append(parts, chunkText(chunk))
+		}
+		out[i] = doc.Clone()
+		out[i].Content = strings.Join(parts, "\n\n")
+	}
+	return out, nil
+}
+
 // applyMMR applies Maximal Marginal Relevance
 func (r *Retriever) applyMMR(docs []*Document, scores []float32, k int) []*Document {
 	if len(docs) <= k {
@@ -565,3 +565,47 @@ func (tss *tokenSplitStream) Close() error {
 func SimpleTokenCounter(text string) int {
 	return len(strings.Fields(text))
 }
+
+// ============================================================================
+// Split Helpers - shared by the structure-aware splitters
+// ============================================================================
+
+// chunkDocument copies doc for its i-th chunk out of total
+func chunkDocument(doc *Document, content string, i, total int) *Document {
+	chunk := doc.Clone()
+	chunk.Content = content
+	chunk.Embedding = nil
+	chunk.ID = fmt.Sprintf("%s_chunk_%d", doc.ID, i)
+	chunk.Metadata[MetadataChunkIndex] = i
+	chunk.Metadata[MetadataChunkTotal] = total
+	chunk.Metadata[MetadataDocumentID] = doc.ID
+	return chunk
+}
+
+// chunkStream implements SplitStream for any split function
+type chunkStream struct {
+	source DocumentStream
+	split  func(ctx context.Context, doc *Document) ([]*Document, error)
+	ctx    context.Context
+	buffer []*Document
+}
+
+func (cs *chunkStream) Next() (*Document, error) {
+	for len(cs.buffer) == 0 {
+		doc, err := cs.source.Next()
+		if err != nil {
+			return nil, err
+		}
+		if cs.buffer, err = cs.split(cs.ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	doc := cs.buffer[0]
+	cs.buffer = cs.buffer[1:]
+	return doc, nil
+}
+
+func (cs *chunkStream) Close() error {
+	return cs.source.Close()
+}
@@ -0,0 +1,274 @@
+package document
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Code Splitter - splits source files at function and class boundaries
+// ============================================================================
+
+// Language identifies a programming language for CodeSplitter
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageJavaScript Language = "javascript"
+	LanguageTypeScript Language = "typescript"
+)
+
+// CodeSplitter splits source code at top-level declarations: functions,
+// methods and types in Go; functions and classes in Python; functions,
+// classes and exported bindings in JavaScript and TypeScript. Doc comments
+// and decorators stay with the declaration they describe, and small
+// neighbouring declarations are packed together up to ChunkSize.
+// Declarations longer than ChunkSize are split at blank lines, then lines.
+//
+// Chunks carry the language, the names of the symbols they define and their
+// line range. Chunks don't overlap.
+type CodeSplitter struct {
+	ChunkSize int      // Target chunk size in bytes
+	Language  Language // Detected from the MetadataSource extension when empty
+}
+
+// NewCodeSplitter creates a code splitter. Pass an empty language to
+// detect it from each document's source file name.
+func NewCodeSplitter(language Language, chunkSize int) *CodeSplitter {
+	return &CodeSplitter{
+		ChunkSize: chunkSize,
+		Language:  language,
+	}
+}
+
+// Split splits a source file into chunks
+func (s *CodeSplitter) Split(ctx context.Context, doc *Document) ([]*Document, error) {
+	if doc == nil || doc.Content == "" {
+		return []*Document{}, nil
+	}
+
+	language := s.Language
+	if language == "" {
+		source, _ := doc.Metadata[MetadataSource].(string)
+		language = LanguageByExtension(source)
+	}
+
+	lines := strings.Split(doc.Content, "\n")
+	var blocks []codeBlock
+	for _, unit := range codeUnits(lines, codeSyntaxes[language]) {
+		blocks = append(blocks, unit.split(lines, s.ChunkSize)...)
+	}
+	blocks = mergeCodeBlocks(lines, blocks, s.ChunkSize)
+
+	documents := make([]*Document, 0, len(blocks))
+	for _, block := range blocks {
+		content := strings.Trim(strings.Join(lines[block.start:block.end], "\n"), "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		chunk := chunkDocument(doc, content, len(documents), 0)
+		if language != "" {
+			chunk.Metadata[MetadataCodeLanguage] = string(language)
+		}
+		if len(block.symbols) > 0 {
+			chunk.Metadata[MetadataSymbols] = block.symbols
+		}
+		chunk.Metadata[MetadataStartLine] = block.start + 1
+		chunk.Metadata[MetadataEndLine] = block.end
+		documents = append(documents, chunk)
+	}
+	for _, chunk := range documents {
+		chunk.Metadata[MetadataChunkTotal] = len(documents)
+	}
+
+	return documents, nil
+}
+
+// SplitStream implements streaming split
+func (s *CodeSplitter) SplitStream(ctx context.Context, stream DocumentStream) (DocumentStream, error) {
+	return &chunkStream{source: stream, split: s.Split, ctx: ctx}, nil
+}
+
+// LanguageByExtension returns the language of a file name, or "" when
+// CodeSplitter doesn't know it
+func LanguageByExtension(name string) Language {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".go":
+		return LanguageGo
+	case ".py", ".pyi":
+		return LanguagePython
+	case ".js", ".jsx", ".mjs", ".cjs":
+		return LanguageJavaScript
+	case ".ts", ".tsx", ".mts", ".cts":
+		return LanguageTypeScript
+	default:
+		return ""
+	}
+}
+
+// codeSyntax describes where declarations start in a language
+type codeSyntax struct {
+	// declarations match the first line of a top-level declaration; the
+	// first submatch, when not empty, is the declared name
+	declarations []*regexp.Regexp
+	// attached are line prefixes (comments, decorators) that belong to the
+	// declaration below them
+	attached []string
+}
+
+var jsSyntax = codeSyntax{
+	declarations: []*regexp.Regexp{
+		regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?function\b\s*\*?\s*([A-Za-z_$][\w$]*)?`),
+		regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\b\s*([A-Za-z_$][\w$]*)?`),
+		regexp.MustCompile(`^(?:export\s+)?(?:declare\s+)?(?:interface|type|enum|namespace)\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`^export\s+default\b()`),
+	},
+	attached: []string{"//", "/*", " *", "*/", "@"},
+}
+
+var codeSyntaxes = map[Language]codeSyntax{
+	LanguageGo: {
+		declarations: []*regexp.Regexp{
+			regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`),
+			regexp.MustCompile(`^(?:type|var|const)\s+([A-Za-z_]\w*)`),
+			regexp.MustCompile(`^(?:type|var|const)\s*\(()`),
+		},
+		attached: []string{"//", "/*", " *", "*/"},
+	},
+	LanguagePython: {
+		declarations: []*regexp.Regexp{
+			regexp.MustCompile(`^(?:async\s+)?def\s+([A-Za-z_]\w*)`),
+			regexp.MustCompile(`^class\s+([A-Za-z_]\w*)`),
+		},
+		attached: []string{"#", "@"},
+	},
+	LanguageJavaScript: jsSyntax,
+	LanguageTypeScript: jsSyntax,
+}
+
+// codeBlock is a range of lines [start, end) and the symbols it defines
+type codeBlock struct {
+	start, end int
+	symbols    []string
+}
+
+// codeUnits cuts a file into the preamble and one unit per top-level
+// declaration. Without a known syntax the whole file is one unit.
+func codeUnits(lines []string, syntax codeSyntax) []codeBlock {
+	var units []codeBlock
+	current := codeBlock{}
+	for i, line := range lines {
+		name, ok := syntax.declaration(line)
+		if !ok {
+			continue
+		}
+
+		start := i
+		for start > current.start && syntax.isAttached(lines[start-1]) {
+			start--
+		}
+		if start > current.start {
+			current.end = start
+			units = append(units, current)
+			current = codeBlock{start: start}
+		}
+		if name != "" {
+			current.symbols = append(current.symbols, name)
+		}
+	}
+	current.end = len(lines)
+	return append(units, current)
+}
+
+func (s codeSyntax) declaration(line string) (string, bool) {
+	for _, re := range s.declarations {
+		if m := re.FindStringSubmatch(line); m != nil {
+			if len(m) > 1 {
+				return m[1], true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
+
+func (s codeSyntax) isAttached(line string) bool {
+	for _, prefix := range s.attached {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// split breaks a unit longer than chunkSize at blank lines, then at lines.
+// Every piece keeps the symbols of the unit it belongs to.
+func (b codeBlock) split(lines []string, chunkSize int) []codeBlock {
+	if chunkSize <= 0 || blockSize(lines, b) <= chunkSize {
+		return []codeBlock{b}
+	}
+
+	var pieces []codeBlock
+	start := b.start
+	for i := b.start; i < b.end; i++ {
+		if strings.TrimSpace(lines[i]) == "" && i > start {
+			pieces = append(pieces, codeBlock{start: start, end: i + 1})
+			start = i + 1
+		}
+	}
+	if start < b.end {
+		pieces = append(pieces, codeBlock{start: start, end: b.end})
+	}
+
+	var out []codeBlock
+	for _, piece := range pieces {
+		if blockSize(lines, piece) <= chunkSize {
+			out = append(out, piece)
+			continue
+		}
+		for i := piece.start; i < piece.end; i++ {
+			out = append(out, codeBlock{start: i, end: i + 1})
+		}
+	}
+	out = mergeCodeBlocks(lines, out, chunkSize)
+	for i := range out {
+		out[i].symbols = b.symbols
+	}
+	return out
+}
+
+// mergeCodeBlocks packs adjacent blocks together while they fit chunkSize
+func mergeCodeBlocks(lines []string, blocks []codeBlock, chunkSize int) []codeBlock {
+	if chunkSize <= 0 || len(blocks) == 0 {
+		return blocks
+	}
+	merged := []codeBlock{blocks[0]}
+	for _, block := range blocks[1:] {
+		last := &merged[len(merged)-1]
+		combined := codeBlock{start: last.start, end: block.end}
+		if blockSize(lines, combined) > chunkSize {
+			merged = append(merged, block)
+			continue
+		}
+		last.end = block.end
+		for _, symbol := range block.symbols {
+			if !slices.Contains(last.symbols, symbol) {
+				last.symbols = append(slices.Clip(last.symbols), symbol)
+			}
+		}
+	}
+	return merged
+}
+
+func blockSize(lines []string, b codeBlock) int {
+	size := 0
+	for _, line := range lines[b.start:b.end] {
+		size += len(line) + 1
+	}
+	return size
+}
@@ -0,0 +1,126 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Contextual Splitter - situates chunks in their document before embedding
+// ============================================================================
+
+// DefaultContextPrompt asks for the context that situates a chunk in its
+// document. {{document}} and {{chunk}} are replaced with their text.
+const DefaultContextPrompt = `<document>
+{{document}}
+</document>
+
+Here is the chunk we want to situate within the whole document:
+<chunk>
+{{chunk}}
+</chunk>
+
+Give a short, succinct context that situates this chunk within the overall document, to improve search retrieval of the chunk. Answer only with the context and nothing else.`
+
+// ContextualSplitter wraps a splitter and prepends to every chunk a short
+// context written by an LLM that has read the whole document, so a chunk
+// such as "revenue grew 3%" is embedded as being about a given company and
+// quarter. The context is also kept in MetadataChunkContext.
+//
+// The content hash of each chunk is taken before the context is added, so
+// chunk IDs stay the same when the model words the context differently on a
+// later run. Parent sections produced by a ParentDocumentSplitter are left
+// as they are.
+//
+// Every chunk costs one LLM call; pair it with an incremental loader such as
+// FileSystemLoader so unchanged files are not enriched again.
+type ContextualSplitter struct {
+	Splitter          Splitter
+	LLM               llm.LLM
+	Prompt            string       // Defaults to DefaultContextPrompt
+	MaxDocumentLength int          // Longer documents are truncated in the prompt (0 = no limit)
+	Concurrency       int          // Parallel LLM calls per document
+	Options           []llm.Option // Passed to every LLM call
+}
+
+// NewContextualSplitter creates a contextual splitter around splitter
+func NewContextualSplitter(splitter Splitter, model llm.LLM) *ContextualSplitter {
+	return &ContextualSplitter{
+		Splitter:    splitter,
+		LLM:         model,
+		Prompt:      DefaultContextPrompt,
+		Concurrency: 4,
+	}
+}
+
+// Split splits a document and enriches its chunks
+func (s *ContextualSplitter) Split(ctx context.Context, doc *Document) ([]*Document, error) {
+	chunks, err := s.Splitter.Split(ctx, doc)
+	if err != nil || len(chunks) == 0 {
+		return chunks, err
+	}
+
+	text := truncateText(doc.Content, s.MaxDocumentLength)
+	prompt := s.Prompt
+	if prompt == "" {
+		prompt = DefaultContextPrompt
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, max(s.Concurrency, 1))
+	for _, chunk := range chunks {
+		if role, _ := chunk.Metadata[MetadataChunkRole].(string); role == ChunkRoleParent {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk *Document) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			message := strings.NewReplacer("{{document}}", text, "{{chunk}}", chunk.Content).Replace(prompt)
+			resp, err := s.LLM.Chat(ctx, []llm.Message{llm.NewUserMessage(message)}, s.Options...)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to generate chunk context: %w", err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			situated := strings.TrimSpace(resp.Message.TextContent())
+			chunk.Metadata[MetadataContentHash] = ContentHash(chunk.Content)
+			if situated != "" {
+				chunk.Metadata[MetadataChunkContext] = situated
+				chunk.Content = situated + "\n\n" + chunk.Content
+			}
+		}(chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return chunks, nil
+}
+
+// SplitStream implements streaming split
+func (s *ContextualSplitter) SplitStream(ctx context.Context, stream DocumentStream) (DocumentStream, error) {
+	return &chunkStream{source: stream, split: s.Split, ctx: ctx}, nil
+}
+
+// chunkText returns the content of a chunk without its situating context
+func chunkText(doc *Document) string {
+	if situated, ok := doc.Metadata[MetadataChunkContext].(string); ok && situated != "" {
+		return strings.TrimPrefix(doc.Content, situated+"\n\n")
+	}
+	return doc.Content
+}
@@ -0,0 +1,104 @@
+package document
+
+import (
+	"context"
+	"strings"
+)
+
+// ============================================================================
+// Markdown Header Splitter - splits along the heading hierarchy
+// ============================================================================
+
+// MarkdownHeaderSplitter splits Markdown at its headings so every chunk
+// stays within one section. The path of enclosing headings is stored in
+// MetadataHeadings (outermost first), which lets retrieval show where a
+// chunk came from and filter on it. Sections longer than ChunkSize are split
+// further with a RecursiveTextSplitter; each of their chunks starts with the
+// section's heading line and keeps the heading path.
+//
+// The HTML and DOCX loaders render headings as Markdown, so this splitter
+// works on their output too.
+type MarkdownHeaderSplitter struct {
+	ChunkSize    int  // Sections longer than this are split further (0 keeps whole sections)
+	ChunkOverlap int  // Overlap between the chunks of a long section
+	MaxLevel     int  // Deepest heading level that starts a chunk (1-6)
+	StripHeaders bool // Remove the heading line from the chunk content
+}
+
+// NewMarkdownHeaderSplitter creates a splitter that starts a chunk at every
+// heading
+func NewMarkdownHeaderSplitter(chunkSize, chunkOverlap int) *MarkdownHeaderSplitter {
+	return &MarkdownHeaderSplitter{
+		ChunkSize:    chunkSize,
+		ChunkOverlap: chunkOverlap,
+		MaxLevel:     6,
+	}
+}
+
+// Split splits a document into sections
+func (s *MarkdownHeaderSplitter) Split(ctx context.Context, doc *Document) ([]*Document, error) {
+	if doc == nil || doc.Content == "" {
+		return []*Document{}, nil
+	}
+
+	maxLevel := s.MaxLevel
+	if maxLevel <= 0 || maxLevel > 6 {
+		maxLevel = 6
+	}
+
+	type piece struct {
+		content  string
+		headings []string
+	}
+	var pieces []piece
+	for _, sec := range splitSectionsToLevel(doc.Content, maxLevel) {
+		content := sec.content
+		if s.StripHeaders && len(sec.headings) > 0 {
+			if first, rest, _ := strings.Cut(content, "\n"); isHeadingLine(first) {
+				content = strings.TrimSpace(rest)
+			}
+		}
+		if content == "" {
+			continue
+		}
+
+		if s.ChunkSize <= 0 || len(content) <= s.ChunkSize {
+			pieces = append(pieces, piece{content, sec.headings})
+			continue
+		}
+		// Repeat the heading line on every chunk of a long section
+		heading := ""
+		if first, rest, _ := strings.Cut(content, "\n"); isHeadingLine(first) {
+			heading, content = first+"\n", strings.TrimSpace(rest)
+		}
+		inner := NewRecursiveTextSplitter(max(s.ChunkSize-len(heading), 1), s.ChunkOverlap)
+		for _, chunk := range inner.splitTextRecursive(content, inner.Separators) {
+			if chunk = strings.TrimSpace(chunk); chunk != "" {
+				pieces = append(pieces, piece{heading + chunk, sec.headings})
+			}
+		}
+	}
+
+	documents := make([]*Document, 0, len(pieces))
+	for i, p := range pieces {
+		chunk := chunkDocument(doc, p.content, i, len(pieces))
+		if len(p.headings) > 0 {
+			chunk.Metadata[MetadataHeadings] = p.headings
+		} else {
+			delete(chunk.Metadata, MetadataHeadings)
+		}
+		documents = append(documents, chunk)
+	}
+
+	return documents, nil
+}
+
+// SplitStream implements streaming split
+func (s *MarkdownHeaderSplitter) SplitStream(ctx context.Context, stream DocumentStream) (DocumentStream, error) {
+	return &chunkStream{source: stream, split: s.Split, ctx: ctx}, nil
+}
+
+func isHeadingLine(line string) bool {
+	level, _ := parseHeading(line)
+	return level > 0
+}
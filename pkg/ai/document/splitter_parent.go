@@ -0,0 +1,108 @@
+package document
+
+import (
+	"context"
+)
+
+// ============================================================================
+// Parent Document Splitter - indexes small chunks that point to larger ones
+// ============================================================================
+
+// Chunk roles set by ParentDocumentSplitter
+const (
+	ChunkRoleParent = "parent"
+	ChunkRoleChild  = "child"
+)
+
+// ParentDocumentSplitter splits documents twice: into parent sections with
+// ParentSplitter, then each section into small child chunks with
+// ChildSplitter. Small chunks match queries precisely; a Retriever set up
+// with WithParentDocuments or WithNeighbours then hands the LLM the larger
+// context around them.
+//
+// The parents come first in the output, each followed by its children.
+// Children point to their parent with MetadataParentID and to the chunks
+// before and after them with MetadataPrevChunkID and MetadataNextChunkID.
+// Parent IDs are derived from the source, the document ID, the role and the
+// content hash; child IDs from their parent's ID, their content hash and
+// their position under the parent. A child therefore never shares its
+// parent's ID, even when the two hold the same text, and IngestionPipeline
+// keeps these IDs so the links stay valid once stored.
+type ParentDocumentSplitter struct {
+	ParentSplitter Splitter // Splits documents into parents; nil keeps whole documents
+	ChildSplitter  Splitter // Splits parents into the indexed chunks
+}
+
+// NewParentDocumentSplitter creates a parent document splitter
+func NewParentDocumentSplitter(parent, child Splitter) *ParentDocumentSplitter {
+	return &ParentDocumentSplitter{
+		ParentSplitter: parent,
+		ChildSplitter:  child,
+	}
+}
+
+// Split splits a document into parents and their children
+func (s *ParentDocumentSplitter) Split(ctx context.Context, doc *Document) ([]*Document, error) {
+	if doc == nil || doc.Content == "" {
+		return []*Document{}, nil
+	}
+
+	parents := []*Document{doc.Clone()}
+	if s.ParentSplitter != nil {
+		var err error
+		if parents, err = s.ParentSplitter.Split(ctx, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	source, _ := doc.Metadata[MetadataSource].(string)
+	scope := source + "\x00" + doc.ID
+	occurrences := make(map[string]int)
+	var documents, children []*Document
+	for _, parent := range parents {
+		hash := linkChunk(parent, ChunkRoleParent)
+		parent.ID = ChunkID(scope, ChunkRoleParent+"\x00"+hash, occurrences[hash])
+		occurrences[hash]++
+		documents = append(documents, parent)
+
+		chunks, err := s.ChildSplitter.Split(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		for i, child := range chunks {
+			child.ID = ChunkID(parent.ID, linkChunk(child, ChunkRoleChild), i)
+			child.Metadata[MetadataParentID] = parent.ID
+			documents = append(documents, child)
+			children = append(children, child)
+		}
+	}
+
+	for i, child := range children {
+		if i > 0 {
+			child.Metadata[MetadataPrevChunkID] = children[i-1].ID
+		}
+		if i+1 < len(children) {
+			child.Metadata[MetadataNextChunkID] = children[i+1].ID
+		}
+	}
+
+	return documents, nil
+}
+
+// SplitStream implements streaming split
+func (s *ParentDocumentSplitter) SplitStream(ctx context.Context, stream DocumentStream) (DocumentStream, error) {
+	return &chunkStream{source: stream, split: s.Split, ctx: ctx}, nil
+}
+
+// linkChunk gives a chunk its role, clears its old links and returns its
+// content hash
+func linkChunk(doc *Document, role string) string {
+	hash := ContentHash(doc.Content)
+	doc.Embedding = nil
+	doc.Metadata[MetadataContentHash] = hash
+	doc.Metadata[MetadataChunkRole] = role
+	delete(doc.Metadata, MetadataParentID)
+	delete(doc.Metadata, MetadataPrevChunkID)
+	delete(doc.Metadata, MetadataNextChunkID)
+	return hash
+}
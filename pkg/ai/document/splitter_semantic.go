@@ -0,0 +1,176 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
+)
+
+// ============================================================================
+// Semantic Splitter - splits where the topic changes
+// ============================================================================
+
+// SemanticSplitter splits text where consecutive sentences stop being about
+// the same thing. Every sentence is embedded together with BufferSize
+// neighbours on each side, and a chunk ends wherever the cosine distance
+// between consecutive windows is above the BreakpointPercentile of all
+// distances in the document. MaxChunkSize forces a break in long runs on
+// one topic and MinChunkSize folds short fragments into the next chunk.
+//
+// Chunks are slices of the original text, so paragraphs and formatting are
+// preserved. Splitting costs one EmbedDocuments call per document.
+type SemanticSplitter struct {
+	Embedder             embedding.Embedder
+	BufferSize           int     // Sentences on each side embedded with a sentence
+	BreakpointPercentile float64 // Distance percentile that starts a new chunk (0-100)
+	MinChunkSize         int     // Chunks shorter than this are merged with the next one
+	MaxChunkSize         int     // Chunks never grow beyond this (0 = no limit)
+	EmbedOptions         []embedding.Option
+}
+
+// NewSemanticSplitter creates a semantic splitter that breaks at the 95th
+// percentile of sentence distances
+func NewSemanticSplitter(embedder embedding.Embedder) *SemanticSplitter {
+	return &SemanticSplitter{
+		Embedder:             embedder,
+		BufferSize:           1,
+		BreakpointPercentile: 95,
+	}
+}
+
+// Split splits a document at topic shifts
+func (s *SemanticSplitter) Split(ctx context.Context, doc *Document) ([]*Document, error) {
+	if doc == nil || strings.TrimSpace(doc.Content) == "" {
+		return []*Document{}, nil
+	}
+
+	text := doc.Content
+	sentences := splitSentences(text)
+	if len(sentences) <= 1 {
+		return []*Document{chunkDocument(doc, strings.TrimSpace(text), 0, 1)}, nil
+	}
+
+	// Embed each sentence with its neighbours to smooth out short sentences
+	windows := make([]string, len(sentences))
+	for i := range sentences {
+		from := max(i-s.BufferSize, 0)
+		to := min(i+s.BufferSize, len(sentences)-1)
+		windows[i] = text[sentences[from].start:sentences[to].end]
+	}
+	embeddings, err := s.Embedder.EmbedDocuments(ctx, windows, s.EmbedOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed sentences: %w", err)
+	}
+	if len(embeddings) != len(windows) {
+		return nil, fmt.Errorf("embedder returned %d embeddings for %d sentences", len(embeddings), len(windows))
+	}
+
+	distances := make([]float64, len(sentences)-1)
+	for i := range distances {
+		distances[i] = 1 - cosineSimilarity(embeddings[i].Vector, embeddings[i+1].Vector)
+	}
+	threshold := percentile(distances, s.BreakpointPercentile)
+
+	var chunks []string
+	start := sentences[0].start
+	for i := range distances {
+		end := sentences[i].end
+		next := sentences[i+1].end
+		size := end - start
+
+		breakpoint := distances[i] > threshold
+		if s.MaxChunkSize > 0 && next-start > s.MaxChunkSize {
+			breakpoint = true
+		}
+		if breakpoint && size >= s.MinChunkSize {
+			chunks = append(chunks, strings.TrimSpace(text[start:end]))
+			start = sentences[i+1].start
+		}
+	}
+	chunks = append(chunks, strings.TrimSpace(text[start:sentences[len(sentences)-1].end]))
+
+	documents := make([]*Document, 0, len(chunks))
+	for i, chunk := range chunks {
+		documents = append(documents, chunkDocument(doc, chunk, i, len(chunks)))
+	}
+	return documents, nil
+}
+
+// SplitStream implements streaming split
+func (s *SemanticSplitter) SplitStream(ctx context.Context, stream DocumentStream) (DocumentStream, error) {
+	return &chunkStream{source: stream, split: s.Split, ctx: ctx}, nil
+}
+
+// span is a byte range of a text
+type span struct {
+	start, end int
+}
+
+// splitSentences finds sentences: runs of text ending with ".", "!" or "?"
+// followed by whitespace, or with a blank line
+func splitSentences(text string) []span {
+	var sentences []span
+	start := -1
+	for i, r := range text {
+		if start < 0 {
+			if !unicode.IsSpace(r) {
+				start = i
+			}
+			continue
+		}
+
+		next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+		end := -1
+		switch {
+		case (r == '.' || r == '!' || r == '?') && (next == utf8.RuneError || unicode.IsSpace(next)):
+			end = i + 1
+		case r == '\n' && next == '\n':
+			end = i
+		}
+		if end >= 0 {
+			sentences = append(sentences, span{start, end})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		sentences = append(sentences, span{start, len(text)})
+	}
+	return sentences
+}
+
+// percentile returns the p-th percentile of values, interpolating linearly
+// between the closest ranks. Unlike the nearest rank, it falls below the
+// largest value of a short list, so a clear topic shift is found even in a
+// document of a few sentences.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	pos := min(max(p/100, 0), 1) * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := min(lower+1, len(sorted)-1)
+	return sorted[lower] + (pos-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 when
+// they can't be compared
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
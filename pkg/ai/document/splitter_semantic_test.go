@@ -0,0 +1,93 @@
+package document
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
+)
+
+// topicEmbedder embeds a text on one axis per topic word it contains
+type topicEmbedder struct {
+	topics []string
+}
+
+func (e topicEmbedder) EmbedDocuments(ctx context.Context, documents []string, opts ...embedding.Option) ([]embedding.Embedding, error) {
+	embeddings := make([]embedding.Embedding, len(documents))
+	for i, doc := range documents {
+		embeddings[i], _ = e.EmbedQuery(ctx, doc, opts...)
+	}
+	return embeddings, nil
+}
+
+func (e topicEmbedder) EmbedQuery(ctx context.Context, text string, opts ...embedding.Option) (embedding.Embedding, error) {
+	vector := make([]float32, len(e.topics))
+	for i, topic := range e.topics {
+		vector[i] = float32(strings.Count(strings.ToLower(text), topic))
+	}
+	return embedding.Embedding{Vector: vector}, nil
+}
+
+func TestSemanticSplitterShortDocument(t *testing.T) {
+	splitter := NewSemanticSplitter(topicEmbedder{topics: []string{"cat", "stock"}})
+	splitter.BufferSize = 0
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "topic shift",
+			text: "Cats purr. Cats nap. Cats hunt. Stocks fell. Stocks rose.",
+			want: []string{"Cats purr. Cats nap. Cats hunt.", "Stocks fell. Stocks rose."},
+		},
+		{
+			name: "two sentences",
+			text: "Cats purr. Stocks fell.",
+			want: []string{"Cats purr. Stocks fell."},
+		},
+		{
+			name: "one topic",
+			text: "Cats purr. Cats nap. Cats hunt.",
+			want: []string{"Cats purr. Cats nap. Cats hunt."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := splitter.Split(context.Background(), NewDocument(tt.text))
+			if err != nil {
+				t.Fatalf("Split: %v", err)
+			}
+			var got []string
+			for _, chunk := range chunks {
+				got = append(got, chunk.Content)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("chunks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{[]float64{0.5}, 95, 0.5},
+		{[]float64{0, 1}, 50, 0.5},
+		{[]float64{0, 0, 0, 1}, 95, 0.85},
+		{[]float64{3, 1, 2}, 0, 1},
+		{[]float64{3, 1, 2}, 100, 3},
+		{[]float64{3, 1, 2}, 150, 3},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.values, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
 	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
@@ -81,29 +83,121 @@ func ExtractVector(emb embedding.Embedding) []float32 {
 // Document Store - combines document processing with vector storage
 // ============================================================================
 
-// DocumentStore manages documents in a vector store
+// DocumentStore manages documents in a vector store. The store acts as an
+// alias for an Index, which SwapIndex can repoint atomically (for example
+// after re-embedding into a new table with Reindexer). WithAlias makes it
+// follow an alias saved in an AliasStore instead.
 type DocumentStore struct {
-	vectorStore *vstore.Client
-	embedder    Embedder
-	namespace   string
-	batchSize   int
+	mu        sync.RWMutex
+	index     Index
+	batchSize int
+
+	// Alias following, see WithAlias
+	aliases    AliasStore
+	aliasName  string
+	aliasIndex string
+	indexes    map[string]Index
+}
+
+// Index is a vector store location together with the embedder that
+// produced its vectors
+type Index struct {
+	Store     *vstore.Client
+	Embedder  Embedder
+	Namespace string
 }
 
 // NewDocumentStore creates a new document store
 func NewDocumentStore(vectorStore *vstore.Client, embedder Embedder) *DocumentStore {
 	return &DocumentStore{
-		vectorStore: vectorStore,
-		embedder:    embedder,
-		batchSize:   100,
+		index:     Index{Store: vectorStore, Embedder: embedder},
+		batchSize: 100,
 	}
 }
 
 // WithNamespace sets the namespace for documents
 func (ds *DocumentStore) WithNamespace(namespace string) *DocumentStore {
-	ds.namespace = namespace
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.index.Namespace = namespace
+	return ds
+}
+
+// Index returns the index the store currently serves from
+func (ds *DocumentStore) Index() Index {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.index
+}
+
+// SwapIndex atomically points the store at index and returns the previous
+// one. Operations already in flight finish against the previous index.
+func (ds *DocumentStore) SwapIndex(index Index) Index {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	previous := ds.index
+	ds.index = index
+	return previous
+}
+
+// WithAlias makes the store follow the alias name in aliases: RefreshAlias
+// and WatchAlias point it at the index the alias names, looked up in
+// indexes. Until the first refresh the store serves its current index.
+func (ds *DocumentStore) WithAlias(aliases AliasStore, name string, indexes map[string]Index) *DocumentStore {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.aliases = aliases
+	ds.aliasName = name
+	ds.indexes = indexes
 	return ds
 }
 
+// RefreshAlias loads the followed alias and swaps to its index if it
+// changed. Stores that follow no alias, and aliases never set, are left
+// alone.
+func (ds *DocumentStore) RefreshAlias(ctx context.Context) error {
+	ds.mu.RLock()
+	aliases, name, current := ds.aliases, ds.aliasName, ds.aliasIndex
+	ds.mu.RUnlock()
+	if aliases == nil {
+		return nil
+	}
+
+	target, err := aliases.LoadAlias(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to load alias %q: %w", name, err)
+	}
+	if target == "" || target == current {
+		return nil
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	index, ok := ds.indexes[target]
+	if !ok {
+		return fmt.Errorf("alias %q points at unknown index %q", name, target)
+	}
+	ds.index = index
+	ds.aliasIndex = target
+	return nil
+}
+
+// WatchAlias calls RefreshAlias every interval until ctx is done; run it
+// in its own goroutine. A failed refresh keeps the current index and is
+// retried on the next tick.
+func (ds *DocumentStore) WatchAlias(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = ds.RefreshAlias(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // WithBatchSize sets the batch size for ingestion
 func (ds *DocumentStore) WithBatchSize(size int) *DocumentStore {
 	ds.batchSize = size
@@ -119,6 +213,7 @@ func (ds *DocumentStore) AddDocuments(ctx context.Context, docs []*Document) err
 	if len(docs) == 0 {
 		return nil
 	}
+	index := ds.Index()
 
 	// Generate embeddings for documents that don't have them
 	docsToEmbed := make([]string, 0)
@@ -134,7 +229,7 @@ func (ds *DocumentStore) AddDocuments(ctx context.Context, docs []*Document) err
 	// Generate embeddings if needed
 	if len(docsToEmbed) > 0 {
 		// Use the embedding.Embedder interface
-		embeddings, err := ds.embedder.EmbedDocuments(ctx, docsToEmbed)
+		embeddings, err := index.Embedder.EmbedDocuments(ctx, docsToEmbed)
 		if err != nil {
 			return fmt.Errorf("failed to generate embeddings: %w", err)
 		}
@@ -159,12 +254,7 @@ func (ds *DocumentStore) AddDocuments(ctx context.Context, docs []*Document) err
 		}
 
 		batch := vectors[i:end]
-		opts := []vstore.Option{}
-		if ds.namespace != "" {
-			opts = append(opts, vstore.WithNamespace(ds.namespace))
-		}
-
-		if err := ds.vectorStore.Upsert(ctx, batch, opts...); err != nil {
+		if err := index.Store.Upsert(ctx, batch, index.options()...); err != nil {
 			return fmt.Errorf("failed to upsert batch: %w", err)
 		}
 	}
@@ -226,8 +316,10 @@ type SearchResult struct {
 
 // Search searches for similar documents
 func (ds *DocumentStore) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	index := ds.Index()
+
 	// Generate query embedding using embedding.Embedder
-	queryEmb, err := ds.embedder.EmbedQuery(ctx, req.Query)
+	queryEmb, err := index.Embedder.EmbedQuery(ctx, req.Query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
@@ -242,7 +334,7 @@ func (ds *DocumentStore) Search(ctx context.Context, req SearchRequest) (*Search
 
 	namespace := req.Namespace
 	if namespace == "" {
-		namespace = ds.namespace
+		namespace = index.Namespace
 	}
 
 	// Build query options
@@ -265,7 +357,7 @@ func (ds *DocumentStore) Search(ctx context.Context, req SearchRequest) (*Search
 	}
 
 	// Execute search
-	results, err := ds.vectorStore.Query(ctx, queryEmbedding, opts...)
+	results, err := index.Store.Query(ctx, queryEmbedding, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vector store: %w", err)
 	}
@@ -310,12 +402,8 @@ func (ds *DocumentStore) SearchStream(ctx context.Context, req SearchRequest) (D
 
 // DeleteDocuments deletes documents by ID
 func (ds *DocumentStore) DeleteDocuments(ctx context.Context, ids []string) error {
-	opts := []vstore.Option{}
-	if ds.namespace != "" {
-		opts = append(opts, vstore.WithNamespace(ds.namespace))
-	}
-
-	return ds.vectorStore.Delete(ctx, ids, opts...)
+	index := ds.Index()
+	return index.Store.Delete(ctx, ids, index.options()...)
 }
 
 // DeleteByFilter deletes documents matching a filter
@@ -349,9 +437,11 @@ func (ds *DocumentStore) DeleteByFilter(ctx context.Context, filter *vstore.Filt
 
 // UpdateDocument updates a document's metadata and/or content
 func (ds *DocumentStore) UpdateDocument(ctx context.Context, doc *Document) error {
+	index := ds.Index()
+
 	// If content changed, regenerate embedding
 	if doc.Embedding == nil {
-		emb, err := ds.embedder.EmbedQuery(ctx, doc.Content)
+		emb, err := index.Embedder.EmbedQuery(ctx, doc.Content)
 		if err != nil {
 			return fmt.Errorf("failed to generate embedding: %w", err)
 		}
@@ -360,13 +450,7 @@ func (ds *DocumentStore) UpdateDocument(ctx context.Context, doc *Document) erro
 
 	// Upsert the document
 	vector := ds.documentToVector(doc)
-
-	opts := []vstore.Option{}
-	if ds.namespace != "" {
-		opts = append(opts, vstore.WithNamespace(ds.namespace))
-	}
-
-	return ds.vectorStore.Upsert(ctx, []vstore.Vector{vector}, opts...)
+	return index.Store.Upsert(ctx, []vstore.Vector{vector}, index.options()...)
 }
 
 // ============================================================================
@@ -375,12 +459,8 @@ func (ds *DocumentStore) UpdateDocument(ctx context.Context, doc *Document) erro
 
 // GetDocuments retrieves documents by ID
 func (ds *DocumentStore) GetDocuments(ctx context.Context, ids []string) ([]*Document, error) {
-	opts := []vstore.Option{}
-	if ds.namespace != "" {
-		opts = append(opts, vstore.WithNamespace(ds.namespace))
-	}
-
-	vectors, err := ds.vectorStore.Fetch(ctx, ids, opts...)
+	index := ds.Index()
+	vectors, err := index.Store.Fetch(ctx, ids, index.options()...)
 	if err != nil {
 		return nil, err
 	}
@@ -404,18 +484,23 @@ func (ds *DocumentStore) GetDocuments(ctx context.Context, ids []string) ([]*Doc
 
 // GetStats returns statistics about the document store
 func (ds *DocumentStore) GetStats(ctx context.Context) (*vstore.Statistics, error) {
-	opts := []vstore.Option{}
-	if ds.namespace != "" {
-		opts = append(opts, vstore.WithNamespace(ds.namespace))
-	}
-
-	return ds.vectorStore.GetStatistics(ctx, opts...)
+	index := ds.Index()
+	return index.Store.GetStatistics(ctx, index.options()...)
 }
 
 // ============================================================================
 // Helper Methods
 // ============================================================================
 
+// options scopes vector store calls to the index namespace
+func (idx Index) options() []vstore.Option {
+	opts := []vstore.Option{}
+	if idx.Namespace != "" {
+		opts = append(opts, vstore.WithNamespace(idx.Namespace))
+	}
+	return opts
+}
+
 // documentToVector converts a document to a vector
 func (ds *DocumentStore) documentToVector(doc *Document) vstore.Vector {
 	// Ensure content is in metadata
@@ -0,0 +1,151 @@
+// Package evalx holds the pieces shared by the evaluation harnesses
+// (agentxeval, rageval): dataset files, the parallel case runner and the
+// parsing of LLM judge verdicts.
+package evalx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// ============================================================================
+// Dataset
+// ============================================================================
+
+// Dataset is the file layout of a dataset: a name and its cases
+type Dataset[C any] struct {
+	Name  string `json:"name"`
+	Cases []C    `json:"cases"`
+}
+
+// Schema tells the loader how to validate the cases of a harness and which
+// error to report for a dataset that can't be used
+type Schema[C any] struct {
+	// ID points at the case ID so missing ones can be filled in
+	ID func(*C) *string
+
+	// Input returns the text a case is run with; it must not be empty
+	Input func(*C) string
+
+	// InputField names the input in validation errors (e.g. "question")
+	InputField string
+
+	Registry *errx.Registry
+	Code     *errx.ErrorCode
+}
+
+// Validate checks that every case has an input and a unique ID.
+// Missing IDs are filled with the case index.
+func (s Schema[C]) Validate(cases []C) error {
+	seen := make(map[string]bool, len(cases))
+	for i := range cases {
+		c := &cases[i]
+		id := s.ID(c)
+		if *id == "" {
+			*id = fmt.Sprintf("case_%03d", i+1)
+		}
+		if strings.TrimSpace(s.Input(c)) == "" {
+			return s.Registry.New(s.Code).
+				WithDetail("case_id", *id).
+				WithDetail("reason", s.InputField+" is empty")
+		}
+		if seen[*id] {
+			return s.Registry.New(s.Code).
+				WithDetail("case_id", *id).
+				WithDetail("reason", "duplicate case id")
+		}
+		seen[*id] = true
+	}
+	return nil
+}
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+// Load reads a dataset from a .json file (a Dataset object or an array of
+// cases) or a .jsonl file (one case per line). Unnamed datasets take the
+// file name.
+func (s Schema[C]) Load(path string) (*Dataset[C], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, s.Registry.NewWithCause(s.Code, err).
+			WithDetail("path", path)
+	}
+	defer f.Close()
+
+	var ds *Dataset[C]
+	if strings.EqualFold(filepath.Ext(path), ".jsonl") {
+		ds, err = s.ParseJSONL(f)
+	} else {
+		ds, err = s.ParseJSON(f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ds.Name == "" {
+		ds.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return ds, nil
+}
+
+// ParseJSON reads a Dataset object or a bare array of cases
+func (s Schema[C]) ParseJSON(r io.Reader) (*Dataset[C], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, s.Registry.NewWithCause(s.Code, err)
+	}
+
+	var ds Dataset[C]
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &ds.Cases)
+	} else {
+		err = json.Unmarshal(data, &ds)
+	}
+	if err != nil {
+		return nil, s.Registry.NewWithCause(s.Code, err)
+	}
+
+	if err := s.Validate(ds.Cases); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// ParseJSONL reads one case per line; blank lines are skipped
+func (s Schema[C]) ParseJSONL(r io.Reader) (*Dataset[C], error) {
+	var ds Dataset[C]
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 8*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var c C
+		if err := json.Unmarshal(text, &c); err != nil {
+			return nil, s.Registry.NewWithCause(s.Code, err).
+				WithDetail("line", line)
+		}
+		ds.Cases = append(ds.Cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, s.Registry.NewWithCause(s.Code, err)
+	}
+
+	if err := s.Validate(ds.Cases); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
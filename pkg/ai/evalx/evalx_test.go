@@ -0,0 +1,182 @@
+package evalx_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+type testCase struct {
+	ID    string `json:"id"`
+	Input string `json:"input"`
+}
+
+var (
+	registry   = errx.NewRegistry("EVALX_TEST")
+	errInvalid = registry.Register("INVALID", errx.TypeValidation, http.StatusBadRequest, "invalid dataset")
+
+	schema = evalx.Schema[testCase]{
+		ID:         func(c *testCase) *string { return &c.ID },
+		Input:      func(c *testCase) string { return c.Input },
+		InputField: "input",
+		Registry:   registry,
+		Code:       errInvalid,
+	}
+)
+
+func errorCode(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+func TestSchemaLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"object.json": `{"name": "named", "cases": [{"id": "a", "input": "one"}, {"input": "two"}]}`,
+		"array.json":  `[{"id": "a", "input": "one"}, {"input": "two"}]`,
+		"lines.jsonl": "{\"id\": \"a\", \"input\": \"one\"}\n\n{\"input\": \"two\"}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []testCase{{ID: "a", Input: "one"}, {ID: "case_002", Input: "two"}}
+	for name, wantName := range map[string]string{"object.json": "named", "array.json": "array", "lines.jsonl": "lines"} {
+		ds, err := schema.Load(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Load %s: %v", name, err)
+		}
+		if ds.Name != wantName || !reflect.DeepEqual(ds.Cases, want) {
+			t.Errorf("Load %s = %q %v, want %q %v", name, ds.Name, ds.Cases, wantName, want)
+		}
+	}
+
+	if _, err := schema.Load(filepath.Join(dir, "missing.json")); errorCode(err) != errInvalid.Code {
+		t.Errorf("Load missing file = %v, want %s", err, errInvalid.Code)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty input", `[{"id": "a", "input": " "}]`},
+		{"duplicate id", `[{"id": "a", "input": "one"}, {"id": "a", "input": "two"}]`},
+		{"bad json", `[{"id": "a", `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := schema.ParseJSON(strings.NewReader(tt.input)); errorCode(err) != errInvalid.Code {
+				t.Errorf("ParseJSON = %v, want %s", err, errInvalid.Code)
+			}
+		})
+	}
+}
+
+func TestRunCases(t *testing.T) {
+	cases := []int{1, 2, 3, 4, 5, 6}
+	var running, peak, reported int32
+	results := evalx.RunCases(context.Background(), cases, 2,
+		func(ctx context.Context, c int) int {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			defer atomic.AddInt32(&running, -1)
+			return c * 10
+		},
+		func(c int, err error) int { return -c },
+		func(int) { reported++ },
+	)
+
+	if want := []int{10, 20, 30, 40, 50, 60}; !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %v, want %v", results, want)
+	}
+	if peak > 2 {
+		t.Errorf("%d cases ran at once, want at most 2", peak)
+	}
+	if reported != int32(len(cases)) {
+		t.Errorf("progress reported %d cases, want %d", reported, len(cases))
+	}
+}
+
+func TestRunCasesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := evalx.RunCases(ctx, []int{1, 2, 3}, 1,
+		func(ctx context.Context, c int) int { return c },
+		func(c int, err error) int { return -c },
+		nil,
+	)
+	// A slot may be free when the context is checked, so any case can run
+	for i, r := range results {
+		if r != i+1 && r != -(i+1) {
+			t.Errorf("result %d = %d", i, r)
+		}
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+		wantErr bool
+	}{
+		{"plain", `{"score": 5, "reasoning": " ok "}`, 1, false},
+		{"fenced", "```json\n{\"score\": 3}\n```", 0.5, false},
+		{"prose", `Here you go: {"score": 1, "reasoning": "bad"} Thanks.`, 0, false},
+		{"out of range", `{"score": 7}`, 0, true},
+		{"not json", `five`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := evalx.ParseVerdict(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVerdict error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && verdict.Value != tt.want {
+				t.Errorf("Value = %v, want %v", verdict.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated", 5, "trunc..."},
+		{"héllo", 2, "h..."},
+		{"日本語", 4, "日..."},
+		{"日本語", 0, "..."},
+	}
+	for _, tt := range tests {
+		if got := evalx.Truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
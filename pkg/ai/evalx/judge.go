@@ -0,0 +1,67 @@
+package evalx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Verdict is a judge's 1-5 grade normalized to 0-1
+type Verdict struct {
+	Value  float64
+	Reason string
+}
+
+// ParseVerdict reads a judge response of the form
+// {"score": <1-5>, "reasoning": "..."}, with or without code fences or
+// surrounding prose
+func ParseVerdict(content string) (Verdict, error) {
+	var verdict struct {
+		Score     float64 `json:"score"`
+		Reasoning string  `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(ExtractJSON(content)), &verdict); err != nil {
+		return Verdict{}, err
+	}
+	if verdict.Score < 1 || verdict.Score > 5 {
+		return Verdict{}, fmt.Errorf("score %v is outside 1-5", verdict.Score)
+	}
+
+	return Verdict{
+		Value:  (verdict.Score - 1) / 4,
+		Reason: strings.TrimSpace(verdict.Reasoning),
+	}, nil
+}
+
+// ExtractJSON strips markdown code fences and surrounding prose
+func ExtractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if start := strings.Index(text, "```"); start >= 0 {
+		rest := text[start+3:]
+		if nl := strings.IndexByte(rest, '\n'); nl >= 0 {
+			rest = rest[nl+1:]
+		}
+		if end := strings.Index(rest, "```"); end >= 0 {
+			return strings.TrimSpace(rest[:end])
+		}
+	}
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
+
+// Truncate shortens s to at most n bytes, backing off to a rune boundary,
+// and marks the cut with "..."
+func Truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,51 @@
+package evalx
+
+import (
+	"context"
+	"sync"
+)
+
+// RunCases runs every case with at most concurrency of them in flight and
+// returns the results in case order. Cases still waiting for a slot when
+// ctx ends get the result of canceled instead. onResult, when set, is
+// called as each case completes, never concurrently.
+func RunCases[C, R any](
+	ctx context.Context,
+	cases []C,
+	concurrency int,
+	run func(context.Context, C) R,
+	canceled func(C, error) R,
+	onResult func(R),
+) []R {
+	results := make([]R, len(cases))
+
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+
+	for i, c := range cases {
+		wg.Add(1)
+		go func(i int, c C) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = canceled(c, ctx.Err())
+				return
+			}
+			defer func() { <-sem }()
+
+			results[i] = run(ctx, c)
+
+			if onResult != nil {
+				progressMu.Lock()
+				onResult(results[i])
+				progressMu.Unlock()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	return results
+}
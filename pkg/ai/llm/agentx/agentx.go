@@ -76,6 +76,12 @@ func (a *Agent) Run(ctx context.Context, userInput string) (string, error) {
 		return "", fmt.Errorf("failed to add user message: %w", err)
 	}
 
+	return a.respond(ctx)
+}
+
+// respond gets the assistant's answer to the conversation in memory,
+// running tools as needed
+func (a *Agent) respond(ctx context.Context) (string, error) {
 	// Get messages from memory
 	messages, err := a.memory.Messages()
 	if err != nil {
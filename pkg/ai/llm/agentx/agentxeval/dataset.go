@@ -0,0 +1,129 @@
+package agentxeval
+
+import (
+	"io"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+)
+
+// ============================================================================
+// Dataset
+// ============================================================================
+
+// Dataset is a named collection of evaluation cases
+type Dataset struct {
+	Name  string `json:"name"`
+	Cases []Case `json:"cases"`
+}
+
+// Case is a single input with the expectations used by scorers
+type Case struct {
+	ID    string `json:"id"`
+	Input string `json:"input"`
+
+	// Reference is the expected answer (exact-match and LLM-judge scorers)
+	Reference string `json:"reference,omitempty"`
+
+	// ExpectedToolCalls is the expected tool trajectory
+	ExpectedToolCalls []ExpectedToolCall `json:"expected_tool_calls,omitempty"`
+
+	// JSONAssertions are checked against the final response parsed as JSON
+	JSONAssertions []JSONAssertion `json:"json_assertions,omitempty"`
+
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// ExpectedToolCall describes a tool call the agent should make. Arguments
+// are matched as a subset: only the listed keys are compared.
+type ExpectedToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// AssertionOperator is the comparison used by a JSON assertion
+type AssertionOperator string
+
+const (
+	AssertEqual       AssertionOperator = "eq"
+	AssertNotEqual    AssertionOperator = "ne"
+	AssertExists      AssertionOperator = "exists"
+	AssertNotExists   AssertionOperator = "not_exists"
+	AssertContains    AssertionOperator = "contains"
+	AssertGreaterThan AssertionOperator = "gt"
+	AssertLessThan    AssertionOperator = "lt"
+	AssertType        AssertionOperator = "type"
+)
+
+// JSONAssertion checks a value at a dot path (e.g. "items.0.name")
+type JSONAssertion struct {
+	Path     string            `json:"path"`
+	Operator AssertionOperator `json:"op"`
+	Value    any               `json:"value,omitempty"`
+}
+
+// NewDataset creates a dataset from cases
+func NewDataset(name string, cases ...Case) *Dataset {
+	return &Dataset{Name: name, Cases: cases}
+}
+
+// datasetSchema validates and decodes agentxeval cases
+var datasetSchema = evalx.Schema[Case]{
+	ID:         func(c *Case) *string { return &c.ID },
+	Input:      func(c *Case) string { return c.Input },
+	InputField: "input",
+	Registry:   errorRegistry,
+	Code:       ErrInvalidDataset,
+}
+
+// Validate checks that every case has an input and a unique ID.
+// Missing IDs are filled with the case index.
+func (d *Dataset) Validate() error {
+	return datasetSchema.Validate(d.Cases)
+}
+
+// Filter returns the cases carrying any of the given tags
+func (d *Dataset) Filter(tags ...string) *Dataset {
+	wanted := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		wanted[t] = true
+	}
+
+	filtered := &Dataset{Name: d.Name}
+	for _, c := range d.Cases {
+		for _, t := range c.Tags {
+			if wanted[t] {
+				filtered.Cases = append(filtered.Cases, c)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+// LoadDataset reads a dataset from a .json file (a Dataset object or an
+// array of cases) or a .jsonl file (one case per line)
+func LoadDataset(path string) (*Dataset, error) {
+	return fromFile(datasetSchema.Load(path))
+}
+
+// ParseJSON reads a Dataset object or a bare array of cases
+func ParseJSON(r io.Reader) (*Dataset, error) {
+	return fromFile(datasetSchema.ParseJSON(r))
+}
+
+// ParseJSONL reads one case per line; blank lines are skipped
+func ParseJSONL(r io.Reader) (*Dataset, error) {
+	return fromFile(datasetSchema.ParseJSONL(r))
+}
+
+func fromFile(ds *evalx.Dataset[Case], err error) (*Dataset, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Dataset{Name: ds.Name, Cases: ds.Cases}, nil
+}
@@ -0,0 +1,47 @@
+package agentxeval
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for agent evaluation
+	errorRegistry = errx.NewRegistry("AGENT_EVAL")
+
+	ErrInvalidDataset = errorRegistry.Register(
+		"INVALID_DATASET",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid evaluation dataset",
+	)
+
+	ErrAgentSetup = errorRegistry.Register(
+		"AGENT_SETUP_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Failed to create agent for evaluation case",
+	)
+
+	ErrAgentRun = errorRegistry.Register(
+		"AGENT_RUN_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Agent failed while running evaluation case",
+	)
+
+	ErrScorerFailed = errorRegistry.Register(
+		"SCORER_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Scorer failed",
+	)
+
+	ErrJudgeResponse = errorRegistry.Register(
+		"JUDGE_RESPONSE_INVALID",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"LLM judge returned an invalid response",
+	)
+)
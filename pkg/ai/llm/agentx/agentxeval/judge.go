@@ -0,0 +1,122 @@
+package agentxeval
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// DefaultJudgePrompt instructs the judge model. It receives the question,
+// the reference answer (possibly empty), the agent's answer and the criteria.
+const DefaultJudgePrompt = `You are an impartial evaluator grading an AI assistant's answer.
+
+Question:
+%s
+
+Reference answer (may be empty):
+%s
+
+Assistant answer:
+%s
+
+Criteria:
+%s
+
+Rate the assistant answer from 1 (unacceptable) to 5 (excellent) against the criteria and, when present, the reference answer.
+Respond with JSON only: {"score": <1-5>, "reasoning": "<one or two sentences>"}`
+
+// DefaultJudgeCriteria is used when no criteria are configured
+const DefaultJudgeCriteria = "Correctness, completeness and faithfulness to the reference; penalize invented facts."
+
+// LLMJudgeScorer grades responses with a judge model
+type LLMJudgeScorer struct {
+	client    *llm.Client
+	name      string
+	prompt    string
+	criteria  string
+	threshold float64
+	options   []llm.Option
+}
+
+// LLMJudgeOption configures an LLMJudgeScorer
+type LLMJudgeOption func(*LLMJudgeScorer)
+
+// WithJudgeName sets the scorer name (useful with several judges)
+func WithJudgeName(name string) LLMJudgeOption {
+	return func(s *LLMJudgeScorer) {
+		s.name = name
+	}
+}
+
+// WithJudgePrompt replaces the judge prompt; it must contain four %s verbs
+// (question, reference, answer, criteria)
+func WithJudgePrompt(prompt string) LLMJudgeOption {
+	return func(s *LLMJudgeScorer) {
+		s.prompt = prompt
+	}
+}
+
+// WithJudgeCriteria sets what the judge should grade
+func WithJudgeCriteria(criteria string) LLMJudgeOption {
+	return func(s *LLMJudgeScorer) {
+		s.criteria = criteria
+	}
+}
+
+// WithPassThreshold sets the normalized score required to pass (default 0.75)
+func WithPassThreshold(threshold float64) LLMJudgeOption {
+	return func(s *LLMJudgeScorer) {
+		s.threshold = threshold
+	}
+}
+
+// WithJudgeOptions adds LLM options (model, temperature) to judge calls
+func WithJudgeOptions(opts ...llm.Option) LLMJudgeOption {
+	return func(s *LLMJudgeScorer) {
+		s.options = append(s.options, opts...)
+	}
+}
+
+// NewLLMJudgeScorer creates an LLM-as-judge scorer
+func NewLLMJudgeScorer(client *llm.Client, opts ...LLMJudgeOption) *LLMJudgeScorer {
+	s := &LLMJudgeScorer{
+		client:    client,
+		name:      "llm_judge",
+		prompt:    DefaultJudgePrompt,
+		criteria:  DefaultJudgeCriteria,
+		threshold: 0.75,
+		options:   []llm.Option{llm.WithTemperature(0)},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *LLMJudgeScorer) Name() string {
+	return s.name
+}
+
+func (s *LLMJudgeScorer) Score(ctx context.Context, run *CaseRun) (Score, error) {
+	prompt := fmt.Sprintf(s.prompt, run.Case.Input, run.Case.Reference, run.Response, s.criteria)
+
+	resp, err := s.client.Chat(ctx, []llm.Message{llm.NewUserMessage(prompt)}, s.options...)
+	if err != nil {
+		return Score{}, err
+	}
+
+	verdict, err := evalx.ParseVerdict(resp.Message.Content)
+	if err != nil {
+		return Score{}, errorRegistry.NewWithCause(ErrJudgeResponse, err).
+			WithDetail("response", evalx.Truncate(resp.Message.Content, 200))
+	}
+
+	return Score{
+		Name:   s.name,
+		Value:  verdict.Value,
+		Passed: verdict.Value >= s.threshold,
+		Reason: verdict.Reason,
+	}, nil
+}
@@ -0,0 +1,305 @@
+package agentxeval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Report
+// ============================================================================
+
+// Report is the outcome of an evaluation run. Cases are sorted by ID so
+// reports from different prompt or model versions diff cleanly.
+type Report struct {
+	Name      string        `json:"name"`
+	Label     string        `json:"label,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Summary   Summary       `json:"summary"`
+	Cases     []CaseResult  `json:"cases"`
+}
+
+// CaseResult is the scored outcome of one case
+type CaseResult struct {
+	ID        string    `json:"id"`
+	Input     string    `json:"input"`
+	Tags      []string  `json:"tags,omitempty"`
+	Response  string    `json:"response"`
+	ToolCalls []string  `json:"tool_calls,omitempty"`
+	Scores    []Score   `json:"scores,omitempty"`
+	Passed    bool      `json:"passed"`
+	Error     string    `json:"error,omitempty"`
+	Usage     llm.Usage `json:"usage"`
+	LatencyMs int64     `json:"latency_ms"`
+}
+
+// Summary aggregates a report
+type Summary struct {
+	Total        int                `json:"total"`
+	Passed       int                `json:"passed"`
+	Failed       int                `json:"failed"`
+	Errored      int                `json:"errored"`
+	PassRate     float64            `json:"pass_rate"`
+	MeanScores   map[string]float64 `json:"mean_scores"`
+	TotalTokens  int                `json:"total_tokens"`
+	AvgLatencyMs int64              `json:"avg_latency_ms"`
+}
+
+// Score returns the named score of a case
+func (c CaseResult) Score(name string) (Score, bool) {
+	for _, s := range c.Scores {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Score{}, false
+}
+
+func summarize(results []CaseResult) Summary {
+	summary := Summary{Total: len(results), MeanScores: make(map[string]float64)}
+	counts := make(map[string]int)
+	var latency int64
+
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			summary.Errored++
+		case r.Passed:
+			summary.Passed++
+		default:
+			summary.Failed++
+		}
+		summary.TotalTokens += r.Usage.TotalTokens
+		latency += r.LatencyMs
+
+		for _, s := range r.Scores {
+			if s.Skipped {
+				continue
+			}
+			summary.MeanScores[s.Name] += s.Value
+			counts[s.Name]++
+		}
+	}
+
+	for name, total := range summary.MeanScores {
+		summary.MeanScores[name] = total / float64(counts[name])
+	}
+	if summary.Total > 0 {
+		summary.PassRate = float64(summary.Passed) / float64(summary.Total)
+		summary.AvgLatencyMs = latency / int64(summary.Total)
+	}
+	return summary
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes a human-readable report
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	title := reportTitle(r)
+	if r.Name != "" && r.Label != "" {
+		title = r.Name + " (" + r.Label + ")"
+	}
+	fmt.Fprintf(&sb, "# Evaluation: %s\n\n", title)
+
+	s := r.Summary
+	fmt.Fprintf(&sb, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Cases | %d |\n", s.Total)
+	fmt.Fprintf(&sb, "| Passed | %d |\n", s.Passed)
+	fmt.Fprintf(&sb, "| Failed | %d |\n", s.Failed)
+	fmt.Fprintf(&sb, "| Errored | %d |\n", s.Errored)
+	fmt.Fprintf(&sb, "| Pass rate | %.1f%% |\n", s.PassRate*100)
+	for _, name := range sortedKeys(s.MeanScores) {
+		fmt.Fprintf(&sb, "| Mean %s | %.3f |\n", name, s.MeanScores[name])
+	}
+	fmt.Fprintf(&sb, "| Total tokens | %d |\n", s.TotalTokens)
+	fmt.Fprintf(&sb, "| Avg latency | %dms |\n\n", s.AvgLatencyMs)
+
+	names := r.scoreNames()
+	sb.WriteString("## Cases\n\n| Case | Result |")
+	for _, name := range names {
+		fmt.Fprintf(&sb, " %s |", name)
+	}
+	sb.WriteString(" Tools |\n|---|---|")
+	for range names {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("---|\n")
+
+	for _, c := range r.Cases {
+		fmt.Fprintf(&sb, "| %s | %s |", c.ID, resultLabel(c))
+		for _, name := range names {
+			score, ok := c.Score(name)
+			switch {
+			case !ok || score.Skipped:
+				sb.WriteString(" - |")
+			default:
+				fmt.Fprintf(&sb, " %.2f |", score.Value)
+			}
+		}
+		fmt.Fprintf(&sb, " %s |\n", strings.Join(c.ToolCalls, " → "))
+	}
+
+	var failures []CaseResult
+	for _, c := range r.Cases {
+		if !c.Passed {
+			failures = append(failures, c)
+		}
+	}
+	if len(failures) > 0 {
+		sb.WriteString("\n## Failures\n")
+		for _, c := range failures {
+			fmt.Fprintf(&sb, "\n### %s\n\n", c.ID)
+			fmt.Fprintf(&sb, "**Input:** %s\n\n", inline(c.Input))
+			if c.Error != "" {
+				fmt.Fprintf(&sb, "**Error:** %s\n", inline(c.Error))
+				continue
+			}
+			fmt.Fprintf(&sb, "**Response:** %s\n\n", inline(c.Response))
+			for _, score := range c.Scores {
+				if !score.Passed && score.Reason != "" {
+					fmt.Fprintf(&sb, "- `%s`: %s\n", score.Name, inline(score.Reason))
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *Report) scoreNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, c := range r.Cases {
+		for _, s := range c.Scores {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				names = append(names, s.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ============================================================================
+// Comparison
+// ============================================================================
+
+// Comparison contrasts a baseline report with a candidate
+type Comparison struct {
+	Baseline       string             `json:"baseline"`
+	Candidate      string             `json:"candidate"`
+	PassRateDelta  float64            `json:"pass_rate_delta"`
+	MeanScoreDelta map[string]float64 `json:"mean_score_delta"`
+	Regressions    []string           `json:"regressions"` // passed in baseline, fail in candidate
+	Improvements   []string           `json:"improvements"`
+}
+
+// Compare reports per-case regressions and aggregate deltas between two runs
+func Compare(baseline, candidate *Report) *Comparison {
+	cmp := &Comparison{
+		Baseline:       reportTitle(baseline),
+		Candidate:      reportTitle(candidate),
+		PassRateDelta:  candidate.Summary.PassRate - baseline.Summary.PassRate,
+		MeanScoreDelta: make(map[string]float64),
+	}
+
+	for name, v := range candidate.Summary.MeanScores {
+		if base, ok := baseline.Summary.MeanScores[name]; ok {
+			cmp.MeanScoreDelta[name] = v - base
+		}
+	}
+
+	base := make(map[string]bool, len(baseline.Cases))
+	for _, c := range baseline.Cases {
+		base[c.ID] = c.Passed
+	}
+	for _, c := range candidate.Cases {
+		passedBefore, ok := base[c.ID]
+		if !ok {
+			continue
+		}
+		switch {
+		case passedBefore && !c.Passed:
+			cmp.Regressions = append(cmp.Regressions, c.ID)
+		case !passedBefore && c.Passed:
+			cmp.Improvements = append(cmp.Improvements, c.ID)
+		}
+	}
+	return cmp
+}
+
+// WriteMarkdown writes the comparison as markdown
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Comparison: %s → %s\n\n", c.Baseline, c.Candidate)
+	fmt.Fprintf(&sb, "| Metric | Delta |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Pass rate | %+.1f%% |\n", c.PassRateDelta*100)
+	for _, name := range sortedKeys(c.MeanScoreDelta) {
+		fmt.Fprintf(&sb, "| Mean %s | %+.3f |\n", name, c.MeanScoreDelta[name])
+	}
+	fmt.Fprintf(&sb, "\n**Regressions (%d):** %s\n\n", len(c.Regressions), listOrNone(c.Regressions))
+	fmt.Fprintf(&sb, "**Improvements (%d):** %s\n", len(c.Improvements), listOrNone(c.Improvements))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func resultLabel(c CaseResult) string {
+	switch {
+	case c.Error != "":
+		return "error"
+	case c.Passed:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+func reportTitle(r *Report) string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return r.Name
+}
+
+func inline(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return evalx.Truncate(s, 500)
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+package agentxeval
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/agentx"
+)
+
+// AgentFactory creates a fresh agent for a case. Each case needs its own
+// agent because agents carry conversation memory.
+type AgentFactory func(ctx context.Context, c Case) (*agentx.Agent, error)
+
+// CaseRun is the observed execution of a case, passed to scorers
+type CaseRun struct {
+	Case       Case                    `json:"-"`
+	Response   string                  `json:"response"`
+	ToolCalls  []llm.ToolCall          `json:"tool_calls,omitempty"`
+	Evaluation *agentx.AgentEvaluation `json:"-"`
+	Usage      llm.Usage               `json:"usage"`
+	Latency    time.Duration           `json:"latency"`
+	Err        error                   `json:"-"`
+}
+
+// Runner runs a dataset against an agent and scores the results
+type Runner struct {
+	factory     AgentFactory
+	scorers     []Scorer
+	concurrency int
+	caseTimeout time.Duration
+	label       string
+	onResult    func(CaseResult)
+}
+
+// RunnerOption configures a Runner
+type RunnerOption func(*Runner)
+
+// WithScorers sets the scorers applied to every case
+func WithScorers(scorers ...Scorer) RunnerOption {
+	return func(r *Runner) {
+		r.scorers = append(r.scorers, scorers...)
+	}
+}
+
+// WithConcurrency sets how many cases run in parallel
+func WithConcurrency(n int) RunnerOption {
+	return func(r *Runner) {
+		if n > 0 {
+			r.concurrency = n
+		}
+	}
+}
+
+// WithCaseTimeout bounds the execution time of each case
+func WithCaseTimeout(d time.Duration) RunnerOption {
+	return func(r *Runner) {
+		r.caseTimeout = d
+	}
+}
+
+// WithLabel tags the report with the prompt or model version being evaluated
+func WithLabel(label string) RunnerOption {
+	return func(r *Runner) {
+		r.label = label
+	}
+}
+
+// WithProgress registers a callback invoked as each case completes
+func WithProgress(fn func(CaseResult)) RunnerOption {
+	return func(r *Runner) {
+		r.onResult = fn
+	}
+}
+
+// NewRunner creates an evaluation runner
+//
+// Example:
+//
+//	runner := agentxeval.NewRunner(
+//	    func(ctx context.Context, c agentxeval.Case) (*agentx.Agent, error) {
+//	        mem := memoryx.NewInMemoryMemory(systemPrompt)
+//	        return agentx.New(client, mem, agentx.WithTools(tools)), nil
+//	    },
+//	    agentxeval.WithScorers(
+//	        agentxeval.NewExactMatchScorer(),
+//	        agentxeval.NewToolTrajectoryScorer(),
+//	        agentxeval.NewLLMJudgeScorer(judgeClient),
+//	    ),
+//	    agentxeval.WithConcurrency(4),
+//	    agentxeval.WithLabel("prompt-v2"),
+//	)
+//	report, err := runner.Run(ctx, dataset)
+//	report.WriteMarkdown(os.Stdout)
+func NewRunner(factory AgentFactory, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		factory:     factory,
+		concurrency: 4,
+		caseTimeout: 2 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run executes every case and returns the scored report. Case failures are
+// recorded in the report; Run only fails on an invalid dataset.
+func (r *Runner) Run(ctx context.Context, dataset *Dataset) (*Report, error) {
+	if err := dataset.Validate(); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	results := evalx.RunCases(ctx, dataset.Cases, r.concurrency, r.runCase,
+		func(c Case, err error) CaseResult {
+			return CaseResult{ID: c.ID, Input: c.Input, Tags: c.Tags, Error: err.Error()}
+		},
+		r.onResult,
+	)
+
+	sort.SliceStable(results, func(a, b int) bool { return results[a].ID < results[b].ID })
+
+	report := &Report{
+		Name:      dataset.Name,
+		Label:     r.label,
+		StartedAt: started,
+		Duration:  time.Since(started),
+		Cases:     results,
+	}
+	report.Summary = summarize(results)
+	return report, nil
+}
+
+// runCase executes and scores a single case
+func (r *Runner) runCase(ctx context.Context, c Case) CaseResult {
+	if r.caseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.caseTimeout)
+		defer cancel()
+	}
+
+	run := r.execute(ctx, c)
+
+	result := CaseResult{
+		ID:        c.ID,
+		Input:     c.Input,
+		Tags:      c.Tags,
+		Response:  run.Response,
+		ToolCalls: toolCallNames(run.ToolCalls),
+		Usage:     run.Usage,
+		LatencyMs: run.Latency.Milliseconds(),
+		Passed:    run.Err == nil,
+	}
+	if run.Err != nil {
+		result.Error = run.Err.Error()
+		return result
+	}
+
+	for _, scorer := range r.scorers {
+		score, err := scorer.Score(ctx, run)
+		if err != nil {
+			score = Score{
+				Name:   scorer.Name(),
+				Reason: errorRegistry.NewWithCause(ErrScorerFailed, err).WithDetail("scorer", scorer.Name()).Error(),
+			}
+		}
+		if score.Skipped {
+			result.Scores = append(result.Scores, score)
+			continue
+		}
+		if !score.Passed {
+			result.Passed = false
+		}
+		result.Scores = append(result.Scores, score)
+	}
+
+	return result
+}
+
+// execute runs the agent and collects its trace
+func (r *Runner) execute(ctx context.Context, c Case) *CaseRun {
+	run := &CaseRun{Case: c}
+
+	agent, err := r.factory(ctx, c)
+	if err != nil {
+		run.Err = errorRegistry.NewWithCause(ErrAgentSetup, err).WithDetail("case_id", c.ID)
+		return run
+	}
+
+	start := time.Now()
+	eval, err := agent.EvaluateWithTools(ctx, c.Input)
+	run.Latency = time.Since(start)
+	if err != nil {
+		run.Err = errorRegistry.NewWithCause(ErrAgentRun, err).WithDetail("case_id", c.ID)
+		return run
+	}
+
+	run.Evaluation = eval
+	run.Response = eval.FinalResponse
+	for _, step := range eval.Steps {
+		run.Usage.PromptTokens += step.TokenUsage.PromptTokens
+		run.Usage.CompletionTokens += step.TokenUsage.CompletionTokens
+		run.Usage.TotalTokens += step.TokenUsage.TotalTokens
+		if step.StepType == "tool_execution" {
+			run.ToolCalls = append(run.ToolCalls, step.ToolCalls...)
+		}
+	}
+
+	return run
+}
+
+func toolCallNames(calls []llm.ToolCall) []string {
+	if len(calls) == 0 {
+		return nil
+	}
+	names := make([]string, len(calls))
+	for i, tc := range calls {
+		names[i] = tc.Function.Name
+	}
+	return names
+}
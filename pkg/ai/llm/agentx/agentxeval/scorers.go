@@ -0,0 +1,410 @@
+package agentxeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// Scorer grades a case run
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, run *CaseRun) (Score, error)
+}
+
+// Score is a scorer's verdict for one case. Value is normalized to [0, 1].
+type Score struct {
+	Name    string  `json:"name"`
+	Value   float64 `json:"value"`
+	Passed  bool    `json:"passed"`
+	Skipped bool    `json:"skipped,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+func skipped(name, reason string) Score {
+	return Score{Name: name, Skipped: true, Passed: true, Reason: reason}
+}
+
+// ============================================================================
+// Exact Match
+// ============================================================================
+
+// ExactMatchScorer compares the final response with the case reference
+type ExactMatchScorer struct {
+	caseSensitive bool
+	contains      bool
+}
+
+// ExactMatchOption configures an ExactMatchScorer
+type ExactMatchOption func(*ExactMatchScorer)
+
+// WithCaseSensitive makes the comparison case sensitive
+func WithCaseSensitive() ExactMatchOption {
+	return func(s *ExactMatchScorer) {
+		s.caseSensitive = true
+	}
+}
+
+// WithContainsMatch passes when the response contains the reference
+func WithContainsMatch() ExactMatchOption {
+	return func(s *ExactMatchScorer) {
+		s.contains = true
+	}
+}
+
+// NewExactMatchScorer creates an exact-match scorer. Whitespace is always
+// normalized; comparison is case insensitive by default.
+func NewExactMatchScorer(opts ...ExactMatchOption) *ExactMatchScorer {
+	s := &ExactMatchScorer{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ExactMatchScorer) Name() string {
+	return "exact_match"
+}
+
+func (s *ExactMatchScorer) Score(ctx context.Context, run *CaseRun) (Score, error) {
+	if run.Case.Reference == "" {
+		return skipped(s.Name(), "no reference answer"), nil
+	}
+
+	want := s.normalize(run.Case.Reference)
+	got := s.normalize(run.Response)
+
+	var ok bool
+	if s.contains {
+		ok = strings.Contains(got, want)
+	} else {
+		ok = got == want
+	}
+
+	score := Score{Name: s.Name(), Passed: ok}
+	if ok {
+		score.Value = 1
+	} else {
+		score.Reason = fmt.Sprintf("expected %q", evalx.Truncate(run.Case.Reference, 120))
+	}
+	return score, nil
+}
+
+func (s *ExactMatchScorer) normalize(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !s.caseSensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// ============================================================================
+// Tool Trajectory
+// ============================================================================
+
+// TrajectoryMode controls how expected tool calls are matched
+type TrajectoryMode string
+
+const (
+	// TrajectoryStrict requires exactly the expected calls in the same order
+	TrajectoryStrict TrajectoryMode = "strict"
+
+	// TrajectoryOrdered requires the expected calls in order; extra calls are allowed
+	TrajectoryOrdered TrajectoryMode = "ordered"
+
+	// TrajectoryUnordered requires the expected calls in any order; extra calls are allowed
+	TrajectoryUnordered TrajectoryMode = "unordered"
+)
+
+// ToolTrajectoryScorer compares the agent's tool calls with the expected ones
+type ToolTrajectoryScorer struct {
+	mode TrajectoryMode
+}
+
+// NewToolTrajectoryScorer creates a trajectory scorer (ordered mode by default)
+func NewToolTrajectoryScorer(mode ...TrajectoryMode) *ToolTrajectoryScorer {
+	s := &ToolTrajectoryScorer{mode: TrajectoryOrdered}
+	if len(mode) > 0 {
+		s.mode = mode[0]
+	}
+	return s
+}
+
+func (s *ToolTrajectoryScorer) Name() string {
+	return "tool_trajectory"
+}
+
+func (s *ToolTrajectoryScorer) Score(ctx context.Context, run *CaseRun) (Score, error) {
+	expected := run.Case.ExpectedToolCalls
+	if len(expected) == 0 {
+		return skipped(s.Name(), "no expected tool calls"), nil
+	}
+
+	actual := run.ToolCalls
+	var matched int
+
+	switch s.mode {
+	case TrajectoryStrict:
+		for i := 0; i < len(expected) && i < len(actual); i++ {
+			if matchesToolCall(expected[i], actual[i]) {
+				matched++
+			}
+		}
+		total := max(len(expected), len(actual))
+		value := float64(matched) / float64(total)
+		return trajectoryScore(s.Name(), value, matched == total, expected, actual), nil
+
+	case TrajectoryUnordered:
+		used := make([]bool, len(actual))
+		for _, exp := range expected {
+			for i, tc := range actual {
+				if !used[i] && matchesToolCall(exp, tc) {
+					used[i] = true
+					matched++
+					break
+				}
+			}
+		}
+
+	default:
+		next := 0
+		for _, exp := range expected {
+			for next < len(actual) && !matchesToolCall(exp, actual[next]) {
+				next++
+			}
+			if next == len(actual) {
+				break
+			}
+			matched++
+			next++
+		}
+	}
+
+	value := float64(matched) / float64(len(expected))
+	return trajectoryScore(s.Name(), value, matched == len(expected), expected, actual), nil
+}
+
+func trajectoryScore(name string, value float64, passed bool, expected []ExpectedToolCall, actual []llm.ToolCall) Score {
+	score := Score{Name: name, Value: value, Passed: passed}
+	if !passed {
+		want := make([]string, len(expected))
+		for i, e := range expected {
+			want[i] = e.Name
+		}
+		score.Reason = fmt.Sprintf("expected [%s], got [%s]",
+			strings.Join(want, ", "), strings.Join(toolCallNames(actual), ", "))
+	}
+	return score
+}
+
+// matchesToolCall checks the name and that every expected argument matches
+func matchesToolCall(exp ExpectedToolCall, tc llm.ToolCall) bool {
+	if exp.Name != tc.Function.Name {
+		return false
+	}
+	if len(exp.Arguments) == 0 {
+		return true
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+		return false
+	}
+	for key, want := range exp.Arguments {
+		got, ok := args[key]
+		if !ok || !jsonEqual(want, got) {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// JSON Assertions
+// ============================================================================
+
+// JSONAssertionScorer parses the final response as JSON and checks the
+// case's JSON assertions
+type JSONAssertionScorer struct{}
+
+// NewJSONAssertionScorer creates a JSON assertion scorer
+func NewJSONAssertionScorer() *JSONAssertionScorer {
+	return &JSONAssertionScorer{}
+}
+
+func (s *JSONAssertionScorer) Name() string {
+	return "json_assertions"
+}
+
+func (s *JSONAssertionScorer) Score(ctx context.Context, run *CaseRun) (Score, error) {
+	assertions := run.Case.JSONAssertions
+	if len(assertions) == 0 {
+		return skipped(s.Name(), "no json assertions"), nil
+	}
+
+	var doc any
+	if err := json.Unmarshal([]byte(evalx.ExtractJSON(run.Response)), &doc); err != nil {
+		return Score{Name: s.Name(), Reason: "response is not valid JSON: " + err.Error()}, nil
+	}
+
+	var failures []string
+	for _, a := range assertions {
+		if msg := checkAssertion(doc, a); msg != "" {
+			failures = append(failures, msg)
+		}
+	}
+
+	passed := len(assertions) - len(failures)
+	return Score{
+		Name:   s.Name(),
+		Value:  float64(passed) / float64(len(assertions)),
+		Passed: len(failures) == 0,
+		Reason: strings.Join(failures, "; "),
+	}, nil
+}
+
+// checkAssertion returns an empty string when the assertion holds
+func checkAssertion(doc any, a JSONAssertion) string {
+	value, found := lookupPath(doc, a.Path)
+
+	switch a.Operator {
+	case AssertExists:
+		if !found {
+			return a.Path + ": missing"
+		}
+		return ""
+	case AssertNotExists:
+		if found {
+			return a.Path + ": should not exist"
+		}
+		return ""
+	}
+
+	if !found {
+		return a.Path + ": missing"
+	}
+
+	switch a.Operator {
+	case AssertEqual, "":
+		if !jsonEqual(a.Value, value) {
+			return fmt.Sprintf("%s: expected %v, got %v", a.Path, a.Value, value)
+		}
+	case AssertNotEqual:
+		if jsonEqual(a.Value, value) {
+			return fmt.Sprintf("%s: should not equal %v", a.Path, a.Value)
+		}
+	case AssertContains:
+		switch v := value.(type) {
+		case string:
+			if !strings.Contains(v, fmt.Sprint(a.Value)) {
+				return fmt.Sprintf("%s: %q does not contain %v", a.Path, v, a.Value)
+			}
+		case []any:
+			for _, item := range v {
+				if jsonEqual(a.Value, item) {
+					return ""
+				}
+			}
+			return fmt.Sprintf("%s: array does not contain %v", a.Path, a.Value)
+		default:
+			return fmt.Sprintf("%s: contains requires a string or array", a.Path)
+		}
+	case AssertGreaterThan, AssertLessThan:
+		got, okGot := value.(float64)
+		want, okWant := toFloat(a.Value)
+		if !okGot || !okWant {
+			return fmt.Sprintf("%s: numeric comparison requires numbers", a.Path)
+		}
+		if a.Operator == AssertGreaterThan && got <= want {
+			return fmt.Sprintf("%s: %v is not > %v", a.Path, got, want)
+		}
+		if a.Operator == AssertLessThan && got >= want {
+			return fmt.Sprintf("%s: %v is not < %v", a.Path, got, want)
+		}
+	case AssertType:
+		if got := jsonTypeName(value); got != fmt.Sprint(a.Value) {
+			return fmt.Sprintf("%s: expected type %v, got %s", a.Path, a.Value, got)
+		}
+	default:
+		return fmt.Sprintf("%s: unknown operator %q", a.Path, a.Operator)
+	}
+	return ""
+}
+
+// lookupPath resolves a dot path; numeric segments index into arrays
+func lookupPath(doc any, path string) (any, bool) {
+	if path == "" || path == "$" {
+		return doc, true
+	}
+	current := doc
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// jsonEqual compares values after a JSON round trip so 1 == 1.0 and typed
+// Go values compare with decoded JSON
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func toFloat(v any) (float64, bool) {
+	f, ok := normalizeJSON(v).(float64)
+	return f, ok
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,93 @@
+package agentx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/memoryx"
+)
+
+// Regenerate produces a new answer to the user message that led to
+// messageID (an assistant or tool message, or the user message itself).
+// The conversation is forked right after that user message, so the
+// original answer stays on its branch. The memory must support branching.
+func (a *Agent) Regenerate(ctx context.Context, messageID string) (string, error) {
+	mem, messages, idx, err := a.locate(messageID)
+	if err != nil {
+		return "", err
+	}
+
+	user := idx
+	for user >= 0 && messages[user].Role != llm.RoleUser {
+		user--
+	}
+	if user < 0 {
+		return "", fmt.Errorf("no user message before %s", messageID)
+	}
+
+	if user+1 < len(messages) {
+		if _, err := mem.Fork(memoryx.MessageID(messages[user+1]), ""); err != nil {
+			return "", fmt.Errorf("failed to fork conversation: %w", err)
+		}
+	}
+	return a.respond(ctx)
+}
+
+// Edit replaces the user message messageID with newInput and answers it.
+// The conversation is forked at the edited message, so the original
+// exchange stays on its branch. The memory must support branching.
+func (a *Agent) Edit(ctx context.Context, messageID, newInput string) (string, error) {
+	mem, messages, idx, err := a.locate(messageID)
+	if err != nil {
+		return "", err
+	}
+	if messages[idx].Role != llm.RoleUser {
+		return "", fmt.Errorf("message %s is not a user message", messageID)
+	}
+
+	if _, err := mem.Fork(messageID, ""); err != nil {
+		return "", fmt.Errorf("failed to fork conversation: %w", err)
+	}
+	return a.Run(ctx, newInput)
+}
+
+// Branches lists the conversation branches of a branching memory
+func (a *Agent) Branches() ([]memoryx.Branch, error) {
+	mem, err := a.branchingMemory()
+	if err != nil {
+		return nil, err
+	}
+	return mem.Branches()
+}
+
+// SwitchBranch makes another conversation branch current
+func (a *Agent) SwitchBranch(branchID string) error {
+	mem, err := a.branchingMemory()
+	if err != nil {
+		return err
+	}
+	return mem.SwitchBranch(branchID)
+}
+
+// locate finds a message in the current branch
+func (a *Agent) locate(messageID string) (memoryx.BranchingMemory, []llm.Message, int, error) {
+	mem, err := a.branchingMemory()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	messages, err := mem.Messages()
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to retrieve messages: %w", err)
+	}
+	idx, err := memoryx.FindMessage(messages, messageID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return mem, messages, idx, nil
+}
+
+func (a *Agent) branchingMemory() (memoryx.BranchingMemory, error) {
+	return memoryx.RequireBranching(a.memory)
+}
@@ -0,0 +1,137 @@
+package memoryx
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/google/uuid"
+)
+
+// MessageIDKey is the metadata key holding a message's ID. Branching
+// memories assign an ID to every message added without one.
+const MessageIDKey = "message_id"
+
+// MainBranch is the ID of the branch every conversation starts on.
+const MainBranch = "main"
+
+// MessageID returns the ID of a message, or "" if it has none.
+func MessageID(m llm.Message) string {
+	id, _ := m.Metadata[MessageIDKey].(string)
+	return id
+}
+
+// withMessageID returns the message with an ID, assigning a new one if needed.
+// The metadata map is copied so callers' messages are never mutated.
+func withMessageID(m llm.Message) llm.Message {
+	if MessageID(m) != "" {
+		return m
+	}
+	metadata := make(map[string]any, len(m.Metadata)+1)
+	maps.Copy(metadata, m.Metadata)
+	metadata[MessageIDKey] = uuid.NewString()
+	m.Metadata = metadata
+	return m
+}
+
+// Branch describes one line of a conversation.
+type Branch struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	ParentID  string    `json:"parent_id,omitempty" db:"parent_id"`
+	ForkedAt  string    `json:"forked_at,omitempty" db:"forked_at"` // message ID the branch diverged at
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Messages  int       `json:"messages" db:"messages"`
+}
+
+// BranchingMemory is a Memory whose history can be edited and forked, so a
+// user can edit an earlier message or regenerate a response while keeping
+// the original conversation reachable.
+//
+// "At" a message means the message itself is excluded: Fork(id) and
+// Truncate(id) both keep only what came before it.
+type BranchingMemory interface {
+	Memory
+
+	// Truncate removes the message and everything after it from the
+	// current branch.
+	Truncate(messageID string) error
+
+	// Fork creates a branch holding the current branch's messages before
+	// messageID (all of them if messageID is empty) and switches to it.
+	Fork(messageID, name string) (Branch, error)
+
+	// Branches lists the conversation's branches.
+	Branches() ([]Branch, error)
+
+	// CurrentBranch returns the branch Messages and Add operate on.
+	CurrentBranch() (Branch, error)
+
+	// SwitchBranch makes another branch current.
+	SwitchBranch(branchID string) error
+}
+
+// Unwrapper is implemented by memories that wrap another Memory.
+type Unwrapper interface {
+	Unwrap() Memory
+}
+
+// AsBranching finds a BranchingMemory in a stack of wrapped memories.
+func AsBranching(m Memory) (BranchingMemory, bool) {
+	for m != nil {
+		if b, ok := m.(BranchingMemory); ok {
+			return b, true
+		}
+		u, ok := m.(Unwrapper)
+		if !ok {
+			return nil, false
+		}
+		m = u.Unwrap()
+	}
+	return nil, false
+}
+
+// RequireBranching is AsBranching returning an error when no memory in the
+// stack supports branching.
+func RequireBranching(m Memory) (BranchingMemory, error) {
+	b, ok := AsBranching(m)
+	if !ok {
+		return nil, errorRegistry.New(ErrBranchingUnsupported).
+			WithDetail("memory", fmt.Sprintf("%T", m))
+	}
+	return b, nil
+}
+
+// FindMessage returns the position of a message in messages.
+func FindMessage(messages []llm.Message, messageID string) (int, error) {
+	idx := indexOfMessage(messages, messageID)
+	if idx < 0 {
+		return -1, errorRegistry.New(ErrMessageNotFound).WithDetail("message_id", messageID)
+	}
+	return idx, nil
+}
+
+// indexOfMessage returns the position of a message ID in messages, or -1.
+func indexOfMessage(messages []llm.Message, messageID string) int {
+	for i, m := range messages {
+		if MessageID(m) == messageID {
+			return i
+		}
+	}
+	return -1
+}
+
+func newBranch(name, parentID, forkedAt string) Branch {
+	id := uuid.NewString()
+	if name == "" {
+		name = id[:8]
+	}
+	return Branch{
+		ID:        id,
+		Name:      name,
+		ParentID:  parentID,
+		ForkedAt:  forkedAt,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,191 @@
+package memoryx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/memoryx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// fakeConversationStore is an in-memory memoryx.ConversationStore.
+type fakeConversationStore struct {
+	branches map[string][]memoryx.Branch
+	messages map[string][]llm.Message // key: conversation/branch
+	active   map[string]string
+}
+
+func newFakeConversationStore() *fakeConversationStore {
+	return &fakeConversationStore{
+		branches: map[string][]memoryx.Branch{},
+		messages: map[string][]llm.Message{},
+		active:   map[string]string{},
+	}
+}
+
+func (s *fakeConversationStore) CreateBranch(_ context.Context, conv string, b memoryx.Branch, msgs []llm.Message) error {
+	s.branches[conv] = append(s.branches[conv], b)
+	s.messages[conv+"/"+b.ID] = append([]llm.Message(nil), msgs...)
+	return nil
+}
+
+func (s *fakeConversationStore) ListBranches(_ context.Context, conv string) ([]memoryx.Branch, error) {
+	out := append([]memoryx.Branch(nil), s.branches[conv]...)
+	for i := range out {
+		out[i].Messages = len(s.messages[conv+"/"+out[i].ID])
+	}
+	return out, nil
+}
+
+func (s *fakeConversationStore) LoadMessages(_ context.Context, conv, branch string) ([]llm.Message, error) {
+	return append([]llm.Message(nil), s.messages[conv+"/"+branch]...), nil
+}
+
+func (s *fakeConversationStore) AppendMessages(_ context.Context, conv, branch string, msgs ...llm.Message) error {
+	s.messages[conv+"/"+branch] = append(s.messages[conv+"/"+branch], msgs...)
+	return nil
+}
+
+func (s *fakeConversationStore) TruncateMessages(_ context.Context, conv, branch string, keep int) error {
+	s.messages[conv+"/"+branch] = s.messages[conv+"/"+branch][:keep]
+	return nil
+}
+
+func (s *fakeConversationStore) ActiveBranch(_ context.Context, conv string) (string, error) {
+	return s.active[conv], nil
+}
+
+func (s *fakeConversationStore) SetActiveBranch(_ context.Context, conv, branch string) error {
+	s.active[conv] = branch
+	return nil
+}
+
+// runBranchingScenario exercises the BranchingMemory contract
+func runBranchingScenario(t *testing.T, mem memoryx.BranchingMemory) {
+	t.Helper()
+
+	mem.Add(llm.NewUserMessage("first question"))
+	mem.Add(llm.NewAssistantMessage("first answer"))
+	mem.Add(llm.NewUserMessage("second question"))
+	mem.Add(llm.NewAssistantMessage("second answer"))
+
+	msgs, _ := mem.Messages()
+	if len(msgs) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(msgs))
+	}
+	for _, m := range msgs {
+		if memoryx.MessageID(m) == "" {
+			t.Fatalf("expected every message to have an ID: %+v", m)
+		}
+	}
+
+	// Edit the second question: fork at it and add the edited version
+	editID := memoryx.MessageID(msgs[3])
+	branch, err := mem.Fork(editID, "edit")
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if branch.ParentID != memoryx.MainBranch || branch.ForkedAt != editID || branch.Messages != 3 {
+		t.Fatalf("unexpected branch: %+v", branch)
+	}
+	mem.Add(llm.NewUserMessage("second question, edited"))
+
+	forked, _ := mem.Messages()
+	if len(forked) != 4 || forked[3].Content != "second question, edited" {
+		t.Fatalf("unexpected forked branch: %+v", forked)
+	}
+
+	branches, _ := mem.Branches()
+	if len(branches) != 2 {
+		t.Fatalf("expected 2 branches, got %d", len(branches))
+	}
+	current, _ := mem.CurrentBranch()
+	if current.ID != branch.ID {
+		t.Fatalf("expected fork to become current, got %s", current.ID)
+	}
+
+	// The original conversation is untouched
+	if err := mem.SwitchBranch(memoryx.MainBranch); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	original, _ := mem.Messages()
+	if len(original) != 5 || original[4].Content != "second answer" {
+		t.Fatalf("expected original branch intact, got %+v", original)
+	}
+
+	// Truncate drops the message and everything after it
+	if err := mem.Truncate(memoryx.MessageID(original[2])); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	truncated, _ := mem.Messages()
+	if len(truncated) != 2 {
+		t.Fatalf("expected 2 messages after truncate, got %d", len(truncated))
+	}
+
+	if err := mem.Truncate("missing"); !hasCode(err, memoryx.ErrMessageNotFound) {
+		t.Fatalf("expected message not found, got %v", err)
+	}
+	if err := mem.SwitchBranch("missing"); !hasCode(err, memoryx.ErrBranchNotFound) {
+		t.Fatalf("expected branch not found, got %v", err)
+	}
+
+	// Clear keeps the system prompt on the current branch
+	mem.Clear()
+	cleared, _ := mem.Messages()
+	if len(cleared) != 1 || cleared[0].Role != llm.RoleSystem {
+		t.Fatalf("expected only system prompt after clear, got %+v", cleared)
+	}
+}
+
+func TestInMemoryMemory_Branching(t *testing.T) {
+	runBranchingScenario(t, memoryx.NewInMemoryMemory("system"))
+}
+
+func TestStoredMemory_Branching(t *testing.T) {
+	runBranchingScenario(t, memoryx.NewStoredMemory(newFakeConversationStore(), "conv-1", "system"))
+}
+
+func TestStoredMemory_ResumesActiveBranch(t *testing.T) {
+	store := newFakeConversationStore()
+
+	first := memoryx.NewStoredMemory(store, "conv-1", "system")
+	first.Add(llm.NewUserMessage("hello"))
+	first.Fork("", "copy")
+	first.Add(llm.NewUserMessage("on the copy"))
+
+	resumed := memoryx.NewStoredMemory(store, "conv-1", "system")
+	msgs, err := resumed.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 || msgs[2].Content != "on the copy" {
+		t.Fatalf("expected to resume on the forked branch, got %+v", msgs)
+	}
+}
+
+func TestAsBranching_UnwrapsMemoryStack(t *testing.T) {
+	base := memoryx.NewInMemoryMemory("system")
+	stacked := memoryx.NewTokenBudgetMemory(memoryx.NewWindowMemory(base, 5), 1000)
+
+	mem, ok := memoryx.AsBranching(stacked)
+	if !ok || mem != base {
+		t.Fatal("expected to find the branching base memory")
+	}
+
+	if _, err := memoryx.RequireBranching(&mockMemory{}); !hasCode(err, memoryx.ErrBranchingUnsupported) {
+		t.Fatalf("expected branching unsupported, got %v", err)
+	}
+}
+
+// mockMemory is a Memory without branching support.
+type mockMemory struct{}
+
+func (m *mockMemory) Messages() ([]llm.Message, error) { return nil, nil }
+func (m *mockMemory) Add(llm.Message) error            { return nil }
+func (m *mockMemory) Clear() error                     { return nil }
+
+func hasCode(err error, code *errx.ErrorCode) bool {
+	var e *errx.Error
+	return errx.As(err, &e) && e.Code == code.Code
+}
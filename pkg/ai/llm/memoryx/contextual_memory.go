@@ -161,6 +161,11 @@ func (c *ContextualMemory) Clear() error {
 	return c.inner.Clear()
 }
 
+// Unwrap returns the wrapped memory.
+func (c *ContextualMemory) Unwrap() Memory {
+	return c.inner
+}
+
 // ClearAll resets both the inner memory and deletes all vectors in the namespace.
 func (c *ContextualMemory) ClearAll(ctx context.Context) error {
 	if err := c.inner.Clear(); err != nil {
@@ -22,6 +22,15 @@
 //	    memoryx.WithRecentToKeep(6),
 //	)
 //
+// [WindowMemory] keeps only the last N turns and [TokenBudgetMemory] drops the
+// oldest messages until the conversation fits a token budget. Both are cheap
+// alternatives to summarization and never separate an assistant tool-call
+// message from its tool results.
+//
+//	mem := memoryx.NewTokenBudgetMemory(base, 128000,
+//	    memoryx.WithReserveTokens(4000),
+//	)
+//
 // [ContextualMemory] wraps any Memory and augments it with semantic retrieval
 // from a vector store. Every message is embedded and stored. On Messages(),
 // it retrieves the most relevant past messages and injects them as context.
@@ -32,6 +41,32 @@
 //	    memoryx.WithContextMinScore(0.7),
 //	)
 //
+// [UserMemory] wraps any Memory with long-term memory about a user. An LLM
+// distills conversations into durable facts and preferences, reconciles them
+// with what is already known (add, update, delete), and stores them per
+// tenant and user in a [FactStore]. Relevant facts are injected into future
+// sessions.
+//
+//	mem := memoryx.NewUserMemory(base, memoryx.NewFactStore(docStore),
+//	    memoryx.NewFactExtractor(cheapLLM, ""),
+//	    memoryx.UserScope{TenantID: tenantID, UserID: userID},
+//	)
+//
+// # Branching
+//
+// Every message added to a [BranchingMemory] gets an ID (see [MessageID]).
+// Truncate and Fork cut a conversation at a message, so a user can edit an
+// earlier message or regenerate a response while the original stays on its
+// own branch. [InMemoryMemory] keeps branches in process; [StoredMemory]
+// persists them through a [ConversationStore] so a conversation can be
+// resumed on its active branch. Wrapping memories expose the memory they
+// wrap via [Unwrapper], and [AsBranching] finds the branching memory in a
+// stack:
+//
+//	mem := memoryx.NewStoredMemory(store, conversationID, "You are a helpful assistant.")
+//	agent := agentx.New(client, memoryx.NewWindowMemory(mem, 8))
+//	answer, err := agent.Edit(ctx, messageID, "What about next week?")
+//
 // # Composition
 //
 // Implementations are designed to be stacked:
@@ -0,0 +1,40 @@
+package memoryx
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for conversation memory
+	errorRegistry = errx.NewRegistry("MEMORY")
+
+	ErrMessageNotFound = errorRegistry.Register(
+		"MESSAGE_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Message not found in the current branch",
+	)
+
+	ErrBranchNotFound = errorRegistry.Register(
+		"BRANCH_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Conversation branch not found",
+	)
+
+	ErrBranchingUnsupported = errorRegistry.Register(
+		"BRANCHING_UNSUPPORTED",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Memory does not support branching",
+	)
+
+	ErrStoreFailed = errorRegistry.Register(
+		"STORE_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Conversation store operation failed",
+	)
+)
@@ -1,6 +1,7 @@
 package memoryx
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
@@ -8,16 +9,29 @@ import (
 
 // InMemoryMemory is a simple in-memory implementation of the Memory interface.
 // It stores messages in a slice and preserves the system prompt on Clear().
+// It also implements BranchingMemory: every message gets an ID and the
+// conversation can be truncated, forked and switched between branches.
 type InMemoryMemory struct {
 	mu       sync.RWMutex
+	messages []llm.Message // current branch
+	current  *Branch
+	branches map[string]*inMemoryBranch
+}
+
+type inMemoryBranch struct {
+	info     Branch
 	messages []llm.Message
 }
 
 // NewInMemoryMemory creates a new in-memory memory, optionally with a system prompt.
 func NewInMemoryMemory(systemPrompt ...string) *InMemoryMemory {
-	m := &InMemoryMemory{}
+	main := &inMemoryBranch{info: Branch{ID: MainBranch, Name: MainBranch}}
+	m := &InMemoryMemory{
+		current:  &main.info,
+		branches: map[string]*inMemoryBranch{MainBranch: main},
+	}
 	if len(systemPrompt) > 0 && systemPrompt[0] != "" {
-		m.messages = []llm.Message{llm.NewSystemMessage(systemPrompt[0])}
+		m.messages = []llm.Message{withMessageID(llm.NewSystemMessage(systemPrompt[0]))}
 	}
 	return m
 }
@@ -33,7 +47,7 @@ func (m *InMemoryMemory) Messages() ([]llm.Message, error) {
 func (m *InMemoryMemory) Add(message llm.Message) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.messages = append(m.messages, message)
+	m.messages = append(m.messages, withMessageID(message))
 	return nil
 }
 
@@ -54,3 +68,89 @@ func (m *InMemoryMemory) Clear() error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Branching
+// ============================================================================
+
+func (m *InMemoryMemory) Truncate(messageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := indexOfMessage(m.messages, messageID)
+	if idx < 0 {
+		return errorRegistry.New(ErrMessageNotFound).WithDetail("message_id", messageID)
+	}
+	m.messages = m.messages[:idx:idx]
+	return nil
+}
+
+func (m *InMemoryMemory) Fork(messageID, name string) (Branch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	end := len(m.messages)
+	if messageID != "" {
+		end = indexOfMessage(m.messages, messageID)
+		if end < 0 {
+			return Branch{}, errorRegistry.New(ErrMessageNotFound).WithDetail("message_id", messageID)
+		}
+	}
+
+	m.saveCurrent()
+
+	branch := &inMemoryBranch{info: newBranch(name, m.current.ID, messageID)}
+	m.branches[branch.info.ID] = branch
+	m.current = &branch.info
+	m.messages = append([]llm.Message(nil), m.messages[:end]...)
+	return m.branchInfo(branch), nil
+}
+
+func (m *InMemoryMemory) Branches() ([]Branch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]Branch, 0, len(m.branches))
+	for _, b := range m.branches {
+		out = append(out, m.branchInfo(b))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+func (m *InMemoryMemory) CurrentBranch() (Branch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.branchInfo(m.branches[m.current.ID]), nil
+}
+
+func (m *InMemoryMemory) SwitchBranch(branchID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	target, ok := m.branches[branchID]
+	if !ok {
+		return errorRegistry.New(ErrBranchNotFound).WithDetail("branch_id", branchID)
+	}
+	m.saveCurrent()
+	m.current = &target.info
+	m.messages = append([]llm.Message(nil), target.messages...)
+	return nil
+}
+
+// saveCurrent stores the working messages back into the current branch
+func (m *InMemoryMemory) saveCurrent() {
+	m.branches[m.current.ID].messages = append([]llm.Message(nil), m.messages...)
+}
+
+func (m *InMemoryMemory) branchInfo(b *inMemoryBranch) Branch {
+	info := b.info
+	if b.info.ID == m.current.ID {
+		info.Messages = len(m.messages)
+	} else {
+		info.Messages = len(b.messages)
+	}
+	return info
+}
@@ -0,0 +1,195 @@
+package memoryxinfra
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/memoryx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresConversationStore is the PostgreSQL implementation of
+// memoryx.ConversationStore (see migrations/002_conversation_memory.up.sql)
+type PostgresConversationStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresConversationStore creates a new conversation store
+func NewPostgresConversationStore(db *sqlx.DB) memoryx.ConversationStore {
+	return &PostgresConversationStore{
+		db: db,
+	}
+}
+
+// CreateBranch stores a branch and its initial messages in one transaction
+func (r *PostgresConversationStore) CreateBranch(ctx context.Context, conversationID string, branch memoryx.Branch, messages []llm.Message) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO conversation_branches (conversation_id, id, name, parent_id, forked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	if _, err := tx.ExecContext(ctx, query,
+		conversationID, branch.ID, branch.Name, branch.ParentID, branch.ForkedAt, branch.CreatedAt,
+	); err != nil {
+		return errx.Wrap(err, "failed to create conversation branch", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID).
+			WithDetail("branch_id", branch.ID)
+	}
+
+	if err := insertMessages(ctx, tx, conversationID, branch.ID, 0, messages); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errx.Wrap(err, "failed to commit transaction", errx.TypeInternal)
+	}
+	return nil
+}
+
+// ListBranches returns the branches of a conversation with their sizes
+func (r *PostgresConversationStore) ListBranches(ctx context.Context, conversationID string) ([]memoryx.Branch, error) {
+	query := `
+		SELECT
+			b.id, b.name, b.parent_id, b.forked_at, b.created_at,
+			(SELECT COUNT(*) FROM conversation_messages m
+			 WHERE m.conversation_id = b.conversation_id AND m.branch_id = b.id) AS messages
+		FROM conversation_branches b
+		WHERE b.conversation_id = $1
+		ORDER BY b.created_at ASC`
+
+	var branches []memoryx.Branch
+	if err := r.db.SelectContext(ctx, &branches, query, conversationID); err != nil {
+		return nil, errx.Wrap(err, "failed to list conversation branches", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+	return branches, nil
+}
+
+// LoadMessages returns the messages of a branch in order
+func (r *PostgresConversationStore) LoadMessages(ctx context.Context, conversationID, branchID string) ([]llm.Message, error) {
+	query := `
+		SELECT message
+		FROM conversation_messages
+		WHERE conversation_id = $1 AND branch_id = $2
+		ORDER BY position ASC`
+
+	var rows [][]byte
+	if err := r.db.SelectContext(ctx, &rows, query, conversationID, branchID); err != nil {
+		return nil, errx.Wrap(err, "failed to load conversation messages", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID).
+			WithDetail("branch_id", branchID)
+	}
+
+	messages := make([]llm.Message, len(rows))
+	for i, row := range rows {
+		if err := json.Unmarshal(row, &messages[i]); err != nil {
+			return nil, errx.Wrap(err, "failed to decode conversation message", errx.TypeInternal).
+				WithDetail("conversation_id", conversationID).
+				WithDetail("position", i)
+		}
+	}
+	return messages, nil
+}
+
+// AppendMessages adds messages after the last position of a branch
+func (r *PostgresConversationStore) AppendMessages(ctx context.Context, conversationID, branchID string, messages ...llm.Message) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errx.Wrap(err, "failed to begin transaction", errx.TypeInternal)
+	}
+	defer tx.Rollback()
+
+	var next int
+	query := `
+		SELECT COALESCE(MAX(position) + 1, 0)
+		FROM conversation_messages
+		WHERE conversation_id = $1 AND branch_id = $2`
+	if err := tx.GetContext(ctx, &next, query, conversationID, branchID); err != nil {
+		return errx.Wrap(err, "failed to get next message position", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+
+	if err := insertMessages(ctx, tx, conversationID, branchID, next, messages); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errx.Wrap(err, "failed to commit transaction", errx.TypeInternal)
+	}
+	return nil
+}
+
+// TruncateMessages keeps only the first keep messages of a branch
+func (r *PostgresConversationStore) TruncateMessages(ctx context.Context, conversationID, branchID string, keep int) error {
+	query := `
+		DELETE FROM conversation_messages
+		WHERE conversation_id = $1 AND branch_id = $2 AND position >= $3`
+
+	if _, err := r.db.ExecContext(ctx, query, conversationID, branchID, keep); err != nil {
+		return errx.Wrap(err, "failed to truncate conversation", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID).
+			WithDetail("branch_id", branchID)
+	}
+	return nil
+}
+
+// ActiveBranch returns the current branch, or "" for unknown conversations
+func (r *PostgresConversationStore) ActiveBranch(ctx context.Context, conversationID string) (string, error) {
+	query := `SELECT active_branch_id FROM conversations WHERE id = $1`
+
+	var branchID string
+	err := r.db.GetContext(ctx, &branchID, query, conversationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", errx.Wrap(err, "failed to get active branch", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID)
+	}
+	return branchID, nil
+}
+
+// SetActiveBranch records the current branch, creating the conversation row if needed
+func (r *PostgresConversationStore) SetActiveBranch(ctx context.Context, conversationID, branchID string) error {
+	query := `
+		INSERT INTO conversations (id, active_branch_id, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (id)
+		DO UPDATE SET active_branch_id = EXCLUDED.active_branch_id, updated_at = NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query, conversationID, branchID); err != nil {
+		return errx.Wrap(err, "failed to set active branch", errx.TypeInternal).
+			WithDetail("conversation_id", conversationID).
+			WithDetail("branch_id", branchID)
+	}
+	return nil
+}
+
+func insertMessages(ctx context.Context, tx *sqlx.Tx, conversationID, branchID string, start int, messages []llm.Message) error {
+	query := `
+		INSERT INTO conversation_messages (conversation_id, branch_id, position, message_id, role, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+
+	for i, msg := range messages {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return errx.Wrap(err, "failed to encode conversation message", errx.TypeInternal)
+		}
+		if _, err := tx.ExecContext(ctx, query,
+			conversationID, branchID, start+i, memoryx.MessageID(msg), msg.Role, data,
+		); err != nil {
+			return errx.Wrap(err, "failed to insert conversation message", errx.TypeInternal).
+				WithDetail("conversation_id", conversationID).
+				WithDetail("branch_id", branchID)
+		}
+	}
+	return nil
+}
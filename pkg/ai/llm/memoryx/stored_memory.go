@@ -0,0 +1,281 @@
+package memoryx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ConversationStore persists branched conversations. Messages are ordered
+// by position within a branch; forking copies the kept prefix into the new
+// branch so every branch can be loaded with a single query.
+type ConversationStore interface {
+	// CreateBranch stores a branch together with its initial messages.
+	CreateBranch(ctx context.Context, conversationID string, branch Branch, messages []llm.Message) error
+
+	// ListBranches returns the branches of a conversation, oldest first.
+	ListBranches(ctx context.Context, conversationID string) ([]Branch, error)
+
+	// LoadMessages returns the messages of a branch in order.
+	LoadMessages(ctx context.Context, conversationID, branchID string) ([]llm.Message, error)
+
+	// AppendMessages adds messages to the end of a branch.
+	AppendMessages(ctx context.Context, conversationID, branchID string, messages ...llm.Message) error
+
+	// TruncateMessages keeps only the first keep messages of a branch.
+	TruncateMessages(ctx context.Context, conversationID, branchID string, keep int) error
+
+	// ActiveBranch returns the branch the conversation was last switched to,
+	// or "" if the conversation does not exist.
+	ActiveBranch(ctx context.Context, conversationID string) (string, error)
+
+	// SetActiveBranch records the current branch of a conversation.
+	SetActiveBranch(ctx context.Context, conversationID, branchID string) error
+}
+
+// StoredMemory is a BranchingMemory backed by a ConversationStore, so
+// conversations and their branches survive restarts. The current branch is
+// cached in memory after the first load; a StoredMemory should be the only
+// writer of its conversation.
+type StoredMemory struct {
+	mu sync.Mutex
+
+	store          ConversationStore
+	conversationID string
+	systemPrompt   string
+
+	loaded   bool
+	branchID string
+	messages []llm.Message
+}
+
+// NewStoredMemory creates a memory for a conversation. The system prompt,
+// if given, is stored as the first message when the conversation is created.
+//
+// Example:
+//
+//	store := memoryxinfra.NewPostgresConversationStore(db)
+//	mem := memoryx.NewStoredMemory(store, conversationID, "You are a helpful assistant.")
+func NewStoredMemory(store ConversationStore, conversationID string, systemPrompt ...string) *StoredMemory {
+	s := &StoredMemory{store: store, conversationID: conversationID}
+	if len(systemPrompt) > 0 {
+		s.systemPrompt = systemPrompt[0]
+	}
+	return s
+}
+
+// ConversationID returns the ID of the stored conversation.
+func (s *StoredMemory) ConversationID() string {
+	return s.conversationID
+}
+
+func (s *StoredMemory) Messages() ([]llm.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(context.Background()); err != nil {
+		return nil, err
+	}
+	out := make([]llm.Message, len(s.messages))
+	copy(out, s.messages)
+	return out, nil
+}
+
+func (s *StoredMemory) Add(message llm.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	message = withMessageID(message)
+	if err := s.store.AppendMessages(ctx, s.conversationID, s.branchID, message); err != nil {
+		return storeError(err, s.conversationID)
+	}
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// Clear removes the current branch's messages, keeping the system prompt.
+func (s *StoredMemory) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	keep := 0
+	if len(s.messages) > 0 && s.messages[0].Role == llm.RoleSystem {
+		keep = 1
+	}
+	return s.truncate(ctx, keep)
+}
+
+func (s *StoredMemory) Truncate(messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	idx := indexOfMessage(s.messages, messageID)
+	if idx < 0 {
+		return errorRegistry.New(ErrMessageNotFound).WithDetail("message_id", messageID)
+	}
+	return s.truncate(ctx, idx)
+}
+
+func (s *StoredMemory) Fork(messageID, name string) (Branch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return Branch{}, err
+	}
+
+	end := len(s.messages)
+	if messageID != "" {
+		end = indexOfMessage(s.messages, messageID)
+		if end < 0 {
+			return Branch{}, errorRegistry.New(ErrMessageNotFound).WithDetail("message_id", messageID)
+		}
+	}
+
+	branch := newBranch(name, s.branchID, messageID)
+	kept := append([]llm.Message(nil), s.messages[:end]...)
+	if err := s.store.CreateBranch(ctx, s.conversationID, branch, kept); err != nil {
+		return Branch{}, storeError(err, s.conversationID)
+	}
+	if err := s.store.SetActiveBranch(ctx, s.conversationID, branch.ID); err != nil {
+		return Branch{}, storeError(err, s.conversationID)
+	}
+
+	s.branchID = branch.ID
+	s.messages = kept
+	branch.Messages = len(kept)
+	return branch, nil
+}
+
+func (s *StoredMemory) Branches() ([]Branch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	branches, err := s.store.ListBranches(ctx, s.conversationID)
+	if err != nil {
+		return nil, storeError(err, s.conversationID)
+	}
+	return branches, nil
+}
+
+func (s *StoredMemory) CurrentBranch() (Branch, error) {
+	branches, err := s.Branches()
+	if err != nil {
+		return Branch{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, b := range branches {
+		if b.ID == s.branchID {
+			return b, nil
+		}
+	}
+	return Branch{}, errorRegistry.New(ErrBranchNotFound).WithDetail("branch_id", s.branchID)
+}
+
+func (s *StoredMemory) SwitchBranch(branchID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := context.Background()
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	branches, err := s.store.ListBranches(ctx, s.conversationID)
+	if err != nil {
+		return storeError(err, s.conversationID)
+	}
+	found := false
+	for _, b := range branches {
+		found = found || b.ID == branchID
+	}
+	if !found {
+		return errorRegistry.New(ErrBranchNotFound).WithDetail("branch_id", branchID)
+	}
+
+	messages, err := s.store.LoadMessages(ctx, s.conversationID, branchID)
+	if err != nil {
+		return storeError(err, s.conversationID)
+	}
+	if err := s.store.SetActiveBranch(ctx, s.conversationID, branchID); err != nil {
+		return storeError(err, s.conversationID)
+	}
+	s.branchID = branchID
+	s.messages = messages
+	return nil
+}
+
+// load reads the active branch once, creating the conversation if needed
+func (s *StoredMemory) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+
+	branchID, err := s.store.ActiveBranch(ctx, s.conversationID)
+	if err != nil {
+		return storeError(err, s.conversationID)
+	}
+
+	if branchID == "" {
+		var initial []llm.Message
+		if s.systemPrompt != "" {
+			initial = []llm.Message{withMessageID(llm.NewSystemMessage(s.systemPrompt))}
+		}
+		main := Branch{ID: MainBranch, Name: MainBranch}
+		if err := s.store.CreateBranch(ctx, s.conversationID, main, initial); err != nil {
+			return storeError(err, s.conversationID)
+		}
+		if err := s.store.SetActiveBranch(ctx, s.conversationID, MainBranch); err != nil {
+			return storeError(err, s.conversationID)
+		}
+		s.branchID = MainBranch
+		s.messages = initial
+		s.loaded = true
+		return nil
+	}
+
+	messages, err := s.store.LoadMessages(ctx, s.conversationID, branchID)
+	if err != nil {
+		return storeError(err, s.conversationID)
+	}
+	s.branchID = branchID
+	s.messages = messages
+	s.loaded = true
+	return nil
+}
+
+func (s *StoredMemory) truncate(ctx context.Context, keep int) error {
+	if err := s.store.TruncateMessages(ctx, s.conversationID, s.branchID, keep); err != nil {
+		return storeError(err, s.conversationID)
+	}
+	s.messages = s.messages[:keep:keep]
+	return nil
+}
+
+func storeError(err error, conversationID string) error {
+	return errorRegistry.NewWithCause(ErrStoreFailed, err).
+		WithDetail("conversation_id", conversationID)
+}
@@ -126,6 +126,11 @@ func (s *SummarizingMemory) Clear() error {
 	return s.inner.Clear()
 }
 
+// Unwrap returns the wrapped memory.
+func (s *SummarizingMemory) Unwrap() Memory {
+	return s.inner
+}
+
 // Messages returns the message list, performing summarization if the token
 // estimate exceeds MaxTokens. The returned slice always starts with the system
 // prompt (if present), followed by an optional summary message, followed by
@@ -157,6 +162,13 @@ func (s *SummarizingMemory) Messages() ([]llm.Message, error) {
 
 	// Split: [toSummarize... | recentToKeep...]
 	splitIdx := len(conversation) - s.RecentToKeep
+	// Don't separate tool results from the assistant message that called them
+	for splitIdx > 0 && conversation[splitIdx].Role == llm.RoleTool {
+		splitIdx--
+	}
+	if splitIdx == 0 {
+		return messages, nil
+	}
 	toSummarize := conversation[:splitIdx]
 	recent := conversation[splitIdx:]
 
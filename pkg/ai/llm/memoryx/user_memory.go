@@ -0,0 +1,712 @@
+package memoryx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// User Facts
+// ============================================================================
+
+// UserScope identifies whose long-term memory is read and written.
+type UserScope struct {
+	TenantID kernel.TenantID
+	UserID   kernel.UserID
+}
+
+// FactCategory classifies a remembered fact.
+type FactCategory string
+
+const (
+	FactCategoryFact       FactCategory = "fact"
+	FactCategoryPreference FactCategory = "preference"
+)
+
+// UserFact is a durable piece of knowledge about a user.
+type UserFact struct {
+	ID        string       `json:"id"`
+	Content   string       `json:"content"`
+	Category  FactCategory `json:"category"`
+	TenantID  string       `json:"tenant_id"`
+	UserID    string       `json:"user_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	// Score is the similarity to the query when the fact was retrieved.
+	Score float32 `json:"score,omitempty"`
+}
+
+const userFactType = "user_fact"
+
+// ============================================================================
+// Fact Store
+// ============================================================================
+
+// FactStore persists user facts in a vector store. Facts of all users share
+// the document store's namespace and are isolated by tenant and user metadata
+// filters, so the vector store must support metadata filtering.
+type FactStore struct {
+	docStore *document.DocumentStore
+}
+
+// NewFactStore creates a fact store on top of a document store.
+func NewFactStore(docStore *document.DocumentStore) *FactStore {
+	return &FactStore{docStore: docStore}
+}
+
+// Search returns the user's facts most relevant to the query.
+func (s *FactStore) Search(ctx context.Context, scope UserScope, query string, topK int, minScore float32) ([]UserFact, error) {
+	result, err := s.docStore.Search(ctx, document.SearchRequest{
+		Query:    query,
+		TopK:     topK,
+		MinScore: minScore,
+		Filter:   scopeFilter(scope),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user facts: %w", err)
+	}
+
+	facts := make([]UserFact, 0, len(result.Documents))
+	for i, doc := range result.Documents {
+		fact := documentToFact(doc)
+		// Guard against stores that ignore filters
+		if fact.TenantID != scope.TenantID.String() || fact.UserID != scope.UserID.String() {
+			continue
+		}
+		fact.Score = result.Scores[i]
+		facts = append(facts, fact)
+	}
+	return facts, nil
+}
+
+// Save inserts or replaces facts. Missing IDs and timestamps are filled in.
+func (s *FactStore) Save(ctx context.Context, scope UserScope, facts ...*UserFact) error {
+	now := time.Now().UTC()
+	docs := make([]*document.Document, len(facts))
+	for i, fact := range facts {
+		if fact.ID == "" {
+			fact.ID = uuid.NewString()
+		}
+		if fact.Category == "" {
+			fact.Category = FactCategoryFact
+		}
+		if fact.CreatedAt.IsZero() {
+			fact.CreatedAt = now
+		}
+		fact.UpdatedAt = now
+		fact.TenantID = scope.TenantID.String()
+		fact.UserID = scope.UserID.String()
+		docs[i] = factToDocument(fact)
+	}
+	return s.docStore.AddDocuments(ctx, docs)
+}
+
+// Delete removes facts by ID.
+func (s *FactStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.docStore.DeleteDocuments(ctx, ids)
+}
+
+func scopeFilter(scope UserScope) *vstore.Filter {
+	return vstore.NewFilter().
+		AddMust("type", vstore.OpEqual, userFactType).
+		AddMust("tenant_id", vstore.OpEqual, scope.TenantID.String()).
+		AddMust(document.MetadataUserID, vstore.OpEqual, scope.UserID.String())
+}
+
+func factToDocument(fact *UserFact) *document.Document {
+	return document.NewDocument(fact.Content).
+		WithID(fact.ID).
+		WithMetadata("type", userFactType).
+		WithMetadata("tenant_id", fact.TenantID).
+		WithMetadata(document.MetadataUserID, fact.UserID).
+		WithMetadata(document.MetadataCategory, string(fact.Category)).
+		WithMetadata(document.MetadataCreatedAt, fact.CreatedAt.Format(time.RFC3339)).
+		WithMetadata(document.MetadataUpdatedAt, fact.UpdatedAt.Format(time.RFC3339))
+}
+
+func documentToFact(doc *document.Document) UserFact {
+	fact := UserFact{ID: doc.ID, Content: doc.Content}
+	if v, ok := doc.GetMetadataString("tenant_id"); ok {
+		fact.TenantID = v
+	}
+	if v, ok := doc.GetMetadataString(document.MetadataUserID); ok {
+		fact.UserID = v
+	}
+	if v, ok := doc.GetMetadataString(document.MetadataCategory); ok {
+		fact.Category = FactCategory(v)
+	}
+	if v, ok := doc.GetMetadataString(document.MetadataCreatedAt); ok {
+		fact.CreatedAt, _ = time.Parse(time.RFC3339, v)
+	}
+	if v, ok := doc.GetMetadataString(document.MetadataUpdatedAt); ok {
+		fact.UpdatedAt, _ = time.Parse(time.RFC3339, v)
+	}
+	return fact
+}
+
+// ============================================================================
+// Fact Extraction
+// ============================================================================
+
+const defaultFactExtractionPrompt = `You maintain long-term memory about a user across conversations.
+From the conversation, identify durable facts about the user (name, role, projects, circumstances) and their preferences (style, formats, tools, likes and dislikes).
+Ignore one-off requests, small talk and anything about the assistant itself. Do not store secrets such as passwords or card numbers.
+
+Compare with the existing memories and respond with JSON only:
+{"operations": [
+  {"op": "add", "content": "<fact in third person>", "category": "fact|preference"},
+  {"op": "update", "id": "<existing id>", "content": "<corrected fact>", "category": "fact|preference"},
+  {"op": "delete", "id": "<existing id>"}
+]}
+
+Use "update" when new information refines or contradicts an existing memory and "delete" when a memory is no longer true.
+Never add a memory that is already present. Return {"operations": []} when there is nothing worth remembering.`
+
+// FactOp is the kind of change the extractor proposes.
+type FactOp string
+
+const (
+	FactOpAdd    FactOp = "add"
+	FactOpUpdate FactOp = "update"
+	FactOpDelete FactOp = "delete"
+)
+
+// FactOperation is one change to a user's memory.
+type FactOperation struct {
+	Op       FactOp       `json:"op"`
+	ID       string       `json:"id,omitempty"`
+	Content  string       `json:"content,omitempty"`
+	Category FactCategory `json:"category,omitempty"`
+}
+
+// FactExtractor asks an LLM which facts to add, update or delete given a
+// conversation and the user's existing facts.
+type FactExtractor struct {
+	llm     llm.LLM
+	prompt  string
+	options []llm.Option
+}
+
+// NewFactExtractor creates an extractor. An empty prompt uses the default.
+func NewFactExtractor(llmClient llm.LLM, prompt string, opts ...llm.Option) *FactExtractor {
+	if prompt == "" {
+		prompt = defaultFactExtractionPrompt
+	}
+	return &FactExtractor{llm: llmClient, prompt: prompt, options: opts}
+}
+
+// Extract returns the operations to apply to the existing facts.
+func (e *FactExtractor) Extract(ctx context.Context, conversation []llm.Message, existing []UserFact) ([]FactOperation, error) {
+	var transcript strings.Builder
+	for _, m := range conversation {
+		if m.Role == llm.RoleSystem || m.Role == llm.RoleTool {
+			continue
+		}
+		if text := m.TextContent(); text != "" {
+			fmt.Fprintf(&transcript, "[%s]: %s\n", m.Role, text)
+		}
+	}
+	if transcript.Len() == 0 {
+		return nil, nil
+	}
+
+	var memories strings.Builder
+	if len(existing) == 0 {
+		memories.WriteString("(none)\n")
+	}
+	for _, f := range existing {
+		fmt.Fprintf(&memories, "- id=%s [%s] %s\n", f.ID, f.Category, f.Content)
+	}
+
+	messages := []llm.Message{
+		llm.NewSystemMessage(e.prompt),
+		llm.NewUserMessage(fmt.Sprintf("Existing memories:\n%s\nConversation:\n%s", memories.String(), transcript.String())),
+	}
+
+	resp, err := e.llm.Chat(ctx, messages, e.options...)
+	if err != nil {
+		return nil, fmt.Errorf("fact extraction LLM call failed: %w", err)
+	}
+
+	var out struct {
+		Operations []FactOperation `json:"operations"`
+	}
+	if err := json.Unmarshal([]byte(jsonObject(resp.Message.Content)), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse fact extraction response: %w", err)
+	}
+	return out.Operations, nil
+}
+
+// jsonObject strips code fences and prose around a JSON object.
+func jsonObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
+
+// ============================================================================
+// User Memory
+// ============================================================================
+
+// UserMemory wraps any Memory with long-term, per-user semantic memory.
+// Conversations are periodically distilled by an LLM into durable facts and
+// preferences, which are deduplicated against and reconciled with what is
+// already known, then stored per tenant and user. On Messages(), the facts
+// relevant to the current conversation are injected after the system prompt,
+// so they carry over into future sessions.
+type UserMemory struct {
+	mu sync.Mutex
+
+	inner     Memory
+	store     *FactStore
+	extractor *FactExtractor
+	scope     UserScope
+
+	// TopK is how many facts to inject. Defaults to 5.
+	TopK int
+
+	// MinScore is the minimum similarity for injected facts. Defaults to 0.0.
+	MinScore float32
+
+	// ExtractEvery runs extraction in the background after this many new
+	// messages. Zero disables automatic extraction; call Extract explicitly.
+	// Defaults to 6.
+	ExtractEvery int
+
+	// ExtractTimeout bounds each background extraction. Defaults to 30s.
+	ExtractTimeout time.Duration
+
+	// SearchTimeout bounds the fact search run by Messages. Defaults to 5s.
+	SearchTimeout time.Duration
+
+	// DedupThreshold is the similarity above which an added fact is treated
+	// as an update of the closest existing one. Defaults to 0.95.
+	DedupThreshold float32
+
+	// FactHeader is the prefix of the injected facts message.
+	FactHeader string
+
+	// OnExtract is an optional callback invoked with the applied operations.
+	OnExtract func(ops []FactOperation)
+
+	pending    []llm.Message
+	extracting bool
+	background sync.WaitGroup
+
+	// facts retrieved for the current user turn, keyed by its query
+	turnQuery string
+	turnFacts []UserFact
+}
+
+// UserMemoryOption configures a UserMemory.
+type UserMemoryOption func(*UserMemory)
+
+// WithUserFactTopK sets how many facts are injected.
+func WithUserFactTopK(k int) UserMemoryOption {
+	return func(u *UserMemory) { u.TopK = k }
+}
+
+// WithUserFactMinScore sets the minimum similarity for injected facts.
+func WithUserFactMinScore(score float32) UserMemoryOption {
+	return func(u *UserMemory) { u.MinScore = score }
+}
+
+// WithExtractEvery sets how many new messages trigger extraction.
+func WithExtractEvery(n int) UserMemoryOption {
+	return func(u *UserMemory) { u.ExtractEvery = n }
+}
+
+// WithExtractTimeout bounds each background extraction.
+func WithExtractTimeout(d time.Duration) UserMemoryOption {
+	return func(u *UserMemory) { u.ExtractTimeout = d }
+}
+
+// WithSearchTimeout bounds the fact search run by Messages.
+func WithSearchTimeout(d time.Duration) UserMemoryOption {
+	return func(u *UserMemory) { u.SearchTimeout = d }
+}
+
+// WithDedupThreshold sets the similarity that turns an add into an update.
+func WithDedupThreshold(threshold float32) UserMemoryOption {
+	return func(u *UserMemory) { u.DedupThreshold = threshold }
+}
+
+// WithFactHeader sets the header of the injected facts message.
+func WithFactHeader(header string) UserMemoryOption {
+	return func(u *UserMemory) { u.FactHeader = header }
+}
+
+// WithOnExtract sets a callback that fires after each extraction.
+func WithOnExtract(fn func(ops []FactOperation)) UserMemoryOption {
+	return func(u *UserMemory) { u.OnExtract = fn }
+}
+
+// NewUserMemory creates a long-term user memory.
+//
+// Example:
+//
+//	facts := memoryx.NewFactStore(docStore)
+//	extractor := memoryx.NewFactExtractor(cheapLLM, "")
+//	scope := memoryx.UserScope{TenantID: tenantID, UserID: userID}
+//
+//	base := memoryx.NewInMemoryMemory("You are a helpful assistant.")
+//	mem := memoryx.NewUserMemory(base, facts, extractor, scope,
+//	    memoryx.WithUserFactTopK(5),
+//	)
+//	defer mem.Extract(ctx) // flush what was learned at the end of the session
+//	defer mem.Wait()       // let a running background extraction finish
+func NewUserMemory(inner Memory, store *FactStore, extractor *FactExtractor, scope UserScope, opts ...UserMemoryOption) *UserMemory {
+	u := &UserMemory{
+		inner:          inner,
+		store:          store,
+		extractor:      extractor,
+		scope:          scope,
+		TopK:           5,
+		ExtractEvery:   6,
+		ExtractTimeout: 30 * time.Second,
+		SearchTimeout:  5 * time.Second,
+		DedupThreshold: 0.95,
+		FactHeader:     "[What you remember about this user]",
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Add stores the message in the inner memory and starts a background
+// extraction once enough new messages have accumulated. Extraction is
+// best-effort.
+func (u *UserMemory) Add(message llm.Message) error {
+	if err := u.inner.Add(message); err != nil {
+		return err
+	}
+	if message.Role == llm.RoleSystem {
+		return nil
+	}
+
+	u.mu.Lock()
+	u.pending = append(u.pending, message)
+	ready := u.ExtractEvery > 0 && len(u.pending) >= u.ExtractEvery
+	u.mu.Unlock()
+
+	if ready {
+		u.extractInBackground()
+	}
+	return nil
+}
+
+// Clear starts a background extraction of the unprocessed messages, then
+// resets the inner memory. Stored facts are kept.
+func (u *UserMemory) Clear() error {
+	u.extractInBackground()
+	return u.inner.Clear()
+}
+
+// Wait blocks until the running background extraction, if any, is done.
+func (u *UserMemory) Wait() {
+	u.background.Wait()
+}
+
+// extractInBackground runs Extract in its own goroutine with a bounded
+// context. Only one runs at a time; messages that arrive meanwhile wait
+// for the next one.
+func (u *UserMemory) extractInBackground() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.extracting || len(u.pending) == 0 {
+		return
+	}
+	u.extracting = true
+	u.background.Add(1)
+
+	go func() {
+		defer u.background.Done()
+		ctx := context.Background()
+		if u.ExtractTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, u.ExtractTimeout)
+			defer cancel()
+		}
+		_ = u.Extract(ctx)
+
+		u.mu.Lock()
+		u.extracting = false
+		u.mu.Unlock()
+	}()
+}
+
+// Unwrap returns the wrapped memory.
+func (u *UserMemory) Unwrap() Memory {
+	return u.inner
+}
+
+// Messages returns the inner messages with the relevant user facts injected
+// after the system prompt. Facts are searched once per user turn, so tool
+// iterations reuse them; a failed or timed out search injects nothing.
+func (u *UserMemory) Messages() ([]llm.Message, error) {
+	messages, err := u.inner.Messages()
+	if err != nil {
+		return nil, err
+	}
+
+	conversation := messages
+	if len(messages) > 0 && messages[0].Role == llm.RoleSystem {
+		conversation = messages[1:]
+	}
+	query := recentUserText(conversation, 3)
+	if query == "" {
+		return messages, nil
+	}
+
+	facts := u.turnFactsFor(query)
+	if len(facts) == 0 {
+		return messages, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(u.FactHeader)
+	sb.WriteString("\n")
+	for _, f := range facts {
+		fmt.Fprintf(&sb, "\n- %s (%s, as of %s)", f.Content, f.Category, f.UpdatedAt.Format("2006-01-02"))
+	}
+
+	factsMsg := llm.Message{
+		Role:    llm.RoleUser,
+		Content: sb.String(),
+		Metadata: map[string]any{
+			"user_memory": true,
+			"fact_count":  len(facts),
+		},
+	}
+
+	result := make([]llm.Message, 0, len(messages)+1)
+	if len(messages) > 0 && messages[0].Role == llm.RoleSystem {
+		result = append(result, messages[0])
+		result = append(result, factsMsg)
+		result = append(result, messages[1:]...)
+	} else {
+		result = append(result, factsMsg)
+		result = append(result, messages...)
+	}
+	return result, nil
+}
+
+// turnFactsFor returns the facts for the query, searching only when the
+// query changed since the last call.
+func (u *UserMemory) turnFactsFor(query string) []UserFact {
+	u.mu.Lock()
+	if query == u.turnQuery {
+		facts := u.turnFacts
+		u.mu.Unlock()
+		return facts
+	}
+	u.mu.Unlock()
+
+	ctx := context.Background()
+	if u.SearchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.SearchTimeout)
+		defer cancel()
+	}
+	facts, err := u.store.Search(ctx, u.scope, query, u.TopK, u.MinScore)
+	if err != nil {
+		facts = nil
+	}
+
+	u.mu.Lock()
+	u.turnQuery = query
+	u.turnFacts = facts
+	u.mu.Unlock()
+	return facts
+}
+
+// Extract distills the messages added since the last extraction into facts
+// and applies the resulting operations to the store.
+func (u *UserMemory) Extract(ctx context.Context) error {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = nil
+	u.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	existing, err := u.store.Search(ctx, u.scope, conversationText(pending), 20, 0)
+	if err != nil {
+		u.requeue(pending)
+		return err
+	}
+
+	ops, err := u.extractor.Extract(ctx, pending, existing)
+	if err != nil {
+		u.requeue(pending)
+		return err
+	}
+
+	applied, err := u.apply(ctx, ops, existing)
+	if err != nil {
+		return err
+	}
+	if u.OnExtract != nil && len(applied) > 0 {
+		u.OnExtract(applied)
+	}
+	return nil
+}
+
+// Facts returns the user's facts most relevant to the query.
+func (u *UserMemory) Facts(ctx context.Context, query string, topK int) ([]UserFact, error) {
+	return u.store.Search(ctx, u.scope, query, topK, 0)
+}
+
+// Forget deletes facts by ID.
+func (u *UserMemory) Forget(ctx context.Context, ids ...string) error {
+	return u.store.Delete(ctx, ids...)
+}
+
+func (u *UserMemory) requeue(messages []llm.Message) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pending = append(messages, u.pending...)
+}
+
+// apply executes the operations, ignoring references to unknown facts and
+// turning near-duplicate adds into updates. It returns the operations that
+// were actually applied.
+func (u *UserMemory) apply(ctx context.Context, ops []FactOperation, existing []UserFact) ([]FactOperation, error) {
+	known := make(map[string]UserFact, len(existing))
+	for _, f := range existing {
+		known[f.ID] = f
+	}
+
+	var applied []FactOperation
+	var toSave []*UserFact
+	var toDelete []string
+
+	for _, op := range ops {
+		content := strings.TrimSpace(op.Content)
+
+		switch op.Op {
+		case FactOpAdd:
+			if content == "" {
+				continue
+			}
+			if dup, ok := u.findDuplicate(ctx, content, existing); ok {
+				op = FactOperation{Op: FactOpUpdate, ID: dup.ID, Content: content, Category: op.Category}
+				fact := dup
+				fact.Content = content
+				if op.Category != "" {
+					fact.Category = op.Category
+				}
+				toSave = append(toSave, &fact)
+				applied = append(applied, op)
+				continue
+			}
+			toSave = append(toSave, &UserFact{Content: content, Category: op.Category})
+			applied = append(applied, op)
+
+		case FactOpUpdate:
+			fact, ok := known[op.ID]
+			if !ok || content == "" {
+				continue
+			}
+			fact.Content = content
+			if op.Category != "" {
+				fact.Category = op.Category
+			}
+			toSave = append(toSave, &fact)
+			applied = append(applied, op)
+
+		case FactOpDelete:
+			if _, ok := known[op.ID]; !ok {
+				continue
+			}
+			toDelete = append(toDelete, op.ID)
+			applied = append(applied, op)
+		}
+	}
+
+	if len(toSave) > 0 {
+		if err := u.store.Save(ctx, u.scope, toSave...); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.store.Delete(ctx, toDelete...); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// findDuplicate looks for an existing fact with the same normalized content
+// or a similarity above DedupThreshold.
+func (u *UserMemory) findDuplicate(ctx context.Context, content string, existing []UserFact) (UserFact, bool) {
+	normalized := normalizeFact(content)
+	for _, f := range existing {
+		if normalizeFact(f.Content) == normalized {
+			return f, true
+		}
+	}
+	if u.DedupThreshold <= 0 {
+		return UserFact{}, false
+	}
+	similar, err := u.store.Search(ctx, u.scope, content, 1, u.DedupThreshold)
+	if err != nil || len(similar) == 0 {
+		return UserFact{}, false
+	}
+	return similar[0], true
+}
+
+func normalizeFact(s string) string {
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(s), " ")), ".")
+}
+
+// recentUserText joins the text of the last n user messages, newest last.
+func recentUserText(conversation []llm.Message, n int) string {
+	var parts []string
+	for i := len(conversation) - 1; i >= 0 && len(parts) < n; i-- {
+		if conversation[i].Role != llm.RoleUser || isInjected(conversation[i]) {
+			continue
+		}
+		if text := conversation[i].TextContent(); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	slices.Reverse(parts)
+	return strings.Join(parts, " ")
+}
+
+// isInjected reports messages added by memory layers rather than the user
+func isInjected(m llm.Message) bool {
+	return m.Metadata["user_memory"] == true || m.Metadata["contextual_memory"] == true
+}
+
+func conversationText(messages []llm.Message) string {
+	var parts []string
+	for _, m := range messages {
+		if m.Role == llm.RoleUser || m.Role == llm.RoleAssistant {
+			if text := m.TextContent(); text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,331 @@
+package memoryx_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/embedding"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/memoryx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstmemory"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+)
+
+// scriptedLLM returns the responses in order and records the prompts it saw.
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (m *scriptedLLM) Chat(_ context.Context, messages []llm.Message, _ ...llm.Option) (llm.Response, error) {
+	m.prompts = append(m.prompts, messages[len(messages)-1].Content)
+	resp := `{"operations": []}`
+	if len(m.responses) > 0 {
+		resp, m.responses = m.responses[0], m.responses[1:]
+	}
+	return llm.Response{Message: llm.NewAssistantMessage(resp)}, nil
+}
+
+func (m *scriptedLLM) ChatStream(_ context.Context, _ []llm.Message, _ ...llm.Option) (llm.Stream, error) {
+	return nil, nil
+}
+
+func newTestFactStore() *memoryx.FactStore {
+	memStore := vstmemory.NewMemoryVectorStore(testDimension, vstore.MetricCosine)
+	docStore := document.NewDocumentStore(vstore.NewClient(memStore), document.NewEmbedder(&deterministicEmbedder{}, testDimension))
+	return memoryx.NewFactStore(docStore)
+}
+
+func newTestUserMemory(store *memoryx.FactStore, mock *scriptedLLM, user string) *memoryx.UserMemory {
+	base := memoryx.NewInMemoryMemory("You are a helpful assistant.")
+	scope := memoryx.UserScope{TenantID: "tenant-1", UserID: kernel.UserID("user-" + user)}
+	return memoryx.NewUserMemory(base, store, memoryx.NewFactExtractor(mock, ""), scope,
+		memoryx.WithExtractEvery(0),
+	)
+}
+
+func TestUserMemory_ExtractsAndInjectsFacts(t *testing.T) {
+	store := newTestFactStore()
+	mock := &scriptedLLM{responses: []string{
+		"```json\n" + `{"operations": [{"op": "add", "content": "User prefers metric units", "category": "preference"}]}` + "\n```",
+	}}
+
+	session1 := newTestUserMemory(store, mock, "a")
+	session1.Add(llm.NewUserMessage("Please always use metric units"))
+	session1.Add(llm.NewAssistantMessage("Sure"))
+	if err := session1.Extract(context.Background()); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+
+	session2 := newTestUserMemory(store, mock, "a")
+	session2.Add(llm.NewUserMessage("How tall is Everest?"))
+
+	msgs, err := session2.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("expected system + facts + user, got %d messages", len(msgs))
+	}
+	if msgs[0].Role != llm.RoleSystem {
+		t.Fatalf("expected system prompt first, got %s", msgs[0].Role)
+	}
+	if msgs[1].Metadata["user_memory"] != true || !strings.Contains(msgs[1].Content, "metric units") {
+		t.Fatalf("expected injected fact, got %q", msgs[1].Content)
+	}
+}
+
+func TestUserMemory_IsolatesUsers(t *testing.T) {
+	store := newTestFactStore()
+	mock := &scriptedLLM{responses: []string{
+		`{"operations": [{"op": "add", "content": "User is allergic to peanuts"}]}`,
+	}}
+
+	alice := newTestUserMemory(store, mock, "alice")
+	alice.Add(llm.NewUserMessage("I'm allergic to peanuts"))
+	if err := alice.Extract(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bob := newTestUserMemory(store, mock, "bob")
+	bob.Add(llm.NewUserMessage("Suggest a snack"))
+	msgs, _ := bob.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected no facts for another user, got %d messages", len(msgs))
+	}
+}
+
+func TestUserMemory_UpdatesAndDeletesFacts(t *testing.T) {
+	store := newTestFactStore()
+	mock := &scriptedLLM{responses: []string{
+		`{"operations": [{"op": "add", "content": "User lives in Lima"}, {"op": "add", "content": "User owns a cat"}]}`,
+	}}
+	mem := newTestUserMemory(store, mock, "a")
+	ctx := context.Background()
+
+	mem.Add(llm.NewUserMessage("I live in Lima with my cat"))
+	if err := mem.Extract(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	facts, _ := mem.Facts(ctx, "where does the user live", 10)
+	if len(facts) != 2 {
+		t.Fatalf("expected 2 facts, got %d", len(facts))
+	}
+	ids := map[string]string{}
+	for _, f := range facts {
+		ids[f.Content] = f.ID
+	}
+
+	mock.responses = []string{
+		`{"operations": [{"op": "update", "id": "` + ids["User lives in Lima"] + `", "content": "User lives in Madrid"}, {"op": "delete", "id": "` + ids["User owns a cat"] + `"}, {"op": "delete", "id": "unknown"}]}`,
+	}
+	var applied []memoryx.FactOperation
+	mem.OnExtract = func(ops []memoryx.FactOperation) { applied = ops }
+
+	mem.Add(llm.NewUserMessage("I moved to Madrid, and sadly my cat passed away"))
+	if err := mem.Extract(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mock.prompts[1], ids["User lives in Lima"]) {
+		t.Fatal("expected existing facts in the extraction prompt")
+	}
+	if len(applied) != 2 {
+		t.Fatalf("expected unknown id to be ignored, got %d applied ops", len(applied))
+	}
+
+	facts, _ = mem.Facts(ctx, "where does the user live", 10)
+	if len(facts) != 1 || facts[0].Content != "User lives in Madrid" {
+		t.Fatalf("expected only the updated fact, got %+v", facts)
+	}
+	if facts[0].ID != ids["User lives in Lima"] {
+		t.Fatalf("expected update in place, got %+v", facts[0])
+	}
+}
+
+func TestUserMemory_DeduplicatesAdds(t *testing.T) {
+	store := newTestFactStore()
+	mock := &scriptedLLM{responses: []string{
+		`{"operations": [{"op": "add", "content": "User is a nurse"}]}`,
+		`{"operations": [{"op": "add", "content": "user is a nurse."}]}`,
+	}}
+	mem := newTestUserMemory(store, mock, "a")
+	ctx := context.Background()
+
+	mem.Add(llm.NewUserMessage("I'm a nurse"))
+	mem.Extract(ctx)
+	mem.Add(llm.NewUserMessage("As a nurse, I work nights"))
+	mem.Extract(ctx)
+
+	facts, _ := mem.Facts(ctx, "nurse", 10)
+	if len(facts) != 1 {
+		t.Fatalf("expected duplicate to be merged, got %d facts", len(facts))
+	}
+}
+
+func TestUserMemory_ExtractsAutomaticallyAndOnClear(t *testing.T) {
+	store := newTestFactStore()
+	mock := &scriptedLLM{}
+	base := memoryx.NewInMemoryMemory("system")
+	mem := memoryx.NewUserMemory(base, store, memoryx.NewFactExtractor(mock, ""),
+		memoryx.UserScope{TenantID: "t", UserID: "u"},
+		memoryx.WithExtractEvery(2),
+	)
+
+	mem.Add(llm.NewUserMessage("one"))
+	if len(mock.prompts) != 0 {
+		t.Fatal("expected no extraction before threshold")
+	}
+	mem.Add(llm.NewAssistantMessage("two"))
+	mem.Wait()
+	if len(mock.prompts) != 1 {
+		t.Fatalf("expected extraction at threshold, got %d calls", len(mock.prompts))
+	}
+
+	mem.Add(llm.NewUserMessage("three"))
+	mem.Clear()
+	mem.Wait()
+	if len(mock.prompts) != 2 {
+		t.Fatalf("expected extraction on clear, got %d calls", len(mock.prompts))
+	}
+	msgs, _ := mem.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected only system prompt after clear, got %d", len(msgs))
+	}
+}
+
+// blockingLLM answers only when its context ends and records the prompts.
+type blockingLLM struct {
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (m *blockingLLM) Chat(ctx context.Context, messages []llm.Message, _ ...llm.Option) (llm.Response, error) {
+	m.mu.Lock()
+	m.prompts = append(m.prompts, messages[len(messages)-1].Content)
+	m.mu.Unlock()
+	<-ctx.Done()
+	return llm.Response{}, ctx.Err()
+}
+
+func (m *blockingLLM) ChatStream(_ context.Context, _ []llm.Message, _ ...llm.Option) (llm.Stream, error) {
+	return nil, nil
+}
+
+func TestUserMemory_BackgroundExtractionIsBounded(t *testing.T) {
+	mock := &blockingLLM{}
+	mem := memoryx.NewUserMemory(memoryx.NewInMemoryMemory("system"), newTestFactStore(),
+		memoryx.NewFactExtractor(mock, ""),
+		memoryx.UserScope{TenantID: "t", UserID: "u"},
+		memoryx.WithExtractEvery(1),
+		memoryx.WithExtractTimeout(50*time.Millisecond),
+	)
+
+	start := time.Now()
+	mem.Add(llm.NewUserMessage("I'm a nurse"))
+	mem.Add(llm.NewUserMessage("I work nights"))
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Fatalf("Add blocked on extraction for %v", elapsed)
+	}
+	mem.Wait()
+	if len(mock.prompts) != 1 {
+		t.Fatalf("expected one extraction at a time, got %d calls", len(mock.prompts))
+	}
+
+	// Messages of the timed out extraction are kept for the next one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := mem.Extract(ctx); err == nil {
+		t.Fatal("expected the blocked extraction to fail")
+	}
+	if last := mock.prompts[len(mock.prompts)-1]; !strings.Contains(last, "nurse") || !strings.Contains(last, "nights") {
+		t.Errorf("expected both messages in the retried extraction, got %q", last)
+	}
+}
+
+// countingEmbedder counts query embeddings and blocks them while block is set.
+type countingEmbedder struct {
+	deterministicEmbedder
+	mu      sync.Mutex
+	queries int
+	block   bool
+}
+
+func (e *countingEmbedder) EmbedQuery(ctx context.Context, text string, opts ...embedding.Option) (embedding.Embedding, error) {
+	e.mu.Lock()
+	e.queries++
+	block := e.block
+	e.mu.Unlock()
+	if block {
+		<-ctx.Done()
+		return embedding.Embedding{}, ctx.Err()
+	}
+	return e.deterministicEmbedder.EmbedQuery(ctx, text, opts...)
+}
+
+func (e *countingEmbedder) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.queries
+}
+
+func newCountingUserMemory(embedder *countingEmbedder, opts ...memoryx.UserMemoryOption) *memoryx.UserMemory {
+	memStore := vstmemory.NewMemoryVectorStore(testDimension, vstore.MetricCosine)
+	docStore := document.NewDocumentStore(vstore.NewClient(memStore), document.NewEmbedder(embedder, testDimension))
+	opts = append([]memoryx.UserMemoryOption{memoryx.WithExtractEvery(0)}, opts...)
+	return memoryx.NewUserMemory(memoryx.NewInMemoryMemory("system"), memoryx.NewFactStore(docStore),
+		memoryx.NewFactExtractor(&scriptedLLM{}, ""),
+		memoryx.UserScope{TenantID: "t", UserID: "u"},
+		opts...,
+	)
+}
+
+func TestUserMemory_SearchesOncePerUserTurn(t *testing.T) {
+	embedder := &countingEmbedder{}
+	mem := newCountingUserMemory(embedder)
+
+	mem.Add(llm.NewUserMessage("What's the weather in Lima?"))
+	mem.Messages()
+	mem.Add(llm.NewAssistantMessage("Let me check"))
+	mem.Messages()
+	mem.Add(llm.NewToolMessage("call-1", "18°C, cloudy"))
+	mem.Messages()
+	if got := embedder.count(); got != 1 {
+		t.Fatalf("expected one search for the turn, got %d", got)
+	}
+
+	mem.Add(llm.NewUserMessage("And tomorrow?"))
+	mem.Messages()
+	if got := embedder.count(); got != 2 {
+		t.Fatalf("expected a new search for the next turn, got %d", got)
+	}
+}
+
+func TestUserMemory_SearchIsBounded(t *testing.T) {
+	embedder := &countingEmbedder{block: true}
+	mem := newCountingUserMemory(embedder, memoryx.WithSearchTimeout(20*time.Millisecond))
+	mem.Add(llm.NewUserMessage("Hello"))
+
+	start := time.Now()
+	msgs, err := mem.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Messages blocked on the fact search for %v", elapsed)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected no facts after a timed out search, got %d messages", len(msgs))
+	}
+
+	// The failed search is not retried within the same turn
+	mem.Messages()
+	if got := embedder.count(); got != 1 {
+		t.Fatalf("expected one search for the turn, got %d", got)
+	}
+}
@@ -0,0 +1,263 @@
+package memoryx
+
+import (
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Window Memory
+// ============================================================================
+
+// WindowMemory wraps any Memory and returns only the last N turns. A turn
+// starts at a user message and includes every assistant and tool message
+// that follows it, so tool calls are never separated from their results.
+//
+// Trimming only affects the view returned by Messages(); the inner memory
+// keeps the full history.
+type WindowMemory struct {
+	inner Memory
+
+	// MaxTurns is the number of recent turns to keep. Defaults to 10.
+	MaxTurns int
+}
+
+// NewWindowMemory wraps an existing Memory with a sliding window of turns.
+//
+// Example:
+//
+//	base := memoryx.NewInMemoryMemory("You are a helpful assistant.")
+//	mem := memoryx.NewWindowMemory(base, 8)
+func NewWindowMemory(inner Memory, maxTurns int) *WindowMemory {
+	if maxTurns <= 0 {
+		maxTurns = 10
+	}
+	return &WindowMemory{inner: inner, MaxTurns: maxTurns}
+}
+
+func (w *WindowMemory) Add(message llm.Message) error {
+	return w.inner.Add(message)
+}
+
+func (w *WindowMemory) Clear() error {
+	return w.inner.Clear()
+}
+
+// Unwrap returns the wrapped memory.
+func (w *WindowMemory) Unwrap() Memory {
+	return w.inner
+}
+
+// Messages returns the system prompt (if present) followed by the last
+// MaxTurns turns.
+func (w *WindowMemory) Messages() ([]llm.Message, error) {
+	messages, err := w.inner.Messages()
+	if err != nil {
+		return nil, err
+	}
+
+	system, conversation := splitSystem(messages)
+
+	// Walk back to the start of the MaxTurns-th most recent user message
+	start := 0
+	turns := 0
+	for i := len(conversation) - 1; i >= 0; i-- {
+		if conversation[i].Role == llm.RoleUser {
+			turns++
+			if turns == w.MaxTurns {
+				start = i
+				break
+			}
+		}
+	}
+
+	return append(system, dropOrphanToolResults(conversation[start:])...), nil
+}
+
+// ============================================================================
+// Token Budget Memory
+// ============================================================================
+
+// TokenBudgetMemory wraps any Memory and drops the oldest messages until the
+// conversation fits a token budget. An assistant message with tool calls and
+// its tool results are dropped together; the system prompt and the most
+// recent message group are always kept.
+//
+// Trimming only affects the view returned by Messages(); the inner memory
+// keeps the full history.
+type TokenBudgetMemory struct {
+	inner     Memory
+	estimator TokenEstimator
+
+	// MaxTokens is the budget for the returned messages, including the
+	// system prompt. Defaults to 8000.
+	MaxTokens int
+
+	// ReserveTokens is subtracted from MaxTokens to leave room for the
+	// model's response. Defaults to 0.
+	ReserveTokens int
+
+	// OnTrim is an optional callback invoked with the number of dropped messages.
+	OnTrim func(dropped int)
+}
+
+// TokenBudgetOption configures a TokenBudgetMemory.
+type TokenBudgetOption func(*TokenBudgetMemory)
+
+// WithBudgetEstimator sets a custom token estimator.
+func WithBudgetEstimator(e TokenEstimator) TokenBudgetOption {
+	return func(t *TokenBudgetMemory) { t.estimator = e }
+}
+
+// WithReserveTokens leaves room for the response within the budget.
+func WithReserveTokens(n int) TokenBudgetOption {
+	return func(t *TokenBudgetMemory) { t.ReserveTokens = n }
+}
+
+// WithOnTrim sets a callback that fires when messages are dropped.
+func WithOnTrim(fn func(dropped int)) TokenBudgetOption {
+	return func(t *TokenBudgetMemory) { t.OnTrim = fn }
+}
+
+// NewTokenBudgetMemory wraps an existing Memory with a token budget.
+//
+// Example:
+//
+//	base := memoryx.NewInMemoryMemory("You are a helpful assistant.")
+//	mem := memoryx.NewTokenBudgetMemory(base, 128000,
+//	    memoryx.WithReserveTokens(4000),
+//	)
+func NewTokenBudgetMemory(inner Memory, maxTokens int, opts ...TokenBudgetOption) *TokenBudgetMemory {
+	t := &TokenBudgetMemory{
+		inner:     inner,
+		MaxTokens: maxTokens,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.MaxTokens <= 0 {
+		t.MaxTokens = 8000
+	}
+	if t.estimator == nil {
+		t.estimator = &CharBasedEstimator{}
+	}
+	return t
+}
+
+func (t *TokenBudgetMemory) Add(message llm.Message) error {
+	return t.inner.Add(message)
+}
+
+func (t *TokenBudgetMemory) Clear() error {
+	return t.inner.Clear()
+}
+
+// Unwrap returns the wrapped memory.
+func (t *TokenBudgetMemory) Unwrap() Memory {
+	return t.inner
+}
+
+// Messages returns the system prompt (if present) followed by as many of
+// the most recent message groups as fit the budget, starting at a user
+// message whenever one fits.
+func (t *TokenBudgetMemory) Messages() ([]llm.Message, error) {
+	messages, err := t.inner.Messages()
+	if err != nil {
+		return nil, err
+	}
+
+	budget := t.MaxTokens - t.ReserveTokens
+	if t.estimator.EstimateTokens(messages) <= budget {
+		return messages, nil
+	}
+
+	system, conversation := splitSystem(messages)
+	groups := groupMessages(conversation)
+	used := t.estimator.EstimateTokens(system)
+
+	// Keep groups from newest to oldest while they fit
+	first := len(groups)
+	for i := len(groups) - 1; i >= 0; i-- {
+		cost := t.estimator.EstimateTokens(groups[i])
+		if used+cost > budget && first < len(groups) {
+			break
+		}
+		used += cost
+		first = i
+	}
+
+	// Start at a user message, like WindowMemory, unless only the latest
+	// non-user groups fit
+	for i := first; i < len(groups); i++ {
+		if groups[i][0].Role == llm.RoleUser {
+			first = i
+			break
+		}
+	}
+
+	var kept []llm.Message
+	for _, g := range groups[first:] {
+		kept = append(kept, g...)
+	}
+	kept = dropOrphanToolResults(kept)
+
+	if t.OnTrim != nil {
+		t.OnTrim(len(conversation) - len(kept))
+	}
+	return append(system, kept...), nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// splitSystem separates a leading system prompt from the conversation.
+// The returned system slice is a fresh copy safe to append to.
+func splitSystem(messages []llm.Message) ([]llm.Message, []llm.Message) {
+	if len(messages) > 0 && messages[0].Role == llm.RoleSystem {
+		return []llm.Message{messages[0]}, messages[1:]
+	}
+	return []llm.Message{}, messages
+}
+
+// groupMessages splits a conversation into units that must stay together:
+// an assistant message with tool calls plus the tool results answering it,
+// or any other single message.
+func groupMessages(conversation []llm.Message) [][]llm.Message {
+	var groups [][]llm.Message
+	for i := 0; i < len(conversation); i++ {
+		msg := conversation[i]
+		if msg.Role != llm.RoleAssistant || len(msg.ToolCalls) == 0 {
+			groups = append(groups, conversation[i:i+1])
+			continue
+		}
+
+		ids := make(map[string]bool, len(msg.ToolCalls))
+		for _, tc := range msg.ToolCalls {
+			ids[tc.ID] = true
+		}
+		end := i + 1
+		for end < len(conversation) && conversation[end].Role == llm.RoleTool && ids[conversation[end].ToolCallID] {
+			end++
+		}
+		groups = append(groups, conversation[i:end])
+		i = end - 1
+	}
+	return groups
+}
+
+// dropOrphanToolResults removes tool results whose assistant tool call is
+// not in the slice; providers reject them.
+func dropOrphanToolResults(messages []llm.Message) []llm.Message {
+	calls := make(map[string]bool)
+	out := make([]llm.Message, 0, len(messages))
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			calls[tc.ID] = true
+		}
+		if msg.Role == llm.RoleTool && !calls[msg.ToolCallID] {
+			continue
+		}
+		out = append(out, msg)
+	}
+	return out
+}
@@ -0,0 +1,187 @@
+package memoryx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/memoryx"
+)
+
+func toolCallMessage(ids ...string) llm.Message {
+	msg := llm.NewAssistantMessage("")
+	for _, id := range ids {
+		msg.ToolCalls = append(msg.ToolCalls, llm.ToolCall{
+			ID:       id,
+			Type:     "function",
+			Function: llm.FunctionCall{Name: "lookup", Arguments: `{"q":"` + id + `"}`},
+		})
+	}
+	return msg
+}
+
+// assertToolPairing fails if a tool result appears without its tool call
+func assertToolPairing(t *testing.T, msgs []llm.Message) {
+	t.Helper()
+	calls := map[string]bool{}
+	for _, m := range msgs {
+		for _, tc := range m.ToolCalls {
+			calls[tc.ID] = true
+		}
+		if m.Role == llm.RoleTool && !calls[m.ToolCallID] {
+			t.Fatalf("tool result %s without its tool call", m.ToolCallID)
+		}
+	}
+}
+
+// --- WindowMemory tests ---
+
+func TestWindowMemory_KeepsLastTurns(t *testing.T) {
+	base := memoryx.NewInMemoryMemory("system")
+	mem := memoryx.NewWindowMemory(base, 2)
+
+	for _, text := range []string{"one", "two", "three"} {
+		mem.Add(llm.NewUserMessage(text))
+		mem.Add(llm.NewAssistantMessage("re: " + text))
+	}
+
+	msgs, _ := mem.Messages()
+	if len(msgs) != 5 {
+		t.Fatalf("expected system + 2 turns (5 messages), got %d", len(msgs))
+	}
+	if msgs[0].Role != llm.RoleSystem || msgs[1].Content != "two" {
+		t.Fatalf("unexpected window start: %+v", msgs[1])
+	}
+
+	all, _ := base.Messages()
+	if len(all) != 7 {
+		t.Fatalf("expected inner memory to keep full history, got %d", len(all))
+	}
+}
+
+func TestWindowMemory_TurnKeepsToolCallsWithResults(t *testing.T) {
+	mem := memoryx.NewWindowMemory(memoryx.NewInMemoryMemory("system"), 1)
+
+	mem.Add(llm.NewUserMessage("old"))
+	mem.Add(llm.NewAssistantMessage("old answer"))
+	mem.Add(llm.NewUserMessage("look things up"))
+	mem.Add(toolCallMessage("a", "b"))
+	mem.Add(llm.NewToolMessage("a", "result a"))
+	mem.Add(llm.NewToolMessage("b", "result b"))
+	mem.Add(llm.NewAssistantMessage("done"))
+
+	msgs, _ := mem.Messages()
+	if len(msgs) != 6 {
+		t.Fatalf("expected system + whole last turn (6 messages), got %d", len(msgs))
+	}
+	assertToolPairing(t, msgs)
+}
+
+func TestWindowMemory_DropsLeadingOrphanToolResults(t *testing.T) {
+	base := memoryx.NewInMemoryMemory()
+	base.Add(llm.NewToolMessage("gone", "stale result"))
+	base.Add(llm.NewUserMessage("hi"))
+
+	msgs, _ := memoryx.NewWindowMemory(base, 5).Messages()
+	if len(msgs) != 1 || msgs[0].Content != "hi" {
+		t.Fatalf("expected orphan tool result to be dropped, got %+v", msgs)
+	}
+}
+
+// --- TokenBudgetMemory tests ---
+
+func TestTokenBudgetMemory_UnderBudgetUnchanged(t *testing.T) {
+	base := memoryx.NewInMemoryMemory("system")
+	mem := memoryx.NewTokenBudgetMemory(base, 1000)
+	mem.Add(llm.NewUserMessage("hello"))
+	mem.Add(llm.NewAssistantMessage("hi"))
+
+	msgs, _ := mem.Messages()
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(msgs))
+	}
+}
+
+func TestTokenBudgetMemory_TrimsOldestAndFits(t *testing.T) {
+	estimator := &memoryx.CharBasedEstimator{}
+	dropped := 0
+	mem := memoryx.NewTokenBudgetMemory(memoryx.NewInMemoryMemory("system"), 200,
+		memoryx.WithBudgetEstimator(estimator),
+		memoryx.WithReserveTokens(50),
+		memoryx.WithOnTrim(func(n int) { dropped = n }),
+	)
+
+	for i := 0; i < 20; i++ {
+		mem.Add(llm.NewUserMessage(strings.Repeat("u", 100)))
+		mem.Add(llm.NewAssistantMessage(strings.Repeat("a", 100)))
+	}
+
+	msgs, _ := mem.Messages()
+	if msgs[0].Role != llm.RoleSystem {
+		t.Fatal("expected system prompt to be kept")
+	}
+	if got := estimator.EstimateTokens(msgs); got > 150 {
+		t.Fatalf("expected at most 150 tokens, got %d", got)
+	}
+	if dropped == 0 || dropped+len(msgs)-1 != 40 {
+		t.Fatalf("unexpected dropped count %d for %d kept", dropped, len(msgs)-1)
+	}
+	if msgs[len(msgs)-1].Role != llm.RoleAssistant {
+		t.Fatal("expected most recent message to be kept")
+	}
+}
+
+func TestTokenBudgetMemory_NeverSplitsToolGroups(t *testing.T) {
+	mem := memoryx.NewTokenBudgetMemory(memoryx.NewInMemoryMemory("system"), 60)
+
+	mem.Add(llm.NewUserMessage(strings.Repeat("x", 80)))
+	mem.Add(toolCallMessage("a", "b"))
+	mem.Add(llm.NewToolMessage("a", strings.Repeat("r", 80)))
+	mem.Add(llm.NewToolMessage("b", strings.Repeat("r", 80)))
+	mem.Add(llm.NewAssistantMessage("final"))
+
+	msgs, _ := mem.Messages()
+	assertToolPairing(t, msgs)
+
+	var toolResults int
+	for _, m := range msgs {
+		if m.Role == llm.RoleTool {
+			toolResults++
+		}
+	}
+	if toolResults != 0 && toolResults != 2 {
+		t.Fatalf("expected tool group kept or dropped whole, got %d results", toolResults)
+	}
+}
+
+func TestTokenBudgetMemory_KeepsLatestGroupOverBudget(t *testing.T) {
+	mem := memoryx.NewTokenBudgetMemory(memoryx.NewInMemoryMemory("system"), 10)
+
+	mem.Add(llm.NewUserMessage("short"))
+	mem.Add(toolCallMessage("a"))
+	mem.Add(llm.NewToolMessage("a", strings.Repeat("r", 400)))
+
+	msgs, _ := mem.Messages()
+	if len(msgs) != 3 {
+		t.Fatalf("expected system + latest tool group, got %d messages", len(msgs))
+	}
+	assertToolPairing(t, msgs)
+}
+
+func TestTokenBudgetMemory_StartsAtUserMessage(t *testing.T) {
+	mem := memoryx.NewTokenBudgetMemory(memoryx.NewInMemoryMemory("system"), 60)
+
+	mem.Add(llm.NewUserMessage(strings.Repeat("u", 100)))
+	mem.Add(llm.NewAssistantMessage("ok"))
+	mem.Add(llm.NewUserMessage("next question"))
+	mem.Add(llm.NewAssistantMessage(strings.Repeat("a", 100)))
+	mem.Add(llm.NewAssistantMessage("follow-up"))
+
+	msgs, _ := mem.Messages()
+	if len(msgs) < 2 || msgs[1].Role != llm.RoleUser {
+		t.Fatalf("expected the kept conversation to start with a user message, got %+v", msgs)
+	}
+	if msgs[1].Content != "next question" {
+		t.Fatalf("expected the latest fitting user turn, got %q", msgs[1].Content)
+	}
+}
@@ -0,0 +1,109 @@
+package toolx
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for tool execution
+	errorRegistry = errx.NewRegistry("TOOLX")
+
+	ErrToolNotFound = errorRegistry.Register(
+		"TOOL_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Tool not found",
+	)
+
+	ErrInvalidArguments = errorRegistry.Register(
+		"INVALID_ARGUMENTS",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid tool arguments",
+	)
+
+	ErrToolFailed = errorRegistry.Register(
+		"TOOL_FAILED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Tool execution failed",
+	)
+
+	ErrFatal = errorRegistry.Register(
+		"FATAL",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Fatal tool error",
+	)
+
+	ErrResultEncoding = errorRegistry.Register(
+		"RESULT_ENCODING",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Failed to encode tool result",
+	)
+)
+
+// Fatal marks err as unrecoverable. Returned from a tool's Call, it aborts
+// the agent run under the default error policy instead of being reported
+// back to the model.
+func Fatal(err error) *errx.Error {
+	return errorRegistry.NewWithCause(ErrFatal, err)
+}
+
+// InvalidArguments reports arguments a tool cannot work with. The model
+// usually recovers by retrying with corrected arguments.
+func InvalidArguments(message string) *errx.Error {
+	return errorRegistry.NewWithMessage(ErrInvalidArguments, message)
+}
+
+// ToolNotFound reports a call to a tool that doesn't exist. Tools that
+// forward calls elsewhere, such as to an MCP server, return it when the
+// remote side no longer knows the tool.
+func ToolNotFound(name string, cause error) *errx.Error {
+	return errorRegistry.NewWithCause(ErrToolNotFound, cause).
+		WithDetail("tool", name)
+}
+
+// IsFatal reports whether err carries the fatal tool error code
+func IsFatal(err error) bool {
+	return hasCode(err, ErrFatal)
+}
+
+// IsToolNotFound reports whether err carries the tool-not-found code
+func IsToolNotFound(err error) bool {
+	return hasCode(err, ErrToolNotFound)
+}
+
+// IsInvalidArguments reports whether err carries the invalid-arguments code
+func IsInvalidArguments(err error) bool {
+	return hasCode(err, ErrInvalidArguments)
+}
+
+func hasCode(err error, code *errx.ErrorCode) bool {
+	var e *errx.Error
+	if !errx.As(err, &e) {
+		return false
+	}
+	return e.Code == code.Code
+}
+
+// toToolError classifies an error returned by a tool. Errors that already
+// carry an errx code keep it; anything else becomes ErrToolFailed. The
+// tool's error is copied, not annotated in place, since tools may return
+// shared error values.
+func toToolError(err error, toolName string) *errx.Error {
+	var e *errx.Error
+	if errx.As(err, &e) {
+		if e.Code == ErrFatal.Code || e.Code == ErrInvalidArguments.Code || e.Code == ErrToolNotFound.Code {
+			copied := *e
+			copied.Details = maps.Clone(e.Details)
+			return copied.WithDetail("tool", toolName)
+		}
+	}
+	return errorRegistry.NewWithCause(ErrToolFailed, err).
+		WithDetail("tool", toolName)
+}
@@ -0,0 +1,76 @@
+package toolx
+
+import (
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// Result is a structured tool result. Tools may return *Result (or Result)
+// from Call to attach multimodal content and metadata to the tool message.
+type Result struct {
+	// Content is the text sent to the model. When empty, the text parts of
+	// Parts are joined instead.
+	Content string
+
+	// Parts carries multimodal content (images, audio, files)
+	Parts []llm.ContentPart
+
+	// Metadata is copied to the tool message; it is not sent to the model
+	Metadata map[string]any
+}
+
+// NewResult creates a text result
+func NewResult(content string) *Result {
+	return &Result{Content: content}
+}
+
+// NewMultimodalResult creates a result from content parts
+func NewMultimodalResult(parts ...llm.ContentPart) *Result {
+	return &Result{Parts: parts}
+}
+
+// WithPart appends a content part
+func (r *Result) WithPart(part llm.ContentPart) *Result {
+	r.Parts = append(r.Parts, part)
+	return r
+}
+
+// WithMetadata sets a metadata value
+func (r *Result) WithMetadata(key string, value any) *Result {
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]any)
+	}
+	r.Metadata[key] = value
+	return r
+}
+
+// Message converts the result into a tool message for the given call
+func (r *Result) Message(toolCallID string) llm.Message {
+	content := r.Content
+	if content == "" {
+		content = joinTextParts(r.Parts)
+	}
+
+	msg := llm.NewToolMessage(toolCallID, content)
+	if len(r.Parts) > 0 {
+		msg.MultiContent = r.Parts
+	}
+	if len(r.Metadata) > 0 {
+		msg.Metadata = make(map[string]any, len(r.Metadata))
+		for k, v := range r.Metadata {
+			msg.Metadata[k] = v
+		}
+	}
+	return msg
+}
+
+func joinTextParts(parts []llm.ContentPart) string {
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Type == llm.ContentPartTypeText {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
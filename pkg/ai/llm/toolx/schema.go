@@ -0,0 +1,320 @@
+package toolx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Argument Validation
+// ============================================================================
+
+// ValidateArguments checks tool call arguments against the tool's JSON schema
+// (llm.Function.Parameters). It supports the subset of JSON Schema used for
+// function calling: type, properties, required, additionalProperties, items,
+// enum, const, numeric and length bounds and pattern.
+func ValidateArguments(tool Toolx, arguments string) error {
+	schema, err := schemaMap(tool.GetTool().Function.Parameters)
+	if err != nil {
+		return errorRegistry.NewWithCause(ErrInvalidArguments, err).
+			WithDetail("tool", tool.Name()).
+			WithDetail("reason", "tool schema is not valid JSON")
+	}
+	if schema == nil {
+		return nil
+	}
+
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(arguments))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return errorRegistry.NewWithMessage(ErrInvalidArguments, "arguments are not valid JSON: "+err.Error()).
+			WithDetail("tool", tool.Name())
+	}
+
+	var problems []string
+	validateValue(schema, value, "$", &problems)
+	if len(problems) > 0 {
+		return errorRegistry.NewWithMessage(ErrInvalidArguments, strings.Join(problems, "; ")).
+			WithDetail("tool", tool.Name()).
+			WithDetail("violations", problems)
+	}
+	return nil
+}
+
+// schemaMap normalizes Parameters (a map, a struct or raw JSON) into plain
+// JSON values, so typed Go literals such as []string{"query"} are handled
+func schemaMap(params any) (map[string]any, error) {
+	switch v := params.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		return decodeSchema(v)
+	case []byte:
+		return decodeSchema(v)
+	case string:
+		return decodeSchema([]byte(v))
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSchema(data)
+}
+
+func decodeSchema(data []byte) (map[string]any, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func validateValue(schema map[string]any, value any, path string, problems *[]string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if matchesType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonType(value)))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: must be one of %s", path, formatValues(enum)))
+	}
+	if c, ok := schema["const"]; ok && !equalValues(c, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: must be %v", path, c))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		validateObject(schema, v, path, problems)
+	case []any:
+		validateArray(schema, v, path, problems)
+	case string:
+		validateString(schema, v, path, problems)
+	case json.Number:
+		validateNumber(schema, v, path, problems)
+	}
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string, problems *[]string) {
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propSchema, known := properties[key].(map[string]any)
+		if known {
+			validateValue(propSchema, obj[key], path+"."+key, problems)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: unknown property", path, key))
+			}
+		case map[string]any:
+			validateValue(additional, obj[key], path+"."+key, problems)
+		}
+	}
+}
+
+func validateArray(schema map[string]any, arr []any, path string, problems *[]string) {
+	if min, ok := schemaNumber(schema["minItems"]); ok && float64(len(arr)) < min {
+		*problems = append(*problems, fmt.Sprintf("%s: must have at least %v items", path, min))
+	}
+	if max, ok := schemaNumber(schema["maxItems"]); ok && float64(len(arr)) > max {
+		*problems = append(*problems, fmt.Sprintf("%s: must have at most %v items", path, max))
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+func validateString(schema map[string]any, s string, path string, problems *[]string) {
+	length := float64(utf8.RuneCountInString(s))
+	if min, ok := schemaNumber(schema["minLength"]); ok && length < min {
+		*problems = append(*problems, fmt.Sprintf("%s: must be at least %v characters", path, min))
+	}
+	if max, ok := schemaNumber(schema["maxLength"]); ok && length > max {
+		*problems = append(*problems, fmt.Sprintf("%s: must be at most %v characters", path, max))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			*problems = append(*problems, fmt.Sprintf("%s: must match pattern %s", path, pattern))
+		}
+	}
+}
+
+func validateNumber(schema map[string]any, n json.Number, path string, problems *[]string) {
+	f, err := n.Float64()
+	if err != nil {
+		return
+	}
+	if min, ok := schemaNumber(schema["minimum"]); ok && f < min {
+		*problems = append(*problems, fmt.Sprintf("%s: must be >= %v", path, min))
+	}
+	if max, ok := schemaNumber(schema["maximum"]); ok && f > max {
+		*problems = append(*problems, fmt.Sprintf("%s: must be <= %v", path, max))
+	}
+	if min, ok := schemaNumber(schema["exclusiveMinimum"]); ok && f <= min {
+		*problems = append(*problems, fmt.Sprintf("%s: must be > %v", path, min))
+	}
+	if max, ok := schemaNumber(schema["exclusiveMaximum"]); ok && f >= max {
+		*problems = append(*problems, fmt.Sprintf("%s: must be < %v", path, max))
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func schemaTypes(t any) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	case []string:
+		return v
+	}
+	return nil
+}
+
+func matchesType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return true
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if equalValues(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares a schema value with a decoded argument, treating
+// numbers by value (schemas hold float64, arguments json.Number)
+func equalValues(schemaValue, value any) bool {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+		s, ok := schemaNumber(schemaValue)
+		return ok && s == f
+	}
+	a, errA := json.Marshal(schemaValue)
+	b, errB := json.Marshal(value)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+func formatValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		data, _ := json.Marshal(v)
+		parts[i] = string(data)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
 )
 
 type Toolx interface {
@@ -16,7 +19,9 @@ type Toolx interface {
 }
 
 type ToolxClient struct {
-	tools map[string]Toolx
+	tools         map[string]Toolx
+	errorPolicy   ErrorPolicy
+	validateInput bool
 }
 
 func FromToolx(tools ...Toolx) *ToolxClient {
@@ -24,7 +29,25 @@ func FromToolx(tools ...Toolx) *ToolxClient {
 	for _, tool := range tools {
 		toolMap[tool.Name()] = tool
 	}
-	return &ToolxClient{tools: toolMap}
+	return &ToolxClient{
+		tools:       toolMap,
+		errorPolicy: DefaultErrorPolicy,
+	}
+}
+
+// WithErrorPolicy sets how Call handles tool errors
+func (t *ToolxClient) WithErrorPolicy(policy ErrorPolicy) *ToolxClient {
+	t.errorPolicy = policy
+	return t
+}
+
+// WithArgumentValidation enables or disables JSON schema validation of
+// arguments before a tool is called (disabled by default). Once enabled,
+// arguments that don't match a tool's declared parameters are reported as
+// ErrInvalidArguments without calling the tool.
+func (t *ToolxClient) WithArgumentValidation(enabled bool) *ToolxClient {
+	t.validateInput = enabled
+	return t
 }
 
 func (t *ToolxClient) GetTools() []llm.Tool {
@@ -35,19 +58,148 @@ func (t *ToolxClient) GetTools() []llm.Tool {
 	return tools
 }
 
+// Get returns the tool registered under name
+func (t *ToolxClient) Get(name string) (Toolx, bool) {
+	tool, ok := t.tools[name]
+	return tool, ok
+}
+
+// ============================================================================
+// Error Policy
+// ============================================================================
+
+// ErrorAction tells Call what to do with a failed tool call
+type ErrorAction int
+
+const (
+	// ErrorActionFeedback returns the error to the model as the tool result
+	ErrorActionFeedback ErrorAction = iota
+
+	// ErrorActionAbort returns the error to the caller, aborting the run
+	ErrorActionAbort
+)
+
+// ErrorPolicy decides whether a tool error is fed back to the model or aborts the run
+type ErrorPolicy func(tc llm.ToolCall, err *errx.Error) ErrorAction
+
+// DefaultErrorPolicy feeds every error back to the model except fatal ones
+func DefaultErrorPolicy(tc llm.ToolCall, err *errx.Error) ErrorAction {
+	if err.Code == ErrFatal.Code {
+		return ErrorActionAbort
+	}
+	return ErrorActionFeedback
+}
+
+// AbortOnError aborts the run on any tool error
+func AbortOnError(tc llm.ToolCall, err *errx.Error) ErrorAction {
+	return ErrorActionAbort
+}
+
+// FeedbackOnError feeds every error back to the model, including fatal ones
+func FeedbackOnError(tc llm.ToolCall, err *errx.Error) ErrorAction {
+	return ErrorActionFeedback
+}
+
+// ============================================================================
+// Execution
+// ============================================================================
+
+// Call executes a tool call. Errors are classified (ErrToolNotFound,
+// ErrInvalidArguments, ErrToolFailed, ErrFatal) and passed to the error
+// policy: fed back to the model as a tool message with error metadata, or
+// returned to abort the run.
 func (t *ToolxClient) Call(ctx context.Context, tc llm.ToolCall) (llm.Message, error) {
+	msg, err := t.Execute(ctx, tc)
+	if err == nil {
+		return msg, nil
+	}
+
+	var toolErr *errx.Error
+	if !errx.As(err, &toolErr) {
+		toolErr = toToolError(err, tc.Function.Name)
+	}
+
+	policy := t.errorPolicy
+	if policy == nil {
+		policy = DefaultErrorPolicy
+	}
+	if policy(tc, toolErr) == ErrorActionAbort {
+		return llm.Message{}, toolErr
+	}
+
+	return t.errorMessage(tc, toolErr), nil
+}
+
+// Execute runs a tool call and returns typed errors without applying the
+// error policy
+func (t *ToolxClient) Execute(ctx context.Context, tc llm.ToolCall) (llm.Message, error) {
 	tool, ok := t.tools[tc.Function.Name]
 	if !ok {
-		return llm.NewToolMessage(tc.ID, "This tool dont exists"), nil // create custom errors for this
+		return llm.Message{}, ToolNotFound(tc.Function.Name, nil)
+	}
+
+	if t.validateInput {
+		if err := ValidateArguments(tool, tc.Function.Arguments); err != nil {
+			return llm.Message{}, err
+		}
 	}
 
 	result, err := tool.Call(ctx, tc.Function.Arguments)
 	if err != nil {
-		return llm.NewToolMessage(tc.ID, "Error calling tool: "+err.Error()), nil //create a custom error for this
+		return llm.Message{}, toToolError(err, tc.Function.Name)
 	}
 
+	return ResultMessage(tc.ID, result)
+}
+
+// errorMessage renders a tool error for the model
+func (t *ToolxClient) errorMessage(tc llm.ToolCall, err *errx.Error) llm.Message {
+	var content string
+	switch err.Code {
+	case ErrToolNotFound.Code:
+		names := make([]string, 0, len(t.tools))
+		for name := range t.tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		content = fmt.Sprintf("Tool %q does not exist. Available tools: %s", tc.Function.Name, strings.Join(names, ", "))
+	case ErrInvalidArguments.Code:
+		content = fmt.Sprintf("Invalid arguments for tool %q: %s", tc.Function.Name, err.Message)
+	default:
+		content = "Error calling tool: " + errorText(err)
+	}
+
+	msg := llm.NewToolMessage(tc.ID, content)
+	msg.Metadata = map[string]any{
+		"error":      true,
+		"error_code": err.Code,
+	}
+	return msg
+}
+
+// errorText prefers the underlying cause over the generic registry message
+func errorText(err *errx.Error) string {
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return err.Message
+}
+
+// ResultMessage converts a tool result into a tool message
+func ResultMessage(id string, result any) (llm.Message, error) {
 	var resultStr string
 	switch v := result.(type) {
+	case *Result:
+		if v == nil {
+			return llm.NewToolMessage(id, "null"), nil
+		}
+		return v.Message(id), nil
+	case Result:
+		return v.Message(id), nil
+	case []llm.ContentPart:
+		return NewMultimodalResult(v...).Message(id), nil
+	case llm.ContentPart:
+		return NewMultimodalResult(v).Message(id), nil
 	case string:
 		resultStr = v
 	case []byte:
@@ -64,9 +216,9 @@ func (t *ToolxClient) Call(ctx context.Context, tc llm.ToolCall) (llm.Message, e
 		// Use JSON marshaling for complex types
 		jsonBytes, jsonErr := json.Marshal(result)
 		if jsonErr != nil {
-			return llm.NewToolMessage(tc.ID, "Error converting result to string: "+jsonErr.Error()), nil //create a custom error for this
+			return llm.Message{}, errorRegistry.NewWithCause(ErrResultEncoding, jsonErr)
 		}
 		resultStr = string(jsonBytes)
 	}
-	return llm.NewToolMessage(tc.ID, resultStr), nil
+	return llm.NewToolMessage(id, resultStr), nil
 }
@@ -0,0 +1,276 @@
+package toolx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// stubTool returns result and err from Call, recording the arguments
+type stubTool struct {
+	name   string
+	params any
+	result any
+	err    error
+	calls  []string
+}
+
+func (s *stubTool) Call(ctx context.Context, inputs string) (any, error) {
+	s.calls = append(s.calls, inputs)
+	return s.result, s.err
+}
+
+func (s *stubTool) GetTool() llm.Tool {
+	return llm.Tool{Type: "function", Function: llm.Function{Name: s.name, Parameters: s.params}}
+}
+
+func (s *stubTool) Name() string { return s.name }
+
+func errorCode(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+func toolCall(name, arguments string) llm.ToolCall {
+	return llm.ToolCall{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: name, Arguments: arguments}}
+}
+
+var searchSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"query": map[string]any{"type": "string", "minLength": 1},
+		"limit": map[string]any{"type": "integer", "minimum": 1, "maximum": 50},
+		"sort":  map[string]any{"type": "string", "enum": []string{"asc", "desc"}},
+		"level": map[string]any{"type": "number", "enum": []any{1, 2.5}},
+		"tags": map[string]any{
+			"type":     "array",
+			"maxItems": 2,
+			"items":    map[string]any{"type": "string", "pattern": "^[a-z]+$"},
+		},
+		"filter": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"year": map[string]any{"type": []string{"integer", "null"}},
+			},
+			"required":             []string{"year"},
+			"additionalProperties": false,
+		},
+		"extra": map[string]any{
+			"type":                 "object",
+			"additionalProperties": map[string]any{"type": "boolean"},
+		},
+	},
+	"required": []string{"query"},
+}
+
+func TestValidateArguments(t *testing.T) {
+	tool := &stubTool{name: "search", params: searchSchema}
+	tests := []struct {
+		name      string
+		arguments string
+		problem   string // empty when the arguments are valid
+	}{
+		{"minimal", `{"query": "go"}`, ""},
+		{"everything", `{"query": "go", "limit": 10, "sort": "asc", "level": 2.5, "tags": ["a", "b"], "filter": {"year": 2020}, "extra": {"x": true}}`, ""},
+		{"integer written as float", `{"query": "go", "limit": 10.0}`, ""},
+		{"numeric enum by value", `{"query": "go", "level": 1.0}`, ""},
+		{"nullable type", `{"query": "go", "filter": {"year": null}}`, ""},
+		{"unknown top-level property allowed", `{"query": "go", "other": 1}`, ""},
+		{"empty arguments", ``, "$.query: is required"},
+		{"not an object", `["go"]`, "$: expected object, got array"},
+		{"missing required", `{"limit": 3}`, "$.query: is required"},
+		{"wrong type", `{"query": 42}`, "$.query: expected string, got number"},
+		{"numeric string is not coerced", `{"query": "go", "limit": "10"}`, "$.limit: expected integer, got string"},
+		{"fraction for integer", `{"query": "go", "limit": 2.5}`, "$.limit: expected integer, got number"},
+		{"below minimum", `{"query": "go", "limit": 0}`, "$.limit: must be >= 1"},
+		{"above maximum", `{"query": "go", "limit": 51}`, "$.limit: must be <= 50"},
+		{"too short", `{"query": ""}`, "$.query: must be at least 1 characters"},
+		{"string enum", `{"query": "go", "sort": "up"}`, `$.sort: must be one of ["asc", "desc"]`},
+		{"numeric enum", `{"query": "go", "level": 3}`, "$.level: must be one of [1, 2.5]"},
+		{"too many items", `{"query": "go", "tags": ["a", "b", "c"]}`, "$.tags: must have at most 2 items"},
+		{"item pattern", `{"query": "go", "tags": ["a", "B"]}`, "$.tags[1]: must match pattern ^[a-z]+$"},
+		{"nested required", `{"query": "go", "filter": {}}`, "$.filter.year: is required"},
+		{"nested type", `{"query": "go", "filter": {"year": "2020"}}`, "$.filter.year: expected integer or null, got string"},
+		{"closed object", `{"query": "go", "filter": {"year": 1, "month": 2}}`, "$.filter.month: unknown property"},
+		{"additional property schema", `{"query": "go", "extra": {"x": "yes"}}`, "$.extra.x: expected boolean, got string"},
+		{"invalid JSON", `{"query": `, "arguments are not valid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := toolx.ValidateArguments(tool, tt.arguments)
+			if tt.problem == "" {
+				if err != nil {
+					t.Fatalf("ValidateArguments: %v", err)
+				}
+				return
+			}
+			if !toolx.IsInvalidArguments(err) {
+				t.Fatalf("ValidateArguments = %v, want %s", err, toolx.ErrInvalidArguments.Code)
+			}
+			if !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("ValidateArguments = %q, want it to mention %q", err, tt.problem)
+			}
+		})
+	}
+}
+
+func TestValidateArgumentsSchemaForms(t *testing.T) {
+	for name, params := range map[string]any{
+		"nil":     nil,
+		"raw":     []byte(`{"type": "object", "required": ["q"]}`),
+		"string":  `{"type": "object", "required": ["q"]}`,
+		"literal": map[string]any{"type": "object", "required": []string{"q"}},
+	} {
+		err := toolx.ValidateArguments(&stubTool{name: "t", params: params}, `{}`)
+		if name == "nil" {
+			if err != nil {
+				t.Errorf("%s schema: %v", name, err)
+			}
+			continue
+		}
+		if !toolx.IsInvalidArguments(err) {
+			t.Errorf("%s schema = %v, want the required property enforced", name, err)
+		}
+	}
+}
+
+func TestArgumentValidationIsOptIn(t *testing.T) {
+	ctx := context.Background()
+	tool := &stubTool{name: "search", params: searchSchema, result: "ok"}
+
+	msg, err := toolx.FromToolx(tool).Call(ctx, toolCall("search", `{"limit": "ten"}`))
+	if err != nil || msg.Content != "ok" || len(tool.calls) != 1 {
+		t.Fatalf("default client = %q, %v after %d calls, want the tool called", msg.Content, err, len(tool.calls))
+	}
+
+	msg, err = toolx.FromToolx(tool).WithArgumentValidation(true).Call(ctx, toolCall("search", `{"limit": "ten"}`))
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if len(tool.calls) != 1 {
+		t.Error("validating client called the tool with invalid arguments")
+	}
+	if msg.Metadata["error_code"] != toolx.ErrInvalidArguments.Code || !strings.Contains(msg.Content, "Invalid arguments") {
+		t.Errorf("message = %q %v, want an invalid arguments error", msg.Content, msg.Metadata)
+	}
+}
+
+func TestErrorPolicy(t *testing.T) {
+	ctx := context.Background()
+	shared := errx.New("shared", errx.TypeValidation).WithDetail("origin", "tool")
+
+	tests := []struct {
+		name     string
+		call     llm.ToolCall
+		toolErr  error
+		policy   toolx.ErrorPolicy
+		code     string
+		aborts   bool
+		contains string
+	}{
+		{"unknown tool", toolCall("missing", `{}`), nil, nil, toolx.ErrToolNotFound.Code, false, "Available tools: fail"},
+		{"plain error", toolCall("fail", `{}`), errors.New("disk full"), nil, toolx.ErrToolFailed.Code, false, "Error calling tool: disk full"},
+		{"invalid arguments", toolCall("fail", `{}`), toolx.InvalidArguments("limit must be positive"), nil, toolx.ErrInvalidArguments.Code, false, "limit must be positive"},
+		{"errx without a tool code", toolCall("fail", `{}`), shared, nil, toolx.ErrToolFailed.Code, false, "shared"},
+		{"fatal aborts by default", toolCall("fail", `{}`), toolx.Fatal(errors.New("quota")), nil, toolx.ErrFatal.Code, true, ""},
+		{"feedback on fatal", toolCall("fail", `{}`), toolx.Fatal(errors.New("quota")), toolx.FeedbackOnError, toolx.ErrFatal.Code, false, "quota"},
+		{"abort on any error", toolCall("fail", `{}`), errors.New("disk full"), toolx.AbortOnError, toolx.ErrToolFailed.Code, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := toolx.FromToolx(&stubTool{name: "fail", err: tt.toolErr})
+			if tt.policy != nil {
+				client.WithErrorPolicy(tt.policy)
+			}
+
+			msg, err := client.Call(ctx, tt.call)
+			if tt.aborts {
+				if errorCode(err) != tt.code {
+					t.Fatalf("Call = %v, want abort with %s", err, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Call: %v", err)
+			}
+			if msg.Role != llm.RoleTool || msg.ToolCallID != "call_1" {
+				t.Errorf("message = %+v, want a tool message for call_1", msg)
+			}
+			if msg.Metadata["error"] != true || msg.Metadata["error_code"] != tt.code {
+				t.Errorf("metadata = %v, want error code %s", msg.Metadata, tt.code)
+			}
+			if !strings.Contains(msg.Content, tt.contains) {
+				t.Errorf("content = %q, want it to contain %q", msg.Content, tt.contains)
+			}
+		})
+	}
+
+	// Shared errors returned by tools are not annotated in place
+	if _, ok := shared.Details["tool"]; ok {
+		t.Error("tool error was modified")
+	}
+	fatal := toolx.Fatal(errors.New("quota"))
+	client := toolx.FromToolx(&stubTool{name: "fail", err: fatal})
+	if _, err := client.Call(ctx, toolCall("fail", `{}`)); errorCode(err) != toolx.ErrFatal.Code {
+		t.Fatalf("Call = %v", err)
+	}
+	if _, ok := fatal.Details["tool"]; ok {
+		t.Error("fatal error was annotated in place")
+	}
+}
+
+func TestResultMessage(t *testing.T) {
+	image := llm.ContentPart{Type: llm.ContentPartTypeImageURL, ImageURL: &llm.ImageURL{URL: "https://example.com/a.png"}}
+	var nilResult *toolx.Result
+
+	tests := []struct {
+		name    string
+		result  any
+		content string
+		parts   int
+	}{
+		{"string", "done", "done", 0},
+		{"bytes", []byte("raw"), "raw", 0},
+		{"int", 42, "42", 0},
+		{"float", 2.5, "2.5", 0},
+		{"bool", true, "true", 0},
+		{"struct", struct {
+			ID int `json:"id"`
+		}{7}, `{"id":7}`, 0},
+		{"nil result", nilResult, "null", 0},
+		{"result", toolx.NewResult("text").WithMetadata("rows", 3), "text", 0},
+		{"result value", toolx.Result{Content: "value"}, "value", 0},
+		{"multimodal", toolx.NewMultimodalResult(llm.TextPart("caption"), image), "caption", 2},
+		{"parts", []llm.ContentPart{image}, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := toolx.ResultMessage("call_1", tt.result)
+			if err != nil {
+				t.Fatalf("ResultMessage: %v", err)
+			}
+			if msg.Role != llm.RoleTool || msg.ToolCallID != "call_1" {
+				t.Errorf("message = %+v, want a tool message for call_1", msg)
+			}
+			if msg.Content != tt.content || len(msg.MultiContent) != tt.parts {
+				t.Errorf("message = %q with %d parts, want %q with %d", msg.Content, len(msg.MultiContent), tt.content, tt.parts)
+			}
+		})
+	}
+
+	msg, _ := toolx.ResultMessage("call_1", toolx.NewResult("text").WithMetadata("rows", 3))
+	if msg.Metadata["rows"] != 3 {
+		t.Errorf("metadata = %v, want the result metadata", msg.Metadata)
+	}
+	if _, err := toolx.ResultMessage("call_1", map[string]any{"f": func() {}}); errorCode(err) != toolx.ErrResultEncoding.Code {
+		t.Errorf("unencodable result = %v, want %s", err, toolx.ErrResultEncoding.Code)
+	}
+}
@@ -0,0 +1,369 @@
+package toolxmcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/Abraxas-365/manifesto/pkg/logx"
+)
+
+const (
+	DefaultClientName     = "manifesto-toolx"
+	DefaultClientVersion  = "1.0.0"
+	DefaultMaxReconnects  = 3
+	DefaultReconnectDelay = time.Second
+)
+
+// Client is an MCP client bound to a single server. It performs the
+// initialization handshake, lists the server's tools and forwards calls,
+// transparently reconnecting when the transport drops.
+type Client struct {
+	transport Transport
+
+	clientInfo     Implementation
+	maxReconnects  int
+	reconnectDelay time.Duration
+	toolPrefix     string
+
+	nextID atomic.Int64
+
+	mu         sync.RWMutex
+	connected  bool
+	initResult *InitializeResult
+	tools      []ToolDefinition // cached tools/list result; nil when stale
+
+	connectMu sync.Mutex // serializes (re)connects
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithClientInfo sets the name and version reported to the server
+func WithClientInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		c.clientInfo = Implementation{Name: name, Version: version}
+	}
+}
+
+// WithMaxReconnects sets how many times a failed call reconnects before giving up
+func WithMaxReconnects(n int) ClientOption {
+	return func(c *Client) {
+		c.maxReconnects = n
+	}
+}
+
+// WithReconnectDelay sets the base delay between reconnect attempts
+func WithReconnectDelay(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.reconnectDelay = d
+	}
+}
+
+// WithToolPrefix prefixes every tool name exposed through toolx
+// (e.g. "github_") so tools from several servers don't collide
+func WithToolPrefix(prefix string) ClientOption {
+	return func(c *Client) {
+		c.toolPrefix = prefix
+	}
+}
+
+// NewClient creates an MCP client over the given transport
+//
+// Example:
+//
+//	client := toolxmcp.NewClient(
+//	    toolxmcp.NewStdioTransport("npx", []string{"-y", "@modelcontextprotocol/server-filesystem", "/data"}),
+//	    toolxmcp.WithToolPrefix("fs_"),
+//	)
+//	if err := client.Connect(ctx); err != nil { ... }
+//	defer client.Close()
+//
+//	mcpTools, err := client.Tools(ctx)
+//	tools := toolx.FromToolx(append(mcpTools, &calculatorTool{})...)
+func NewClient(transport Transport, opts ...ClientOption) *Client {
+	c := &Client{
+		transport:      transport,
+		clientInfo:     Implementation{Name: DefaultClientName, Version: DefaultClientVersion},
+		maxReconnects:  DefaultMaxReconnects,
+		reconnectDelay: DefaultReconnectDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ============================================================================
+// Lifecycle
+// ============================================================================
+
+// Connect opens the transport and performs the initialization handshake
+func (c *Client) Connect(ctx context.Context) error {
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+	return c.connect(ctx)
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	if err := c.transport.Connect(ctx, c.handleNotification); err != nil {
+		return err
+	}
+
+	params, _ := json.Marshal(InitializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      c.clientInfo,
+	})
+
+	resp, err := c.transport.Call(ctx, &Request{
+		JSONRPC: jsonRPCVersion,
+		ID:      c.nextID.Add(1),
+		Method:  MethodInitialize,
+		Params:  params,
+	})
+	if err != nil {
+		c.transport.Close()
+		return errx.Wrap(err, "MCP initialize failed", errx.TypeExternal)
+	}
+	if resp.Error != nil {
+		c.transport.Close()
+		return rpcError(resp.Error, MethodInitialize)
+	}
+
+	var result InitializeResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		c.transport.Close()
+		return errorRegistry.NewWithCause(ErrInvalidResponse, err).
+			WithDetail("method", MethodInitialize)
+	}
+
+	if !supportedVersions[result.ProtocolVersion] {
+		c.transport.Close()
+		return errorRegistry.New(ErrUnsupportedVersion).
+			WithDetail("version", result.ProtocolVersion)
+	}
+
+	if err := c.transport.Notify(ctx, &Notification{
+		JSONRPC: jsonRPCVersion,
+		Method:  MethodInitialized,
+	}); err != nil {
+		c.transport.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.connected = true
+	c.initResult = &result
+	c.tools = nil // tools may differ after a reconnect
+	c.mu.Unlock()
+
+	return nil
+}
+
+// reconnect re-establishes the session unless another goroutine already did
+func (c *Client) reconnect(ctx context.Context, generation *InitializeResult) error {
+	c.connectMu.Lock()
+	defer c.connectMu.Unlock()
+
+	c.mu.RLock()
+	current := c.initResult
+	c.mu.RUnlock()
+	if current != generation && current != nil {
+		return nil
+	}
+
+	c.transport.Close()
+	return c.connect(ctx)
+}
+
+// Close terminates the session
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.connected = false
+	c.initResult = nil
+	c.tools = nil
+	c.mu.Unlock()
+	return c.transport.Close()
+}
+
+// ServerInfo returns the server identity reported during initialization
+func (c *Client) ServerInfo() Implementation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.initResult == nil {
+		return Implementation{}
+	}
+	return c.initResult.ServerInfo
+}
+
+// Instructions returns the usage instructions the server provided, if any
+func (c *Client) Instructions() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.initResult == nil {
+		return ""
+	}
+	return c.initResult.Instructions
+}
+
+// ============================================================================
+// Tools
+// ============================================================================
+
+// ListTools returns every tool the server exposes, following pagination.
+// Results are cached until the server reports a list change or reconnects.
+func (c *Client) ListTools(ctx context.Context) ([]ToolDefinition, error) {
+	c.mu.RLock()
+	cached := c.tools
+	c.mu.RUnlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var all []ToolDefinition
+	cursor := ""
+	for {
+		var page ListToolsResult
+		if err := c.call(ctx, MethodToolsList, ListToolsParams{Cursor: cursor}, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Tools...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if all == nil {
+		all = []ToolDefinition{}
+	}
+
+	c.mu.Lock()
+	c.tools = all
+	c.mu.Unlock()
+
+	return all, nil
+}
+
+// CallTool invokes a tool by its server-side name. A result with IsError set
+// is returned as-is; the caller decides how to surface it.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	var result CallToolResult
+	if err := c.call(ctx, MethodToolsCall, CallToolParams{Name: name, Arguments: arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Tools returns the server's tools adapted to toolx.Toolx
+func (c *Client) Tools(ctx context.Context) ([]toolx.Toolx, error) {
+	defs, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]toolx.Toolx, len(defs))
+	for i, def := range defs {
+		tools[i] = newTool(c, def)
+	}
+	return tools, nil
+}
+
+// ============================================================================
+// RPC
+// ============================================================================
+
+// call sends a request, reconnecting and retrying when the connection drops.
+// Only requests that never reached the server, or idempotent methods, are
+// sent again.
+func (c *Client) call(ctx context.Context, method string, params any, out any) error {
+	c.mu.RLock()
+	connected := c.connected
+	c.mu.RUnlock()
+	if !connected {
+		return errorRegistry.New(ErrNotConnected).
+			WithDetail("method", method)
+	}
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return errorRegistry.NewWithCause(ErrInvalidResponse, err).
+			WithDetail("method", method)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxReconnects; attempt++ {
+		c.mu.RLock()
+		generation := c.initResult
+		c.mu.RUnlock()
+
+		resp, err := c.transport.Call(ctx, &Request{
+			JSONRPC: jsonRPCVersion,
+			ID:      c.nextID.Add(1),
+			Method:  method,
+			Params:  rawParams,
+		})
+		if err == nil {
+			if resp.Error != nil {
+				return rpcError(resp.Error, method)
+			}
+			if out != nil {
+				if err := json.Unmarshal(resp.Result, out); err != nil {
+					return errorRegistry.NewWithCause(ErrInvalidResponse, err).
+						WithDetail("method", method)
+				}
+			}
+			return nil
+		}
+
+		lastErr = err
+		if !isReconnectable(err, method) || attempt == c.maxReconnects {
+			break
+		}
+
+		logx.Warnf("toolxmcp: connection to %s lost (%v), reconnecting (attempt %d/%d)",
+			c.ServerInfo().Name, err, attempt+1, c.maxReconnects)
+
+		backoff := c.reconnectDelay * time.Duration(attempt+1)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err := c.reconnect(ctx, generation); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// handleNotification reacts to server notifications
+func (c *Client) handleNotification(method string, params json.RawMessage) {
+	switch method {
+	case MethodToolsListChanged:
+		c.mu.Lock()
+		c.tools = nil
+		c.mu.Unlock()
+	}
+}
+
+func rpcError(e *RPCError, method string) *errx.Error {
+	err := errorRegistry.NewWithCause(ErrRPC, e).
+		WithDetail("method", method).
+		WithDetail("rpc_code", e.Code)
+	if e.Code == CodeInvalidParams {
+		err.Type = errx.TypeValidation
+		err.HTTPStatus = 400
+	}
+	return err
+}
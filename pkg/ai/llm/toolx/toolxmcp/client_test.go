@@ -0,0 +1,127 @@
+package toolxmcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx/toolxmcp"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+func errorCode(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// flakyServer answers initialize, fails the first tools/list with a 503 and
+// every tools/call with a 500, counting the requests per method
+type flakyServer struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *flakyServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		return
+	}
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	n := s.calls[req.Method]
+	s.mu.Unlock()
+
+	var result any
+	switch {
+	case len(req.ID) == 0:
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case req.Method == toolxmcp.MethodInitialize:
+		result = toolxmcp.InitializeResult{
+			ProtocolVersion: toolxmcp.ProtocolVersion,
+			ServerInfo:      toolxmcp.Implementation{Name: "flaky", Version: "1"},
+		}
+	case req.Method == toolxmcp.MethodToolsList && n == 1:
+		http.Error(w, "warming up", http.StatusServiceUnavailable)
+		return
+	case req.Method == toolxmcp.MethodToolsList:
+		result = toolxmcp.ListToolsResult{Tools: []toolxmcp.ToolDefinition{{Name: "charge"}}}
+	default:
+		http.Error(w, "boom", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func TestServerErrorsRetryOnlyIdempotentMethods(t *testing.T) {
+	ctx := context.Background()
+	server := &flakyServer{calls: map[string]int{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	client := toolxmcp.NewClient(toolxmcp.NewHTTPTransport(ts.URL), toolxmcp.WithReconnectDelay(time.Millisecond))
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools after a 503: %v", err)
+	}
+	if len(tools) != 1 || server.count(toolxmcp.MethodToolsList) != 2 {
+		t.Errorf("tools = %v after %d tools/list requests, want 1 tool after a retry", tools, server.count(toolxmcp.MethodToolsList))
+	}
+
+	_, err = client.CallTool(ctx, "charge", json.RawMessage(`{"amount": 10}`))
+	if errorCode(err) != toolxmcp.ErrServerError.Code {
+		t.Errorf("CallTool = %v, want %s", err, toolxmcp.ErrServerError.Code)
+	}
+	if n := server.count(toolxmcp.MethodToolsCall); n != 1 {
+		t.Errorf("tools/call sent %d times, want 1", n)
+	}
+}
+
+func TestUnreachableServer(t *testing.T) {
+	ctx := context.Background()
+	server := &flakyServer{calls: map[string]int{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	client := toolxmcp.NewClient(toolxmcp.NewHTTPTransport(ts.URL), toolxmcp.WithReconnectDelay(time.Millisecond), toolxmcp.WithMaxReconnects(1))
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	// Nothing listens anymore: the request can't have reached the server, so
+	// the failure is reported as a connection failure, which is safe to retry
+	ts.Close()
+	if _, err := client.CallTool(ctx, "charge", nil); errorCode(err) != toolxmcp.ErrConnectionFailed.Code {
+		t.Errorf("CallTool = %v, want %s", err, toolxmcp.ErrConnectionFailed.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package toolxmcp
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for the MCP client and server
+	errorRegistry = errx.NewRegistry("MCP")
+
+	// Connection Errors
+	ErrConnectionFailed = errorRegistry.Register(
+		"CONNECTION_FAILED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Failed to connect to MCP server",
+	)
+
+	ErrConnectionClosed = errorRegistry.Register(
+		"CONNECTION_CLOSED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Connection to MCP server was closed",
+	)
+
+	ErrServerError = errorRegistry.Register(
+		"SERVER_ERROR",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"MCP server failed to handle the request",
+	)
+
+	ErrSessionExpired = errorRegistry.Register(
+		"SESSION_EXPIRED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"MCP session expired",
+	)
+
+	ErrNotConnected = errorRegistry.Register(
+		"NOT_CONNECTED",
+		errx.TypeValidation,
+		http.StatusPreconditionFailed,
+		"MCP client is not connected",
+	)
+
+	// Protocol Errors
+	ErrInvalidResponse = errorRegistry.Register(
+		"INVALID_RESPONSE",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Invalid response from MCP server",
+	)
+
+	ErrRPC = errorRegistry.Register(
+		"RPC_ERROR",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"MCP server returned an error",
+	)
+
+	ErrUnsupportedVersion = errorRegistry.Register(
+		"UNSUPPORTED_PROTOCOL_VERSION",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"MCP server negotiated an unsupported protocol version",
+	)
+
+	// Tool Errors
+	ErrToolExecution = errorRegistry.Register(
+		"TOOL_EXECUTION_FAILED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"MCP tool reported an error",
+	)
+
+	// Server Errors
+	ErrTenantRequired = errorRegistry.Register(
+		"TENANT_REQUIRED",
+		errx.TypeAuthorization,
+		http.StatusUnauthorized,
+		"Tenant context is required",
+	)
+
+	ErrRetrievalFailed = errorRegistry.Register(
+		"RETRIEVAL_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Knowledge base retrieval failed",
+	)
+)
+
+// idempotentMethods can be sent again after the server may have handled
+// them. A tools/call is never resent: the tool may already have run.
+var idempotentMethods = map[string]bool{
+	MethodInitialize: true,
+	MethodToolsList:  true,
+	MethodPing:       true,
+}
+
+// isReconnectable reports whether a request for method that failed with err
+// can be retried on a new connection. Requests that never reached the server
+// (ErrConnectionFailed, ErrSessionExpired) always can; requests lost after
+// they were sent only when the method is idempotent.
+func isReconnectable(err error, method string) bool {
+	var e *errx.Error
+	if !errx.As(err, &e) {
+		return false
+	}
+	switch e.Code {
+	case ErrConnectionFailed.Code, ErrSessionExpired.Code:
+		return true
+	case ErrConnectionClosed.Code, ErrServerError.Code:
+		return idempotentMethods[method]
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package toolxmcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+
+	DefaultHTTPTimeout = 2 * time.Minute
+)
+
+// HTTPTransport talks to an MCP server over the streamable HTTP transport.
+// Every message is POSTed to a single endpoint; the server answers with
+// either a JSON body or a text/event-stream carrying the response.
+type HTTPTransport struct {
+	endpoint   string
+	httpClient *http.Client
+	headers    map[string]string
+
+	mu              sync.RWMutex
+	sessionID       string
+	protocolVersion string
+	handler         NotificationHandler
+}
+
+// HTTPOption configures an HTTPTransport
+type HTTPOption func(*HTTPTransport)
+
+// WithHTTPClient sets the underlying HTTP client
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.httpClient = client
+	}
+}
+
+// WithHeader adds a header sent on every request (e.g. Authorization)
+func WithHeader(key, value string) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.headers[key] = value
+	}
+}
+
+// WithBearerToken sets the Authorization header
+func WithBearerToken(token string) HTTPOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// NewHTTPTransport creates a streamable HTTP transport for endpoint
+func NewHTTPTransport(endpoint string, opts ...HTTPOption) *HTTPTransport {
+	t := &HTTPTransport{
+		endpoint: endpoint,
+		headers:  make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.httpClient == nil {
+		t.httpClient = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	return t
+}
+
+// Connect resets the session; the session itself is created by the initialize request
+func (t *HTTPTransport) Connect(ctx context.Context, handler NotificationHandler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessionID = ""
+	t.protocolVersion = ""
+	t.handler = handler
+	return nil
+}
+
+// Call POSTs the request and waits for its response
+func (t *HTTPTransport) Call(ctx context.Context, req *Request) (*Response, error) {
+	httpResp, err := t.post(ctx, req, req.Method == MethodInitialize)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if req.Method == MethodInitialize {
+		if sid := httpResp.Header.Get(headerSessionID); sid != "" {
+			t.mu.Lock()
+			t.sessionID = sid
+			t.mu.Unlock()
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(httpResp.Header.Get("Content-Type"))
+
+	var resp *Response
+	switch mediaType {
+	case "text/event-stream":
+		resp, err = t.readEventStream(ctx, httpResp.Body, req.ID)
+	default:
+		resp, err = t.readJSON(httpResp.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Method == MethodInitialize && resp.Error == nil {
+		var result InitializeResult
+		if json.Unmarshal(resp.Result, &result) == nil {
+			t.mu.Lock()
+			t.protocolVersion = result.ProtocolVersion
+			t.mu.Unlock()
+		}
+	}
+
+	return resp, nil
+}
+
+// Notify POSTs a notification; the server answers 202 Accepted
+func (t *HTTPTransport) Notify(ctx context.Context, n *Notification) error {
+	httpResp, err := t.post(ctx, n, false)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, httpResp.Body)
+	httpResp.Body.Close()
+	return nil
+}
+
+// Close terminates the session on the server
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.sessionID = ""
+	t.mu.Unlock()
+
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return nil
+	}
+	t.setHeaders(req, sessionID, false)
+
+	// Servers may not support explicit termination (405); that's fine
+	if resp, err := t.httpClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func (t *HTTPTransport) post(ctx context.Context, msg any, initializing bool) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrConnectionFailed, err).
+			WithDetail("endpoint", t.endpoint)
+	}
+
+	t.mu.RLock()
+	sessionID := t.sessionID
+	t.mu.RUnlock()
+	t.setHeaders(req, sessionID, !initializing)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// A failed dial never sent the request; anything later may have
+		code := ErrConnectionClosed
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			code = ErrConnectionFailed
+		}
+		return nil, errorRegistry.NewWithCause(code, err).
+			WithDetail("endpoint", t.endpoint)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && sessionID != "":
+		// The server dropped our session; the client must re-initialize
+		resp.Body.Close()
+		t.mu.Lock()
+		t.sessionID = ""
+		t.mu.Unlock()
+		return nil, errorRegistry.New(ErrSessionExpired).
+			WithDetail("session_id", sessionID)
+
+	case resp.StatusCode >= 500:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, errorRegistry.New(ErrServerError).
+			WithDetail("status_code", resp.StatusCode).
+			WithDetail("body", string(data))
+
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, errorRegistry.New(ErrInvalidResponse).
+			WithDetail("status_code", resp.StatusCode).
+			WithDetail("body", string(data))
+	}
+
+	return resp, nil
+}
+
+func (t *HTTPTransport) setHeaders(req *http.Request, sessionID string, withVersion bool) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	if sessionID != "" {
+		req.Header.Set(headerSessionID, sessionID)
+	}
+	if withVersion {
+		t.mu.RLock()
+		version := t.protocolVersion
+		t.mu.RUnlock()
+		if version != "" {
+			req.Header.Set(headerProtocolVersion, version)
+		}
+	}
+}
+
+func (t *HTTPTransport) readJSON(body io.Reader) (*Response, error) {
+	var msg rawMessage
+	if err := json.NewDecoder(body).Decode(&msg); err != nil {
+		return nil, errorRegistry.NewWithCause(ErrInvalidResponse, err)
+	}
+	resp, err := msg.response()
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrInvalidResponse, err)
+	}
+	return resp, nil
+}
+
+// readEventStream consumes SSE events until the response for id arrives.
+// Notifications and server requests received on the way are dispatched.
+func (t *HTTPTransport) readEventStream(ctx context.Context, body io.Reader, id int64) (*Response, error) {
+	reader := bufio.NewReader(body)
+	var data strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			if err == io.EOF {
+				return nil, errorRegistry.New(ErrConnectionClosed).
+					WithDetail("error", "event stream ended before response")
+			}
+			return nil, errorRegistry.NewWithCause(ErrConnectionClosed, err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if line != "" {
+			if after, ok := strings.CutPrefix(line, "data:"); ok {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(after, " "))
+			}
+			continue
+		}
+
+		// Blank line terminates an event
+		if data.Len() == 0 {
+			continue
+		}
+		payload := data.String()
+		data.Reset()
+
+		var msg rawMessage
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			continue
+		}
+
+		switch {
+		case msg.isResponse():
+			resp, err := msg.response()
+			if err == nil && resp.ID == id {
+				return resp, nil
+			}
+		case msg.isRequest():
+			t.reply(ctx, replyToServerRequest(&msg))
+		case msg.isNotification():
+			t.mu.RLock()
+			handler := t.handler
+			t.mu.RUnlock()
+			if handler != nil {
+				handler(msg.Method, msg.Params)
+			}
+		}
+	}
+}
+
+// reply POSTs a response to a server-initiated request
+func (t *HTTPTransport) reply(ctx context.Context, data []byte) {
+	resp, err := t.post(ctx, json.RawMessage(data), false)
+	if err != nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
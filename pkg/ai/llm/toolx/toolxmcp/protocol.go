@@ -0,0 +1,220 @@
+package toolxmcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the MCP protocol revision requested during initialization
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions lists protocol revisions this client can talk
+var supportedVersions = map[string]bool{
+	"2025-06-18": true,
+	"2025-03-26": true,
+	"2024-11-05": true,
+}
+
+const jsonRPCVersion = "2.0"
+
+// JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// ============================================================================
+// JSON-RPC Messages
+// ============================================================================
+
+// Request is a JSON-RPC request sent to the server
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Notification is a JSON-RPC notification (a request without an ID)
+type Notification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response returned by the server
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error object of a JSON-RPC response
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// rawMessage is used to classify incoming messages before decoding them
+type rawMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+func (m *rawMessage) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+func (m *rawMessage) isNotification() bool {
+	return m.Method != "" && len(m.ID) == 0
+}
+
+func (m *rawMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// response converts the raw message into a Response, tolerating string IDs
+func (m *rawMessage) response() (*Response, error) {
+	resp := &Response{JSONRPC: m.JSONRPC, Result: m.Result, Error: m.Error}
+	if err := json.Unmarshal(m.ID, &resp.ID); err != nil {
+		var s string
+		if err := json.Unmarshal(m.ID, &s); err != nil {
+			return nil, fmt.Errorf("invalid response id %s", string(m.ID))
+		}
+		if _, err := fmt.Sscanf(s, "%d", &resp.ID); err != nil {
+			return nil, fmt.Errorf("invalid response id %q", s)
+		}
+	}
+	return resp, nil
+}
+
+// NotificationHandler receives server-initiated notifications
+type NotificationHandler func(method string, params json.RawMessage)
+
+// ============================================================================
+// MCP Methods
+// ============================================================================
+
+const (
+	MethodInitialize       = "initialize"
+	MethodInitialized      = "notifications/initialized"
+	MethodPing             = "ping"
+	MethodToolsList        = "tools/list"
+	MethodToolsCall        = "tools/call"
+	MethodToolsListChanged = "notifications/tools/list_changed"
+)
+
+// Implementation identifies a client or server
+type Implementation struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version"`
+}
+
+// InitializeParams is sent by the client to start a session
+type InitializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      Implementation `json:"clientInfo"`
+}
+
+// InitializeResult is returned by the server after initialization
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+// ServerCapabilities describes what the server supports
+type ServerCapabilities struct {
+	Tools     *ToolsCapability `json:"tools,omitempty"`
+	Resources map[string]any   `json:"resources,omitempty"`
+	Prompts   map[string]any   `json:"prompts,omitempty"`
+	Logging   map[string]any   `json:"logging,omitempty"`
+}
+
+// ToolsCapability describes the server's tool support
+type ToolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// ToolDefinition describes a tool exposed by an MCP server
+type ToolDefinition struct {
+	Name         string          `json:"name"`
+	Title        string          `json:"title,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	InputSchema  json.RawMessage `json:"inputSchema"`
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+}
+
+// ListToolsParams requests a page of tools
+type ListToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListToolsResult is a page of tools
+type ListToolsResult struct {
+	Tools      []ToolDefinition `json:"tools"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+// CallToolParams invokes a tool
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// CallToolResult is the outcome of a tool invocation
+type CallToolResult struct {
+	Content           []Content      `json:"content"`
+	StructuredContent any            `json:"structuredContent,omitempty"`
+	IsError           bool           `json:"isError,omitempty"`
+	Meta              map[string]any `json:"_meta,omitempty"`
+}
+
+// ContentType identifies the kind of a content block
+type ContentType string
+
+const (
+	ContentTypeText         ContentType = "text"
+	ContentTypeImage        ContentType = "image"
+	ContentTypeAudio        ContentType = "audio"
+	ContentTypeResource     ContentType = "resource"
+	ContentTypeResourceLink ContentType = "resource_link"
+)
+
+// Content is a single content block in a tool result
+type Content struct {
+	Type     ContentType       `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	Data     string            `json:"data,omitempty"` // base64 for image/audio
+	MimeType string            `json:"mimeType,omitempty"`
+	Resource *ResourceContents `json:"resource,omitempty"`
+
+	// resource_link fields
+	URI         string `json:"uri,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ResourceContents is an embedded resource
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"` // base64
+}
@@ -0,0 +1,128 @@
+package toolxmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+)
+
+// RetrieverFunc resolves the retriever for a request
+type RetrieverFunc func(ctx context.Context) (*document.Retriever, error)
+
+// StaticRetriever always uses the same retriever
+func StaticRetriever(r *document.Retriever) RetrieverFunc {
+	return func(context.Context) (*document.Retriever, error) {
+		return r, nil
+	}
+}
+
+// TenantRetriever builds a retriever for the tenant found in the request
+// context. Requests without a tenant are rejected.
+func TenantRetriever(build func(tenantID kernel.TenantID) *document.Retriever) RetrieverFunc {
+	return func(ctx context.Context) (*document.Retriever, error) {
+		tenantID, ok := TenantFromContext(ctx)
+		if !ok {
+			return nil, errorRegistry.New(ErrTenantRequired)
+		}
+		return build(tenantID), nil
+	}
+}
+
+// ============================================================================
+// Retriever Tool
+// ============================================================================
+
+// RetrieverTool exposes a document.Retriever as a toolx tool
+type RetrieverTool struct {
+	name        string
+	description string
+	retriever   RetrieverFunc
+}
+
+var _ toolx.Toolx = (*RetrieverTool)(nil)
+
+// NewRetrieverTool creates a knowledge base search tool
+func NewRetrieverTool(name, description string, fn RetrieverFunc) *RetrieverTool {
+	if description == "" {
+		description = "Search the knowledge base and return the most relevant passages for a query"
+	}
+	return &RetrieverTool{name: name, description: description, retriever: fn}
+}
+
+// Name returns the tool name
+func (t *RetrieverTool) Name() string {
+	return t.name
+}
+
+// GetTool returns the tool definition
+func (t *RetrieverTool) GetTool() llm.Tool {
+	return llm.Tool{
+		Type: "function",
+		Function: llm.Function{
+			Name:        t.name,
+			Description: t.description,
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "Natural language search query",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+	}
+}
+
+// Call runs the query and formats the matching documents
+func (t *RetrieverTool) Call(ctx context.Context, inputs string) (any, error) {
+	var args struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(inputs), &args); err != nil || strings.TrimSpace(args.Query) == "" {
+		return nil, errx.New("query is required", errx.TypeValidation).
+			WithDetail("tool", t.name)
+	}
+
+	retriever, err := t.retriever(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := retriever.Retrieve(ctx, args.Query)
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrRetrievalFailed, err).
+			WithDetail("tool", t.name)
+	}
+
+	return formatDocuments(docs), nil
+}
+
+// formatDocuments renders documents as numbered passages with their source
+func formatDocuments(docs []*document.Document) string {
+	if len(docs) == 0 {
+		return "No relevant documents found."
+	}
+
+	var sb strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "[%d] id: %s", i+1, doc.ID)
+		if source, ok := doc.Metadata[document.MetadataSource]; ok {
+			fmt.Fprintf(&sb, "\nsource: %v", source)
+		}
+		sb.WriteString("\n")
+		sb.WriteString(doc.Content)
+	}
+	return sb.String()
+}
@@ -0,0 +1,356 @@
+package toolxmcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+	"github.com/Abraxas-365/manifesto/pkg/logx"
+)
+
+// ToolsetFunc resolves the tools available for a request. It receives the
+// request context, so implementations can scope tools by tenant.
+type ToolsetFunc func(ctx context.Context) (*toolx.ToolxClient, error)
+
+// Server exposes toolx tools over the Model Context Protocol. It is
+// transport-agnostic: ServeStdio and ServeHTTP feed it JSON-RPC messages.
+type Server struct {
+	info         Implementation
+	instructions string
+	toolsets     []ToolsetFunc
+}
+
+// ServerOption configures a Server
+type ServerOption func(*Server)
+
+// WithInstructions sets usage instructions returned to clients on initialize
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithTools exposes a fixed set of tools
+func WithTools(tools *toolx.ToolxClient) ServerOption {
+	return func(s *Server) {
+		s.toolsets = append(s.toolsets, func(context.Context) (*toolx.ToolxClient, error) {
+			return tools, nil
+		})
+	}
+}
+
+// WithToolset exposes tools resolved per request (e.g. per tenant)
+func WithToolset(fn ToolsetFunc) ServerOption {
+	return func(s *Server) {
+		s.toolsets = append(s.toolsets, fn)
+	}
+}
+
+// WithRetriever exposes a knowledge base search tool backed by a document.Retriever
+func WithRetriever(name, description string, fn RetrieverFunc) ServerOption {
+	return WithTools(toolx.FromToolx(NewRetrieverTool(name, description, fn)))
+}
+
+// NewServer creates an MCP server
+//
+// Example:
+//
+//	server := toolxmcp.NewServer("manifesto", "1.0.0",
+//	    toolxmcp.WithTools(toolx.FromToolx(&weatherTool{})),
+//	    toolxmcp.WithRetriever("search_knowledge_base", "Search company documents",
+//	        toolxmcp.TenantRetriever(func(tenantID kernel.TenantID) *document.Retriever {
+//	            return document.NewRetriever(stores.ForTenant(tenantID)).WithTopK(5)
+//	        })),
+//	)
+//	server.ServeStdio(ctx, os.Stdin, os.Stdout)
+func NewServer(name, version string, opts ...ServerOption) *Server {
+	s := &Server{
+		info: Implementation{Name: name, Version: version},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ============================================================================
+// Message Handling
+// ============================================================================
+
+// HandleMessage processes a single JSON-RPC message and returns the encoded
+// response, or nil when the message is a notification or response.
+func (s *Server) HandleMessage(ctx context.Context, data []byte) []byte {
+	var msg rawMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return encodeError(json.RawMessage("null"), CodeParseError, "parse error")
+	}
+
+	if !msg.isRequest() {
+		if msg.Method == "" && len(msg.ID) == 0 {
+			return encodeError(json.RawMessage("null"), CodeInvalidRequest, "invalid request")
+		}
+		// Notifications and responses need no reply
+		return nil
+	}
+
+	result, rpcErr := s.dispatch(ctx, msg.Method, msg.Params)
+	if rpcErr != nil {
+		return encodeError(msg.ID, rpcErr.Code, rpcErr.Message)
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonRPCVersion,
+		"id":      msg.ID,
+		"result":  result,
+	})
+	if err != nil {
+		return encodeError(msg.ID, CodeInternalError, "failed to encode result")
+	}
+	return out
+}
+
+func (s *Server) dispatch(ctx context.Context, method string, params json.RawMessage) (any, *RPCError) {
+	switch method {
+	case MethodInitialize:
+		return s.initialize(params)
+	case MethodPing:
+		return map[string]any{}, nil
+	case MethodToolsList:
+		return s.listTools(ctx)
+	case MethodToolsCall:
+		return s.callTool(ctx, params)
+	default:
+		return nil, &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, *RPCError) {
+	var req InitializeParams
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "invalid initialize params"}
+	}
+
+	version := ProtocolVersion
+	if supportedVersions[req.ProtocolVersion] {
+		version = req.ProtocolVersion
+	}
+
+	return InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    ServerCapabilities{Tools: &ToolsCapability{}},
+		ServerInfo:      s.info,
+		Instructions:    s.instructions,
+	}, nil
+}
+
+func (s *Server) listTools(ctx context.Context) (any, *RPCError) {
+	tools, err := s.resolveTools(ctx)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defs := make([]ToolDefinition, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, toolDefinition(tools[name].GetTool()))
+	}
+
+	return ListToolsResult{Tools: defs}, nil
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *RPCError) {
+	var req CallToolParams
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "invalid tools/call params"}
+	}
+
+	tools, err := s.resolveTools(ctx)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	tool, ok := tools[req.Name]
+	if !ok {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + req.Name}
+	}
+
+	args := string(req.Arguments)
+	if args == "" || args == "null" {
+		args = "{}"
+	}
+
+	if err := toolx.ValidateArguments(tool, args); err != nil {
+		return toolErrorResult(req.Name, err), nil
+	}
+
+	result, err := tool.Call(ctx, args)
+	if err != nil {
+		return toolErrorResult(req.Name, err), nil
+	}
+
+	msg, err := toolx.ResultMessage("", result)
+	if err != nil {
+		return toolErrorResult(req.Name, err), nil
+	}
+
+	return callToolResult(msg), nil
+}
+
+// toolErrorResult reports a tool failure in the result so the model can see it
+func toolErrorResult(name string, err error) CallToolResult {
+	logx.WithError(err).Debugf("toolxmcp: tool %s failed", name)
+	return CallToolResult{
+		Content: []Content{{Type: ContentTypeText, Text: err.Error()}},
+		IsError: true,
+	}
+}
+
+// resolveTools merges every toolset for the request into a name index
+func (s *Server) resolveTools(ctx context.Context) (map[string]toolx.Toolx, error) {
+	tools := make(map[string]toolx.Toolx)
+	for _, fn := range s.toolsets {
+		client, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			continue
+		}
+		for _, t := range client.GetTools() {
+			if tool, ok := client.Get(t.Function.Name); ok {
+				tools[t.Function.Name] = tool
+			}
+		}
+	}
+	return tools, nil
+}
+
+// ============================================================================
+// Tenant Context
+// ============================================================================
+
+// ContextWithAuth stores the auth context (and its tenant and user) in ctx,
+// using the kernel context keys
+func ContextWithAuth(ctx context.Context, auth *kernel.AuthContext) context.Context {
+	ctx = context.WithValue(ctx, kernel.AuthContextKey, auth)
+	ctx = context.WithValue(ctx, kernel.TenantContextKey, auth.TenantID)
+	if auth.UserID != nil {
+		ctx = context.WithValue(ctx, kernel.UserContextKey, *auth.UserID)
+	}
+	return ctx
+}
+
+// TenantFromContext returns the tenant of the current request
+func TenantFromContext(ctx context.Context) (kernel.TenantID, bool) {
+	tenantID, ok := ctx.Value(kernel.TenantContextKey).(kernel.TenantID)
+	return tenantID, ok && !tenantID.IsEmpty()
+}
+
+// ============================================================================
+// Conversion
+// ============================================================================
+
+// toolDefinition converts an llm.Tool into an MCP tool definition
+func toolDefinition(tool llm.Tool) ToolDefinition {
+	schema, err := json.Marshal(tool.Function.Parameters)
+	if err != nil || tool.Function.Parameters == nil || string(schema) == "null" {
+		schema = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+	return ToolDefinition{
+		Name:        tool.Function.Name,
+		Description: tool.Function.Description,
+		InputSchema: schema,
+	}
+}
+
+// callToolResult converts a tool message into MCP content blocks
+func callToolResult(msg llm.Message) CallToolResult {
+	if len(msg.MultiContent) == 0 {
+		return CallToolResult{Content: []Content{{Type: ContentTypeText, Text: msg.Content}}}
+	}
+
+	content := make([]Content, 0, len(msg.MultiContent))
+	for _, part := range msg.MultiContent {
+		if c, ok := mcpContent(part); ok {
+			content = append(content, c)
+		}
+	}
+	return CallToolResult{Content: content}
+}
+
+func mcpContent(part llm.ContentPart) (Content, bool) {
+	switch part.Type {
+	case llm.ContentPartTypeText:
+		return Content{Type: ContentTypeText, Text: part.Text}, true
+
+	case llm.ContentPartTypeImageURL:
+		if part.ImageURL == nil {
+			return Content{}, false
+		}
+		if mimeType, data, ok := parseDataURL(part.ImageURL.URL); ok {
+			return Content{Type: ContentTypeImage, Data: data, MimeType: mimeType}, true
+		}
+		return Content{Type: ContentTypeResourceLink, URI: part.ImageURL.URL, Name: "image"}, true
+
+	case llm.ContentPartTypeInputAudio:
+		if part.InputAudio == nil {
+			return Content{}, false
+		}
+		return Content{Type: ContentTypeAudio, Data: part.InputAudio.Data, MimeType: "audio/" + part.InputAudio.Format}, true
+
+	case llm.ContentPartTypeFile:
+		if part.File == nil {
+			return Content{}, false
+		}
+		if part.File.FileData == "" {
+			return Content{Type: ContentTypeText, Text: "file: " + part.File.FileID}, true
+		}
+		return Content{Type: ContentTypeResource, Resource: &ResourceContents{
+			URI:      "file:///" + part.File.Filename,
+			MimeType: "application/octet-stream",
+			Blob:     part.File.FileData,
+		}}, true
+	}
+	return Content{}, false
+}
+
+// parseDataURL splits a base64 data URL into its MIME type and payload
+func parseDataURL(url string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return "", "", false
+	}
+	meta, data, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", "", false
+	}
+	mimeType, ok := strings.CutSuffix(meta, ";base64")
+	if !ok {
+		data = base64.StdEncoding.EncodeToString([]byte(data))
+	}
+	return mimeType, data, true
+}
+
+func encodeError(id json.RawMessage, code int, message string) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": jsonRPCVersion,
+		"id":      id,
+		"error":   RPCError{Code: code, Message: message},
+	})
+	return data
+}
+
+func internalError(err error) *RPCError {
+	return &RPCError{Code: CodeInternalError, Message: fmt.Sprintf("internal error: %v", err)}
+}
@@ -0,0 +1,235 @@
+package toolxmcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx/toolxmcp"
+)
+
+// funcTool is a toolx tool backed by a function
+type funcTool struct {
+	name   string
+	params any
+	call   func(args map[string]any) (any, error)
+}
+
+func (f *funcTool) Call(ctx context.Context, inputs string) (any, error) {
+	var args map[string]any
+	if err := json.Unmarshal([]byte(inputs), &args); err != nil {
+		return nil, err
+	}
+	return f.call(args)
+}
+
+func (f *funcTool) GetTool() llm.Tool {
+	return llm.Tool{Type: "function", Function: llm.Function{Name: f.name, Description: f.name + " tool", Parameters: f.params}}
+}
+
+func (f *funcTool) Name() string { return f.name }
+
+// newTestServer exposes echo and fail, plus ephemeral, which disappears
+// after the first time the tools are resolved
+func newTestServer() *toolxmcp.Server {
+	echo := &funcTool{
+		name: "echo",
+		params: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+			"required":   []string{"text"},
+		},
+		call: func(args map[string]any) (any, error) { return "echo: " + args["text"].(string), nil },
+	}
+	fail := &funcTool{name: "fail", call: func(map[string]any) (any, error) { return nil, errors.New("boom") }}
+	ephemeral := &funcTool{name: "ephemeral", call: func(map[string]any) (any, error) { return "still here", nil }}
+
+	var resolved atomic.Int32
+	return toolxmcp.NewServer("test-server", "1.0.0",
+		toolxmcp.WithInstructions("Use echo to repeat text."),
+		toolxmcp.WithTools(toolx.FromToolx(echo, fail)),
+		toolxmcp.WithToolset(func(ctx context.Context) (*toolx.ToolxClient, error) {
+			if resolved.Add(1) > 1 {
+				return nil, nil
+			}
+			return toolx.FromToolx(ephemeral), nil
+		}),
+	)
+}
+
+// TestMain runs the test server over stdin/stdout when the stdio round trip
+// launches this binary as its server process
+func TestMain(m *testing.M) {
+	if os.Getenv("TOOLXMCP_TEST_SERVER") == "1" {
+		newTestServer().ServeStdio(context.Background(), os.Stdin, os.Stdout)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func testRoundTrip(t *testing.T, transport toolxmcp.Transport) {
+	ctx := context.Background()
+	client := toolxmcp.NewClient(transport, toolxmcp.WithToolPrefix("srv_"), toolxmcp.WithReconnectDelay(time.Millisecond))
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	if info := client.ServerInfo(); info.Name != "test-server" || info.Version != "1.0.0" {
+		t.Errorf("ServerInfo = %+v", info)
+	}
+	if client.Instructions() != "Use echo to repeat text." {
+		t.Errorf("Instructions = %q", client.Instructions())
+	}
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "srv_echo,srv_ephemeral,srv_fail" {
+		t.Fatalf("tools = %v", names)
+	}
+
+	tx := toolx.FromToolx(tools...)
+	echoTool, _ := tx.Get("srv_echo")
+	if required := echoTool.GetTool().Function.Parameters.(map[string]any)["required"]; len(required.([]any)) != 1 {
+		t.Errorf("echo schema = %v, want the server's input schema", echoTool.GetTool().Function.Parameters)
+	}
+
+	msg, err := tx.Execute(ctx, llm.ToolCall{ID: "1", Function: llm.FunctionCall{Name: "srv_echo", Arguments: `{"text": "hi"}`}})
+	if err != nil || msg.Content != "echo: hi" {
+		t.Errorf("echo = %q, %v", msg.Content, err)
+	}
+
+	// Arguments that aren't JSON never reach the server
+	_, err = tx.Execute(ctx, llm.ToolCall{ID: "1", Function: llm.FunctionCall{Name: "srv_echo", Arguments: `{"text": `}})
+	if !toolx.IsInvalidArguments(err) {
+		t.Errorf("echo with broken JSON = %v, want %s", err, toolx.ErrInvalidArguments.Code)
+	}
+
+	// The server validates arguments and reports tool failures in the result
+	result, err := client.CallTool(ctx, "echo", json.RawMessage(`{"text": 1}`))
+	if err != nil || !result.IsError || !strings.Contains(toolxmcp.ContentParts(result)[0].Text, "$.text") {
+		t.Errorf("echo with invalid arguments = %+v, %v", result, err)
+	}
+	_, err = tx.Execute(ctx, llm.ToolCall{ID: "2", Function: llm.FunctionCall{Name: "srv_fail", Arguments: `{}`}})
+	if !strings.Contains(errorString(err), "boom") || errorCode(err) != toolx.ErrToolFailed.Code {
+		t.Errorf("fail = %v, want the tool's error as %s", err, toolx.ErrToolFailed.Code)
+	}
+
+	if _, err := client.CallTool(ctx, "missing", nil); errorCode(err) != toolxmcp.ErrRPC.Code {
+		t.Errorf("unknown tool = %v, want %s", err, toolxmcp.ErrRPC.Code)
+	}
+	_, err = tx.Execute(ctx, llm.ToolCall{ID: "3", Function: llm.FunctionCall{Name: "srv_ephemeral", Arguments: `{}`}})
+	if !toolx.IsToolNotFound(err) {
+		t.Errorf("tool removed from the server = %v, want %s", err, toolx.ErrToolNotFound.Code)
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestRoundTripStdio(t *testing.T) {
+	testRoundTrip(t, toolxmcp.NewStdioTransport(os.Args[0], []string{"-test.run=^$"},
+		toolxmcp.WithEnv("TOOLXMCP_TEST_SERVER=1")))
+}
+
+func TestRoundTripHTTP(t *testing.T) {
+	ts := httptest.NewServer(newTestServer())
+	defer ts.Close()
+	testRoundTrip(t, toolxmcp.NewHTTPTransport(ts.URL))
+}
+
+func TestServeStdio(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`,
+		`not json`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		``,
+		`{"jsonrpc": "2.0", "id": "two", "method": "resources/list"}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := newTestServer().ServeStdio(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+
+	replies := map[string]toolxmcp.RPCError{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var reply struct {
+			ID     json.RawMessage    `json:"id"`
+			Result json.RawMessage    `json:"result"`
+			Error  *toolxmcp.RPCError `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &reply); err != nil {
+			t.Fatalf("reply %q: %v", line, err)
+		}
+		if reply.Error == nil {
+			reply.Error = &toolxmcp.RPCError{}
+		}
+		replies[string(reply.ID)] = *reply.Error
+	}
+
+	if len(replies) != 3 {
+		t.Fatalf("replies = %v, want one per request and one for the parse error", replies)
+	}
+	if replies["1"].Code != 0 {
+		t.Errorf("ping = %+v, want a result", replies["1"])
+	}
+	if replies["null"].Code != toolxmcp.CodeParseError {
+		t.Errorf("bad line = %+v, want a parse error", replies["null"])
+	}
+	if replies[`"two"`].Code != toolxmcp.CodeMethodNotFound {
+		t.Errorf("unknown method = %+v, want method not found", replies[`"two"`])
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	ts := httptest.NewServer(newTestServer())
+	defer ts.Close()
+
+	post := func(body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification = %d, want 202", resp.StatusCode)
+	}
+	if resp := post(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`); resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("ping = %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "POST, DELETE" {
+		t.Errorf("GET = %d, want 405 with Allow", resp.StatusCode)
+	}
+}
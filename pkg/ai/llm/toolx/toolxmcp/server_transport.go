@@ -0,0 +1,108 @@
+package toolxmcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// MaxHTTPMessageSize bounds the body of a single HTTP request
+const MaxHTTPMessageSize = 4 * 1024 * 1024
+
+// ============================================================================
+// Stdio
+// ============================================================================
+
+// ServeStdio reads newline-delimited JSON-RPC messages from r and writes the
+// responses to w until r is exhausted or ctx is cancelled. Requests are
+// handled concurrently; responses may be written out of order.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var (
+		wg      sync.WaitGroup
+		writeMu sync.Mutex
+	)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		msg := append([]byte(nil), line...)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := s.HandleMessage(ctx, msg)
+			if resp == nil {
+				return
+			}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			w.Write(append(resp, '\n'))
+		}()
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	wg.Wait()
+	return scanner.Err()
+}
+
+// ============================================================================
+// Streamable HTTP
+// ============================================================================
+
+// ServeHTTP implements the streamable HTTP transport in stateless mode: every
+// POST carries one message and is answered with a JSON body. Authentication
+// and tenant resolution belong to the surrounding middleware, which should
+// store them in the request context with ContextWithAuth.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		// Stateless: there is no session to terminate
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		// No server-initiated stream is offered
+		w.Header().Set("Allow", "POST, DELETE")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxHTTPMessageSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	status, resp := s.HandleHTTPMessage(r.Context(), body)
+	if resp == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(resp)
+}
+
+// HandleHTTPMessage handles a POSTed message and returns the HTTP status and
+// body to send. Notifications are acknowledged with 202 and no body.
+func (s *Server) HandleHTTPMessage(ctx context.Context, body []byte) (int, []byte) {
+	resp := s.HandleMessage(ctx, body)
+	if resp == nil {
+		return http.StatusAccepted, nil
+	}
+	return http.StatusOK, resp
+}
@@ -0,0 +1,263 @@
+package toolxmcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/Abraxas-365/manifesto/pkg/logx"
+)
+
+// StdioTransport runs an MCP server as a subprocess and exchanges
+// newline-delimited JSON-RPC messages over its stdin/stdout.
+type StdioTransport struct {
+	command string
+	args    []string
+	env     []string
+	dir     string
+	stderr  io.Writer
+
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	pending map[int64]chan *Response
+	closed  chan struct{}
+	handler NotificationHandler
+}
+
+// StdioOption configures a StdioTransport
+type StdioOption func(*StdioTransport)
+
+// WithEnv adds environment variables (KEY=VALUE) to the subprocess
+func WithEnv(env ...string) StdioOption {
+	return func(t *StdioTransport) {
+		t.env = append(t.env, env...)
+	}
+}
+
+// WithDir sets the working directory of the subprocess
+func WithDir(dir string) StdioOption {
+	return func(t *StdioTransport) {
+		t.dir = dir
+	}
+}
+
+// WithStderr redirects the subprocess stderr (server logs). Discarded by default.
+func WithStderr(w io.Writer) StdioOption {
+	return func(t *StdioTransport) {
+		t.stderr = w
+	}
+}
+
+// NewStdioTransport creates a transport that launches command with args
+func NewStdioTransport(command string, args []string, opts ...StdioOption) *StdioTransport {
+	t := &StdioTransport{
+		command: command,
+		args:    args,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Connect starts the server process
+func (t *StdioTransport) Connect(ctx context.Context, handler NotificationHandler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cmd != nil {
+		if !t.isClosed() {
+			return nil
+		}
+		// Previous process died — reap it before starting a new one
+		old := t.cmd
+		t.stdin.Close()
+		go old.Wait()
+	}
+
+	// The process must outlive the connect context, so it is not bound to ctx
+	cmd := exec.Command(t.command, t.args...)
+	cmd.Env = append(os.Environ(), t.env...)
+	cmd.Dir = t.dir
+	cmd.Stderr = t.stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return errorRegistry.NewWithCause(ErrConnectionFailed, err).
+			WithDetail("command", t.command)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errorRegistry.NewWithCause(ErrConnectionFailed, err).
+			WithDetail("command", t.command)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return errorRegistry.NewWithCause(ErrConnectionFailed, err).
+			WithDetail("command", t.command)
+	}
+
+	t.cmd = cmd
+	t.stdin = stdin
+	t.pending = make(map[int64]chan *Response)
+	t.closed = make(chan struct{})
+	t.handler = handler
+
+	go t.readLoop(stdout, t.closed)
+
+	return nil
+}
+
+// Call writes the request and waits for the matching response
+func (t *StdioTransport) Call(ctx context.Context, req *Request) (*Response, error) {
+	t.mu.Lock()
+	if t.cmd == nil || t.isClosed() {
+		t.mu.Unlock()
+		return nil, errorRegistry.New(ErrConnectionFailed).
+			WithDetail("method", req.Method)
+	}
+	ch := make(chan *Response, 1)
+	t.pending[req.ID] = ch
+	closed := t.closed
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, req.ID)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-closed:
+		return nil, errorRegistry.New(ErrConnectionClosed).
+			WithDetail("method", req.Method)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Notify writes a notification
+func (t *StdioTransport) Notify(ctx context.Context, n *Notification) error {
+	t.mu.Lock()
+	if t.cmd == nil || t.isClosed() {
+		t.mu.Unlock()
+		return errorRegistry.New(ErrConnectionClosed)
+	}
+	t.mu.Unlock()
+	return t.write(n)
+}
+
+// Close terminates the server process
+func (t *StdioTransport) Close() error {
+	t.mu.Lock()
+	cmd := t.cmd
+	stdin := t.stdin
+	t.cmd = nil
+	t.mu.Unlock()
+
+	if cmd == nil {
+		return nil
+	}
+
+	// Closing stdin asks a well-behaved server to exit
+	stdin.Close()
+	if cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+	cmd.Wait()
+	return nil
+}
+
+func (t *StdioTransport) write(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	// The server reads whole lines, so a failed write was never handled
+	if _, err := t.stdin.Write(data); err != nil {
+		return errorRegistry.NewWithCause(ErrConnectionFailed, err)
+	}
+	return nil
+}
+
+// readLoop dispatches messages from the server until stdout is closed
+func (t *StdioTransport) readLoop(stdout io.Reader, closed chan struct{}) {
+	defer close(closed)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg rawMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			logx.Debugf("toolxmcp: ignoring non JSON-RPC output from %s: %s", t.command, string(line))
+			continue
+		}
+
+		switch {
+		case msg.isResponse():
+			resp, err := msg.response()
+			if err != nil {
+				continue
+			}
+			t.mu.Lock()
+			ch, ok := t.pending[resp.ID]
+			t.mu.Unlock()
+			if ok {
+				ch <- resp
+			}
+
+		case msg.isRequest():
+			t.writeRaw(replyToServerRequest(&msg))
+
+		case msg.isNotification():
+			if t.handler != nil {
+				t.handler(msg.Method, msg.Params)
+			}
+		}
+	}
+}
+
+func (t *StdioTransport) writeRaw(data []byte) {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if t.stdin != nil {
+		t.stdin.Write(append(data, '\n'))
+	}
+}
+
+// isClosed reports whether the read loop has exited. Callers must hold t.mu.
+func (t *StdioTransport) isClosed() bool {
+	if t.closed == nil {
+		return true
+	}
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
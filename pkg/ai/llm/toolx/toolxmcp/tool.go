@@ -0,0 +1,213 @@
+package toolxmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// Tool adapts a tool exposed by an MCP server to toolx.Toolx
+type Tool struct {
+	client     *Client
+	definition ToolDefinition
+	name       string
+}
+
+var _ toolx.Toolx = (*Tool)(nil)
+
+func newTool(client *Client, def ToolDefinition) *Tool {
+	return &Tool{
+		client:     client,
+		definition: def,
+		name:       client.toolPrefix + def.Name,
+	}
+}
+
+// Name returns the (possibly prefixed) tool name
+func (t *Tool) Name() string {
+	return t.name
+}
+
+// Definition returns the tool definition reported by the server
+func (t *Tool) Definition() ToolDefinition {
+	return t.definition
+}
+
+// GetTool converts the MCP definition into an llm.Tool using the server's input schema
+func (t *Tool) GetTool() llm.Tool {
+	description := t.definition.Description
+	if description == "" {
+		description = t.definition.Title
+	}
+
+	return llm.Tool{
+		Type: "function",
+		Function: llm.Function{
+			Name:        t.name,
+			Description: description,
+			Parameters:  schemaParameters(t.definition.InputSchema),
+		},
+	}
+}
+
+// Call forwards the arguments to the server. Text-only results are returned
+// as a string; results carrying images, audio or blobs are returned as
+// []llm.ContentPart.
+func (t *Tool) Call(ctx context.Context, inputs string) (any, error) {
+	var args json.RawMessage
+	if strings.TrimSpace(inputs) != "" {
+		if !json.Valid([]byte(inputs)) {
+			return nil, toolx.InvalidArguments("arguments are not valid JSON").
+				WithDetail("tool", t.name)
+		}
+		args = json.RawMessage(inputs)
+	}
+
+	result, err := t.client.CallTool(ctx, t.definition.Name, args)
+	if err != nil {
+		if isUnknownToolError(err) {
+			return nil, toolx.ToolNotFound(t.definition.Name, err)
+		}
+		return nil, err
+	}
+
+	parts := ContentParts(result)
+
+	if result.IsError {
+		return nil, errorRegistry.NewWithMessage(ErrToolExecution, joinText(parts)).
+			WithDetail("tool", t.definition.Name)
+	}
+
+	if isTextOnly(parts) {
+		return joinText(parts), nil
+	}
+	return parts, nil
+}
+
+// ============================================================================
+// Content Conversion
+// ============================================================================
+
+// ContentParts converts a tool result into llm content parts. When the server
+// returns only structured content, it is rendered as JSON text.
+func ContentParts(result *CallToolResult) []llm.ContentPart {
+	parts := make([]llm.ContentPart, 0, len(result.Content))
+	for _, c := range result.Content {
+		if part, ok := contentPart(c); ok {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 && result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			parts = append(parts, llm.TextPart(string(data)))
+		}
+	}
+
+	return parts
+}
+
+func contentPart(c Content) (llm.ContentPart, bool) {
+	switch c.Type {
+	case ContentTypeText:
+		return llm.TextPart(c.Text), true
+
+	case ContentTypeImage:
+		return llm.ImagePart(fmt.Sprintf("data:%s;base64,%s", c.MimeType, c.Data)), true
+
+	case ContentTypeAudio:
+		return llm.AudioPart(c.Data, audioFormat(c.MimeType)), true
+
+	case ContentTypeResource:
+		if c.Resource == nil {
+			return llm.ContentPart{}, false
+		}
+		if c.Resource.Blob != "" {
+			if strings.HasPrefix(c.Resource.MimeType, "image/") {
+				return llm.ImagePart(fmt.Sprintf("data:%s;base64,%s", c.Resource.MimeType, c.Resource.Blob)), true
+			}
+			return llm.FileDataPart(c.Resource.Blob, path.Base(c.Resource.URI)), true
+		}
+		return llm.TextPart(c.Resource.Text), true
+
+	case ContentTypeResourceLink:
+		text := "Resource: " + c.URI
+		if c.Name != "" {
+			text = fmt.Sprintf("Resource %s: %s", c.Name, c.URI)
+		}
+		if c.Description != "" {
+			text += " (" + c.Description + ")"
+		}
+		return llm.TextPart(text), true
+	}
+
+	return llm.ContentPart{}, false
+}
+
+// audioFormat maps an audio MIME type to the format expected by llm.AudioPart
+func audioFormat(mimeType string) string {
+	switch mimeType {
+	case "audio/mpeg", "audio/mp3":
+		return "mp3"
+	case "audio/wav", "audio/x-wav", "audio/wave":
+		return "wav"
+	}
+	if _, sub, ok := strings.Cut(mimeType, "/"); ok {
+		return sub
+	}
+	return mimeType
+}
+
+func isTextOnly(parts []llm.ContentPart) bool {
+	for _, p := range parts {
+		if p.Type != llm.ContentPartTypeText {
+			return false
+		}
+	}
+	return true
+}
+
+func joinText(parts []llm.ContentPart) string {
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Type == llm.ContentPartTypeText {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// schemaParameters decodes an input schema, defaulting to an empty object schema
+func schemaParameters(schema json.RawMessage) map[string]any {
+	params := map[string]any{}
+	if len(schema) > 0 {
+		json.Unmarshal(schema, &params)
+	}
+	if _, ok := params["type"]; !ok {
+		params["type"] = "object"
+	}
+	if _, ok := params["properties"]; !ok {
+		params["properties"] = map[string]any{}
+	}
+	return params
+}
+
+// isUnknownToolError detects the server rejecting a tool name it doesn't know
+func isUnknownToolError(err error) bool {
+	var rpcErr *RPCError
+	if !errx.As(err, &rpcErr) {
+		return false
+	}
+	if rpcErr.Code == CodeMethodNotFound {
+		return true
+	}
+	msg := strings.ToLower(rpcErr.Message)
+	return rpcErr.Code == CodeInvalidParams &&
+		(strings.Contains(msg, "unknown tool") || strings.Contains(msg, "not found"))
+}
@@ -0,0 +1,74 @@
+package toolxmcpapi
+
+import (
+	"encoding/json"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx/toolxmcp"
+	"github.com/Abraxas-365/manifesto/pkg/iam"
+	"github.com/Abraxas-365/manifesto/pkg/iam/auth"
+	"github.com/Abraxas-365/manifesto/pkg/iam/scopes"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MCPHandlers mounts an MCP server on the Fiber app. Requests are
+// authenticated with JWTs or API keys and scoped to the caller's tenant.
+type MCPHandlers struct {
+	server *toolxmcp.Server
+}
+
+func NewMCPHandlers(server *toolxmcp.Server) *MCPHandlers {
+	return &MCPHandlers{server: server}
+}
+
+// RegisterRoutes exposes the streamable HTTP endpoint at /mcp.
+// mcp:read allows listing tools; calling them requires mcp:execute.
+func (h *MCPHandlers) RegisterRoutes(router fiber.Router, authMiddleware *auth.UnifiedAuthMiddleware) {
+	mcp := router.Group("/mcp",
+		authMiddleware.Authenticate(),
+		authMiddleware.RequireAnyScope(scopes.ScopeMCPAll, scopes.ScopeMCPRead, scopes.ScopeMCPExecute),
+	)
+
+	mcp.Post("/", h.HandleMessage)
+	mcp.Get("/", h.OpenStream)
+	mcp.Delete("/", h.TerminateSession)
+}
+
+func (h *MCPHandlers) HandleMessage(c *fiber.Ctx) error {
+	authContext, ok := auth.GetAuthContext(c)
+	if !ok {
+		return iam.ErrUnauthorized()
+	}
+
+	var peek struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(c.Body(), &peek); err == nil && peek.Method == toolxmcp.MethodToolsCall {
+		if !authContext.HasAnyScope(scopes.ScopeMCPAll, scopes.ScopeMCPExecute) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":          "Insufficient permissions",
+				"required_scope": scopes.ScopeMCPExecute,
+			})
+		}
+	}
+
+	ctx := toolxmcp.ContextWithAuth(c.UserContext(), authContext)
+
+	status, resp := h.server.HandleHTTPMessage(ctx, c.Body())
+	if resp == nil {
+		return c.SendStatus(status)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(status).Send(resp)
+}
+
+// OpenStream rejects server-initiated streams; the server is stateless
+func (h *MCPHandlers) OpenStream(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderAllow, "POST, DELETE")
+	return c.SendStatus(fiber.StatusMethodNotAllowed)
+}
+
+// TerminateSession acknowledges session termination; there is no session state
+func (h *MCPHandlers) TerminateSession(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusOK)
+}
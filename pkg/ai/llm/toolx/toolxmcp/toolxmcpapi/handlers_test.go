@@ -0,0 +1,132 @@
+package toolxmcpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx/toolxmcp"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm/toolx/toolxmcp/toolxmcpapi"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/Abraxas-365/manifesto/pkg/iam/scopes"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+	"github.com/gofiber/fiber/v2"
+)
+
+// tenantTool reports the tenant of the request it runs in
+type tenantTool struct{}
+
+func (tenantTool) Call(ctx context.Context, inputs string) (any, error) {
+	tenantID, _ := toolxmcp.TenantFromContext(ctx)
+	return "tenant " + tenantID.String(), nil
+}
+
+func (tenantTool) GetTool() llm.Tool {
+	return llm.Tool{Type: "function", Function: llm.Function{Name: "whoami"}}
+}
+
+func (tenantTool) Name() string { return "whoami" }
+
+// newApp mounts the handlers behind a middleware that authenticates every
+// request with auth, or leaves it anonymous when auth is nil
+func newApp(auth *kernel.AuthContext) *fiber.App {
+	handlers := toolxmcpapi.NewMCPHandlers(toolxmcp.NewServer("api", "1.0.0",
+		toolxmcp.WithTools(toolx.FromToolx(tenantTool{}))))
+
+	// Like the server's global handler, render errx errors with their status
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		var e *errx.Error
+		if errx.As(err, &e) {
+			return c.Status(e.HTTPStatus).JSON(e)
+		}
+		return fiber.DefaultErrorHandler(c, err)
+	}})
+	app.Use(func(c *fiber.Ctx) error {
+		if auth != nil {
+			c.Locals("auth", auth)
+		}
+		return c.Next()
+	})
+	app.Post("/mcp", handlers.HandleMessage)
+	app.Get("/mcp", handlers.OpenStream)
+	app.Delete("/mcp", handlers.TerminateSession)
+	return app
+}
+
+func send(t *testing.T, app *fiber.App, method, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func apiKey(scope ...string) *kernel.AuthContext {
+	return &kernel.AuthContext{TenantID: kernel.NewTenantID("acme"), Scopes: scope, IsAPIKey: true}
+}
+
+const (
+	listTools = `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`
+	callTool  = `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "whoami", "arguments": {}}}`
+)
+
+func TestHandleMessageScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       *kernel.AuthContext
+		body       string
+		wantStatus int
+	}{
+		{"read lists tools", apiKey(scopes.ScopeMCPRead), listTools, http.StatusOK},
+		{"read can't call tools", apiKey(scopes.ScopeMCPRead), callTool, http.StatusForbidden},
+		{"execute calls tools", apiKey(scopes.ScopeMCPExecute), callTool, http.StatusOK},
+		{"wildcard calls tools", apiKey(scopes.ScopeMCPAll), callTool, http.StatusOK},
+		{"notification", apiKey(scopes.ScopeMCPRead), `{"jsonrpc": "2.0", "method": "notifications/initialized"}`, http.StatusAccepted},
+		{"anonymous", nil, listTools, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := send(t, newApp(tt.auth), http.MethodPost, tt.body)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", status, tt.wantStatus, body)
+			}
+		})
+	}
+}
+
+func TestHandleMessageRunsInTenant(t *testing.T) {
+	status, body := send(t, newApp(apiKey(scopes.ScopeMCPExecute)), http.MethodPost, callTool)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	var resp struct {
+		Result toolxmcp.CallToolResult `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Result.Content) != 1 || resp.Result.Content[0].Text != "tenant acme" {
+		t.Errorf("result = %+v, want the caller's tenant", resp.Result)
+	}
+}
+
+func TestStreamAndSessionEndpoints(t *testing.T) {
+	app := newApp(apiKey(scopes.ScopeMCPRead))
+	if status, _ := send(t, app, http.MethodGet, ""); status != http.StatusMethodNotAllowed {
+		t.Errorf("GET = %d, want 405", status)
+	}
+	if status, _ := send(t, app, http.MethodDelete, ""); status != http.StatusOK {
+		t.Errorf("DELETE = %d, want 200", status)
+	}
+}
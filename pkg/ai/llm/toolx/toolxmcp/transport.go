@@ -0,0 +1,54 @@
+package toolxmcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Transport carries JSON-RPC messages between the client and an MCP server.
+// Implementations must be safe for concurrent use.
+type Transport interface {
+	// Connect establishes the underlying connection. Server notifications are
+	// delivered to handler. Connect may be called again after Close to reconnect.
+	Connect(ctx context.Context, handler NotificationHandler) error
+
+	// Call sends a request and blocks until the matching response arrives
+	Call(ctx context.Context, req *Request) (*Response, error)
+
+	// Notify sends a notification without waiting for a response
+	Notify(ctx context.Context, n *Notification) error
+
+	// Close releases the connection
+	Close() error
+}
+
+// pingResponse answers a server-initiated ping
+func pingResponse(id json.RawMessage) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": jsonRPCVersion,
+		"id":      id,
+		"result":  map[string]any{},
+	})
+	return data
+}
+
+// methodNotFoundResponse rejects a server-initiated request we don't support
+func methodNotFoundResponse(id json.RawMessage, method string) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": jsonRPCVersion,
+		"id":      id,
+		"error": RPCError{
+			Code:    CodeMethodNotFound,
+			Message: "method not supported by client: " + method,
+		},
+	})
+	return data
+}
+
+// replyToServerRequest builds the reply for a server-initiated request
+func replyToServerRequest(msg *rawMessage) []byte {
+	if msg.Method == MethodPing {
+		return pingResponse(msg.ID)
+	}
+	return methodNotFoundResponse(msg.ID, msg.Method)
+}
@@ -0,0 +1,61 @@
+package prompt
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for prompt templates
+	errorRegistry = errx.NewRegistry("PROMPT")
+
+	ErrTemplateNotFound = errorRegistry.Register(
+		"TEMPLATE_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Prompt template not found",
+	)
+
+	ErrVersionNotFound = errorRegistry.Register(
+		"VERSION_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Prompt template version not found",
+	)
+
+	ErrInvalidTemplate = errorRegistry.Register(
+		"INVALID_TEMPLATE",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid prompt template",
+	)
+
+	ErrMissingVariable = errorRegistry.Register(
+		"MISSING_VARIABLE",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Required prompt variable is missing",
+	)
+
+	ErrInvalidVariable = errorRegistry.Register(
+		"INVALID_VARIABLE",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Prompt variable has the wrong type",
+	)
+
+	ErrRenderFailed = errorRegistry.Register(
+		"RENDER_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Failed to render prompt template",
+	)
+
+	ErrLoadFailed = errorRegistry.Register(
+		"LOAD_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Failed to load prompt templates",
+	)
+)
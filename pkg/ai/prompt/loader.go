@@ -0,0 +1,161 @@
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/fsx"
+	"github.com/Abraxas-365/manifesto/pkg/logx"
+)
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+// LoadFromFS loads templates from a file system (local or S3). Layout:
+//
+//	<root>/partials/<name>.tmpl     partials, available as {{template "<name>" .}}
+//	<root>/<name>/<version>.json    one file per version
+//	<root>/<name>.json              a single version (name and version inside)
+//
+// Templates already in the registry are kept; loaded versions replace
+// versions with the same name.
+func (r *Registry) LoadFromFS(ctx context.Context, fs fsx.FileReader, root string) error {
+	partials, err := loadPartials(ctx, fs, path.Join(root, "partials"))
+	if err != nil {
+		return err
+	}
+
+	defs, err := loadTemplates(ctx, fs, root)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	merged := maps.Clone(r.partials)
+	maps.Copy(merged, partials)
+
+	// Compile everything into fresh maps so a bad file leaves the registry intact
+	all := r.definitionsLocked()
+	for _, def := range defs {
+		if all[def.Name] == nil {
+			all[def.Name] = make(map[string]*Template)
+		}
+		all[def.Name][def.Version] = def
+	}
+	return r.replaceLocked(all, merged)
+}
+
+// StartAutoReload reloads templates every interval until ctx is done, so
+// prompt edits are picked up without a redeploy. Errors are logged and the
+// previous templates stay in place.
+func (r *Registry) StartAutoReload(ctx context.Context, fs fsx.FileReader, root string, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.LoadFromFS(ctx, fs, root); err != nil {
+					logx.WithError(err).Warnf("prompt: reload from %s failed", root)
+				}
+			}
+		}
+	}()
+}
+
+func loadPartials(ctx context.Context, fs fsx.FileReader, dir string) (map[string]string, error) {
+	partials := make(map[string]string)
+
+	exists, err := fs.Exists(ctx, dir)
+	if err != nil || !exists {
+		return partials, nil
+	}
+
+	entries, err := fs.List(ctx, dir)
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).WithDetail("path", dir)
+	}
+	for _, entry := range entries {
+		if entry.IsDir || !strings.HasSuffix(entry.Name, ".tmpl") {
+			continue
+		}
+		p := path.Join(dir, entry.Name)
+		data, err := fs.ReadFile(ctx, p)
+		if err != nil {
+			return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).WithDetail("path", p)
+		}
+		partials[strings.TrimSuffix(entry.Name, ".tmpl")] = strings.TrimRight(string(data), "\n")
+	}
+	return partials, nil
+}
+
+func loadTemplates(ctx context.Context, fs fsx.FileReader, root string) ([]*Template, error) {
+	entries, err := fs.List(ctx, root)
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).WithDetail("path", root)
+	}
+
+	var defs []*Template
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir && entry.Name == "partials":
+			continue
+
+		case entry.IsDir:
+			dir := path.Join(root, entry.Name)
+			files, err := fs.List(ctx, dir)
+			if err != nil {
+				return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).WithDetail("path", dir)
+			}
+			for _, file := range files {
+				if file.IsDir || !strings.HasSuffix(file.Name, ".json") {
+					continue
+				}
+				def, err := readTemplate(ctx, fs, path.Join(dir, file.Name))
+				if err != nil {
+					return nil, err
+				}
+				if def.Name == "" {
+					def.Name = entry.Name
+				}
+				if def.Version == "" {
+					def.Version = strings.TrimSuffix(file.Name, ".json")
+				}
+				defs = append(defs, def)
+			}
+
+		case strings.HasSuffix(entry.Name, ".json"):
+			def, err := readTemplate(ctx, fs, path.Join(root, entry.Name))
+			if err != nil {
+				return nil, err
+			}
+			if def.Name == "" {
+				def.Name = strings.TrimSuffix(entry.Name, ".json")
+			}
+			defs = append(defs, def)
+		}
+	}
+	return defs, nil
+}
+
+func readTemplate(ctx context.Context, fs fsx.FileReader, p string) (*Template, error) {
+	data, err := fs.ReadFile(ctx, p)
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).WithDetail("path", p)
+	}
+	var def Template
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, errorRegistry.NewWithCause(ErrInvalidTemplate, err).WithDetail("path", p)
+	}
+	return &def, nil
+}
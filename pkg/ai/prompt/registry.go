@@ -0,0 +1,628 @@
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/iam/tenant"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+)
+
+// Tenant setting keys. A tenant can pin a version, split traffic between
+// versions or replace the template entirely:
+//
+//	prompt.<name>.version  = "v2"
+//	prompt.<name>.variants = "v1:90,v2:10"
+//	prompt.<name>.override = {"messages": [...], ...}
+const settingPrefix = "prompt."
+
+// ============================================================================
+// Registry
+// ============================================================================
+
+// Registry holds named, versioned prompt templates
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]map[string]*compiled
+	partials  map[string]string
+	active    map[string]string
+	traffic   map[string][]variant
+
+	tenantConfig tenant.TenantConfigRepository
+	cacheTTL     time.Duration
+	cacheMu      sync.Mutex
+	cache        map[kernel.TenantID]cachedSettings
+	overrides    map[string]cachedOverride
+}
+
+type variant struct {
+	version string
+	weight  int
+}
+
+// cachedOverride is a compiled tenant override and the hash of its source
+type cachedOverride struct {
+	hash     string
+	compiled *compiled
+}
+
+type cachedSettings struct {
+	settings  map[string]string
+	expiresAt time.Time
+}
+
+// RegistryOption configures a Registry
+type RegistryOption func(*Registry)
+
+// WithTenantConfig enables per-tenant version pins, traffic splits and
+// template overrides read from tenant settings
+func WithTenantConfig(repo tenant.TenantConfigRepository) RegistryOption {
+	return func(r *Registry) {
+		r.tenantConfig = repo
+	}
+}
+
+// WithTenantCacheTTL caches tenant settings for the given duration
+// (default 1 minute, 0 disables caching)
+func WithTenantCacheTTL(ttl time.Duration) RegistryOption {
+	return func(r *Registry) {
+		r.cacheTTL = ttl
+	}
+}
+
+// WithPartial registers a named partial usable as {{template "name" .}}
+func WithPartial(name, source string) RegistryOption {
+	return func(r *Registry) {
+		r.partials[name] = source
+	}
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		templates: make(map[string]map[string]*compiled),
+		partials:  make(map[string]string),
+		active:    make(map[string]string),
+		traffic:   make(map[string][]variant),
+		cacheTTL:  time.Minute,
+		cache:     make(map[kernel.TenantID]cachedSettings),
+		overrides: make(map[string]cachedOverride),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register compiles and adds a template version
+func (r *Registry) Register(def Template) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, err := compile(&def, r.partials)
+	if err != nil {
+		return err
+	}
+	if r.templates[def.Name] == nil {
+		r.templates[def.Name] = make(map[string]*compiled)
+	}
+	r.templates[def.Name][def.Version] = c
+	return nil
+}
+
+// RegisterPartial adds a partial and recompiles existing templates. If a
+// template fails to compile with the new partial, the registry is left
+// unchanged.
+func (r *Registry) RegisterPartial(name, source string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	partials := maps.Clone(r.partials)
+	partials[name] = source
+	return r.replaceLocked(r.definitionsLocked(), partials)
+}
+
+// definitionsLocked returns the definitions of every registered version
+func (r *Registry) definitionsLocked() map[string]map[string]*Template {
+	defs := make(map[string]map[string]*Template, len(r.templates))
+	for name, versions := range r.templates {
+		defs[name] = make(map[string]*Template, len(versions))
+		for version, c := range versions {
+			defs[name][version] = c.def
+		}
+	}
+	return defs
+}
+
+// replaceLocked compiles defs with partials into fresh maps and installs
+// them only when every template compiles
+func (r *Registry) replaceLocked(defs map[string]map[string]*Template, partials map[string]string) error {
+	templates := make(map[string]map[string]*compiled, len(defs))
+	for name, versions := range defs {
+		templates[name] = make(map[string]*compiled, len(versions))
+		for version, def := range versions {
+			c, err := compile(def, partials)
+			if err != nil {
+				return err
+			}
+			templates[name][version] = c
+		}
+	}
+
+	r.templates = templates
+	r.partials = partials
+	r.clearOverrides()
+	return nil
+}
+
+// SetActive sets the default version of a template
+func (r *Registry) SetActive(name, version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.lookupLocked(name, version); err != nil {
+		return err
+	}
+	r.active[name] = version
+	return nil
+}
+
+// SetTraffic splits traffic between versions by weight, e.g.
+// {"v1": 90, "v2": 10}. An empty map removes the split.
+func (r *Registry) SetTraffic(name string, weights map[string]int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	variants := make([]variant, 0, len(weights))
+	for version, weight := range weights {
+		if _, err := r.lookupLocked(name, version); err != nil {
+			return err
+		}
+		if weight > 0 {
+			variants = append(variants, variant{version: version, weight: weight})
+		}
+	}
+	if len(variants) == 0 {
+		delete(r.traffic, name)
+		return nil
+	}
+	sortVariants(variants)
+	r.traffic[name] = variants
+	return nil
+}
+
+// Names returns the registered template names
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of a template, oldest first
+func (r *Registry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sortedVersions(r.templates[name])
+}
+
+// Get returns a template definition. An empty version returns the active one.
+func (r *Registry) Get(name, version string) (*Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if version == "" {
+		version = r.defaultVersionLocked(name)
+	}
+	c, err := r.lookupLocked(name, version)
+	if err != nil {
+		return nil, err
+	}
+	def := *c.def
+	return &def, nil
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// Rendered is a rendered prompt with the version that produced it
+type Rendered struct {
+	Name     string        `json:"name"`
+	Version  string        `json:"version"`
+	Tenant   string        `json:"tenant,omitempty"`
+	Override bool          `json:"override,omitempty"`
+	Messages []llm.Message `json:"messages"`
+}
+
+// RenderOption configures a single render
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	tenantID  kernel.TenantID
+	version   string
+	bucketKey string
+}
+
+// WithTenant applies the tenant's prompt settings
+func WithTenant(tenantID kernel.TenantID) RenderOption {
+	return func(o *renderOptions) {
+		o.tenantID = tenantID
+	}
+}
+
+// WithVersion forces a version, bypassing pins and traffic splits
+func WithVersion(version string) RenderOption {
+	return func(o *renderOptions) {
+		o.version = version
+	}
+}
+
+// WithBucketKey sets the key (usually a user or session ID) used to assign
+// a traffic-split variant. The same key always gets the same version.
+func WithBucketKey(key string) RenderOption {
+	return func(o *renderOptions) {
+		o.bucketKey = key
+	}
+}
+
+// Render renders a template into messages
+func (r *Registry) Render(ctx context.Context, name string, vars Vars, opts ...RenderOption) ([]llm.Message, error) {
+	rendered, err := r.RenderPrompt(ctx, name, vars, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return rendered.Messages, nil
+}
+
+// RenderSystem renders a template and returns the content of its system
+// messages, for use as an agent's system prompt
+func (r *Registry) RenderSystem(ctx context.Context, name string, vars Vars, opts ...RenderOption) (string, error) {
+	messages, err := r.Render(ctx, name, vars, opts...)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, msg := range messages {
+		if msg.Role == llm.RoleSystem {
+			parts = append(parts, msg.Content)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// RenderPrompt renders a template and reports which version was used
+func (r *Registry) RenderPrompt(ctx context.Context, name string, vars Vars, opts ...RenderOption) (*Rendered, error) {
+	o := &renderOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.tenantID == "" {
+		if tenantID, ok := ctx.Value(kernel.TenantContextKey).(kernel.TenantID); ok {
+			o.tenantID = tenantID
+		}
+	}
+
+	c, override, err := r.resolve(ctx, name, o)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.render(vars)
+	if err != nil {
+		return nil, err
+	}
+	return &Rendered{
+		Name:     name,
+		Version:  c.def.Version,
+		Tenant:   o.tenantID.String(),
+		Override: override,
+		Messages: messages,
+	}, nil
+}
+
+// resolve picks the template to render: explicit version, tenant override,
+// tenant pin, tenant split, global split, active version, latest version
+func (r *Registry) resolve(ctx context.Context, name string, o *renderOptions) (*compiled, bool, error) {
+	var settings map[string]string
+	if o.tenantID != "" && r.tenantConfig != nil {
+		var err error
+		settings, err = r.tenantSettings(ctx, o.tenantID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if o.version != "" {
+		return r.lookup(name, o.version)
+	}
+
+	if src, ok := settings[settingPrefix+name+".override"]; ok && src != "" {
+		c, err := r.tenantOverride(o.tenantID, name, src)
+		return c, true, err
+	}
+
+	if version := settings[settingPrefix+name+".version"]; version != "" {
+		return r.lookup(name, version)
+	}
+
+	if spec := settings[settingPrefix+name+".variants"]; spec != "" {
+		variants, err := parseVariants(spec)
+		if err != nil {
+			return nil, false, errorRegistry.NewWithCause(ErrInvalidTemplate, err).
+				WithDetail("tenant", o.tenantID).
+				WithDetail("setting", settingPrefix+name+".variants")
+		}
+		return r.lookup(name, pickVariant(variants, name, o))
+	}
+
+	r.mu.RLock()
+	variants := r.traffic[name]
+	version := r.defaultVersionLocked(name)
+	r.mu.RUnlock()
+
+	if len(variants) > 0 {
+		version = pickVariant(variants, name, o)
+	}
+	return r.lookup(name, version)
+}
+
+func (r *Registry) lookup(name, version string) (*compiled, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, err := r.lookupLocked(name, version)
+	return c, false, err
+}
+
+func (r *Registry) lookupLocked(name, version string) (*compiled, error) {
+	versions, ok := r.templates[name]
+	if !ok {
+		return nil, errorRegistry.New(ErrTemplateNotFound).WithDetail("template", name)
+	}
+	c, ok := versions[version]
+	if !ok {
+		return nil, errorRegistry.New(ErrVersionNotFound).
+			WithDetail("template", name).
+			WithDetail("version", version)
+	}
+	return c, nil
+}
+
+// defaultVersionLocked returns the active version or the latest one
+func (r *Registry) defaultVersionLocked(name string) string {
+	if version, ok := r.active[name]; ok {
+		return version
+	}
+	versions := sortedVersions(r.templates[name])
+	if len(versions) == 0 {
+		return ""
+	}
+	return versions[len(versions)-1]
+}
+
+// ============================================================================
+// Tenant Settings
+// ============================================================================
+
+func (r *Registry) tenantSettings(ctx context.Context, tenantID kernel.TenantID) (map[string]string, error) {
+	if r.cacheTTL > 0 {
+		r.cacheMu.Lock()
+		cached, ok := r.cache[tenantID]
+		r.cacheMu.Unlock()
+		if ok && time.Now().Before(cached.expiresAt) {
+			return cached.settings, nil
+		}
+	}
+
+	settings, err := r.tenantConfig.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errorRegistry.NewWithCause(ErrLoadFailed, err).
+			WithDetail("tenant", tenantID)
+	}
+
+	if r.cacheTTL > 0 {
+		r.cacheMu.Lock()
+		r.cache[tenantID] = cachedSettings{settings: settings, expiresAt: time.Now().Add(r.cacheTTL)}
+		r.cacheMu.Unlock()
+	}
+	return settings, nil
+}
+
+// InvalidateTenant drops cached settings for a tenant
+func (r *Registry) InvalidateTenant(tenantID kernel.TenantID) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+
+	delete(r.cache, tenantID)
+	prefix := tenantID.String() + "/"
+	for key := range r.overrides {
+		if strings.HasPrefix(key, prefix) {
+			delete(r.overrides, key)
+		}
+	}
+}
+
+// tenantOverride compiles a tenant's template override. One compiled
+// override is cached per tenant and template, and replaced when its source
+// changes, so edits take effect once the settings cache expires.
+func (r *Registry) tenantOverride(tenantID kernel.TenantID, name, src string) (*compiled, error) {
+	key := tenantID.String() + "/" + name
+	hash := hashString(src)
+
+	r.cacheMu.Lock()
+	cached, ok := r.overrides[key]
+	r.cacheMu.Unlock()
+	if ok && cached.hash == hash {
+		return cached.compiled, nil
+	}
+
+	var def Template
+	if err := json.Unmarshal([]byte(src), &def); err != nil {
+		return nil, errorRegistry.NewWithCause(ErrInvalidTemplate, err).
+			WithDetail("tenant", tenantID).
+			WithDetail("template", name)
+	}
+	def.Name = name
+	if def.Version == "" {
+		def.Version = "tenant-" + tenantID.String()
+	}
+
+	r.mu.RLock()
+	c, err := compile(&def, r.partials)
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	r.cacheMu.Lock()
+	r.overrides[key] = cachedOverride{hash: hash, compiled: c}
+	r.cacheMu.Unlock()
+	return c, nil
+}
+
+func (r *Registry) clearOverrides() {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	r.overrides = make(map[string]cachedOverride)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// parseVariants parses "v1:90,v2:10"
+func parseVariants(spec string) ([]variant, error) {
+	var variants []variant
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		version, weight, found := strings.Cut(part, ":")
+		w := 1
+		if found {
+			var err error
+			if w, err = strconv.Atoi(strings.TrimSpace(weight)); err != nil {
+				return nil, err
+			}
+		}
+		if w > 0 {
+			variants = append(variants, variant{version: strings.TrimSpace(version), weight: w})
+		}
+	}
+	sortVariants(variants)
+	return variants, nil
+}
+
+// pickVariant assigns a version deterministically from the bucket key
+// (falling back to the tenant) so a user keeps seeing the same variant
+func pickVariant(variants []variant, name string, o *renderOptions) string {
+	if len(variants) == 0 {
+		return ""
+	}
+	total := 0
+	for _, v := range variants {
+		total += v.weight
+	}
+
+	key := o.bucketKey
+	if key == "" {
+		key = o.tenantID.String()
+	}
+	bucket := int(hashUint(name+"/"+key) % uint64(total))
+
+	for _, v := range variants {
+		if bucket < v.weight {
+			return v.version
+		}
+		bucket -= v.weight
+	}
+	return variants[len(variants)-1].version
+}
+
+func sortVariants(variants []variant) {
+	sort.Slice(variants, func(i, j int) bool {
+		return compareVersions(variants[i].version, variants[j].version) < 0
+	})
+}
+
+func sortedVersions(versions map[string]*compiled) []string {
+	out := make([]string, 0, len(versions))
+	for v := range versions {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return compareVersions(out[i], out[j]) < 0
+	})
+	return out
+}
+
+// compareVersions orders versions so "v2" < "v10" and "1.2" < "1.10"
+func compareVersions(a, b string) int {
+	as := splitVersion(a)
+	bs := splitVersion(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return an - bn
+			}
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// splitVersion splits "v1.2-beta" into ["v", "1", "2", "beta"]
+func splitVersion(v string) []string {
+	var parts []string
+	var current strings.Builder
+	digit := false
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+	for _, ch := range v {
+		isDigit := ch >= '0' && ch <= '9'
+		switch {
+		case ch == '.' || ch == '-' || ch == '_':
+			flush()
+			continue
+		case current.Len() > 0 && isDigit != digit:
+			flush()
+		}
+		digit = isDigit
+		current.WriteRune(ch)
+	}
+	flush()
+	return parts
+}
+
+func hashUint(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func hashString(s string) string {
+	return strconv.FormatUint(hashUint(s), 36)
+}
@@ -0,0 +1,248 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/Abraxas-365/manifesto/pkg/fsx/fsxlocal"
+	"github.com/Abraxas-365/manifesto/pkg/kernel"
+)
+
+func greeting(version, content string) Template {
+	return Template{
+		Name:     "greeting",
+		Version:  version,
+		Messages: []MessageTemplate{{Role: "system", Content: content}},
+	}
+}
+
+func errorCode(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+func renderSystem(t *testing.T, r *Registry, opts ...RenderOption) string {
+	t.Helper()
+	system, err := r.RenderSystem(context.Background(), "greeting", Vars{"name": "Ada"}, opts...)
+	if err != nil {
+		t.Fatalf("RenderSystem: %v", err)
+	}
+	return system
+}
+
+func TestRegistryVersions(t *testing.T) {
+	r := NewRegistry()
+	for _, version := range []string{"v2", "v10", "v1"} {
+		if err := r.Register(greeting(version, "Hello "+version)); err != nil {
+			t.Fatalf("Register %s: %v", version, err)
+		}
+	}
+
+	if got, want := r.Versions("greeting"), []string{"v1", "v2", "v10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions = %v, want %v", got, want)
+	}
+	if got := renderSystem(t, r); got != "Hello v10" {
+		t.Errorf("default render = %q, want the latest version", got)
+	}
+
+	if err := r.SetActive("greeting", "v2"); err != nil {
+		t.Fatalf("SetActive: %v", err)
+	}
+	if got := renderSystem(t, r); got != "Hello v2" {
+		t.Errorf("render = %q, want the active version", got)
+	}
+	if got := renderSystem(t, r, WithVersion("v1")); got != "Hello v1" {
+		t.Errorf("render with version = %q, want v1", got)
+	}
+
+	if err := r.SetActive("greeting", "v3"); errorCode(err) != ErrVersionNotFound.Code {
+		t.Errorf("SetActive unknown version = %v, want %s", err, ErrVersionNotFound.Code)
+	}
+	if _, err := r.Get("missing", ""); errorCode(err) != ErrTemplateNotFound.Code {
+		t.Errorf("Get unknown template = %v, want %s", err, ErrTemplateNotFound.Code)
+	}
+}
+
+func TestRegistryTrafficSplit(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry()
+	for _, version := range []string{"v1", "v2"} {
+		if err := r.Register(greeting(version, version)); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	if err := r.SetTraffic("greeting", map[string]int{"v1": 50, "v2": 50}); err != nil {
+		t.Fatalf("SetTraffic: %v", err)
+	}
+
+	counts := map[string]int{}
+	for i := range 200 {
+		key := string(rune('a'+i%26)) + string(rune('a'+i/26))
+		first, err := r.RenderPrompt(ctx, "greeting", nil, WithBucketKey(key))
+		if err != nil {
+			t.Fatalf("RenderPrompt: %v", err)
+		}
+		again, err := r.RenderPrompt(ctx, "greeting", nil, WithBucketKey(key))
+		if err != nil {
+			t.Fatalf("RenderPrompt: %v", err)
+		}
+		if first.Version != again.Version {
+			t.Fatalf("key %s got %s then %s", key, first.Version, again.Version)
+		}
+		counts[first.Version]++
+	}
+	if counts["v1"] < 60 || counts["v2"] < 60 {
+		t.Errorf("split = %v, want both versions well represented", counts)
+	}
+
+	if err := r.SetTraffic("greeting", map[string]int{"v3": 10}); errorCode(err) != ErrVersionNotFound.Code {
+		t.Errorf("SetTraffic unknown version = %v, want %s", err, ErrVersionNotFound.Code)
+	}
+
+	// Removing the split falls back to the latest version
+	if err := r.SetTraffic("greeting", nil); err != nil {
+		t.Fatalf("SetTraffic(nil): %v", err)
+	}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if got := renderSystem(t, r, WithBucketKey(key)); got != "v2" {
+			t.Errorf("render after removing split = %q, want v2", got)
+		}
+	}
+}
+
+func TestRegistryPartials(t *testing.T) {
+	r := NewRegistry(WithPartial("who", "{{.name}}"))
+	if err := r.Register(greeting("v1", `Hello {{template "who" .}}`)); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if got := renderSystem(t, r); got != "Hello Ada" {
+		t.Errorf("render = %q, want %q", got, "Hello Ada")
+	}
+
+	// Existing templates pick up a replaced partial
+	if err := r.RegisterPartial("who", "dear {{.name}}"); err != nil {
+		t.Fatalf("RegisterPartial: %v", err)
+	}
+	if got := renderSystem(t, r); got != "Hello dear Ada" {
+		t.Errorf("render = %q, want %q", got, "Hello dear Ada")
+	}
+
+	// A partial that doesn't compile leaves the registry unchanged
+	if err := r.RegisterPartial("who", "{{.name"); errorCode(err) != ErrInvalidTemplate.Code {
+		t.Fatalf("RegisterPartial broken = %v, want %s", err, ErrInvalidTemplate.Code)
+	}
+	if got := renderSystem(t, r); got != "Hello dear Ada" {
+		t.Errorf("render after failed partial = %q, want %q", got, "Hello dear Ada")
+	}
+	if err := r.Register(greeting("v2", `Hi {{template "who" .}}`)); err != nil {
+		t.Fatalf("Register after failed partial: %v", err)
+	}
+	if got := renderSystem(t, r); got != "Hi dear Ada" {
+		t.Errorf("render = %q, want %q", got, "Hi dear Ada")
+	}
+}
+
+// tenantSettings is an in-memory tenant.TenantConfigRepository
+type tenantSettings map[kernel.TenantID]map[string]string
+
+func (s tenantSettings) FindByTenant(_ context.Context, tenantID kernel.TenantID) (map[string]string, error) {
+	return s[tenantID], nil
+}
+
+func (s tenantSettings) SaveSetting(_ context.Context, tenantID kernel.TenantID, key, value string) error {
+	if s[tenantID] == nil {
+		s[tenantID] = make(map[string]string)
+	}
+	s[tenantID][key] = value
+	return nil
+}
+
+func (s tenantSettings) DeleteSetting(_ context.Context, tenantID kernel.TenantID, key string) error {
+	delete(s[tenantID], key)
+	return nil
+}
+
+func TestRegistryTenantOverrideReplacesEdits(t *testing.T) {
+	ctx := context.Background()
+	settings := tenantSettings{}
+	r := NewRegistry(WithTenantConfig(settings), WithTenantCacheTTL(0))
+	if err := r.Register(greeting("v1", "Hello {{.name}}")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	for _, greet := range []string{"Hi", "Hey", "Howdy"} {
+		override := `{"messages": [{"role": "system", "content": "` + greet + ` {{.name}}"}]}`
+		settings.SaveSetting(ctx, "acme", "prompt.greeting.override", override)
+		if got, want := renderSystem(t, r, WithTenant("acme")), greet+" Ada"; got != want {
+			t.Fatalf("render = %q, want %q", got, want)
+		}
+	}
+	if len(r.overrides) != 1 {
+		t.Errorf("cached overrides = %d, want 1 per tenant and template", len(r.overrides))
+	}
+}
+
+func TestLoadFromFSKeepsRegistryOnFailure(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs, err := fsxlocal.NewLocalFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewLocalFileSystem: %v", err)
+	}
+
+	write("partials/who.tmpl", "{{.name}}\n")
+	write("greeting/v1.json", `{"messages": [{"role": "system", "content": "Hello {{template \"who\" .}}"}]}`)
+
+	r := NewRegistry()
+	if err := r.LoadFromFS(ctx, fs, "."); err != nil {
+		t.Fatalf("LoadFromFS: %v", err)
+	}
+	if got := renderSystem(t, r); got != "Hello Ada" {
+		t.Fatalf("render = %q, want %q", got, "Hello Ada")
+	}
+
+	tests := []struct {
+		name string
+		file string
+		body string
+	}{
+		{"broken partial", "partials/who.tmpl", "{{.name"},
+		{"broken template", "greeting/v2.json", `{"messages": [{"role": "system", "content": "{{.name"}]}`},
+		{"invalid json", "greeting/v2.json", `{"messages": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write("partials/who.tmpl", "{{.name}}\n")
+			write("greeting/v2.json", `{"messages": [{"role": "system", "content": "Hi {{template \"who\" .}}"}]}`)
+			write(tt.file, tt.body)
+
+			if err := r.LoadFromFS(ctx, fs, "."); err == nil {
+				t.Fatal("LoadFromFS accepted a broken file")
+			}
+			if got := r.Versions("greeting"); !reflect.DeepEqual(got, []string{"v1"}) {
+				t.Errorf("Versions = %v, want [v1]", got)
+			}
+			if got := renderSystem(t, r); got != "Hello Ada" {
+				t.Errorf("render = %q, want %q", got, "Hello Ada")
+			}
+		})
+	}
+}
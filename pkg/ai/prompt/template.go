@@ -0,0 +1,355 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Template Definition
+// ============================================================================
+
+// Template is a named, versioned prompt. Message contents are Go
+// text/template sources rendered with the declared variables.
+type Template struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Description string            `json:"description,omitempty"`
+	Variables   []Variable        `json:"variables,omitempty"`
+	Messages    []MessageTemplate `json:"messages"`
+
+	// Examples are few-shot pairs. With ExampleFormat "messages" (default)
+	// they are inserted as user/assistant turns after the system messages;
+	// with "inline" they are only available to templates as .examples.
+	Examples      []Example     `json:"examples,omitempty"`
+	ExampleFormat ExampleFormat `json:"example_format,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MessageTemplate is one templated message
+type MessageTemplate struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Example is a few-shot input/output pair
+type Example struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// ExampleFormat controls how few-shot examples are rendered
+type ExampleFormat string
+
+const (
+	ExampleFormatMessages ExampleFormat = "messages"
+	ExampleFormatInline   ExampleFormat = "inline"
+)
+
+// VariableType is the declared type of a template variable
+type VariableType string
+
+const (
+	VarString VariableType = "string"
+	VarInt    VariableType = "int"
+	VarFloat  VariableType = "float"
+	VarBool   VariableType = "bool"
+	VarList   VariableType = "list"
+	VarObject VariableType = "object"
+	VarAny    VariableType = "any"
+)
+
+// Variable declares a template input
+type Variable struct {
+	Name        string       `json:"name"`
+	Type        VariableType `json:"type,omitempty"`
+	Required    bool         `json:"required,omitempty"`
+	Default     any          `json:"default,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+// Vars are the values passed to Render
+type Vars map[string]any
+
+// ============================================================================
+// Compilation
+// ============================================================================
+
+// compiled is a parsed template ready to render
+type compiled struct {
+	def      *Template
+	messages []*template.Template
+}
+
+// compile parses every message with the given partials
+func compile(def *Template, partials map[string]string) (*compiled, error) {
+	if def.Name == "" || def.Version == "" {
+		return nil, errorRegistry.New(ErrInvalidTemplate).
+			WithDetail("reason", "name and version are required")
+	}
+	if len(def.Messages) == 0 {
+		return nil, errorRegistry.New(ErrInvalidTemplate).
+			WithDetail("template", def.Name).
+			WithDetail("reason", "at least one message is required")
+	}
+
+	c := &compiled{def: def}
+	for i, msg := range def.Messages {
+		t := template.New(fmt.Sprintf("%s@%s#%d", def.Name, def.Version, i)).
+			Funcs(funcMap()).
+			Option("missingkey=error")
+
+		for name, src := range partials {
+			if _, err := t.New(name).Parse(src); err != nil {
+				return nil, errorRegistry.NewWithCause(ErrInvalidTemplate, err).
+					WithDetail("partial", name)
+			}
+		}
+
+		parsed, err := t.Parse(msg.Content)
+		if err != nil {
+			return nil, errorRegistry.NewWithCause(ErrInvalidTemplate, err).
+				WithDetail("template", def.Name).
+				WithDetail("version", def.Version).
+				WithDetail("message", i)
+		}
+		c.messages = append(c.messages, parsed)
+	}
+	return c, nil
+}
+
+// render validates the variables and renders the messages
+func (c *compiled) render(vars Vars) ([]llm.Message, error) {
+	data, err := c.bind(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []llm.Message
+	examplesInserted := false
+
+	for i, t := range c.messages {
+		role := c.def.Messages[i].Role
+
+		if !examplesInserted && role != llm.RoleSystem && c.def.ExampleFormat != ExampleFormatInline {
+			messages = append(messages, exampleMessages(c.def.Examples)...)
+			examplesInserted = true
+		}
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, errorRegistry.NewWithCause(ErrRenderFailed, err).
+				WithDetail("template", c.def.Name).
+				WithDetail("version", c.def.Version)
+		}
+
+		messages = append(messages, llm.Message{
+			Role:    role,
+			Content: strings.TrimSpace(buf.String()),
+			Metadata: map[string]any{
+				"prompt":         c.def.Name,
+				"prompt_version": c.def.Version,
+			},
+		})
+	}
+
+	if !examplesInserted && c.def.ExampleFormat != ExampleFormatInline {
+		messages = append(messages, exampleMessages(c.def.Examples)...)
+	}
+
+	return messages, nil
+}
+
+// bind applies defaults and checks required variables and types
+func (c *compiled) bind(vars Vars) (map[string]any, error) {
+	data := make(map[string]any, len(vars)+1)
+	maps.Copy(data, vars)
+
+	for _, v := range c.def.Variables {
+		value, ok := data[v.Name]
+		if !ok || value == nil {
+			if v.Default != nil {
+				data[v.Name] = v.Default
+				continue
+			}
+			if v.Required {
+				return nil, errorRegistry.New(ErrMissingVariable).
+					WithDetail("template", c.def.Name).
+					WithDetail("variable", v.Name)
+			}
+			// Optional variables render as their zero value, not "<no value>"
+			data[v.Name] = zeroValue(v.Type)
+			continue
+		}
+
+		coerced, err := coerce(v.Type, value)
+		if err != nil {
+			return nil, errorRegistry.NewWithCause(ErrInvalidVariable, err).
+				WithDetail("template", c.def.Name).
+				WithDetail("variable", v.Name).
+				WithDetail("expected", v.Type)
+		}
+		data[v.Name] = coerced
+	}
+
+	if _, ok := data["examples"]; !ok {
+		data["examples"] = c.def.Examples
+	}
+	return data, nil
+}
+
+func exampleMessages(examples []Example) []llm.Message {
+	messages := make([]llm.Message, 0, len(examples)*2)
+	for _, ex := range examples {
+		messages = append(messages,
+			llm.NewUserMessage(ex.Input),
+			llm.NewAssistantMessage(ex.Output),
+		)
+	}
+	return messages
+}
+
+// coerce converts value to the declared type, accepting string forms of
+// scalars (as stored in tenant settings)
+func coerce(t VariableType, value any) (any, error) {
+	switch t {
+	case "", VarAny:
+		return value, nil
+
+	case VarString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case fmt.Stringer:
+			return v.String(), nil
+		}
+		return nil, fmt.Errorf("expected string, got %T", value)
+
+	case VarInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case int32:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		case string:
+			return strconv.Atoi(strings.TrimSpace(v))
+		}
+		return nil, fmt.Errorf("expected int, got %T", value)
+
+	case VarFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+		return nil, fmt.Errorf("expected float, got %T", value)
+
+	case VarBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		}
+		return nil, fmt.Errorf("expected bool, got %T", value)
+
+	case VarList:
+		switch v := value.(type) {
+		case []any, []string, []int, []float64, []map[string]any:
+			return v, nil
+		case string:
+			var list []any
+			if err := json.Unmarshal([]byte(v), &list); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("expected list, got %T", value)
+
+	case VarObject:
+		switch v := value.(type) {
+		case map[string]any, map[string]string:
+			return v, nil
+		case string:
+			var obj map[string]any
+			if err := json.Unmarshal([]byte(v), &obj); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		}
+		return nil, fmt.Errorf("expected object, got %T", value)
+	}
+
+	return nil, fmt.Errorf("unknown variable type %q", t)
+}
+
+func zeroValue(t VariableType) any {
+	switch t {
+	case VarInt:
+		return 0
+	case VarFloat:
+		return 0.0
+	case VarBool:
+		return false
+	case VarList:
+		return []any{}
+	case VarObject:
+		return map[string]any{}
+	}
+	return ""
+}
+
+// funcMap provides helpers commonly needed in prompts
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"default": func(def, value any) any {
+			if value == nil || value == "" {
+				return def
+			}
+			return value
+		},
+		"bullets": func(items any) string {
+			var sb strings.Builder
+			switch v := items.(type) {
+			case []string:
+				for _, item := range v {
+					sb.WriteString("- " + item + "\n")
+				}
+			case []any:
+				for _, item := range v {
+					fmt.Fprintf(&sb, "- %v\n", item)
+				}
+			}
+			return strings.TrimRight(sb.String(), "\n")
+		},
+	}
+}
@@ -22,17 +22,26 @@ func WithDefaultModel(model string) ProviderOption {
 	}
 }
 
+// WithDefaultRerankModel sets the default rerank model ID
+func WithDefaultRerankModel(model string) ProviderOption {
+	return func(p *BedrockProvider) {
+		p.defaultRerankModel = model
+	}
+}
+
 // BedrockProvider implements the LLM interface for AWS Bedrock
 type BedrockProvider struct {
-	client       *bedrockruntime.Client
-	defaultModel string
+	client             *bedrockruntime.Client
+	defaultModel       string
+	defaultRerankModel string
 }
 
 // NewBedrockProvider creates a new Bedrock provider
 func NewBedrockProvider(cfg aws.Config, opts ...ProviderOption) *BedrockProvider {
 	p := &BedrockProvider{
-		client:       bedrockruntime.NewFromConfig(cfg),
-		defaultModel: "anthropic.claude-sonnet-4-20250514-v1:0",
+		client:             bedrockruntime.NewFromConfig(cfg),
+		defaultModel:       "anthropic.claude-sonnet-4-20250514-v1:0",
+		defaultRerankModel: "cohere.rerank-v3-5:0",
 	}
 
 	for _, opt := range opts {
@@ -0,0 +1,90 @@
+package aibedrock
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/rerank"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+// ============================================================================
+// Rerank Implementation
+// ============================================================================
+
+type rerankRequest struct {
+	Query      string   `json:"query"`
+	Documents  []string `json:"documents"`
+	TopN       int      `json:"top_n,omitempty"`
+	APIVersion int      `json:"api_version,omitempty"`
+}
+
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank implements the rerank.Reranker interface with the rerank models
+// served through InvokeModel (cohere.rerank-v3-5:0, amazon.rerank-v1:0)
+func (p *BedrockProvider) Rerank(ctx context.Context, query string, documents []string, opts ...rerank.Option) ([]rerank.Result, error) {
+	if query == "" {
+		return nil, errorRegistry.NewWithMessage(ErrInvalidMessage, "query cannot be empty")
+	}
+	if len(documents) == 0 {
+		return []rerank.Result{}, nil
+	}
+
+	options := rerank.DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Model == "" {
+		options.Model = p.defaultRerankModel
+	}
+
+	req := rerankRequest{
+		Query:     query,
+		Documents: documents,
+		TopN:      options.TopN,
+	}
+	if strings.Contains(options.Model, "cohere.") {
+		req.APIVersion = 2
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, WrapError(err, ErrJSONParsing)
+	}
+
+	output, err := p.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(options.Model),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		return nil, ParseBedrockError(err).
+			WithDetail("model", options.Model).
+			WithDetail("num_documents", len(documents))
+	}
+
+	var resp rerankResponse
+	if err := json.Unmarshal(output.Body, &resp); err != nil {
+		return nil, WrapError(err, ErrJSONParsing).
+			WithDetail("model", options.Model)
+	}
+
+	results := make([]rerank.Result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Index < 0 || r.Index >= len(documents) {
+			return nil, errorRegistry.NewWithMessage(ErrAPIResponse, "rerank result index out of range").
+				WithDetail("index", r.Index)
+		}
+		results = append(results, rerank.Result{Index: r.Index, Score: r.RelevanceScore})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,195 @@
+package aicohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/rerank"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+const (
+	DefaultBaseURL     = "https://api.cohere.com"
+	DefaultTimeout     = time.Minute
+	MaxRetries         = 3
+	DefaultRerankModel = "rerank-v3.5"
+)
+
+// CohereProvider implements reranking with the Cohere API
+type CohereProvider struct {
+	apiKey             string
+	baseURL            string
+	httpClient         *http.Client
+	maxRetries         int
+	defaultRerankModel string
+}
+
+// NewCohereProvider creates a new Cohere provider. The API key is read from
+// COHERE_API_KEY when empty.
+func NewCohereProvider(apiKey string, opts ...ProviderOption) (*CohereProvider, error) {
+	if apiKey == "" {
+		apiKey = os.Getenv("COHERE_API_KEY")
+	}
+
+	if apiKey == "" {
+		return nil, errorRegistry.New(ErrMissingAPIKey)
+	}
+
+	provider := &CohereProvider{
+		apiKey:             apiKey,
+		baseURL:            DefaultBaseURL,
+		maxRetries:         MaxRetries,
+		defaultRerankModel: DefaultRerankModel,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	if provider.httpClient == nil {
+		provider.httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return provider, nil
+}
+
+// ============================================================================
+// Rerank Implementation
+// ============================================================================
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank implements the rerank.Reranker interface
+func (p *CohereProvider) Rerank(ctx context.Context, query string, documents []string, opts ...rerank.Option) ([]rerank.Result, error) {
+	if query == "" {
+		return nil, errorRegistry.NewWithMessage(ErrInvalidInput, "query cannot be empty")
+	}
+	if len(documents) == 0 {
+		return []rerank.Result{}, nil
+	}
+
+	options := rerank.DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Model == "" {
+		options.Model = p.defaultRerankModel
+	}
+
+	body, err := p.post(ctx, "/v2/rerank", rerankRequest{
+		Model:     options.Model,
+		Query:     query,
+		Documents: documents,
+		TopN:      options.TopN,
+	})
+	if err != nil {
+		return nil, err.WithDetail("model", options.Model)
+	}
+
+	var resp rerankResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, WrapError(err, ErrAPIResponse).
+			WithDetail("error", "failed to parse rerank response")
+	}
+
+	results := make([]rerank.Result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Index < 0 || r.Index >= len(documents) {
+			return nil, errorRegistry.NewWithMessage(ErrAPIResponse, "rerank result index out of range").
+				WithDetail("index", r.Index)
+		}
+		results = append(results, rerank.Result{Index: r.Index, Score: r.RelevanceScore})
+	}
+
+	return results, nil
+}
+
+// ============================================================================
+// HTTP
+// ============================================================================
+
+// post sends a JSON request, retrying rate limits and server errors
+func (p *CohereProvider) post(ctx context.Context, endpoint string, payload any) ([]byte, *errx.Error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, WrapError(err, ErrInvalidInput).
+			WithDetail("error", "failed to marshal request payload")
+	}
+
+	var lastErr *errx.Error
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		if attempt > 0 {
+			// Exponential backoff
+			backoff := time.Duration(1<<(attempt-1)) * time.Second
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, WrapError(ctx.Err(), ErrAPIRequest).
+					WithDetail("error", "context cancelled during retry")
+			}
+		}
+
+		body, err := p.doRequest(ctx, endpoint, jsonData)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		status, _ := err.Details["status_code"].(int)
+		if err.Code != ErrAPIRateLimit.Code && status < 500 {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (p *CohereProvider) doRequest(ctx context.Context, endpoint string, body []byte) ([]byte, *errx.Error) {
+	url := p.baseURL + endpoint
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, WrapError(err, ErrAPIRequest).
+			WithDetail("error", "failed to create HTTP request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, WrapError(err, ErrAPIRequest).
+			WithDetail("error", "HTTP request failed").
+			WithDetail("url", url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, WrapError(err, ErrAPIResponse).
+			WithDetail("error", "failed to read response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, ParseAPIError(resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
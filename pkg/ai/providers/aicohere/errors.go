@@ -0,0 +1,99 @@
+package aicohere
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for Cohere provider
+	errorRegistry = errx.NewRegistry("COHERE")
+
+	// API Errors
+	ErrAPIRequest = errorRegistry.Register(
+		"API_REQUEST_FAILED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Failed to make request to Cohere API",
+	)
+
+	ErrAPIResponse = errorRegistry.Register(
+		"API_RESPONSE_INVALID",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Invalid response from Cohere API",
+	)
+
+	ErrAPIUnauthorized = errorRegistry.Register(
+		"API_UNAUTHORIZED",
+		errx.TypeAuthorization,
+		http.StatusUnauthorized,
+		"Invalid or missing API key",
+	)
+
+	ErrAPIRateLimit = errorRegistry.Register(
+		"API_RATE_LIMIT",
+		errx.TypeExternal,
+		http.StatusTooManyRequests,
+		"Cohere API rate limit exceeded",
+	)
+
+	// Input Errors
+	ErrInvalidInput = errorRegistry.Register(
+		"INVALID_INPUT",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid input parameters",
+	)
+
+	// Configuration Errors
+	ErrMissingAPIKey = errorRegistry.Register(
+		"MISSING_API_KEY",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Missing Cohere API key",
+	)
+)
+
+// ParseAPIError parses an error response from the Cohere API
+func ParseAPIError(statusCode int, body []byte) *errx.Error {
+	var errResp struct {
+		Message string `json:"message"`
+	}
+	message := string(body)
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		message = errResp.Message
+	}
+
+	var baseErr *errx.ErrorCode
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		baseErr = ErrAPIUnauthorized
+	case http.StatusTooManyRequests:
+		baseErr = ErrAPIRateLimit
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		baseErr = ErrInvalidInput
+	default:
+		baseErr = ErrAPIRequest
+	}
+
+	return errorRegistry.NewWithMessage(baseErr, message).
+		WithDetail("status_code", statusCode)
+}
+
+// WrapError wraps a standard error with appropriate Cohere error code
+func WrapError(err error, code *errx.ErrorCode) *errx.Error {
+	if err == nil {
+		return nil
+	}
+
+	// Check if it's already a custom error
+	var customErr *errx.Error
+	if errx.As(err, &customErr) {
+		return customErr
+	}
+
+	return errorRegistry.NewWithCause(code, err)
+}
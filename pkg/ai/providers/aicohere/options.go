@@ -0,0 +1,36 @@
+package aicohere
+
+import (
+	"net/http"
+)
+
+// ProviderOption configures the Cohere provider
+type ProviderOption func(*CohereProvider)
+
+// WithBaseURL sets a custom base URL
+func WithBaseURL(url string) ProviderOption {
+	return func(p *CohereProvider) {
+		p.baseURL = url
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(client *http.Client) ProviderOption {
+	return func(p *CohereProvider) {
+		p.httpClient = client
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries
+func WithMaxRetries(maxRetries int) ProviderOption {
+	return func(p *CohereProvider) {
+		p.maxRetries = maxRetries
+	}
+}
+
+// WithDefaultRerankModel sets the default rerank model
+func WithDefaultRerankModel(model string) ProviderOption {
+	return func(p *CohereProvider) {
+		p.defaultRerankModel = model
+	}
+}
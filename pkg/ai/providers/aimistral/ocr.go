@@ -196,6 +196,12 @@ func (m *MistralProvider) convertInputToDocument(input ocr.Input) DocumentInput
 			mimeType = "application/pdf"
 		}
 		dataURL := fmt.Sprintf("data:%s;base64,%s", mimeType, string(input.Data))
+		if strings.HasPrefix(mimeType, "image/") {
+			return DocumentInput{
+				Type:     "image_url",
+				ImageURL: dataURL,
+			}
+		}
 		return DocumentInput{
 			Type:        "document_url",
 			DocumentURL: dataURL,
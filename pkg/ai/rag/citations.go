@@ -0,0 +1,82 @@
+package rag
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+)
+
+// Citation points from a citation marker in an answer to the document it
+// cites
+type Citation struct {
+	Number     int    // Number used in the answer, e.g. 2 for [2]
+	DocumentID string // ID of the cited document
+	Source     string // MetadataSource of the document
+	Title      string // MetadataTitle of the document
+	Page       int    // MetadataPageNumber of the document, 0 when unknown
+	Metadata   document.Metadata
+}
+
+// citationMarker matches [1], [1, 3] and [1-3]
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*[,\-–]\s*\d+)*)\]`)
+
+// ParseCitations finds the citation markers in an answer and returns the
+// sources they cite, in order of first citation. Numbers that match no
+// source are ignored.
+func ParseCitations(text string, sources []Source) []Citation {
+	byNumber := make(map[int]Source, len(sources))
+	for _, source := range sources {
+		byNumber[source.Number] = source
+	}
+
+	var citations []Citation
+	seen := make(map[int]bool)
+	for _, number := range citedNumbers(text) {
+		source, ok := byNumber[number]
+		if !ok || seen[number] {
+			continue
+		}
+		seen[number] = true
+		citations = append(citations, newCitation(source))
+	}
+	return citations
+}
+
+// citedNumbers returns the numbers in citation markers, expanding ranges
+func citedNumbers(text string) []int {
+	var numbers []int
+	for _, match := range citationMarker.FindAllStringSubmatch(text, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			from, to, isRange := strings.Cut(strings.ReplaceAll(part, "–", "-"), "-")
+			first, err := strconv.Atoi(strings.TrimSpace(from))
+			if err != nil {
+				continue
+			}
+			last := first
+			if isRange {
+				if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first || last-first > 100 {
+					last = first
+				}
+			}
+			for n := first; n <= last; n++ {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+	return numbers
+}
+
+func newCitation(source Source) Citation {
+	doc := source.Document
+	citation := Citation{
+		Number:     source.Number,
+		DocumentID: doc.ID,
+		Metadata:   doc.Metadata,
+	}
+	citation.Source, _ = doc.GetMetadataString(document.MetadataSource)
+	citation.Title, _ = doc.GetMetadataString(document.MetadataTitle)
+	citation.Page, _ = doc.GetMetadataInt(document.MetadataPageNumber)
+	return citation
+}
@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+)
+
+func numberedSources(n int) []Source {
+	sources := make([]Source, n)
+	for i := range sources {
+		sources[i] = Source{Number: i + 1, Document: document.NewDocument("passage")}
+	}
+	return sources
+}
+
+func citationNumbers(citations []Citation) []int {
+	var numbers []int
+	for _, c := range citations {
+		numbers = append(numbers, c.Number)
+	}
+	return numbers
+}
+
+func TestParseCitations(t *testing.T) {
+	sources := numberedSources(3)
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{"none", "No citations here.", nil},
+		{"single", "Refunds take 5 days [1].", []int{1}},
+		{"order of first citation", "A [2]. B [1][2]. C [2].", []int{2, 1}},
+		{"list", "Both agree [1, 3].", []int{1, 3}},
+		{"range", "All of them [1-3].", []int{1, 2, 3}},
+		{"en dash range", "All of them [1–3].", []int{1, 2, 3}},
+		{"spaced range", "All of them [1 - 2].", []int{1, 2}},
+		{"reversed range", "Odd [3-1].", []int{3}},
+		{"range past the sources", "Some [2-9].", []int{2, 3}},
+		{"oversized range", "Many [1-500].", []int{1}},
+		{"out of range", "Made up [0] and [7].", nil},
+		{"not a marker", "See [a] and [1a].", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := citationNumbers(ParseCitations(tt.text, sources)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCitations(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCitationsDocumentFields(t *testing.T) {
+	doc := document.NewDocument("Refunds take 5 days.").
+		WithID("doc-1").
+		WithMetadata(document.MetadataSource, "refunds.pdf").
+		WithMetadata(document.MetadataTitle, "Refund Policy").
+		WithMetadata(document.MetadataPageNumber, 4)
+
+	citations := ParseCitations("Five days [1].", []Source{{Number: 1, Document: doc}})
+	if len(citations) != 1 {
+		t.Fatalf("got %d citations, want 1", len(citations))
+	}
+	c := citations[0]
+	if c.DocumentID != "doc-1" || c.Source != "refunds.pdf" || c.Title != "Refund Policy" || c.Page != 4 {
+		t.Errorf("citation = %+v", c)
+	}
+}
@@ -0,0 +1,201 @@
+// Package rag answers questions from retrieved documents with numbered
+// citations.
+//
+// A [Chain] retrieves documents for a question, packs as many of them as fit
+// a token budget into a numbered list of sources, asks the model to answer
+// citing those numbers, and maps the citations in the answer back to the
+// documents they point at.
+//
+//	retriever := document.NewRetriever(store).WithTopK(8)
+//	chain := rag.New(retriever, model, rag.WithMaxContextTokens(6000))
+//
+//	answer, err := chain.Ask(ctx, "How long do refunds take?")
+//	for _, c := range answer.Citations {
+//	    fmt.Printf("[%d] %s p.%d\n", c.Number, c.Source, c.Page)
+//	}
+//
+// [Chain.Stream] returns an llm.Stream that forwards the answer as it is
+// generated and ends with a message carrying the citations.
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// DefaultSystemPrompt tells the model to answer from the sources and cite
+// them by number
+const DefaultSystemPrompt = `Answer the question using only the numbered sources provided. After each statement, cite the sources that support it with their numbers in square brackets, such as [1] or [1][3]. Do not cite sources that don't support the statement. If the sources don't contain the answer, say that you don't know.`
+
+// DefaultPromptTemplate is the user message sent to the model. {{context}}
+// and {{question}} are replaced before the call.
+const DefaultPromptTemplate = `Sources:
+
+{{context}}
+
+Question: {{question}}`
+
+// Retriever finds the documents to answer a question from.
+// *document.Retriever implements it.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string) ([]*document.Document, error)
+}
+
+// Chain answers questions from retrieved documents
+type Chain struct {
+	retriever        Retriever
+	llm              llm.LLM
+	systemPrompt     string
+	promptTemplate   string
+	maxContextTokens int
+	tokenCounter     document.TokenCounter
+	options          []llm.Option
+}
+
+// Option configures a Chain
+type Option func(*Chain)
+
+// WithSystemPrompt replaces the instructions given to the model
+func WithSystemPrompt(prompt string) Option {
+	return func(c *Chain) {
+		c.systemPrompt = prompt
+	}
+}
+
+// WithPromptTemplate replaces the user message template
+func WithPromptTemplate(template string) Option {
+	return func(c *Chain) {
+		c.promptTemplate = template
+	}
+}
+
+// WithMaxContextTokens limits the tokens spent on sources (0 = no limit)
+func WithMaxContextTokens(tokens int) Option {
+	return func(c *Chain) {
+		c.maxContextTokens = tokens
+	}
+}
+
+// WithTokenCounter sets how the context budget is counted. The default
+// assumes 4 characters per token.
+func WithTokenCounter(counter document.TokenCounter) Option {
+	return func(c *Chain) {
+		c.tokenCounter = counter
+	}
+}
+
+// WithOptions adds LLM options to every call
+func WithOptions(options ...llm.Option) Option {
+	return func(c *Chain) {
+		c.options = append(c.options, options...)
+	}
+}
+
+// New creates a chain that answers from the documents found by retriever
+func New(retriever Retriever, model llm.LLM, opts ...Option) *Chain {
+	chain := &Chain{
+		retriever:      retriever,
+		llm:            model,
+		systemPrompt:   DefaultSystemPrompt,
+		promptTemplate: DefaultPromptTemplate,
+		tokenCounter:   estimateTokens,
+	}
+
+	for _, opt := range opts {
+		opt(chain)
+	}
+
+	return chain
+}
+
+// Answer is the model's answer to a question
+type Answer struct {
+	// Text is the answer, with citation markers such as [1]
+	Text string
+
+	// Citations are the sources cited in Text, in order of first citation
+	Citations []Citation
+
+	// Sources are all the passages given to the model
+	Sources []Source
+
+	// Usage contains token usage statistics. Streamed answers carry the
+	// usage reported by the model's stream, or an estimate made with the
+	// chain's token counter when the stream reports none.
+	Usage llm.Usage
+}
+
+// Ask retrieves documents for a question and answers it
+func (c *Chain) Ask(ctx context.Context, question string) (*Answer, error) {
+	messages, sources, err := c.prepare(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.llm.Chat(ctx, messages, c.options...)
+	if err != nil {
+		return nil, fmt.Errorf("LLM error: %w", err)
+	}
+
+	text := resp.Message.TextContent()
+	return &Answer{
+		Text:      text,
+		Citations: ParseCitations(text, sources),
+		Sources:   sources,
+		Usage:     resp.Usage,
+	}, nil
+}
+
+// Stream retrieves documents for a question and streams the answer
+func (c *Chain) Stream(ctx context.Context, question string) (*AnswerStream, error) {
+	messages, sources, err := c.prepare(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.llm.ChatStream(ctx, messages, c.options...)
+	if err != nil {
+		return nil, fmt.Errorf("LLM error: %w", err)
+	}
+
+	var promptTokens int
+	for _, message := range messages {
+		promptTokens += c.tokenCounter(message.TextContent())
+	}
+	return &AnswerStream{
+		stream:       stream,
+		sources:      sources,
+		promptTokens: promptTokens,
+		tokenCounter: c.tokenCounter,
+	}, nil
+}
+
+// prepare retrieves the sources and builds the messages for a question
+func (c *Chain) prepare(ctx context.Context, question string) ([]llm.Message, []Source, error) {
+	docs, err := c.retriever.Retrieve(ctx, question)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve documents: %w", err)
+	}
+
+	sources := c.selectSources(docs)
+	prompt := strings.NewReplacer(
+		"{{context}}", formatSources(sources),
+		"{{question}}", question,
+	).Replace(c.promptTemplate)
+
+	var messages []llm.Message
+	if c.systemPrompt != "" {
+		messages = append(messages, llm.NewSystemMessage(c.systemPrompt))
+	}
+	messages = append(messages, llm.NewUserMessage(prompt))
+	return messages, sources, nil
+}
+
+// estimateTokens assumes 4 characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,81 @@
+package rageval
+
+import (
+	"io"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+)
+
+// ============================================================================
+// Dataset
+// ============================================================================
+
+// Dataset is a named collection of evaluation questions
+type Dataset struct {
+	Name  string `json:"name"`
+	Cases []Case `json:"cases"`
+}
+
+// Case is a question with what a good retrieval and answer look like
+type Case struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+
+	// Relevant identifies the documents that answer the question: document
+	// IDs, or values of the evaluator's match key (e.g. sources). Retrieval
+	// metrics are skipped when empty.
+	Relevant []string `json:"relevant,omitempty"`
+
+	// Reference is the expected answer, shown to the answer relevance judge
+	Reference string `json:"reference,omitempty"`
+
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// NewDataset creates a dataset from cases
+func NewDataset(name string, cases ...Case) *Dataset {
+	return &Dataset{Name: name, Cases: cases}
+}
+
+// datasetSchema validates and decodes rageval cases
+var datasetSchema = evalx.Schema[Case]{
+	ID:         func(c *Case) *string { return &c.ID },
+	Input:      func(c *Case) string { return c.Question },
+	InputField: "question",
+	Registry:   errorRegistry,
+	Code:       ErrInvalidDataset,
+}
+
+// Validate checks that every case has a question and a unique ID.
+// Missing IDs are filled with the case index.
+func (d *Dataset) Validate() error {
+	return datasetSchema.Validate(d.Cases)
+}
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+// LoadDataset reads a dataset from a .json file (a Dataset object or an
+// array of cases) or a .jsonl file (one case per line)
+func LoadDataset(path string) (*Dataset, error) {
+	return fromFile(datasetSchema.Load(path))
+}
+
+// ParseJSON reads a Dataset object or a bare array of cases
+func ParseJSON(r io.Reader) (*Dataset, error) {
+	return fromFile(datasetSchema.ParseJSON(r))
+}
+
+// ParseJSONL reads one case per line; blank lines are skipped
+func ParseJSONL(r io.Reader) (*Dataset, error) {
+	return fromFile(datasetSchema.ParseJSONL(r))
+}
+
+func fromFile(ds *evalx.Dataset[Case], err error) (*Dataset, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Dataset{Name: ds.Name, Cases: ds.Cases}, nil
+}
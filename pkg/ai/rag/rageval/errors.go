@@ -0,0 +1,47 @@
+package rageval
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for RAG evaluation
+	errorRegistry = errx.NewRegistry("RAG_EVAL")
+
+	ErrInvalidDataset = errorRegistry.Register(
+		"INVALID_DATASET",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid evaluation dataset",
+	)
+
+	ErrInvalidConfig = errorRegistry.Register(
+		"INVALID_CONFIG",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid evaluation configuration",
+	)
+
+	ErrRetrieval = errorRegistry.Register(
+		"RETRIEVAL_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Retriever failed while running evaluation case",
+	)
+
+	ErrGeneration = errorRegistry.Register(
+		"GENERATION_FAILED",
+		errx.TypeInternal,
+		http.StatusInternalServerError,
+		"Chain failed to answer evaluation case",
+	)
+
+	ErrJudgeResponse = errorRegistry.Register(
+		"JUDGE_RESPONSE_INVALID",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"LLM judge returned an invalid response",
+	)
+)
@@ -0,0 +1,271 @@
+package rageval
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/rag"
+)
+
+// Config is one retrieval setup under evaluation, e.g. a store filled with
+// a given splitter and embedding model
+type Config struct {
+	// Name identifies the configuration in reports
+	Name string
+
+	// Retriever is scored with recall@k, MRR and nDCG
+	Retriever rag.Retriever
+
+	// Chain answers the questions for the judged metrics. Optional; it
+	// usually wraps Retriever.
+	Chain *rag.Chain
+}
+
+// Evaluator scores retrieval configurations against a dataset
+type Evaluator struct {
+	k                  int
+	matchKey           string
+	judge              llm.LLM
+	judgeOptions       []llm.Option
+	faithfulnessPrompt string
+	relevancePrompt    string
+	concurrency        int
+	caseTimeout        time.Duration
+	onResult           func(CaseResult)
+}
+
+// Option configures an Evaluator
+type Option func(*Evaluator)
+
+// WithK sets the cut-off of the retrieval metrics (default 5)
+func WithK(k int) Option {
+	return func(e *Evaluator) {
+		e.k = k
+	}
+}
+
+// WithMatchKey compares case relevance with a metadata field of the
+// retrieved documents instead of their IDs. Chunk IDs change with the
+// splitter, so use a stable key such as document.MetadataSource or
+// document.MetadataDocumentID when comparing splitters; chunks sharing a
+// value count once, at the rank of the first.
+func WithMatchKey(key string) Option {
+	return func(e *Evaluator) {
+		e.matchKey = key
+	}
+}
+
+// WithJudge sets the model grading faithfulness and answer relevance.
+// Without a judge only retrieval metrics are computed.
+func WithJudge(model llm.LLM, opts ...llm.Option) Option {
+	return func(e *Evaluator) {
+		e.judge = model
+		e.judgeOptions = append(e.judgeOptions, opts...)
+	}
+}
+
+// WithFaithfulnessPrompt replaces the faithfulness prompt; it must contain
+// two %s verbs (sources, answer)
+func WithFaithfulnessPrompt(prompt string) Option {
+	return func(e *Evaluator) {
+		e.faithfulnessPrompt = prompt
+	}
+}
+
+// WithAnswerRelevancePrompt replaces the answer relevance prompt; it must
+// contain three %s verbs (question, reference, answer)
+func WithAnswerRelevancePrompt(prompt string) Option {
+	return func(e *Evaluator) {
+		e.relevancePrompt = prompt
+	}
+}
+
+// WithConcurrency sets how many cases run in parallel
+func WithConcurrency(n int) Option {
+	return func(e *Evaluator) {
+		if n > 0 {
+			e.concurrency = n
+		}
+	}
+}
+
+// WithCaseTimeout bounds the execution time of each case
+func WithCaseTimeout(d time.Duration) Option {
+	return func(e *Evaluator) {
+		e.caseTimeout = d
+	}
+}
+
+// WithProgress registers a callback invoked as each case completes
+func WithProgress(fn func(CaseResult)) Option {
+	return func(e *Evaluator) {
+		e.onResult = fn
+	}
+}
+
+// NewEvaluator creates a RAG evaluator
+//
+// Example:
+//
+//	eval := rageval.NewEvaluator(
+//	    rageval.WithK(5),
+//	    rageval.WithMatchKey(document.MetadataSource),
+//	    rageval.WithJudge(judgeModel),
+//	)
+//	cmp, err := eval.Compare(ctx, dataset,
+//	    rageval.Config{Name: "recursive-512", Retriever: baseline, Chain: rag.New(baseline, model)},
+//	    rageval.Config{Name: "markdown-headers", Retriever: candidate, Chain: rag.New(candidate, model)},
+//	)
+//	cmp.WriteMarkdown(os.Stdout)
+func NewEvaluator(opts ...Option) *Evaluator {
+	e := &Evaluator{
+		k:                  5,
+		judgeOptions:       []llm.Option{llm.WithTemperature(0)},
+		faithfulnessPrompt: DefaultFaithfulnessPrompt,
+		relevancePrompt:    DefaultAnswerRelevancePrompt,
+		concurrency:        4,
+		caseTimeout:        2 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Evaluate runs every case against a configuration. Case failures are
+// recorded in the report; Evaluate only fails on an invalid dataset or
+// configuration.
+func (e *Evaluator) Evaluate(ctx context.Context, dataset *Dataset, config Config) (*Report, error) {
+	if err := dataset.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Retriever == nil && config.Chain == nil {
+		return nil, errorRegistry.NewWithMessage(ErrInvalidConfig, "configuration needs a retriever or a chain").
+			WithDetail("config", config.Name)
+	}
+
+	started := time.Now()
+	results := evalx.RunCases(ctx, dataset.Cases, e.concurrency,
+		func(ctx context.Context, c Case) CaseResult { return e.runCase(ctx, config, c) },
+		func(c Case, err error) CaseResult {
+			return CaseResult{ID: c.ID, Question: c.Question, Tags: c.Tags, Error: err.Error()}
+		},
+		e.onResult,
+	)
+
+	sort.SliceStable(results, func(a, b int) bool { return results[a].ID < results[b].ID })
+
+	report := &Report{
+		Name:      dataset.Name,
+		Label:     config.Name,
+		K:         e.k,
+		StartedAt: started,
+		Duration:  time.Since(started),
+		Cases:     results,
+	}
+	report.Summary = summarize(results)
+	return report, nil
+}
+
+// Compare evaluates several configurations on the same dataset
+func (e *Evaluator) Compare(ctx context.Context, dataset *Dataset, configs ...Config) (*Comparison, error) {
+	reports := make([]*Report, 0, len(configs))
+	for _, config := range configs {
+		report, err := e.Evaluate(ctx, dataset, config)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return Compare(reports...), nil
+}
+
+// runCase retrieves, answers and scores a single case
+func (e *Evaluator) runCase(ctx context.Context, config Config, c Case) CaseResult {
+	if e.caseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.caseTimeout)
+		defer cancel()
+	}
+
+	result := CaseResult{
+		ID:       c.ID,
+		Question: c.Question,
+		Tags:     c.Tags,
+		Scores:   make(map[string]float64),
+	}
+
+	if config.Retriever != nil {
+		start := time.Now()
+		docs, err := config.Retriever.Retrieve(ctx, c.Question)
+		result.LatencyMs = time.Since(start).Milliseconds()
+		if err != nil {
+			result.Error = errorRegistry.NewWithCause(ErrRetrieval, err).WithDetail("case_id", c.ID).Error()
+			return result
+		}
+
+		result.Retrieved = e.identify(docs)
+		if len(c.Relevant) > 0 {
+			result.Scores[MetricRecall] = RecallAtK(result.Retrieved, c.Relevant, e.k)
+			result.Scores[MetricMRR] = ReciprocalRank(result.Retrieved, c.Relevant, e.k)
+			result.Scores[MetricNDCG] = NDCGAtK(result.Retrieved, c.Relevant, e.k)
+		}
+	}
+
+	if config.Chain == nil || e.judge == nil {
+		return result
+	}
+
+	start := time.Now()
+	answer, err := config.Chain.Ask(ctx, c.Question)
+	result.AnswerLatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = errorRegistry.NewWithCause(ErrGeneration, err).WithDetail("case_id", c.ID).Error()
+		return result
+	}
+	result.Answer = answer.Text
+	result.Usage = answer.Usage
+
+	judged := []struct {
+		metric string
+		grade  func() (evalx.Verdict, error)
+	}{
+		{MetricFaithfulness, func() (evalx.Verdict, error) { return e.faithfulness(ctx, answer) }},
+		{MetricAnswerRelevance, func() (evalx.Verdict, error) { return e.answerRelevance(ctx, c, answer) }},
+	}
+	for _, j := range judged {
+		verdict, err := j.grade()
+		if err != nil {
+			result.setReason(j.metric, "judge failed: "+err.Error())
+			continue
+		}
+		result.Scores[j.metric] = verdict.Value
+		result.setReason(j.metric, verdict.Reason)
+	}
+
+	return result
+}
+
+// identify returns the identifiers of retrieved documents in rank order,
+// keeping the first of any duplicates. Documents without an identifier keep
+// their rank as "" so they can't match.
+func (e *Evaluator) identify(docs []*document.Document) []string {
+	ids := make([]string, 0, len(docs))
+	seen := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		id := doc.ID
+		if e.matchKey != "" {
+			id, _ = doc.GetMetadataString(e.matchKey)
+		}
+		if id != "" && seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
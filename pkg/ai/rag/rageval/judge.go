@@ -0,0 +1,70 @@
+package rageval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+	"github.com/Abraxas-365/manifesto/pkg/ai/rag"
+)
+
+// DefaultFaithfulnessPrompt asks whether an answer is supported by its
+// sources. It receives the numbered sources and the answer.
+const DefaultFaithfulnessPrompt = `You are an impartial evaluator checking whether an answer is supported by the sources it was written from.
+
+Sources:
+%s
+
+Answer:
+%s
+
+Rate from 1 to 5 how well the claims in the answer are supported by the sources: 5 when every claim is supported, 1 when the answer is mostly unsupported or contradicts the sources. Saying that the sources don't contain the answer counts as supported.
+Respond with JSON only: {"score": <1-5>, "reasoning": "<one or two sentences>"}`
+
+// DefaultAnswerRelevancePrompt asks whether an answer addresses the
+// question. It receives the question, the reference answer (possibly empty)
+// and the answer.
+const DefaultAnswerRelevancePrompt = `You are an impartial evaluator grading whether an answer addresses a question.
+
+Question:
+%s
+
+Reference answer (may be empty):
+%s
+
+Answer:
+%s
+
+Rate from 1 to 5 how directly and completely the answer addresses the question and, when present, agrees with the reference answer: 5 when it fully answers the question, 1 when it is off-topic or evasive.
+Respond with JSON only: {"score": <1-5>, "reasoning": "<one or two sentences>"}`
+
+// faithfulness grades an answer against the sources it was given
+func (e *Evaluator) faithfulness(ctx context.Context, answer *rag.Answer) (evalx.Verdict, error) {
+	sources := make([]string, len(answer.Sources))
+	for i, source := range answer.Sources {
+		sources[i] = fmt.Sprintf("[%d] %s", source.Number, source.Document.Content)
+	}
+	return e.askJudge(ctx, fmt.Sprintf(e.faithfulnessPrompt, strings.Join(sources, "\n\n"), answer.Text))
+}
+
+// answerRelevance grades how well an answer addresses the question
+func (e *Evaluator) answerRelevance(ctx context.Context, c Case, answer *rag.Answer) (evalx.Verdict, error) {
+	return e.askJudge(ctx, fmt.Sprintf(e.relevancePrompt, c.Question, c.Reference, answer.Text))
+}
+
+func (e *Evaluator) askJudge(ctx context.Context, prompt string) (evalx.Verdict, error) {
+	resp, err := e.judge.Chat(ctx, []llm.Message{llm.NewUserMessage(prompt)}, e.judgeOptions...)
+	if err != nil {
+		return evalx.Verdict{}, err
+	}
+
+	content := resp.Message.TextContent()
+	verdict, err := evalx.ParseVerdict(content)
+	if err != nil {
+		return evalx.Verdict{}, errorRegistry.NewWithCause(ErrJudgeResponse, err).
+			WithDetail("response", evalx.Truncate(content, 200))
+	}
+	return verdict, nil
+}
@@ -0,0 +1,91 @@
+package rageval
+
+import (
+	"math"
+)
+
+// ============================================================================
+// Retrieval Metrics
+// ============================================================================
+
+// Metric names used in reports
+const (
+	MetricRecall          = "recall"
+	MetricMRR             = "mrr"
+	MetricNDCG            = "ndcg"
+	MetricFaithfulness    = "faithfulness"
+	MetricAnswerRelevance = "answer_relevance"
+)
+
+// RecallAtK is the fraction of the relevant documents found in the first k
+// retrieved
+func RecallAtK(retrieved, relevant []string, k int) float64 {
+	if len(relevant) == 0 {
+		return 0
+	}
+	want := toSet(relevant)
+	found := 0
+	for _, id := range topK(retrieved, k) {
+		if want[id] {
+			found++
+			delete(want, id)
+		}
+	}
+	return float64(found) / float64(len(toSet(relevant)))
+}
+
+// ReciprocalRank is 1/rank of the first relevant document in the first k
+// retrieved, or 0 when there is none. Its mean over cases is the MRR.
+func ReciprocalRank(retrieved, relevant []string, k int) float64 {
+	want := toSet(relevant)
+	for i, id := range topK(retrieved, k) {
+		if want[id] {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// NDCGAtK is the normalized discounted cumulative gain of the first k
+// retrieved documents with binary relevance: 1 when the relevant documents
+// fill the top ranks, less the further down they are.
+func NDCGAtK(retrieved, relevant []string, k int) float64 {
+	want := toSet(relevant)
+	if len(want) == 0 {
+		return 0
+	}
+
+	var dcg float64
+	for i, id := range topK(retrieved, k) {
+		if want[id] {
+			dcg += 1 / math.Log2(float64(i+2))
+			delete(want, id)
+		}
+	}
+
+	ideal := len(toSet(relevant))
+	if k > 0 {
+		ideal = min(ideal, k)
+	}
+	var idcg float64
+	for i := range ideal {
+		idcg += 1 / math.Log2(float64(i+2))
+	}
+	return dcg / idcg
+}
+
+// topK returns the first k items (all when k <= 0)
+func topK(items []string, k int) []string {
+	if k > 0 && len(items) > k {
+		return items[:k]
+	}
+	return items
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
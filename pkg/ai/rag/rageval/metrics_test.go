@@ -0,0 +1,52 @@
+package rageval_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/rag/rageval"
+)
+
+func TestRetrievalMetrics(t *testing.T) {
+	// Discounts of ranks 1-3
+	d1, d2, d3 := 1.0, 1/math.Log2(3), 1/math.Log2(4)
+
+	tests := []struct {
+		name      string
+		retrieved []string
+		relevant  []string
+		k         int
+		recall    float64
+		rr        float64
+		ndcg      float64
+	}{
+		{"perfect", []string{"a", "b", "c"}, []string{"a", "b"}, 3, 1, 1, 1},
+		{"second rank", []string{"x", "a", "y"}, []string{"a"}, 3, 1, 0.5, d2},
+		{"partial", []string{"a", "x", "b"}, []string{"a", "b", "c"}, 3, 2.0 / 3, 1, (d1 + d3) / (d1 + d2 + d3)},
+		{"beyond k", []string{"x", "y", "a"}, []string{"a"}, 2, 0, 0, 0},
+		{"k larger than relevant", []string{"x", "a"}, []string{"a"}, 5, 1, 0.5, d2},
+		{"ideal capped at k", []string{"a", "b"}, []string{"a", "b", "c"}, 2, 2.0 / 3, 1, 1},
+		{"all when k is zero", []string{"x", "y", "a"}, []string{"a"}, 0, 1, 1.0 / 3, d3},
+		{"duplicate relevant", []string{"a"}, []string{"a", "a"}, 3, 1, 1, 1},
+		{"nothing retrieved", nil, []string{"a"}, 3, 0, 0, 0},
+		{"nothing relevant", []string{"a"}, nil, 3, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := []struct {
+				name string
+				got  float64
+				want float64
+			}{
+				{"RecallAtK", rageval.RecallAtK(tt.retrieved, tt.relevant, tt.k), tt.recall},
+				{"ReciprocalRank", rageval.ReciprocalRank(tt.retrieved, tt.relevant, tt.k), tt.rr},
+				{"NDCGAtK", rageval.NDCGAtK(tt.retrieved, tt.relevant, tt.k), tt.ndcg},
+			}
+			for _, m := range metrics {
+				if math.Abs(m.got-m.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", m.name, m.got, m.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package rageval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/evalx"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// ============================================================================
+// Report
+// ============================================================================
+
+// Report is the outcome of evaluating one configuration. Cases are sorted
+// by ID so reports from different configurations diff cleanly.
+type Report struct {
+	Name      string        `json:"name"`
+	Label     string        `json:"label,omitempty"`
+	K         int           `json:"k"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Summary   Summary       `json:"summary"`
+	Cases     []CaseResult  `json:"cases"`
+}
+
+// CaseResult is the scored outcome of one case. Scores holds only the
+// metrics that could be computed: retrieval metrics need relevant documents
+// and judged metrics need a chain and a judge.
+type CaseResult struct {
+	ID              string             `json:"id"`
+	Question        string             `json:"question"`
+	Tags            []string           `json:"tags,omitempty"`
+	Retrieved       []string           `json:"retrieved,omitempty"`
+	Answer          string             `json:"answer,omitempty"`
+	Scores          map[string]float64 `json:"scores,omitempty"`
+	Reasons         map[string]string  `json:"reasons,omitempty"`
+	Error           string             `json:"error,omitempty"`
+	Usage           llm.Usage          `json:"usage"`
+	LatencyMs       int64              `json:"latency_ms"`
+	AnswerLatencyMs int64              `json:"answer_latency_ms,omitempty"`
+}
+
+// Summary aggregates a report. Means are taken over the cases that have
+// the metric.
+type Summary struct {
+	Total        int                `json:"total"`
+	Errored      int                `json:"errored"`
+	MeanScores   map[string]float64 `json:"mean_scores"`
+	Counts       map[string]int     `json:"counts"`
+	TotalTokens  int                `json:"total_tokens"`
+	AvgLatencyMs int64              `json:"avg_latency_ms"`
+}
+
+func (c *CaseResult) setReason(metric, reason string) {
+	if reason == "" {
+		return
+	}
+	if c.Reasons == nil {
+		c.Reasons = make(map[string]string)
+	}
+	c.Reasons[metric] = reason
+}
+
+func summarize(results []CaseResult) Summary {
+	summary := Summary{
+		Total:      len(results),
+		MeanScores: make(map[string]float64),
+		Counts:     make(map[string]int),
+	}
+	var latency int64
+
+	for _, r := range results {
+		if r.Error != "" {
+			summary.Errored++
+		}
+		summary.TotalTokens += r.Usage.TotalTokens
+		latency += r.LatencyMs
+
+		for name, value := range r.Scores {
+			summary.MeanScores[name] += value
+			summary.Counts[name]++
+		}
+	}
+
+	for name, total := range summary.MeanScores {
+		summary.MeanScores[name] = total / float64(summary.Counts[name])
+	}
+	if summary.Total > 0 {
+		summary.AvgLatencyMs = latency / int64(summary.Total)
+	}
+	return summary
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes a human-readable report
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	title := reportTitle(r)
+	if r.Name != "" && r.Label != "" {
+		title = r.Name + " (" + r.Label + ")"
+	}
+	fmt.Fprintf(&sb, "# RAG Evaluation: %s\n\n", title)
+
+	s := r.Summary
+	names := metricNames(s.MeanScores)
+	fmt.Fprintf(&sb, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Cases | %d |\n", s.Total)
+	fmt.Fprintf(&sb, "| Errored | %d |\n", s.Errored)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "| %s | %.3f |\n", metricLabel(name, r.K), s.MeanScores[name])
+	}
+	fmt.Fprintf(&sb, "| Total tokens | %d |\n", s.TotalTokens)
+	fmt.Fprintf(&sb, "| Avg retrieval latency | %dms |\n\n", s.AvgLatencyMs)
+
+	sb.WriteString("## Cases\n\n| Case |")
+	for _, name := range names {
+		fmt.Fprintf(&sb, " %s |", metricLabel(name, r.K))
+	}
+	sb.WriteString(" Retrieved |\n|---|")
+	for range names {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("---|\n")
+
+	for _, c := range r.Cases {
+		fmt.Fprintf(&sb, "| %s |", c.ID)
+		for _, name := range names {
+			if value, ok := c.Scores[name]; ok {
+				fmt.Fprintf(&sb, " %.2f |", value)
+			} else {
+				sb.WriteString(" - |")
+			}
+		}
+		if c.Error != "" {
+			fmt.Fprintf(&sb, " error: %s |\n", inline(c.Error))
+			continue
+		}
+		fmt.Fprintf(&sb, " %s |\n", inline(strings.Join(topK(c.Retrieved, r.K), ", ")))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ============================================================================
+// Comparison
+// ============================================================================
+
+// Comparison puts the reports of several configurations side by side
+type Comparison struct {
+	Dataset string    `json:"dataset"`
+	Reports []*Report `json:"reports"`
+}
+
+// Compare builds a comparison of reports on the same dataset
+func Compare(reports ...*Report) *Comparison {
+	cmp := &Comparison{Reports: reports}
+	if len(reports) > 0 {
+		cmp.Dataset = reports[0].Name
+	}
+	return cmp
+}
+
+// Best returns the label of the report with the highest mean of a metric,
+// or "" when no report has it. Ties go to the earlier report.
+func (c *Comparison) Best(metric string) string {
+	best, bestValue := "", -1.0
+	for _, r := range c.Reports {
+		if value, ok := r.Summary.MeanScores[metric]; ok && value > bestValue {
+			best, bestValue = reportTitle(r), value
+		}
+	}
+	return best
+}
+
+func (c *Comparison) bestValue(metric string) float64 {
+	best := -1.0
+	for _, r := range c.Reports {
+		if value, ok := r.Summary.MeanScores[metric]; ok {
+			best = max(best, value)
+		}
+	}
+	return best
+}
+
+// WriteJSON writes the comparison as indented JSON
+func (c *Comparison) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteMarkdown writes a table of mean metrics per configuration, with the
+// best value of each metric in bold, followed by the per-case nDCG
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# RAG Comparison: %s\n\n", c.Dataset)
+
+	all := make(map[string]float64)
+	k := 0
+	for _, r := range c.Reports {
+		for name := range r.Summary.MeanScores {
+			all[name] = 0
+		}
+		k = max(k, r.K)
+	}
+	names := metricNames(all)
+
+	sb.WriteString("| Metric |")
+	for _, r := range c.Reports {
+		fmt.Fprintf(&sb, " %s |", inline(reportTitle(r)))
+	}
+	sb.WriteString("\n|---|")
+	for range c.Reports {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+
+	for _, name := range names {
+		fmt.Fprintf(&sb, "| %s |", metricLabel(name, k))
+		best := c.bestValue(name)
+		for _, r := range c.Reports {
+			value, ok := r.Summary.MeanScores[name]
+			switch {
+			case !ok:
+				sb.WriteString(" - |")
+			case value == best && len(c.Reports) > 1:
+				fmt.Fprintf(&sb, " **%.3f** |", value)
+			default:
+				fmt.Fprintf(&sb, " %.3f |", value)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("| Errored |")
+	for _, r := range c.Reports {
+		fmt.Fprintf(&sb, " %d |", r.Summary.Errored)
+	}
+	sb.WriteString("\n| Avg retrieval latency |")
+	for _, r := range c.Reports {
+		fmt.Fprintf(&sb, " %dms |", r.Summary.AvgLatencyMs)
+	}
+	sb.WriteString("\n")
+
+	if _, ok := all[MetricNDCG]; ok {
+		fmt.Fprintf(&sb, "\n## %s per case\n\n| Case |", metricLabel(MetricNDCG, k))
+		for _, r := range c.Reports {
+			fmt.Fprintf(&sb, " %s |", inline(reportTitle(r)))
+		}
+		sb.WriteString("\n|---|")
+		for range c.Reports {
+			sb.WriteString("---|")
+		}
+		sb.WriteString("\n")
+
+		for _, id := range c.caseIDs() {
+			fmt.Fprintf(&sb, "| %s |", id)
+			for _, r := range c.Reports {
+				if value, ok := r.caseScore(id, MetricNDCG); ok {
+					fmt.Fprintf(&sb, " %.2f |", value)
+				} else {
+					sb.WriteString(" - |")
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (c *Comparison) caseIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, r := range c.Reports {
+		for _, cr := range r.Cases {
+			if !seen[cr.ID] {
+				seen[cr.ID] = true
+				ids = append(ids, cr.ID)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *Report) caseScore(id, metric string) (float64, bool) {
+	for _, c := range r.Cases {
+		if c.ID == id {
+			value, ok := c.Scores[metric]
+			return value, ok
+		}
+	}
+	return 0, false
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// metricOrder lists the known metrics in report order
+var metricOrder = []string{MetricRecall, MetricMRR, MetricNDCG, MetricFaithfulness, MetricAnswerRelevance}
+
+// metricNames returns the metrics of m, known ones first
+func metricNames(m map[string]float64) []string {
+	var names []string
+	for _, name := range metricOrder {
+		if _, ok := m[name]; ok {
+			names = append(names, name)
+		}
+	}
+	var others []string
+	for name := range m {
+		if !slices.Contains(metricOrder, name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+func metricLabel(name string, k int) string {
+	suffix := ""
+	if k > 0 {
+		suffix = fmt.Sprintf("@%d", k)
+	}
+	switch name {
+	case MetricRecall:
+		return "Recall" + suffix
+	case MetricNDCG:
+		return "nDCG" + suffix
+	case MetricMRR:
+		return "MRR"
+	case MetricFaithfulness:
+		return "Faithfulness"
+	case MetricAnswerRelevance:
+		return "Answer relevance"
+	}
+	return name
+}
+
+func reportTitle(r *Report) string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return r.Name
+}
+
+func inline(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return evalx.Truncate(s, 500)
+}
@@ -0,0 +1,91 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+)
+
+// Source is a passage given to the model, numbered as it appears in the
+// prompt
+type Source struct {
+	Number   int
+	Document *document.Document
+}
+
+// selectSources numbers the documents in retrieval order, keeping those
+// that fit the token budget. A document that doesn't fit is skipped so a
+// shorter one further down can still be used; when not even the first one
+// fits it is truncated.
+func (c *Chain) selectSources(docs []*document.Document) []Source {
+	sources := make([]Source, 0, len(docs))
+	used := 0
+	for _, doc := range docs {
+		if doc == nil || strings.TrimSpace(doc.Content) == "" {
+			continue
+		}
+
+		source := Source{Number: len(sources) + 1, Document: doc}
+		tokens := c.tokenCounter(formatSource(source))
+		if c.maxContextTokens > 0 && used+tokens > c.maxContextTokens {
+			// Leave room for the source header
+			room := c.maxContextTokens - (tokens - c.tokenCounter(doc.Content))
+			if len(sources) > 0 || room <= 0 {
+				continue
+			}
+			source.Document = truncate(doc, room, c.tokenCounter)
+			tokens = c.maxContextTokens
+		}
+
+		sources = append(sources, source)
+		used += tokens
+	}
+	return sources
+}
+
+// truncate shortens a document's content to about tokens tokens
+func truncate(doc *document.Document, tokens int, counter document.TokenCounter) *document.Document {
+	content := doc.Content
+	total := counter(content)
+	if total <= tokens || total == 0 {
+		return doc
+	}
+
+	// Cut proportionally, then back off to a rune and word boundary
+	cut := len(content) * tokens / total
+	for cut > 0 && counter(content[:cut]) > tokens {
+		cut = cut * 9 / 10
+	}
+	for cut > 0 && cut < len(content) && content[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	if i := strings.LastIndexAny(content[:cut], " \n"); i > cut/2 {
+		cut = i
+	}
+
+	truncated := doc.Clone()
+	truncated.Content = strings.TrimSpace(content[:cut]) + "..."
+	return truncated
+}
+
+// formatSources renders the numbered list of sources for the prompt
+func formatSources(sources []Source) string {
+	parts := make([]string, len(sources))
+	for i, source := range sources {
+		parts[i] = formatSource(source)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func formatSource(source Source) string {
+	var header strings.Builder
+	fmt.Fprintf(&header, "[%d]", source.Number)
+	if name, ok := source.Document.GetMetadataString(document.MetadataSource); ok {
+		fmt.Fprintf(&header, " Source: %s", name)
+		if page, ok := source.Document.GetMetadataInt(document.MetadataPageNumber); ok {
+			fmt.Fprintf(&header, ", page %d", page)
+		}
+	}
+	return header.String() + "\n" + source.Document.Content
+}
@@ -0,0 +1,87 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+)
+
+func sourceContents(sources []Source) []string {
+	contents := make([]string, len(sources))
+	for i, s := range sources {
+		contents[i] = s.Document.Content
+	}
+	return contents
+}
+
+func TestSelectSources(t *testing.T) {
+	short := document.NewDocument(strings.Repeat("a", 20))
+	medium := document.NewDocument(strings.Repeat("b", 40))
+	long := document.NewDocument(strings.Repeat("c", 400))
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		docs      []*document.Document
+		want      []string
+	}{
+		{"no limit", 0, []*document.Document{medium, long, short}, []string{medium.Content, long.Content, short.Content}},
+		{"skips empty documents", 0, []*document.Document{nil, document.NewDocument("  \n"), short}, []string{short.Content}},
+		// [1] takes 11 tokens; [2] would take 101, so the short one moves up
+		{"skips what doesn't fit", 20, []*document.Document{medium, long, short}, []string{medium.Content, short.Content}},
+		{"stops at the budget", 11, []*document.Document{medium, short}, []string{medium.Content}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := New(nil, nil, WithMaxContextTokens(tt.maxTokens))
+			sources := chain.selectSources(tt.docs)
+			if got := sourceContents(sources); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+			for i, s := range sources {
+				if s.Number != i+1 {
+					t.Errorf("source %d numbered %d", i, s.Number)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectSourcesTruncatesFirst(t *testing.T) {
+	words := strings.Repeat("word ", 100)
+	doc := document.NewDocument(words).WithMetadata(document.MetadataSource, "a.txt")
+	chain := New(nil, nil, WithMaxContextTokens(20))
+
+	sources := chain.selectSources([]*document.Document{doc, document.NewDocument("tiny")})
+	if len(sources) != 1 {
+		t.Fatalf("selected %d sources, want only the truncated first one", len(sources))
+	}
+	content := sources[0].Document.Content
+	if !strings.HasSuffix(content, "word...") {
+		t.Errorf("content = %q, want it cut at a word and marked", content)
+	}
+	if tokens := chain.tokenCounter(formatSource(sources[0])); tokens > 20+1 {
+		t.Errorf("truncated source takes %d tokens, want about 20", tokens)
+	}
+	if doc.Content != words {
+		t.Error("truncation changed the retrieved document")
+	}
+	if sources[0].Document.Metadata[document.MetadataSource] != "a.txt" {
+		t.Error("truncated document lost its metadata")
+	}
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	doc := document.NewDocument(strings.Repeat("é", 100))
+	for tokens := 1; tokens < 50; tokens++ {
+		got := truncate(doc, tokens, estimateTokens).Content
+		if !utf8.ValidString(got) {
+			t.Fatalf("truncate to %d tokens cut a rune: %q", tokens, got)
+		}
+	}
+	if got := truncate(doc, 100, estimateTokens); got != doc {
+		t.Error("truncate changed a document that fits")
+	}
+}
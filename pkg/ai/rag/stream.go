@@ -0,0 +1,98 @@
+package rag
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/document"
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// MetadataCitations is the key of the citations on the last message of an
+// AnswerStream
+const MetadataCitations = "citations"
+
+// AnswerStream streams an answer. It forwards the model's chunks and, once
+// the model is done, returns one more assistant message without content
+// whose Metadata[MetadataCitations] holds the []Citation of the answer,
+// before io.EOF. It implements llm.Stream.
+type AnswerStream struct {
+	stream       llm.Stream
+	sources      []Source
+	promptTokens int
+	tokenCounter document.TokenCounter
+	text         strings.Builder
+	answer       *Answer
+	done         bool
+}
+
+// usageReporter is implemented by streams that report token usage once
+// they are done
+type usageReporter interface {
+	Usage() llm.Usage
+}
+
+// Next returns the next chunk of the answer, then the citations
+func (s *AnswerStream) Next() (llm.Message, error) {
+	if s.done {
+		return llm.Message{}, io.EOF
+	}
+	if s.answer != nil {
+		s.done = true
+		return llm.Message{
+			Role:     llm.RoleAssistant,
+			Metadata: map[string]any{MetadataCitations: s.answer.Citations},
+		}, nil
+	}
+
+	chunk, err := s.stream.Next()
+	if errors.Is(err, io.EOF) {
+		text := s.text.String()
+		s.answer = &Answer{
+			Text:      text,
+			Citations: ParseCitations(text, s.sources),
+			Sources:   s.sources,
+			Usage:     s.usage(text),
+		}
+		return s.Next()
+	}
+	if err != nil {
+		return llm.Message{}, err
+	}
+
+	s.text.WriteString(chunk.TextContent())
+	return chunk, nil
+}
+
+// usage returns the usage reported by the model's stream, or an estimate
+// made with the chain's token counter when it reports none
+func (s *AnswerStream) usage(text string) llm.Usage {
+	if reporter, ok := s.stream.(usageReporter); ok {
+		if usage := reporter.Usage(); usage.TotalTokens > 0 {
+			return usage
+		}
+	}
+	completion := s.tokenCounter(text)
+	return llm.Usage{
+		PromptTokens:     s.promptTokens,
+		CompletionTokens: completion,
+		TotalTokens:      s.promptTokens + completion,
+	}
+}
+
+// Close closes the underlying stream
+func (s *AnswerStream) Close() error {
+	return s.stream.Close()
+}
+
+// Sources returns the passages given to the model
+func (s *AnswerStream) Sources() []Source {
+	return s.sources
+}
+
+// Answer returns the complete answer once the stream has reached its end,
+// or nil before
+func (s *AnswerStream) Answer() *Answer {
+	return s.answer
+}
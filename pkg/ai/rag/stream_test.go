@@ -0,0 +1,86 @@
+package rag
+
+import (
+	"io"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/llm"
+)
+
+// chunkStream replays chunks, optionally reporting usage
+type chunkStream struct {
+	chunks []llm.Message
+	usage  llm.Usage
+}
+
+func (s *chunkStream) Next() (llm.Message, error) {
+	if len(s.chunks) == 0 {
+		return llm.Message{}, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *chunkStream) Close() error { return nil }
+
+// reportingStream reports the usage of the call once done
+type reportingStream struct{ chunkStream }
+
+func (s *reportingStream) Usage() llm.Usage { return s.usage }
+
+func drain(t *testing.T, stream *AnswerStream) llm.Message {
+	t.Helper()
+	var last llm.Message
+	for {
+		msg, err := stream.Next()
+		if err == io.EOF {
+			return last
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		last = msg
+	}
+}
+
+func TestAnswerStream(t *testing.T) {
+	chunks := []llm.Message{
+		{Role: llm.RoleAssistant, Content: "Five days "},
+		{Role: llm.RoleAssistant, MultiContent: []llm.ContentPart{llm.TextPart("[1].")}},
+	}
+	stream := &AnswerStream{
+		stream:       &chunkStream{chunks: chunks},
+		sources:      numberedSources(1),
+		promptTokens: 30,
+		tokenCounter: estimateTokens,
+	}
+
+	last := drain(t, stream)
+	answer := stream.Answer()
+	if answer == nil || answer.Text != "Five days [1]." {
+		t.Fatalf("answer = %+v, want the text of every chunk", answer)
+	}
+	if citations, _ := last.Metadata[MetadataCitations].([]Citation); len(citations) != 1 {
+		t.Errorf("last message citations = %v, want 1", last.Metadata[MetadataCitations])
+	}
+	if want := (llm.Usage{PromptTokens: 30, CompletionTokens: 4, TotalTokens: 34}); answer.Usage != want {
+		t.Errorf("estimated usage = %+v, want %+v", answer.Usage, want)
+	}
+}
+
+func TestAnswerStreamReportedUsage(t *testing.T) {
+	usage := llm.Usage{PromptTokens: 120, CompletionTokens: 8, TotalTokens: 128}
+	stream := &AnswerStream{
+		stream: &reportingStream{chunkStream{
+			chunks: []llm.Message{{Role: llm.RoleAssistant, Content: "Yes."}},
+			usage:  usage,
+		}},
+		tokenCounter: estimateTokens,
+	}
+
+	drain(t, stream)
+	if got := stream.Answer().Usage; got != usage {
+		t.Errorf("usage = %+v, want the stream's %+v", got, usage)
+	}
+}
@@ -0,0 +1,35 @@
+package rerank
+
+// RerankOptions contains options for reranking
+type RerankOptions struct {
+	// Model is the rerank model to use
+	Model string
+
+	// TopN limits the number of results (0 returns every document)
+	TopN int
+}
+
+// Option is a function type to modify RerankOptions
+type Option func(*RerankOptions)
+
+// WithModel sets the rerank model to use
+func WithModel(model string) Option {
+	return func(o *RerankOptions) {
+		o.Model = model
+	}
+}
+
+// WithTopN sets the number of results to return
+func WithTopN(n int) Option {
+	return func(o *RerankOptions) {
+		o.TopN = n
+	}
+}
+
+// DefaultOptions returns the default rerank options
+func DefaultOptions() *RerankOptions {
+	return &RerankOptions{
+		// Default model will be provider-specific
+		TopN: 0,
+	}
+}
@@ -0,0 +1,36 @@
+package rerank
+
+import (
+	"context"
+)
+
+// Reranker scores documents by their relevance to a query, as hosted rerank
+// models (Cohere Rerank, Amazon Rerank) do
+type Reranker interface {
+	// Rerank returns the documents ordered by relevance, most relevant first
+	Rerank(ctx context.Context, query string, documents []string, opts ...Option) ([]Result, error)
+}
+
+// Result is the relevance of one document
+type Result struct {
+	// Index is the position of the document in the request
+	Index int
+
+	// Score is the relevance score, between 0 and 1 for the supported models
+	Score float64
+}
+
+// Client represents a configured rerank client
+type Client struct {
+	reranker Reranker
+}
+
+// NewClient creates a new rerank client
+func NewClient(reranker Reranker) *Client {
+	return &Client{reranker: reranker}
+}
+
+// Rerank returns the documents ordered by relevance, most relevant first
+func (c *Client) Rerank(ctx context.Context, query string, documents []string, opts ...Option) ([]Result, error) {
+	return c.reranker.Rerank(ctx, query, documents, opts...)
+}
@@ -17,6 +17,7 @@ type Client struct {
 	hybridSearcher   HybridSearcher
 	sparseSupport    SparseVectorSupport
 	statsProvider    StatisticsProvider
+	lister           VectorLister
 }
 
 // NewClient creates a client from a provider
@@ -47,6 +48,9 @@ func NewClient(storer VectorStorer) *Client {
 	if sp, ok := storer.(StatisticsProvider); ok {
 		client.statsProvider = sp
 	}
+	if vl, ok := storer.(VectorLister); ok {
+		client.lister = vl
+	}
 
 	return client
 }
@@ -217,6 +221,14 @@ func (c *Client) HybridQuery(ctx context.Context, vector []float32, query string
 	return c.hybridSearcher.HybridQuery(ctx, vector, query, opts...)
 }
 
+// UpsertSparse sets the sparse values of existing vectors
+func (c *Client) UpsertSparse(ctx context.Context, ids []string, vectors []SparseVector, opts ...Option) error {
+	if c.sparseSupport == nil {
+		return fmt.Errorf("sparse vectors not supported by this provider")
+	}
+	return c.sparseSupport.UpsertSparse(ctx, ids, vectors, opts...)
+}
+
 // QuerySparse queries with sparse vectors
 func (c *Client) QuerySparse(ctx context.Context, vector SparseVector, opts ...Option) (*QueryResult, error) {
 	if c.sparseSupport == nil {
@@ -237,6 +249,18 @@ func (c *Client) GetStatistics(ctx context.Context, opts ...Option) (*Statistics
 	return c.statsProvider.GetStatistics(ctx, opts...)
 }
 
+// ============================================================================
+// Listing
+// ============================================================================
+
+// List returns a page of stored vectors
+func (c *Client) List(ctx context.Context, cursor string, limit int, opts ...Option) (*ListResult, error) {
+	if c.lister == nil {
+		return nil, fmt.Errorf("listing not supported by this provider")
+	}
+	return c.lister.List(ctx, cursor, limit, opts...)
+}
+
 // ============================================================================
 // Capability Checks
 // ============================================================================
@@ -248,3 +272,4 @@ func (c *Client) SupportsIndexManagement() bool   { return c.indexManager != nil
 func (c *Client) SupportsHybridSearch() bool      { return c.hybridSearcher != nil }
 func (c *Client) SupportsSparseVectors() bool     { return c.sparseSupport != nil }
 func (c *Client) SupportsStatistics() bool        { return c.statsProvider != nil }
+func (c *Client) SupportsListing() bool           { return c.lister != nil }
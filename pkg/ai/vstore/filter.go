@@ -0,0 +1,145 @@
+package vstore
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// Filter Expression Trees
+// ============================================================================
+
+// Where returns a filter with a single must condition
+func Where(field string, op FilterOperator, value any) Filter {
+	return Filter{Must: []Condition{{Field: field, Operator: op, Value: value}}}
+}
+
+// And returns a filter that matches when every filter matches. Filters that
+// hold only must conditions are merged into Must instead of nesting.
+func And(filters ...Filter) Filter {
+	var out Filter
+	for _, f := range filters {
+		switch {
+		case f.IsEmpty():
+		case f.onlyMust():
+			out.Must = append(out.Must, f.Must...)
+		default:
+			out.And = append(out.And, f)
+		}
+	}
+	return out
+}
+
+// Or returns a filter that matches when at least one filter matches.
+// Single-condition filters are merged into Should instead of nesting.
+func Or(filters ...Filter) Filter {
+	var out Filter
+	for _, f := range filters {
+		if cond, ok := f.single(); ok {
+			out.Should = append(out.Should, cond)
+			continue
+		}
+		out.Or = append(out.Or, f)
+	}
+	return out
+}
+
+// Not returns a filter that matches when filter doesn't
+func Not(filter Filter) Filter {
+	if cond, ok := filter.single(); ok {
+		return Filter{MustNot: []Condition{cond}}
+	}
+	return Filter{Not: []Filter{filter}}
+}
+
+// AddAnd adds sub-filters that must all match
+func (f *Filter) AddAnd(filters ...Filter) *Filter {
+	f.And = append(f.And, filters...)
+	return f
+}
+
+// AddOr adds sub-filters to the OR group shared with Should
+func (f *Filter) AddOr(filters ...Filter) *Filter {
+	f.Or = append(f.Or, filters...)
+	return f
+}
+
+// AddNot adds sub-filters that must not match
+func (f *Filter) AddNot(filters ...Filter) *Filter {
+	f.Not = append(f.Not, filters...)
+	return f
+}
+
+// IsEmpty reports whether the filter has no conditions at any level, in
+// which case it matches everything
+func (f Filter) IsEmpty() bool {
+	if len(f.Must) > 0 || len(f.Should) > 0 || len(f.MustNot) > 0 {
+		return false
+	}
+	for _, group := range [][]Filter{f.And, f.Or, f.Not} {
+		for _, sub := range group {
+			if !sub.IsEmpty() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Conditions returns every condition in the tree, depth first
+func (f Filter) Conditions() []Condition {
+	var out []Condition
+	out = append(out, f.Must...)
+	out = append(out, f.Should...)
+	out = append(out, f.MustNot...)
+	for _, group := range [][]Filter{f.And, f.Or, f.Not} {
+		for _, sub := range group {
+			out = append(out, sub.Conditions()...)
+		}
+	}
+	return out
+}
+
+// onlyMust reports whether the filter holds nothing but must conditions
+func (f Filter) onlyMust() bool {
+	return len(f.Must) > 0 && len(f.Should) == 0 && len(f.MustNot) == 0 &&
+		len(f.And) == 0 && len(f.Or) == 0 && len(f.Not) == 0
+}
+
+// single returns the only condition of a filter that is equivalent to it
+func (f Filter) single() (Condition, bool) {
+	if len(f.And) > 0 || len(f.Or) > 0 || len(f.Not) > 0 || len(f.MustNot) > 0 {
+		return Condition{}, false
+	}
+	switch {
+	case len(f.Must) == 1 && len(f.Should) == 0:
+		return f.Must[0], true
+	case len(f.Should) == 1 && len(f.Must) == 0:
+		return f.Should[0], true
+	}
+	return Condition{}, false
+}
+
+// ============================================================================
+// Field Paths
+// ============================================================================
+
+// FieldPath splits a condition field into the keys of a nested metadata
+// path ("author.name" -> ["author", "name"])
+func FieldPath(field string) []string {
+	return strings.Split(field, ".")
+}
+
+// LookupField resolves a possibly nested field in metadata
+func LookupField(metadata map[string]any, field string) (any, bool) {
+	var current any = metadata
+	for _, key := range FieldPath(field) {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
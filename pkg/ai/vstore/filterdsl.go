@@ -0,0 +1,582 @@
+package vstore
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Filter DSL
+// ============================================================================
+
+// ErrInvalidFilter is returned (wrapped) by ParseFilter for malformed
+// expressions
+var ErrInvalidFilter = errors.New("invalid filter expression")
+
+// ParseFilter parses a filter expression such as
+//
+//	tenant = "A" AND (type = "pdf" OR type = "doc") AND NOT archived = true
+//
+// Grammar (keywords are case-insensitive):
+//
+//	expr       = term { OR term }
+//	term       = factor { AND factor }
+//	factor     = NOT factor | "(" expr ")" | condition
+//	condition  = field ( "=" | "!=" | ">" | ">=" | "<" | "<=" ) value
+//	           | field [ NOT ] IN list
+//	           | field [ NOT ] EXISTS
+//	           | field CONTAINS value
+//	           | field HAS [ ANY | ALL ] ( value | list )
+//	list       = "[" value { "," value } "]"
+//	value      = string | number | true | false
+//
+// Fields are identifiers joined by dots for nested metadata
+// ("author.name"), or any text in backticks. Strings use single or double
+// quotes with backslash escapes. HAS, HAS ANY and HAS ALL map to the array
+// operators.
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{lexer: filterLexer{input: expr}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return &Filter{}, nil
+	}
+
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &filter, nil
+}
+
+// String renders the filter in the syntax accepted by ParseFilter. Values of
+// types the syntax can't express are rendered with %v.
+func (f Filter) String() string {
+	var parts []string
+	for _, cond := range f.Must {
+		parts = append(parts, cond.String())
+	}
+	for _, sub := range f.And {
+		if !sub.IsEmpty() {
+			parts = append(parts, "("+sub.String()+")")
+		}
+	}
+
+	if len(f.Should) > 0 || len(f.Or) > 0 {
+		var should []string
+		for _, cond := range f.Should {
+			should = append(should, cond.String())
+		}
+		for _, sub := range f.Or {
+			should = append(should, "("+sub.String()+")")
+		}
+		if len(parts) == 0 && len(f.MustNot) == 0 && len(f.Not) == 0 {
+			return strings.Join(should, " OR ")
+		}
+		parts = append(parts, "("+strings.Join(should, " OR ")+")")
+	}
+
+	for _, cond := range f.MustNot {
+		parts = append(parts, "NOT "+cond.String())
+	}
+	for _, sub := range f.Not {
+		parts = append(parts, "NOT ("+sub.String()+")")
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// String renders the condition in the syntax accepted by ParseFilter
+func (c Condition) String() string {
+	field := formatField(c.Field)
+	switch c.Operator {
+	case OpEqual:
+		return field + " = " + formatValue(c.Value)
+	case OpNotEqual:
+		return field + " != " + formatValue(c.Value)
+	case OpGreaterThan:
+		return field + " > " + formatValue(c.Value)
+	case OpGreaterThanOrEqual:
+		return field + " >= " + formatValue(c.Value)
+	case OpLessThan:
+		return field + " < " + formatValue(c.Value)
+	case OpLessThanOrEqual:
+		return field + " <= " + formatValue(c.Value)
+	case OpIn:
+		return field + " IN " + formatList(c.Value)
+	case OpNotIn:
+		return field + " NOT IN " + formatList(c.Value)
+	case OpExists:
+		return field + " EXISTS"
+	case OpContains:
+		return field + " CONTAINS " + formatValue(c.Value)
+	case OpArrayContains:
+		return field + " HAS " + formatValue(c.Value)
+	case OpArrayContainsAny:
+		return field + " HAS ANY " + formatList(c.Value)
+	case OpArrayContainsAll:
+		return field + " HAS ALL " + formatList(c.Value)
+	default:
+		return fmt.Sprintf("%s %s %s", field, c.Operator, formatValue(c.Value))
+	}
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type filterParser struct {
+	lexer filterLexer
+	tok   filterToken
+}
+
+func (p *filterParser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *filterParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidFilter, fmt.Sprintf(format, args...), p.tok.pos)
+}
+
+// keyword reports whether the current token is the given keyword
+func (p *filterParser) keyword(word string) bool {
+	return p.tok.kind == tokIdent && !p.tok.quoted && strings.EqualFold(p.tok.text, word)
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return Filter{}, err
+	}
+	filters := []Filter{first}
+	for p.keyword("OR") {
+		if err := p.advance(); err != nil {
+			return Filter{}, err
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return Filter{}, err
+		}
+		filters = append(filters, next)
+	}
+	if len(filters) == 1 {
+		return first, nil
+	}
+	return Or(filters...), nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	first, err := p.parseFactor()
+	if err != nil {
+		return Filter{}, err
+	}
+	filters := []Filter{first}
+	for p.keyword("AND") {
+		if err := p.advance(); err != nil {
+			return Filter{}, err
+		}
+		next, err := p.parseFactor()
+		if err != nil {
+			return Filter{}, err
+		}
+		filters = append(filters, next)
+	}
+	if len(filters) == 1 {
+		return first, nil
+	}
+	return And(filters...), nil
+}
+
+func (p *filterParser) parseFactor() (Filter, error) {
+	switch {
+	case p.keyword("NOT"):
+		if err := p.advance(); err != nil {
+			return Filter{}, err
+		}
+		inner, err := p.parseFactor()
+		if err != nil {
+			return Filter{}, err
+		}
+		return Not(inner), nil
+
+	case p.tok.kind == tokLParen:
+		if err := p.advance(); err != nil {
+			return Filter{}, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return Filter{}, err
+		}
+		if p.tok.kind != tokRParen {
+			return Filter{}, p.errorf("expected ')', got %s", p.tok)
+		}
+		return inner, p.advance()
+
+	default:
+		cond, negate, err := p.parseCondition()
+		if err != nil {
+			return Filter{}, err
+		}
+		if negate {
+			return Filter{MustNot: []Condition{cond}}, nil
+		}
+		return Where(cond.Field, cond.Operator, cond.Value), nil
+	}
+}
+
+// parseCondition parses a single comparison. negate is set for the
+// NOT EXISTS form, which has no operator of its own.
+func (p *filterParser) parseCondition() (cond Condition, negate bool, err error) {
+	if p.tok.kind != tokIdent || (!p.tok.quoted && isFilterKeyword(p.tok.text)) {
+		return Condition{}, false, p.errorf("expected a field, got %s", p.tok)
+	}
+	cond.Field = p.tok.text
+	if err := p.advance(); err != nil {
+		return Condition{}, false, err
+	}
+
+	if p.tok.kind == tokOperator {
+		cond.Operator = comparisonOperators[p.tok.text]
+		if err := p.advance(); err != nil {
+			return Condition{}, false, err
+		}
+		cond.Value, err = p.parseValue()
+		return cond, false, err
+	}
+
+	switch {
+	case p.keyword("IN"):
+		cond.Operator = OpIn
+	case p.keyword("NOT"):
+		if err := p.advance(); err != nil {
+			return Condition{}, false, err
+		}
+		switch {
+		case p.keyword("IN"):
+			cond.Operator = OpNotIn
+		case p.keyword("EXISTS"):
+			cond.Operator = OpExists
+			return cond, true, p.advance()
+		default:
+			return Condition{}, false, p.errorf("expected IN or EXISTS after NOT, got %s", p.tok)
+		}
+	case p.keyword("EXISTS"):
+		cond.Operator = OpExists
+		return cond, false, p.advance()
+	case p.keyword("CONTAINS"):
+		cond.Operator = OpContains
+		if err := p.advance(); err != nil {
+			return Condition{}, false, err
+		}
+		cond.Value, err = p.parseValue()
+		return cond, false, err
+	case p.keyword("HAS"):
+		if err := p.advance(); err != nil {
+			return Condition{}, false, err
+		}
+		switch {
+		case p.keyword("ANY"):
+			cond.Operator = OpArrayContainsAny
+		case p.keyword("ALL"):
+			cond.Operator = OpArrayContainsAll
+		default:
+			cond.Operator = OpArrayContains
+			cond.Value, err = p.parseValue()
+			return cond, false, err
+		}
+	default:
+		return Condition{}, false, p.errorf("expected an operator after %q, got %s", cond.Field, p.tok)
+	}
+
+	if err := p.advance(); err != nil {
+		return Condition{}, false, err
+	}
+	cond.Value, err = p.parseList()
+	return cond, false, err
+}
+
+func (p *filterParser) parseList() ([]any, error) {
+	if p.tok.kind != tokLBracket {
+		return nil, p.errorf("expected '[', got %s", p.tok)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	values := []any{}
+	for p.tok.kind != tokRBracket {
+		if len(values) > 0 {
+			if p.tok.kind != tokComma {
+				return nil, p.errorf("expected ',' or ']', got %s", p.tok)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, p.advance()
+}
+
+func (p *filterParser) parseValue() (any, error) {
+	var value any
+	switch {
+	case p.tok.kind == tokString:
+		value = p.tok.text
+	case p.tok.kind == tokNumber:
+		if n, err := strconv.ParseInt(p.tok.text, 10, 64); err == nil {
+			value = n
+		} else if f, err := strconv.ParseFloat(p.tok.text, 64); err == nil {
+			value = f
+		} else {
+			return nil, p.errorf("invalid number %q", p.tok.text)
+		}
+	case p.keyword("true"):
+		value = true
+	case p.keyword("false"):
+		value = false
+	default:
+		return nil, p.errorf("expected a value, got %s", p.tok)
+	}
+	return value, p.advance()
+}
+
+var comparisonOperators = map[string]FilterOperator{
+	"=":  OpEqual,
+	"==": OpEqual,
+	"!=": OpNotEqual,
+	"<>": OpNotEqual,
+	">":  OpGreaterThan,
+	">=": OpGreaterThanOrEqual,
+	"<":  OpLessThan,
+	"<=": OpLessThanOrEqual,
+}
+
+var filterKeywords = []string{"AND", "OR", "NOT", "IN", "EXISTS", "CONTAINS", "HAS", "ANY", "ALL", "TRUE", "FALSE"}
+
+func isFilterKeyword(s string) bool {
+	for _, kw := range filterKeywords {
+		if strings.EqualFold(s, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Lexer
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOperator
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type filterToken struct {
+	kind   tokenKind
+	text   string
+	pos    int
+	quoted bool // backtick-quoted identifier, never a keyword
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+type filterLexer struct {
+	input string
+	pos   int
+}
+
+func (l *filterLexer) errorf(pos int, format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidFilter, fmt.Sprintf(format, args...), pos)
+}
+
+func (l *filterLexer) next() (filterToken, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return filterToken{kind: tokEOF, pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	single := func(kind tokenKind) (filterToken, error) {
+		l.pos++
+		return filterToken{kind: kind, text: string(c), pos: start}, nil
+	}
+
+	switch {
+	case c == '(':
+		return single(tokLParen)
+	case c == ')':
+		return single(tokRParen)
+	case c == '[':
+		return single(tokLBracket)
+	case c == ']':
+		return single(tokRBracket)
+	case c == ',':
+		return single(tokComma)
+	case strings.IndexByte("=!<>", c) >= 0:
+		for _, op := range []string{">=", "<=", "!=", "<>", "==", "=", ">", "<"} {
+			if strings.HasPrefix(l.input[l.pos:], op) {
+				l.pos += len(op)
+				return filterToken{kind: tokOperator, text: op, pos: start}, nil
+			}
+		}
+		return filterToken{}, l.errorf(start, "unexpected %q", c)
+	case c == '"' || c == '\'':
+		text, err := l.scanQuoted(c)
+		return filterToken{kind: tokString, text: text, pos: start}, err
+	case c == '`':
+		text, err := l.scanQuoted(c)
+		if err == nil && text == "" {
+			err = l.errorf(start, "empty field name")
+		}
+		return filterToken{kind: tokIdent, text: text, pos: start, quoted: true}, err
+	case c == '-' || c == '.' || isDigit(c):
+		for l.pos++; l.pos < len(l.input); l.pos++ {
+			d := l.input[l.pos]
+			if !isDigit(d) && d != '.' && d != 'e' && d != 'E' &&
+				!((d == '-' || d == '+') && (l.input[l.pos-1] == 'e' || l.input[l.pos-1] == 'E')) {
+				break
+			}
+		}
+		return filterToken{kind: tokNumber, text: l.input[start:l.pos], pos: start}, nil
+	case isIdentStart(c):
+		for l.pos++; l.pos < len(l.input) && isIdentPart(l.input[l.pos]); l.pos++ {
+		}
+		return filterToken{kind: tokIdent, text: l.input[start:l.pos], pos: start}, nil
+	default:
+		return filterToken{}, l.errorf(start, "unexpected %q", c)
+	}
+}
+
+// scanQuoted reads text up to the closing quote, resolving backslash escapes
+func (l *filterLexer) scanQuoted(quote byte) (string, error) {
+	start := l.pos
+	var sb strings.Builder
+	for l.pos++; l.pos < len(l.input); l.pos++ {
+		c := l.input[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return sb.String(), nil
+		case c == '\\' && l.pos+1 < len(l.input):
+			l.pos++
+			switch e := l.input[l.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", l.errorf(start, "unterminated %c", quote)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.' || c == '-'
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+func formatField(field string) string {
+	plain := field != "" && isIdentStart(field[0]) && !isFilterKeyword(field)
+	for i := 0; plain && i < len(field); i++ {
+		plain = isIdentPart(field[i])
+	}
+	if plain {
+		return field
+	}
+	return "`" + quoteEscaper('`').Replace(field) + "`"
+}
+
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return `"` + quoteEscaper('"').Replace(val) + `"`
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return formatFloat(strconv.FormatFloat(val, 'g', -1, 64))
+	case float32:
+		return formatFloat(strconv.FormatFloat(float64(val), 'g', -1, 32))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// formatFloat keeps whole floats distinguishable from integers
+func formatFloat(s string) string {
+	if strings.ContainsAny(s, ".eEIN") {
+		return s
+	}
+	return s + ".0"
+}
+
+func formatList(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "[" + formatValue(v) + "]"
+	}
+	items := make([]string, rv.Len())
+	for i := range items {
+		items[i] = formatValue(rv.Index(i).Interface())
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func quoteEscaper(quote byte) *strings.Replacer {
+	return strings.NewReplacer(`\`, `\\`, string(quote), `\`+string(quote), "\n", `\n`, "\t", `\t`)
+}
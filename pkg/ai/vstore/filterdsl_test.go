@@ -0,0 +1,93 @@
+package vstore_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want vstore.Filter
+	}{
+		{``, vstore.Filter{}},
+		{`tenant = "A"`, vstore.Where("tenant", vstore.OpEqual, "A")},
+		{`year >= 2020 and rating < 4.5`, vstore.And(
+			vstore.Where("year", vstore.OpGreaterThanOrEqual, int64(2020)),
+			vstore.Where("rating", vstore.OpLessThan, 4.5),
+		)},
+		{`tenant = 'A' AND (type = "pdf" OR type = "doc") AND NOT archived = true`, vstore.And(
+			vstore.Where("tenant", vstore.OpEqual, "A"),
+			vstore.Or(
+				vstore.Where("type", vstore.OpEqual, "pdf"),
+				vstore.Where("type", vstore.OpEqual, "doc"),
+			),
+			vstore.Not(vstore.Where("archived", vstore.OpEqual, true)),
+		)},
+		{`a = 1 OR b = 2 AND c = 3`, vstore.Or(
+			vstore.Where("a", vstore.OpEqual, int64(1)),
+			vstore.And(
+				vstore.Where("b", vstore.OpEqual, int64(2)),
+				vstore.Where("c", vstore.OpEqual, int64(3)),
+			),
+		)},
+		{`author.name != "bob"`, vstore.Where("author.name", vstore.OpNotEqual, "bob")},
+		{"`content-type` = 'text/html'", vstore.Where("content-type", vstore.OpEqual, "text/html")},
+		{`type IN ["pdf", "doc"]`, vstore.Where("type", vstore.OpIn, []any{"pdf", "doc"})},
+		{`type NOT IN []`, vstore.Where("type", vstore.OpNotIn, []any{})},
+		{`deleted_at NOT EXISTS`, vstore.Not(vstore.Where("deleted_at", vstore.OpExists, nil))},
+		{`title CONTAINS "it's"`, vstore.Where("title", vstore.OpContains, "it's")},
+		{`tags HAS "go"`, vstore.Where("tags", vstore.OpArrayContains, "go")},
+		{`tags HAS ANY ["go", "rust"]`, vstore.Where("tags", vstore.OpArrayContainsAny, []any{"go", "rust"})},
+		{`tags HAS ALL ["go"]`, vstore.Where("tags", vstore.OpArrayContainsAll, []any{"go"})},
+		{`score > -1.5e2`, vstore.Where("score", vstore.OpGreaterThan, -150.0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := vstore.ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseFilter = %#v, want %#v", *got, tt.want)
+			}
+
+			// String renders an expression that parses to the same tree
+			again, err := vstore.ParseFilter(got.String())
+			if err != nil {
+				t.Fatalf("ParseFilter(%q): %v", got.String(), err)
+			}
+			if !reflect.DeepEqual(again, got) {
+				t.Errorf("round trip through %q = %#v, want %#v", got.String(), *again, *got)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, expr := range []string{
+		`tenant`,
+		`tenant =`,
+		`= "A"`,
+		`(tenant = "A"`,
+		`tenant = "A")`,
+		`tenant = "A`,
+		`tenant = "A" AND`,
+		`tenant IN "A"`,
+		`tags HAS ANY ["a" "b"]`,
+		`a NOT = 1`,
+		`and = 1`,
+		`a = 1.2.3`,
+		`a ~ 1`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := vstore.ParseFilter(expr); !errors.Is(err, vstore.ErrInvalidFilter) {
+				t.Errorf("ParseFilter(%q) error = %v, want ErrInvalidFilter", expr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package vstore
+
+import (
+	"sort"
+)
+
+// ============================================================================
+// Hybrid Result Fusion
+// ============================================================================
+
+// FusionMethod defines how hybrid search merges vector and keyword results
+type FusionMethod string
+
+const (
+	// FusionRRF ranks by reciprocal rank fusion, ignoring raw scores
+	FusionRRF FusionMethod = "rrf"
+
+	// FusionWeighted blends min-max normalized scores
+	FusionWeighted FusionMethod = "weighted"
+)
+
+// DefaultRRFK is the rank constant commonly used for reciprocal rank fusion
+const DefaultRRFK = 60
+
+// HybridCandidateCount returns how many results each side of a hybrid search
+// should fetch before fusion
+func (o *Options) HybridCandidateCount() int {
+	if o.HybridCandidates > 0 {
+		return o.HybridCandidates
+	}
+	return o.TopK * 4
+}
+
+// FuseResults merges vector and keyword matches (each sorted best first)
+// into a single ranking using options.Fusion, weighted by options.HybridAlpha
+// (0 = keyword only, 1 = vector only). The result is truncated to TopK and
+// filtered by MinScore, which applies to the fused score.
+//
+// With FusionRRF a match scores alpha/(k+rank_vector) + (1-alpha)/(k+rank_keyword),
+// where a missing side contributes 0. With FusionWeighted each list's scores
+// are min-max normalized to [0, 1] before blending.
+func FuseResults(vectorMatches, keywordMatches []Match, options *Options) []Match {
+	alpha := options.HybridAlpha
+	if alpha < 0 {
+		alpha = 0
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+
+	var vectorScores, keywordScores map[string]float32
+	switch options.Fusion {
+	case FusionWeighted:
+		vectorScores = normalizedScores(vectorMatches)
+		keywordScores = normalizedScores(keywordMatches)
+	default:
+		k := options.RRFK
+		if k <= 0 {
+			k = DefaultRRFK
+		}
+		vectorScores = reciprocalRanks(vectorMatches, k)
+		keywordScores = reciprocalRanks(keywordMatches, k)
+	}
+
+	// Vector matches win on payload since they can carry values
+	merged := make(map[string]Match, len(vectorMatches)+len(keywordMatches))
+	for _, m := range keywordMatches {
+		merged[m.ID] = m
+	}
+	for _, m := range vectorMatches {
+		if existing, ok := merged[m.ID]; ok && m.Metadata == nil {
+			m.Metadata = existing.Metadata
+		}
+		merged[m.ID] = m
+	}
+
+	fused := make([]Match, 0, len(merged))
+	for id, m := range merged {
+		// Skip matches found only by a side that carries no weight
+		_, inVector := vectorScores[id]
+		_, inKeyword := keywordScores[id]
+		if !(inVector && alpha > 0) && !(inKeyword && alpha < 1) {
+			continue
+		}
+
+		m.Score = alpha*vectorScores[id] + (1-alpha)*keywordScores[id]
+		if !options.MeetsMinScore(m.Score) {
+			continue
+		}
+		fused = append(fused, m)
+	}
+
+	sort.Slice(fused, func(i, j int) bool {
+		if fused[i].Score != fused[j].Score {
+			return fused[i].Score > fused[j].Score
+		}
+		return fused[i].ID < fused[j].ID
+	})
+
+	if options.TopK > 0 && len(fused) > options.TopK {
+		fused = fused[:options.TopK]
+	}
+	return fused
+}
+
+// reciprocalRanks scores each match by 1/(k+rank), rank starting at 1
+func reciprocalRanks(matches []Match, k int) map[string]float32 {
+	scores := make(map[string]float32, len(matches))
+	for i, m := range matches {
+		if _, seen := scores[m.ID]; !seen {
+			scores[m.ID] = 1 / float32(k+i+1)
+		}
+	}
+	return scores
+}
+
+// normalizedScores min-max normalizes match scores to [0, 1]. When every
+// score is equal, all matches score 1.
+func normalizedScores(matches []Match) map[string]float32 {
+	scores := make(map[string]float32, len(matches))
+	if len(matches) == 0 {
+		return scores
+	}
+
+	lo, hi := matches[0].Score, matches[0].Score
+	for _, m := range matches {
+		lo = min(lo, m.Score)
+		hi = max(hi, m.Score)
+	}
+
+	for _, m := range matches {
+		if hi == lo {
+			scores[m.ID] = 1
+		} else {
+			scores[m.ID] = (m.Score - lo) / (hi - lo)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,79 @@
+package vstore_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+func matches(ids ...string) []vstore.Match {
+	out := make([]vstore.Match, len(ids))
+	for i, id := range ids {
+		out[i] = vstore.Match{ID: id, Score: float32(len(ids) - i)}
+	}
+	return out
+}
+
+func TestFuseResults(t *testing.T) {
+	vector := matches("a", "b", "c")
+	keyword := matches("c", "d")
+
+	tests := []struct {
+		name string
+		opts []vstore.Option
+		want []string
+	}{
+		{"rrf", nil, []string{"c", "a", "b", "d"}},
+		{"rrf vector only", []vstore.Option{vstore.WithHybridAlpha(1)}, []string{"a", "b", "c"}},
+		{"rrf keyword only", []vstore.Option{vstore.WithHybridAlpha(0)}, []string{"c", "d"}},
+		{"alpha above 1", []vstore.Option{vstore.WithHybridAlpha(2)}, []string{"a", "b", "c"}},
+		{"top k", []vstore.Option{vstore.WithTopK(2)}, []string{"c", "a"}},
+		// Normalized: a 1, b 0.5, c 0 on the vector side; c 1, d 0 on the keyword side
+		{"weighted", []vstore.Option{vstore.WithFusion(vstore.FusionWeighted)}, []string{"a", "c", "b", "d"}},
+		{"weighted min score", []vstore.Option{
+			vstore.WithFusion(vstore.FusionWeighted), vstore.WithMinScore(0.5),
+		}, []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fused := vstore.FuseResults(vector, keyword, vstore.ApplyOptions(tt.opts...))
+			var got []string
+			for _, m := range fused {
+				got = append(got, m.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fused = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuseResultsScores(t *testing.T) {
+	fused := vstore.FuseResults(matches("a", "b"), matches("b"), vstore.ApplyOptions(vstore.WithRRFK(1)))
+
+	// b: 0.5/(1+2) + 0.5/(1+1); a: 0.5/(1+1)
+	want := map[string]float64{"b": 0.5/3 + 0.5/2, "a": 0.5 / 2}
+	if len(fused) != len(want) {
+		t.Fatalf("fused %d matches, want %d", len(fused), len(want))
+	}
+	for _, m := range fused {
+		if math.Abs(float64(m.Score)-want[m.ID]) > 1e-6 {
+			t.Errorf("score of %s = %v, want %v", m.ID, m.Score, want[m.ID])
+		}
+	}
+}
+
+func TestFuseResultsKeepsPayload(t *testing.T) {
+	vector := []vstore.Match{{ID: "a", Score: 1, Values: []float32{1, 0}}}
+	keyword := []vstore.Match{{ID: "a", Score: 3, Metadata: map[string]any{"content": "alpha"}}}
+
+	fused := vstore.FuseResults(vector, keyword, vstore.ApplyOptions())
+	if len(fused) != 1 {
+		t.Fatalf("fused %d matches, want 1", len(fused))
+	}
+	if fused[0].Values == nil || fused[0].Metadata["content"] != "alpha" {
+		t.Errorf("fused match = %+v, want values and metadata of both sides", fused[0])
+	}
+}
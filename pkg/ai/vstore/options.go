@@ -14,7 +14,7 @@ type Options struct {
 	// IncludeMetadata in search results
 	IncludeMetadata bool
 
-	// MinScore threshold for results
+	// MinScore threshold for results (0 = no threshold)
 	MinScore float32
 
 	// Filter for metadata filtering
@@ -23,6 +23,16 @@ type Options struct {
 	// HybridAlpha for hybrid search (0 = keyword, 1 = vector)
 	HybridAlpha float32
 
+	// Fusion is how hybrid search combines vector and keyword results
+	Fusion FusionMethod
+
+	// RRFK is the rank constant for reciprocal rank fusion
+	RRFK int
+
+	// HybridCandidates is how many results each side of a hybrid search
+	// contributes before fusion (0 = 4 x TopK)
+	HybridCandidates int
+
 	// SparseValues for hybrid dense/sparse search
 	SparseValues *SparseVector
 
@@ -67,6 +77,13 @@ func WithMinScore(score float32) Option {
 	}
 }
 
+// MeetsMinScore reports whether a score passes the MinScore threshold.
+// A zero MinScore disables the threshold, so negative similarities are
+// kept by default.
+func (o *Options) MeetsMinScore(score float32) bool {
+	return o.MinScore == 0 || score >= o.MinScore
+}
+
 // Filter options
 func WithFilter(filter *Filter) Option {
 	return func(o *Options) {
@@ -81,6 +98,24 @@ func WithHybridAlpha(alpha float32) Option {
 	}
 }
 
+func WithFusion(method FusionMethod) Option {
+	return func(o *Options) {
+		o.Fusion = method
+	}
+}
+
+func WithRRFK(k int) Option {
+	return func(o *Options) {
+		o.RRFK = k
+	}
+}
+
+func WithHybridCandidates(n int) Option {
+	return func(o *Options) {
+		o.HybridCandidates = n
+	}
+}
+
 func WithSparseValues(sparse *SparseVector) Option {
 	return func(o *Options) {
 		o.SparseValues = sparse
@@ -113,6 +148,8 @@ func DefaultOptions() *Options {
 		IncludeMetadata: true,
 		MinScore:        0,
 		HybridAlpha:     0.5,
+		Fusion:          FusionRRF,
+		RRFK:            DefaultRRFK,
 		BatchSize:       100,
 		ProviderOptions: make(map[string]any),
 	}
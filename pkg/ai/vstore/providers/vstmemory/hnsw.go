@@ -0,0 +1,537 @@
+package vstmemory
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// ============================================================================
+// HNSW Configuration
+// ============================================================================
+
+const (
+	DefaultHNSWM              = 16
+	DefaultHNSWEfConstruction = 200
+	DefaultHNSWEfSearch       = 64
+	DefaultHNSWSeed           = 100
+
+	// EfSearchOption is the vstore provider option overriding efSearch for
+	// a single query, e.g. vstore.WithProviderOption(vstmemory.EfSearchOption, 200)
+	EfSearchOption = "ef_search"
+
+	// selectiveFilterRatio: filters matching fewer than 1 in this many
+	// vectors are answered with an exact scan instead of the graph
+	selectiveFilterRatio = 10
+
+	// filterSampleSize is the number of vectors checked against a filter to
+	// estimate its selectivity
+	filterSampleSize = 256
+)
+
+// HNSWConfig configures the approximate nearest-neighbour index
+type HNSWConfig struct {
+	// M is the number of links per node on upper layers (2*M on layer 0)
+	M int `json:"m"`
+
+	// EfConstruction is the candidate list size used while inserting
+	EfConstruction int `json:"ef_construction"`
+
+	// EfSearch is the candidate list size used while querying; higher
+	// values improve recall at the cost of speed
+	EfSearch int `json:"ef_search"`
+
+	// Seed for the random level generator, so graphs are reproducible
+	Seed int64 `json:"seed"`
+}
+
+// withDefaults fills unset fields
+func (c HNSWConfig) withDefaults() HNSWConfig {
+	if c.M <= 1 {
+		c.M = DefaultHNSWM
+	}
+	if c.EfConstruction <= 0 {
+		c.EfConstruction = DefaultHNSWEfConstruction
+	}
+	if c.EfSearch <= 0 {
+		c.EfSearch = DefaultHNSWEfSearch
+	}
+	if c.Seed == 0 {
+		c.Seed = DefaultHNSWSeed
+	}
+	return c
+}
+
+// ============================================================================
+// HNSW Graph
+// ============================================================================
+
+// hnswNode is a vector in the graph with its links on every layer it is part of
+type hnswNode struct {
+	id        string
+	level     int
+	neighbors [][]string
+}
+
+// hnswIndex is a hierarchical navigable small world graph over the vectors
+// of one namespace. It holds IDs only; vector values are looked up through
+// the values function so the store remains the single source of truth.
+//
+// Scores are similarities (higher is closer), as computed by the store's metric.
+type hnswIndex struct {
+	config     HNSWConfig
+	nodes      map[string]*hnswNode
+	entry      string
+	maxLevel   int
+	levelMult  float64
+	rng        *rand.Rand
+	values     func(id string) []float32
+	similarity func(a, b []float32) float32
+}
+
+func newHNSWIndex(config HNSWConfig, values func(string) []float32, similarity func(a, b []float32) float32) *hnswIndex {
+	config = config.withDefaults()
+	return &hnswIndex{
+		config:     config,
+		nodes:      make(map[string]*hnswNode),
+		levelMult:  1 / math.Log(float64(config.M)),
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		values:     values,
+		similarity: similarity,
+	}
+}
+
+// Len returns the number of indexed vectors
+func (h *hnswIndex) Len() int {
+	return len(h.nodes)
+}
+
+// insert adds a vector to the graph. The vector must already be readable
+// through the values function.
+func (h *hnswIndex) insert(id string) {
+	if _, exists := h.nodes[id]; exists {
+		h.remove(id)
+	}
+
+	level := h.randomLevel()
+	node := &hnswNode{id: id, level: level, neighbors: make([][]string, level+1)}
+
+	if h.entry == "" {
+		h.nodes[id] = node
+		h.entry = id
+		h.maxLevel = level
+		return
+	}
+
+	query := h.values(id)
+	entryPoints := []scoredNode{h.score(query, h.entry)}
+
+	// Greedy descent through the layers above the node's level
+	for layer := h.maxLevel; layer > level; layer-- {
+		entryPoints = h.searchLayer(query, entryPoints, 1, layer, nil)
+	}
+
+	h.nodes[id] = node
+	for layer := min(level, h.maxLevel); layer >= 0; layer-- {
+		candidates := h.searchLayer(query, entryPoints, h.config.EfConstruction, layer, nil)
+		selected := h.selectNeighbors(query, candidates, h.config.M)
+
+		node.neighbors[layer] = idsOf(selected)
+		for _, neighbor := range selected {
+			h.link(neighbor.id, id, layer)
+		}
+
+		entryPoints = candidates
+	}
+
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entry = id
+	}
+}
+
+// remove deletes a vector from the graph and reconnects its neighbours
+// among themselves so the graph stays navigable. Links pointing to the
+// removed node from elsewhere are skipped during search.
+func (h *hnswIndex) remove(id string) {
+	node, exists := h.nodes[id]
+	if !exists {
+		return
+	}
+	delete(h.nodes, id)
+
+	for layer := 0; layer <= node.level; layer++ {
+		for _, neighborID := range node.neighbors[layer] {
+			neighbor, ok := h.nodes[neighborID]
+			if !ok || layer > neighbor.level {
+				continue
+			}
+
+			// Candidates: the neighbour's remaining links plus the removed node's links
+			query := h.values(neighborID)
+			seen := map[string]bool{neighborID: true, id: true}
+			var candidates []scoredNode
+			for _, list := range [][]string{neighbor.neighbors[layer], node.neighbors[layer]} {
+				for _, candidateID := range list {
+					if seen[candidateID] {
+						continue
+					}
+					seen[candidateID] = true
+					if _, ok := h.nodes[candidateID]; ok {
+						candidates = append(candidates, h.score(query, candidateID))
+					}
+				}
+			}
+
+			sortScored(candidates)
+			neighbor.neighbors[layer] = idsOf(h.selectNeighbors(query, candidates, h.maxConnections(layer)))
+		}
+	}
+
+	if h.entry == id {
+		h.entry = ""
+		h.maxLevel = 0
+		for _, candidate := range h.nodes {
+			if h.entry == "" || candidate.level > h.maxLevel ||
+				(candidate.level == h.maxLevel && candidate.id < h.entry) {
+				h.entry = candidate.id
+				h.maxLevel = candidate.level
+			}
+		}
+	}
+}
+
+// search returns up to k nearest vectors to query, best first. When allow
+// is set, only vectors it accepts are returned; the rest are still used to
+// navigate the graph.
+func (h *hnswIndex) search(query []float32, k, ef int, allow func(id string) bool) []scoredNode {
+	if h.entry == "" || k <= 0 {
+		return nil
+	}
+
+	entryPoints := []scoredNode{h.score(query, h.entry)}
+	for layer := h.maxLevel; layer > 0; layer-- {
+		entryPoints = h.searchLayer(query, entryPoints, 1, layer, nil)
+	}
+
+	results := h.searchLayer(query, entryPoints, max(ef, k), 0, allow)
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// searchLayer is the beam search of the HNSW paper (algorithm 2). It
+// returns up to ef allowed nodes, best first.
+func (h *hnswIndex) searchLayer(query []float32, entryPoints []scoredNode, ef, layer int, allow func(string) bool) []scoredNode {
+	visited := make(map[string]bool, ef*4)
+	candidates := &maxScoredHeap{}
+	results := &minScoredHeap{}
+
+	for _, ep := range entryPoints {
+		if visited[ep.id] {
+			continue
+		}
+		visited[ep.id] = true
+		heap.Push(candidates, ep)
+		if allow == nil || allow(ep.id) {
+			heap.Push(results, ep)
+		}
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(scoredNode)
+		if results.Len() >= ef && current.score < (*results)[0].score {
+			break
+		}
+
+		node, ok := h.nodes[current.id]
+		if !ok || layer > node.level {
+			continue
+		}
+
+		for _, neighborID := range node.neighbors[layer] {
+			if visited[neighborID] {
+				continue
+			}
+			visited[neighborID] = true
+
+			// Links to a removed node, or to one re-inserted on fewer layers
+			if neighbor, ok := h.nodes[neighborID]; !ok || layer > neighbor.level {
+				continue
+			}
+
+			neighbor := h.score(query, neighborID)
+			if results.Len() < ef || neighbor.score > (*results)[0].score {
+				heap.Push(candidates, neighbor)
+				if allow == nil || allow(neighborID) {
+					heap.Push(results, neighbor)
+					if results.Len() > ef {
+						heap.Pop(results)
+					}
+				}
+			}
+		}
+	}
+
+	out := make([]scoredNode, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(scoredNode)
+	}
+	return out
+}
+
+// selectNeighbors is the neighbour selection heuristic of the HNSW paper
+// (algorithm 4): a candidate is kept only if it is closer to the query than
+// to every neighbour already kept, which preserves links across clusters.
+// Remaining slots are filled with the best discarded candidates.
+// candidates must be sorted best first.
+func (h *hnswIndex) selectNeighbors(query []float32, candidates []scoredNode, m int) []scoredNode {
+	if len(candidates) <= m {
+		return candidates
+	}
+
+	selected := make([]scoredNode, 0, m)
+	var discarded []scoredNode
+	for _, candidate := range candidates {
+		if len(selected) >= m {
+			break
+		}
+
+		values := h.values(candidate.id)
+		keep := true
+		for _, s := range selected {
+			if h.similarity(values, h.values(s.id)) > candidate.score {
+				keep = false
+				break
+			}
+		}
+
+		if keep {
+			selected = append(selected, candidate)
+		} else {
+			discarded = append(discarded, candidate)
+		}
+	}
+
+	for _, candidate := range discarded {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, candidate)
+	}
+	return selected
+}
+
+// link adds a directed edge and prunes the source node if it has too many links
+func (h *hnswIndex) link(from, to string, layer int) {
+	node, ok := h.nodes[from]
+	if !ok || layer > node.level {
+		return
+	}
+	node.neighbors[layer] = append(node.neighbors[layer], to)
+
+	maxConn := h.maxConnections(layer)
+	if len(node.neighbors[layer]) <= maxConn {
+		return
+	}
+
+	query := h.values(from)
+	candidates := make([]scoredNode, 0, len(node.neighbors[layer]))
+	for _, id := range node.neighbors[layer] {
+		if _, ok := h.nodes[id]; ok {
+			candidates = append(candidates, h.score(query, id))
+		}
+	}
+	sortScored(candidates)
+	node.neighbors[layer] = idsOf(h.selectNeighbors(query, candidates, maxConn))
+}
+
+func (h *hnswIndex) maxConnections(layer int) int {
+	if layer == 0 {
+		return h.config.M * 2
+	}
+	return h.config.M
+}
+
+func (h *hnswIndex) randomLevel() int {
+	return int(math.Floor(-math.Log(1-h.rng.Float64()) * h.levelMult))
+}
+
+func (h *hnswIndex) score(query []float32, id string) scoredNode {
+	return scoredNode{id: id, score: h.similarity(query, h.values(id))}
+}
+
+// ============================================================================
+// Priority Queues
+// ============================================================================
+
+type scoredNode struct {
+	id    string
+	score float32
+}
+
+// maxScoredHeap pops the best (highest score) node first
+type maxScoredHeap []scoredNode
+
+func (q maxScoredHeap) Len() int           { return len(q) }
+func (q maxScoredHeap) Less(i, j int) bool { return q[i].score > q[j].score }
+func (q maxScoredHeap) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *maxScoredHeap) Push(x any)        { *q = append(*q, x.(scoredNode)) }
+func (q *maxScoredHeap) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// minScoredHeap pops the worst (lowest score) node first
+type minScoredHeap []scoredNode
+
+func (q minScoredHeap) Len() int           { return len(q) }
+func (q minScoredHeap) Less(i, j int) bool { return q[i].score < q[j].score }
+func (q minScoredHeap) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *minScoredHeap) Push(x any)        { *q = append(*q, x.(scoredNode)) }
+func (q *minScoredHeap) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+func sortScored(nodes []scoredNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].score != nodes[j].score {
+			return nodes[i].score > nodes[j].score
+		}
+		return nodes[i].id < nodes[j].id
+	})
+}
+
+func idsOf(nodes []scoredNode) []string {
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.id
+	}
+	return ids
+}
+
+// ============================================================================
+// Store Integration
+// ============================================================================
+
+// index adds a stored vector to its namespace's graph
+func (m *MemoryVectorStore) index(stored *StoredVector) {
+	if m.indexes == nil {
+		return
+	}
+
+	idx, ok := m.indexes[stored.Namespace]
+	if !ok {
+		idx = m.newIndex()
+		m.indexes[stored.Namespace] = idx
+	}
+	idx.insert(stored.ID)
+}
+
+// unindex removes a stored vector from its namespace's graph
+func (m *MemoryVectorStore) unindex(stored *StoredVector) {
+	if m.indexes == nil {
+		return
+	}
+
+	if idx, ok := m.indexes[stored.Namespace]; ok {
+		idx.remove(stored.ID)
+		if idx.Len() == 0 {
+			delete(m.indexes, stored.Namespace)
+		}
+	}
+}
+
+func (m *MemoryVectorStore) newIndex() *hnswIndex {
+	return newHNSWIndex(*m.hnswConfig, func(id string) []float32 {
+		return m.vectors[id].Values
+	}, m.calculateSimilarity)
+}
+
+// searchIndex answers a query from the namespace graphs (all of them when
+// no namespace is given). It reports false when the caller should fall back
+// to an exact scan: the filter is very selective, or a filtered search found
+// fewer than TopK matches while more vectors exist.
+func (m *MemoryVectorStore) searchIndex(vector []float32, options *vstore.Options) ([]scoredVector, bool) {
+	topK := options.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+
+	ef := m.hnswConfig.EfSearch
+	if v, ok := toFloat64(options.ProviderOptions[EfSearchOption]); ok && v > 0 {
+		ef = int(v)
+	}
+
+	var allow func(string) bool
+	if options.Filter != nil {
+		allow = func(id string) bool {
+			return m.matchesFilter(m.vectors[id].Metadata, options.Filter)
+		}
+	}
+
+	var indexes []*hnswIndex
+	if options.Namespace != "" {
+		if idx, ok := m.indexes[options.Namespace]; ok {
+			indexes = append(indexes, idx)
+		}
+	} else {
+		for _, idx := range m.indexes {
+			indexes = append(indexes, idx)
+		}
+	}
+
+	total := 0
+	for _, idx := range indexes {
+		total += idx.Len()
+	}
+
+	// Graph traversal degrades when few vectors pass the filter; an exact
+	// scan over them is both faster and exact. The filter's selectivity is
+	// estimated on a sample, relying on Go's random map iteration order; a
+	// filter the sample overestimates is caught by the TopK check below.
+	if allow != nil && total > 0 {
+		sampled, allowed := 0, 0
+		for _, idx := range indexes {
+			// Each namespace contributes in proportion to its size
+			quota := (filterSampleSize*idx.Len() + total - 1) / total
+			for id := range idx.nodes {
+				if quota == 0 {
+					break
+				}
+				quota--
+				sampled++
+				if allow(id) {
+					allowed++
+				}
+			}
+		}
+		if allowed*selectiveFilterRatio < sampled {
+			return nil, false
+		}
+	}
+
+	var scores []scoredVector
+	for _, idx := range indexes {
+		for _, n := range idx.search(vector, topK, ef, allow) {
+			scores = append(scores, scoredVector{stored: m.vectors[n.id], score: n.score})
+		}
+	}
+
+	if allow != nil && len(scores) < topK && len(scores) < total {
+		return nil, false
+	}
+	return scores, true
+}
@@ -0,0 +1,162 @@
+package vstmemory_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstmemory"
+)
+
+const testDimension = 8
+
+// randomVectors returns n reproducible vectors with IDs v0..v(n-1)
+func randomVectors(n int, seed int64) []vstore.Vector {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([]vstore.Vector, n)
+	for i := range vectors {
+		values := make([]float32, testDimension)
+		for j := range values {
+			values[j] = rng.Float32()*2 - 1
+		}
+		vectors[i] = vstore.Vector{
+			ID:       fmt.Sprintf("v%d", i),
+			Values:   values,
+			Metadata: map[string]any{"even": i%2 == 0},
+		}
+	}
+	return vectors
+}
+
+func newHNSWStore(m int) *vstmemory.MemoryVectorStore {
+	return vstmemory.NewMemoryVectorStore(testDimension, vstore.MetricCosine, vstmemory.WithHNSW(vstmemory.HNSWConfig{M: m}))
+}
+
+// selfRecall queries every vector and returns the share found as the top match
+func selfRecall(t *testing.T, store vstore.VectorStorer, vectors []vstore.Vector, opts ...vstore.Option) float64 {
+	t.Helper()
+	found := 0
+	for _, v := range vectors {
+		result, err := store.Query(context.Background(), v.Values, append(opts, vstore.WithTopK(1))...)
+		if err != nil {
+			t.Fatalf("Query %s: %v", v.ID, err)
+		}
+		if len(result.Matches) == 1 && result.Matches[0].ID == v.ID {
+			found++
+		}
+	}
+	return float64(found) / float64(len(vectors))
+}
+
+func TestHNSWRemoveReconnectsNeighbours(t *testing.T) {
+	ctx := context.Background()
+	store := newHNSWStore(4)
+	vectors := randomVectors(400, 1)
+	if err := store.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	// Removing half the graph must leave the rest reachable
+	var removed []string
+	var kept []vstore.Vector
+	for i, v := range vectors {
+		if i%2 == 0 {
+			removed = append(removed, v.ID)
+		} else {
+			kept = append(kept, v)
+		}
+	}
+	if err := store.Delete(ctx, removed); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if got := store.Count(); got != len(kept) {
+		t.Fatalf("Count = %d, want %d", got, len(kept))
+	}
+	if recall := selfRecall(t, store, kept); recall < 0.95 {
+		t.Errorf("recall after removal = %.2f, want >= 0.95", recall)
+	}
+	for _, v := range vectors[:10] {
+		result, err := store.Query(ctx, v.Values, vstore.WithTopK(5))
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		for _, match := range result.Matches {
+			for _, id := range removed {
+				if match.ID == id {
+					t.Fatalf("removed vector %s returned", id)
+				}
+			}
+		}
+	}
+}
+
+func TestHNSWReupsert(t *testing.T) {
+	ctx := context.Background()
+	store := newHNSWStore(8)
+	vectors := randomVectors(200, 2)
+	if err := store.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	// Same IDs, new values, half of them moved to another namespace
+	moved := randomVectors(200, 3)
+	var stay, move []vstore.Vector
+	for i, v := range moved {
+		if i%2 == 0 {
+			stay = append(stay, v)
+		} else {
+			move = append(move, v)
+		}
+	}
+	if err := store.Upsert(ctx, stay); err != nil {
+		t.Fatalf("Upsert stay: %v", err)
+	}
+	if err := store.Upsert(ctx, move, vstore.WithNamespace("other")); err != nil {
+		t.Fatalf("Upsert move: %v", err)
+	}
+
+	if got := store.Count(); got != len(vectors) {
+		t.Fatalf("Count = %d, want %d", got, len(vectors))
+	}
+	if recall := selfRecall(t, store, stay); recall < 0.95 {
+		t.Errorf("recall of updated vectors = %.2f, want >= 0.95", recall)
+	}
+	if recall := selfRecall(t, store, move, vstore.WithNamespace("other")); recall < 0.95 {
+		t.Errorf("recall of moved vectors = %.2f, want >= 0.95", recall)
+	}
+
+	// A query across namespaces returns each moved vector once
+	result, err := store.Query(ctx, move[0].Values, vstore.WithTopK(len(vectors)))
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	seen := make(map[string]bool, len(result.Matches))
+	for _, match := range result.Matches {
+		if seen[match.ID] {
+			t.Fatalf("vector %s returned twice", match.ID)
+		}
+		seen[match.ID] = true
+	}
+}
+
+func TestHNSWSelectiveFilter(t *testing.T) {
+	ctx := context.Background()
+	store := newHNSWStore(8)
+	vectors := randomVectors(500, 4)
+	vectors[7].Metadata["rare"] = true
+	if err := store.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	filter := vstore.NewFilter().AddMust("rare", vstore.OpEqual, true)
+	result, err := store.Query(ctx, vectors[0].Values, vstore.WithTopK(3), vstore.WithFilter(filter))
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].ID != vectors[7].ID {
+		t.Fatalf("filtered matches = %v, want only %s", result.Matches, vectors[7].ID)
+	}
+}
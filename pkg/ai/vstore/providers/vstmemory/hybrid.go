@@ -0,0 +1,249 @@
+package vstmemory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// Compile-time interface checks
+var (
+	_ vstore.HybridSearcher      = (*MemoryVectorStore)(nil)
+	_ vstore.SparseVectorSupport = (*MemoryVectorStore)(nil)
+)
+
+// ============================================================================
+// HybridSearcher Implementation
+// ============================================================================
+
+// HybridQuery combines dense similarity with keyword relevance. The keyword
+// side is BM25 over the text field, or a sparse dot product when the
+// SparseValues option is set. Each side contributes HybridCandidates results,
+// which are merged with vstore.FuseResults; HybridAlpha weights the dense side.
+func (m *MemoryVectorStore) HybridQuery(ctx context.Context, vector []float32, query string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	candidateOpts := append(slices.Clone(opts),
+		vstore.WithTopK(options.HybridCandidateCount()),
+		vstore.WithMinScore(0),
+	)
+
+	var denseMatches []vstore.Match
+	if options.HybridAlpha > 0 {
+		result, err := m.Query(ctx, vector, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+		denseMatches = result.Matches
+	}
+
+	var keywordMatches []vstore.Match
+	if options.HybridAlpha < 1 {
+		var result *vstore.QueryResult
+		var err error
+		switch {
+		case options.SparseValues != nil:
+			result, err = m.QuerySparse(ctx, *options.SparseValues, candidateOpts...)
+		case strings.TrimSpace(query) != "":
+			result, err = m.KeywordQuery(ctx, query, candidateOpts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			keywordMatches = result.Matches
+		}
+	}
+
+	return &vstore.QueryResult{
+		Matches:   vstore.FuseResults(denseMatches, keywordMatches, options),
+		Namespace: options.Namespace,
+	}, nil
+}
+
+// KeywordQuery ranks vectors by BM25 relevance of the text field to query.
+// Corpus statistics are computed over the vectors matching the namespace
+// and filter. Vectors sharing no term with the query are not returned.
+func (m *MemoryVectorStore) KeywordQuery(ctx context.Context, query string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	queryTerms := uniqueTerms(tokenize(query))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	candidates := m.candidates(options)
+	if len(candidates) == 0 || len(queryTerms) == 0 {
+		return &vstore.QueryResult{Matches: []vstore.Match{}, Namespace: options.Namespace}, nil
+	}
+
+	// Corpus statistics
+	var totalLength int
+	docFreq := make(map[string]int, len(queryTerms))
+	for _, stored := range candidates {
+		totalLength += stored.length
+		for _, term := range queryTerms {
+			if stored.terms[term] > 0 {
+				docFreq[term]++
+			}
+		}
+	}
+	avgLength := float64(totalLength) / float64(len(candidates))
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	n := float64(len(candidates))
+	scores := make([]scoredVector, 0, len(candidates))
+	for _, stored := range candidates {
+		var score float64
+		for _, term := range queryTerms {
+			tf := float64(stored.terms[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := m.bm25K1 * (1 - m.bm25B + m.bm25B*float64(stored.length)/avgLength)
+			score += idf * tf * (m.bm25K1 + 1) / (tf + norm)
+		}
+
+		if score > 0 && options.MeetsMinScore(float32(score)) {
+			scores = append(scores, scoredVector{stored: stored, score: float32(score)})
+		}
+	}
+
+	return &vstore.QueryResult{
+		Matches:   m.topMatches(scores, options),
+		Namespace: options.Namespace,
+	}, nil
+}
+
+// ============================================================================
+// SparseVectorSupport Implementation
+// ============================================================================
+
+// UpsertSparse sets the sparse values of existing vectors
+func (m *MemoryVectorStore) UpsertSparse(ctx context.Context, ids []string, vectors []vstore.SparseVector, opts ...vstore.Option) error {
+	if len(ids) != len(vectors) {
+		return fmt.Errorf("ids and sparse vectors length mismatch: %d ids, %d vectors", len(ids), len(vectors))
+	}
+	for i, v := range vectors {
+		if len(v.Indices) != len(v.Values) {
+			return fmt.Errorf("sparse vector %s has %d indices and %d values", ids[i], len(v.Indices), len(v.Values))
+		}
+	}
+
+	options := vstore.ApplyOptions(opts...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		stored, exists := m.vectors[id]
+		if !exists || (options.Namespace != "" && stored.Namespace != options.Namespace) {
+			return fmt.Errorf("vector not found: %s", id)
+		}
+	}
+
+	for i, id := range ids {
+		m.vectors[id].SparseValues = copySparse(&vectors[i])
+	}
+
+	return nil
+}
+
+// QuerySparse ranks vectors by the dot product of their sparse values with
+// the query. Vectors without sparse values or without overlapping indices
+// are not returned.
+func (m *MemoryVectorStore) QuerySparse(ctx context.Context, vector vstore.SparseVector, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if len(vector.Indices) != len(vector.Values) {
+		return nil, fmt.Errorf("sparse query has %d indices and %d values", len(vector.Indices), len(vector.Values))
+	}
+
+	options := vstore.ApplyOptions(opts...)
+
+	query := make(map[uint32]float32, len(vector.Indices))
+	for i, idx := range vector.Indices {
+		query[idx] += vector.Values[i]
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	candidates := m.candidates(options)
+	scores := make([]scoredVector, 0, len(candidates))
+	for _, stored := range candidates {
+		if stored.SparseValues == nil {
+			continue
+		}
+
+		var score float32
+		overlap := false
+		for i, idx := range stored.SparseValues.Indices {
+			if q, ok := query[idx]; ok {
+				score += q * stored.SparseValues.Values[i]
+				overlap = true
+			}
+		}
+
+		if overlap && options.MeetsMinScore(score) {
+			scores = append(scores, scoredVector{stored: stored, score: score})
+		}
+	}
+
+	return &vstore.QueryResult{
+		Matches:   m.topMatches(scores, options),
+		Namespace: options.Namespace,
+	}, nil
+}
+
+// ============================================================================
+// Text Helpers
+// ============================================================================
+
+// indexText computes the BM25 term statistics of a stored vector
+func (m *MemoryVectorStore) indexText(stored *StoredVector) {
+	text, _ := stored.Metadata[m.textField].(string)
+	tokens := tokenize(text)
+
+	stored.terms = make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		stored.terms[token]++
+	}
+	stored.length = len(tokens)
+}
+
+// tokenize lowercases text and splits it into letter and digit runs
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// uniqueTerms removes duplicate terms, keeping the first occurrence
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// copySparse returns a deep copy of a sparse vector
+func copySparse(v *vstore.SparseVector) *vstore.SparseVector {
+	if v == nil {
+		return nil
+	}
+	return &vstore.SparseVector{
+		Indices: slices.Clone(v.Indices),
+		Values:  slices.Clone(v.Values),
+	}
+}
@@ -0,0 +1,35 @@
+package vstmemory
+
+const (
+	DefaultTextField = "content"
+	DefaultBM25K1    = 1.2
+	DefaultBM25B     = 0.75
+)
+
+// StoreOption configures the in-memory vector store
+type StoreOption func(*MemoryVectorStore)
+
+// WithTextField sets the metadata field indexed for keyword search
+// (default: "content")
+func WithTextField(field string) StoreOption {
+	return func(m *MemoryVectorStore) {
+		m.textField = field
+	}
+}
+
+// WithBM25Params sets the BM25 term saturation (k1) and length
+// normalization (b) parameters
+func WithBM25Params(k1, b float64) StoreOption {
+	return func(m *MemoryVectorStore) {
+		m.bm25K1 = k1
+		m.bm25B = b
+	}
+}
+
+// WithHNSW enables an HNSW approximate nearest-neighbour index for Query.
+// Zero fields in config take their defaults.
+func WithHNSW(config HNSWConfig) StoreOption {
+	return func(m *MemoryVectorStore) {
+		m.hnswConfig = &config
+	}
+}
@@ -0,0 +1,214 @@
+package vstmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/fsx"
+)
+
+// snapshotVersion is bumped when the snapshot format changes incompatibly
+const snapshotVersion = 1
+
+// snapshot is the persisted form of a MemoryVectorStore
+type snapshot struct {
+	Version   int                      `json:"version"`
+	Dimension int                      `json:"dimension"`
+	Metric    vstore.Metric            `json:"metric"`
+	HNSW      *HNSWConfig              `json:"hnsw,omitempty"`
+	Vectors   []snapshotVector         `json:"vectors"`
+	Graphs    map[string]snapshotGraph `json:"graphs,omitempty"` // namespace -> graph
+}
+
+type snapshotVector struct {
+	ID           string               `json:"id"`
+	Namespace    string               `json:"namespace"`
+	Values       []float32            `json:"values"`
+	SparseValues *vstore.SparseVector `json:"sparse_values,omitempty"`
+	Metadata     map[string]any       `json:"metadata,omitempty"`
+}
+
+type snapshotGraph struct {
+	Entry    string         `json:"entry"`
+	MaxLevel int            `json:"max_level"`
+	Nodes    []snapshotNode `json:"nodes"`
+}
+
+type snapshotNode struct {
+	ID        string     `json:"id"`
+	Level     int        `json:"level"`
+	Neighbors [][]string `json:"neighbors"`
+}
+
+// SaveSnapshot writes every vector, and the HNSW graphs when enabled, to
+// path so the store can be restored with LoadSnapshot.
+//
+// Metadata is encoded as JSON, so numeric values come back as float64.
+func (m *MemoryVectorStore) SaveSnapshot(ctx context.Context, fs fsx.FileWriter, path string) error {
+	m.mu.RLock()
+	snap := snapshot{
+		Version:   snapshotVersion,
+		Dimension: m.dimension,
+		Metric:    m.metric,
+		HNSW:      m.hnswConfig,
+		Vectors:   make([]snapshotVector, 0, len(m.vectors)),
+	}
+
+	namespaces := make([]string, 0, len(m.namespaces))
+	for ns := range m.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	// Keep per-namespace insertion order
+	for _, ns := range namespaces {
+		for _, id := range m.namespaces[ns] {
+			stored := m.vectors[id]
+			if stored == nil {
+				continue
+			}
+			snap.Vectors = append(snap.Vectors, snapshotVector{
+				ID:           stored.ID,
+				Namespace:    stored.Namespace,
+				Values:       stored.Values,
+				SparseValues: stored.SparseValues,
+				Metadata:     stored.Metadata,
+			})
+		}
+	}
+
+	if m.indexes != nil {
+		snap.Graphs = make(map[string]snapshotGraph, len(m.indexes))
+		for ns, idx := range m.indexes {
+			graph := snapshotGraph{
+				Entry:    idx.entry,
+				MaxLevel: idx.maxLevel,
+				Nodes:    make([]snapshotNode, 0, len(idx.nodes)),
+			}
+			for _, node := range idx.nodes {
+				graph.Nodes = append(graph.Nodes, snapshotNode{
+					ID:        node.id,
+					Level:     node.level,
+					Neighbors: node.neighbors,
+				})
+			}
+			sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+			snap.Graphs[ns] = graph
+		}
+	}
+
+	data, err := json.Marshal(snap)
+	m.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := fs.WriteFile(ctx, path, data); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces the store's contents with a snapshot written by
+// SaveSnapshot. The snapshot's dimension and metric must match the store.
+// Saved HNSW graphs are restored when the store's index uses the same M;
+// otherwise the index is rebuilt from the vectors.
+func (m *MemoryVectorStore) LoadSnapshot(ctx context.Context, fs fsx.FileReader, path string) error {
+	data, err := fs.ReadFile(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", snap.Version)
+	}
+	if snap.Dimension != m.dimension {
+		return fmt.Errorf("snapshot dimension mismatch: expected %d, got %d", m.dimension, snap.Dimension)
+	}
+	if snap.Metric != m.metric {
+		return fmt.Errorf("snapshot metric mismatch: expected %s, got %s", m.metric, snap.Metric)
+	}
+
+	// Decode into locals so a bad snapshot leaves the store untouched
+	vectors := make(map[string]*StoredVector, len(snap.Vectors))
+	namespaces := make(map[string][]string)
+	for _, v := range snap.Vectors {
+		if len(v.Values) != m.dimension {
+			return fmt.Errorf("snapshot vector %s has dimension %d", v.ID, len(v.Values))
+		}
+		if _, exists := vectors[v.ID]; exists {
+			return fmt.Errorf("snapshot vector %s appears more than once", v.ID)
+		}
+		stored := &StoredVector{
+			ID:           v.ID,
+			Values:       v.Values,
+			SparseValues: v.SparseValues,
+			Metadata:     v.Metadata,
+			Namespace:    v.Namespace,
+		}
+		if stored.Metadata == nil {
+			stored.Metadata = make(map[string]any)
+		}
+		m.indexText(stored)
+		vectors[v.ID] = stored
+		namespaces[v.Namespace] = append(namespaces[v.Namespace], v.ID)
+	}
+
+	var indexes map[string]*hnswIndex
+	restored := false
+	if m.indexes != nil && snap.HNSW != nil && snap.HNSW.M == m.hnswConfig.M {
+		indexes, restored = m.restoreGraphs(snap.Graphs, vectors)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.vectors = vectors
+	m.namespaces = namespaces
+	if m.indexes == nil {
+		return nil
+	}
+	if restored {
+		m.indexes = indexes
+		return nil
+	}
+
+	// Rebuild from scratch
+	m.indexes = make(map[string]*hnswIndex)
+	for _, v := range snap.Vectors {
+		m.index(m.vectors[v.ID])
+	}
+	return nil
+}
+
+// restoreGraphs builds indexes from saved graphs, reporting false if they
+// don't cover exactly the given vectors
+func (m *MemoryVectorStore) restoreGraphs(graphs map[string]snapshotGraph, vectors map[string]*StoredVector) (map[string]*hnswIndex, bool) {
+	indexes := make(map[string]*hnswIndex, len(graphs))
+	indexed := 0
+	for ns, graph := range graphs {
+		idx := m.newIndex()
+		idx.entry = graph.Entry
+		idx.maxLevel = graph.MaxLevel
+		for _, node := range graph.Nodes {
+			stored, ok := vectors[node.ID]
+			if !ok || stored.Namespace != ns || node.Level < 0 || len(node.Neighbors) != node.Level+1 {
+				return nil, false
+			}
+			idx.nodes[node.ID] = &hnswNode{id: node.ID, level: node.Level, neighbors: node.Neighbors}
+		}
+		if _, ok := idx.nodes[idx.entry]; !ok && len(idx.nodes) > 0 {
+			return nil, false
+		}
+		indexes[ns] = idx
+		indexed += len(idx.nodes)
+	}
+	return indexes, indexed == len(vectors)
+}
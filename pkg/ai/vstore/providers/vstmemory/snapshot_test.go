@@ -0,0 +1,93 @@
+package vstmemory_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstmemory"
+	"github.com/Abraxas-365/manifesto/pkg/fsx/fsxlocal"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	fs, err := fsxlocal.NewLocalFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalFileSystem: %v", err)
+	}
+
+	store := newHNSWStore(8)
+	vectors := randomVectors(150, 5)
+	if err := store.Upsert(ctx, vectors[:100]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := store.Upsert(ctx, vectors[100:], vstore.WithNamespace("other")); err != nil {
+		t.Fatalf("Upsert other: %v", err)
+	}
+	if err := store.SaveSnapshot(ctx, fs, "store.json"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store *vstmemory.MemoryVectorStore
+	}{
+		{"same graph", newHNSWStore(8)},
+		{"rebuilt graph", newHNSWStore(4)},
+		{"exact", vstmemory.NewMemoryVectorStore(testDimension, vstore.MetricCosine)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.store.LoadSnapshot(ctx, fs, "store.json"); err != nil {
+				t.Fatalf("LoadSnapshot: %v", err)
+			}
+			if got := tt.store.Count(); got != len(vectors) {
+				t.Fatalf("Count = %d, want %d", got, len(vectors))
+			}
+			if recall := selfRecall(t, tt.store, vectors[:100]); recall < 0.95 {
+				t.Errorf("recall = %.2f, want >= 0.95", recall)
+			}
+			if recall := selfRecall(t, tt.store, vectors[100:], vstore.WithNamespace("other")); recall < 0.95 {
+				t.Errorf("recall in namespace = %.2f, want >= 0.95", recall)
+			}
+
+			fetched, err := tt.store.Fetch(ctx, []string{"v3"})
+			if err != nil || len(fetched) != 1 {
+				t.Fatalf("Fetch = %v, %v", fetched, err)
+			}
+			if fetched[0].Metadata["even"] != false {
+				t.Errorf("metadata = %v, want even=false", fetched[0].Metadata)
+			}
+		})
+	}
+}
+
+func TestLoadSnapshotKeepsStoreOnError(t *testing.T) {
+	ctx := context.Background()
+	fs, err := fsxlocal.NewLocalFileSystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalFileSystem: %v", err)
+	}
+
+	bad := fmt.Sprintf(`{"version":1,"dimension":%d,"metric":"cosine","vectors":[{"id":"x","values":[1]}]}`, testDimension)
+	if err := fs.WriteFile(ctx, "bad.json", []byte(bad)); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	store := newHNSWStore(8)
+	vectors := randomVectors(50, 6)
+	if err := store.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := store.LoadSnapshot(ctx, fs, "bad.json"); err == nil {
+		t.Fatal("LoadSnapshot accepted a vector of the wrong dimension")
+	}
+
+	if got := store.Count(); got != len(vectors) {
+		t.Fatalf("Count = %d, want %d", got, len(vectors))
+	}
+	if recall := selfRecall(t, store, vectors); recall < 0.95 {
+		t.Errorf("recall after failed load = %.2f, want >= 0.95", recall)
+	}
+}
@@ -3,7 +3,10 @@ package vstmemory
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -18,28 +21,57 @@ type MemoryVectorStore struct {
 	namespaces map[string][]string      // Namespace -> []IDs
 	dimension  int
 	metric     vstore.Metric
+
+	// Keyword search
+	textField string
+	bm25K1    float64
+	bm25B     float64
+
+	// Approximate nearest-neighbour search, one graph per namespace
+	hnswConfig *HNSWConfig
+	indexes    map[string]*hnswIndex
 }
 
 // StoredVector represents a vector with metadata in memory
 type StoredVector struct {
-	ID        string
-	Values    []float32
-	Metadata  map[string]any
-	Namespace string
+	ID           string
+	Values       []float32
+	SparseValues *vstore.SparseVector
+	Metadata     map[string]any
+	Namespace    string
+
+	// BM25 statistics for the text field
+	terms  map[string]int
+	length int
 }
 
 // NewMemoryVectorStore creates a new in-memory vector store
-func NewMemoryVectorStore(dimension int, metric vstore.Metric) *MemoryVectorStore {
+func NewMemoryVectorStore(dimension int, metric vstore.Metric, opts ...StoreOption) *MemoryVectorStore {
 	if metric == "" {
 		metric = vstore.MetricCosine
 	}
 
-	return &MemoryVectorStore{
+	store := &MemoryVectorStore{
 		vectors:    make(map[string]*StoredVector),
 		namespaces: make(map[string][]string),
 		dimension:  dimension,
 		metric:     metric,
+		textField:  DefaultTextField,
+		bm25K1:     DefaultBM25K1,
+		bm25B:      DefaultBM25B,
+	}
+
+	for _, opt := range opts {
+		opt(store)
 	}
+
+	if store.hnswConfig != nil {
+		config := store.hnswConfig.withDefaults()
+		store.hnswConfig = &config
+		store.indexes = make(map[string]*hnswIndex)
+	}
+
+	return store
 }
 
 // ============================================================================
@@ -52,17 +84,19 @@ func (m *MemoryVectorStore) Upsert(ctx context.Context, vectors []vstore.Vector,
 		return nil
 	}
 
+	// Validate everything up front so a bad vector doesn't leave a partial write
+	for _, v := range vectors {
+		if err := m.validateVector(v); err != nil {
+			return err
+		}
+	}
+
 	options := vstore.ApplyOptions(opts...)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for _, v := range vectors {
-		// Validate dimension
-		if len(v.Values) != m.dimension {
-			return fmt.Errorf("vector dimension mismatch: expected %d, got %d", m.dimension, len(v.Values))
-		}
-
 		namespace := options.Namespace
 
 		// Check if vector already exists and remove from old namespace
@@ -70,6 +104,7 @@ func (m *MemoryVectorStore) Upsert(ctx context.Context, vectors []vstore.Vector,
 			if existing.Namespace != namespace {
 				m.removeFromNamespace(existing.Namespace, v.ID)
 			}
+			m.unindex(existing)
 		}
 
 		// Store vector
@@ -84,11 +119,14 @@ func (m *MemoryVectorStore) Upsert(ctx context.Context, vectors []vstore.Vector,
 		for k, val := range v.Metadata {
 			stored.Metadata[k] = val
 		}
+		stored.SparseValues = copySparse(v.SparseValues)
+		m.indexText(stored)
 
 		m.vectors[v.ID] = stored
 
 		// Add to namespace
 		m.addToNamespace(namespace, v.ID)
+		m.index(stored)
 	}
 
 	return nil
@@ -105,87 +143,37 @@ func (m *MemoryVectorStore) Query(ctx context.Context, vector []float32, opts ..
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Get vectors to search (filter by namespace if specified)
-	var candidateIDs []string
-	if options.Namespace != "" {
-		candidateIDs = m.namespaces[options.Namespace]
-	} else {
-		candidateIDs = make([]string, 0, len(m.vectors))
-		for id := range m.vectors {
-			candidateIDs = append(candidateIDs, id)
+	// Use the ANN index when enabled
+	if m.indexes != nil {
+		if found, ok := m.searchIndex(vector, options); ok {
+			scores := make([]scoredVector, 0, len(found))
+			for _, s := range found {
+				if options.MeetsMinScore(s.score) {
+					scores = append(scores, s)
+				}
+			}
+			return &vstore.QueryResult{
+				Matches:   m.topMatches(scores, options),
+				Namespace: options.Namespace,
+			}, nil
 		}
 	}
 
 	// Calculate similarities
-	type scoredVector struct {
-		id    string
-		score float32
-	}
-
-	scores := make([]scoredVector, 0, len(candidateIDs))
+	candidates := m.candidates(options)
+	scores := make([]scoredVector, 0, len(candidates))
 
-	for _, id := range candidateIDs {
-		stored := m.vectors[id]
-		if stored == nil {
-			continue
-		}
-
-		// Apply metadata filter if provided
-		if options.Filter != nil && !m.matchesFilter(stored.Metadata, options.Filter) {
-			continue
-		}
-
-		// Calculate similarity
+	for _, stored := range candidates {
 		score := m.calculateSimilarity(vector, stored.Values)
 
 		// Apply min score filter
-		if score >= options.MinScore {
-			scores = append(scores, scoredVector{id: id, score: score})
-		}
-	}
-
-	// Sort by score (descending)
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].score > scores[j].score
-	})
-
-	// Take top K
-	topK := options.TopK
-	if topK <= 0 {
-		topK = 10
-	}
-	if topK > len(scores) {
-		topK = len(scores)
-	}
-
-	// Build result
-	matches := make([]vstore.Match, topK)
-	for i := 0; i < topK; i++ {
-		stored := m.vectors[scores[i].id]
-		match := vstore.Match{
-			ID:       stored.ID,
-			Score:    scores[i].score,
-			Metadata: make(map[string]any),
-		}
-
-		// Include values if requested
-		if options.IncludeValues {
-			match.Values = make([]float32, len(stored.Values))
-			copy(match.Values, stored.Values)
+		if options.MeetsMinScore(score) {
+			scores = append(scores, scoredVector{stored: stored, score: score})
 		}
-
-		// Include metadata if requested
-		if options.IncludeMetadata {
-			for k, v := range stored.Metadata {
-				match.Metadata[k] = v
-			}
-		}
-
-		matches[i] = match
 	}
 
 	return &vstore.QueryResult{
-		Matches:   matches,
+		Matches:   m.topMatches(scores, options),
 		Namespace: options.Namespace,
 	}, nil
 }
@@ -214,6 +202,7 @@ func (m *MemoryVectorStore) Delete(ctx context.Context, ids []string, opts ...vs
 
 		// Remove from namespace
 		m.removeFromNamespace(stored.Namespace, id)
+		m.unindex(stored)
 
 		// Delete vector
 		delete(m.vectors, id)
@@ -247,9 +236,10 @@ func (m *MemoryVectorStore) Fetch(ctx context.Context, ids []string, opts ...vst
 		}
 
 		v := vstore.Vector{
-			ID:       stored.ID,
-			Values:   make([]float32, len(stored.Values)),
-			Metadata: make(map[string]any),
+			ID:           stored.ID,
+			Values:       make([]float32, len(stored.Values)),
+			Metadata:     make(map[string]any),
+			SparseValues: copySparse(stored.SparseValues),
 		}
 
 		copy(v.Values, stored.Values)
@@ -269,7 +259,7 @@ func (m *MemoryVectorStore) Fetch(ctx context.Context, ids []string, opts ...vst
 
 // QueryWithFilter performs filtered similarity search
 func (m *MemoryVectorStore) QueryWithFilter(ctx context.Context, vector []float32, filter vstore.Filter, opts ...vstore.Option) (*vstore.QueryResult, error) {
-	opts = append(opts, vstore.WithFilter(&filter))
+	opts = append(slices.Clone(opts), vstore.WithFilter(&filter))
 	return m.Query(ctx, vector, opts...)
 }
 
@@ -277,17 +267,29 @@ func (m *MemoryVectorStore) QueryWithFilter(ctx context.Context, vector []float3
 // BatchProcessor Implementation
 // ============================================================================
 
-// UpsertBatch upserts vectors in batches
+// UpsertBatch upserts the valid vectors and reports each invalid one in
+// the result instead of failing the whole batch
 func (m *MemoryVectorStore) UpsertBatch(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) (*vstore.BatchResult, error) {
-	if err := m.Upsert(ctx, vectors, opts...); err != nil {
-		return &vstore.BatchResult{
-			FailedCount: len(vectors),
-		}, err
+	result := &vstore.BatchResult{}
+	valid := make([]vstore.Vector, 0, len(vectors))
+	for _, v := range vectors {
+		if err := m.validateVector(v); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, vstore.BatchError{
+				ID:    v.ID,
+				Error: err.Error(),
+			})
+			continue
+		}
+		valid = append(valid, v)
 	}
 
-	return &vstore.BatchResult{
-		SuccessCount: len(vectors),
-	}, nil
+	if err := m.Upsert(ctx, valid, opts...); err != nil {
+		return nil, err
+	}
+	result.SuccessCount = len(valid)
+
+	return result, nil
 }
 
 // DeleteBatch deletes multiple vectors
@@ -318,6 +320,7 @@ func (m *MemoryVectorStore) ListNamespaces(ctx context.Context) ([]string, error
 			namespaces = append(namespaces, ns)
 		}
 	}
+	sort.Strings(namespaces)
 
 	return namespaces, nil
 }
@@ -339,6 +342,9 @@ func (m *MemoryVectorStore) DeleteNamespace(ctx context.Context, namespace strin
 	}
 
 	delete(m.namespaces, namespace)
+	if m.indexes != nil {
+		delete(m.indexes, namespace)
+	}
 	return nil
 }
 
@@ -377,6 +383,46 @@ func (m *MemoryVectorStore) GetStatistics(ctx context.Context, opts ...vstore.Op
 	return stats, nil
 }
 
+// ============================================================================
+// VectorLister Implementation
+// ============================================================================
+
+var _ vstore.VectorLister = (*MemoryVectorStore)(nil)
+
+// List returns vectors in ID order; the cursor is the last ID of the
+// previous page
+func (m *MemoryVectorStore) List(ctx context.Context, cursor string, limit int, opts ...vstore.Option) (*vstore.ListResult, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	options := vstore.ApplyOptions(opts...)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	candidates := m.candidates(options)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	start := sort.Search(len(candidates), func(i int) bool { return candidates[i].ID > cursor })
+	page := candidates[start:min(start+limit, len(candidates))]
+
+	result := &vstore.ListResult{Vectors: make([]vstore.Vector, len(page))}
+	for i, stored := range page {
+		v := vstore.Vector{
+			ID:       stored.ID,
+			Metadata: maps.Clone(stored.Metadata),
+		}
+		if options.IncludeValues {
+			v.Values = slices.Clone(stored.Values)
+			v.SparseValues = copySparse(stored.SparseValues)
+		}
+		result.Vectors[i] = v
+	}
+	if start+limit < len(candidates) {
+		result.NextCursor = page[len(page)-1].ID
+	}
+	return result, nil
+}
+
 // ============================================================================
 // Helper Methods
 // ============================================================================
@@ -409,6 +455,100 @@ func (m *MemoryVectorStore) removeFromNamespace(namespace, id string) {
 	m.namespaces[namespace] = newIDs
 }
 
+// scoredVector pairs a stored vector with its score for a query
+type scoredVector struct {
+	stored *StoredVector
+	score  float32
+}
+
+// candidates returns the stored vectors in the requested namespace that
+// match the filter
+func (m *MemoryVectorStore) candidates(options *vstore.Options) []*StoredVector {
+	var ids []string
+	if options.Namespace != "" {
+		ids = m.namespaces[options.Namespace]
+	} else {
+		ids = make([]string, 0, len(m.vectors))
+		for id := range m.vectors {
+			ids = append(ids, id)
+		}
+	}
+
+	candidates := make([]*StoredVector, 0, len(ids))
+	for _, id := range ids {
+		stored := m.vectors[id]
+		if stored == nil {
+			continue
+		}
+
+		// Apply metadata filter if provided
+		if options.Filter != nil && !m.matchesFilter(stored.Metadata, options.Filter) {
+			continue
+		}
+
+		candidates = append(candidates, stored)
+	}
+
+	return candidates
+}
+
+// topMatches sorts scored vectors (best first) and builds the top K matches
+func (m *MemoryVectorStore) topMatches(scores []scoredVector, options *vstore.Options) []vstore.Match {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].stored.ID < scores[j].stored.ID
+	})
+
+	topK := options.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+	if topK > len(scores) {
+		topK = len(scores)
+	}
+
+	matches := make([]vstore.Match, topK)
+	for i := 0; i < topK; i++ {
+		stored := scores[i].stored
+		match := vstore.Match{
+			ID:       stored.ID,
+			Score:    scores[i].score,
+			Metadata: make(map[string]any),
+		}
+
+		// Include values if requested
+		if options.IncludeValues {
+			match.Values = make([]float32, len(stored.Values))
+			copy(match.Values, stored.Values)
+			match.SparseValues = copySparse(stored.SparseValues)
+		}
+
+		// Include metadata if requested
+		if options.IncludeMetadata {
+			for k, v := range stored.Metadata {
+				match.Metadata[k] = v
+			}
+		}
+
+		matches[i] = match
+	}
+
+	return matches
+}
+
+// validateVector checks a vector's ID and dimension
+func (m *MemoryVectorStore) validateVector(v vstore.Vector) error {
+	if v.ID == "" {
+		return fmt.Errorf("vector ID cannot be empty")
+	}
+	if len(v.Values) != m.dimension {
+		return fmt.Errorf("vector dimension mismatch: expected %d, got %d", m.dimension, len(v.Values))
+	}
+	return nil
+}
+
 // calculateSimilarity calculates similarity between two vectors
 func (m *MemoryVectorStore) calculateSimilarity(v1, v2 []float32) float32 {
 	switch m.metric {
@@ -423,17 +563,24 @@ func (m *MemoryVectorStore) calculateSimilarity(v1, v2 []float32) float32 {
 	}
 }
 
-// matchesFilter checks if metadata matches filter
+// matchesFilter checks if metadata matches filter, recursing into nested
+// And/Or/Not sub-filters
 func (m *MemoryVectorStore) matchesFilter(metadata map[string]any, filter *vstore.Filter) bool {
-	// Check Must conditions (AND)
+	// Check Must conditions and And sub-filters (AND)
 	for _, cond := range filter.Must {
 		if !m.matchesCondition(metadata, cond) {
 			return false
 		}
 	}
+	for i := range filter.And {
+		if !m.matchesFilter(metadata, &filter.And[i]) {
+			return false
+		}
+	}
 
-	// Check Should conditions (OR) - at least one must match if any exist
-	if len(filter.Should) > 0 {
+	// Check Should conditions and Or sub-filters (OR) - at least one must
+	// match if any exist
+	if len(filter.Should) > 0 || len(filter.Or) > 0 {
 		matched := false
 		for _, cond := range filter.Should {
 			if m.matchesCondition(metadata, cond) {
@@ -441,24 +588,32 @@ func (m *MemoryVectorStore) matchesFilter(metadata map[string]any, filter *vstor
 				break
 			}
 		}
+		for i := 0; !matched && i < len(filter.Or); i++ {
+			matched = m.matchesFilter(metadata, &filter.Or[i])
+		}
 		if !matched {
 			return false
 		}
 	}
 
-	// Check MustNot conditions (NOT)
+	// Check MustNot conditions and Not sub-filters (NOT)
 	for _, cond := range filter.MustNot {
 		if m.matchesCondition(metadata, cond) {
 			return false
 		}
 	}
+	for i := range filter.Not {
+		if m.matchesFilter(metadata, &filter.Not[i]) {
+			return false
+		}
+	}
 
 	return true
 }
 
 // matchesCondition checks if metadata matches a single condition
 func (m *MemoryVectorStore) matchesCondition(metadata map[string]any, cond vstore.Condition) bool {
-	value, exists := metadata[cond.Field]
+	value, exists := vstore.LookupField(metadata, cond.Field)
 
 	switch cond.Operator {
 	case vstore.OpExists:
@@ -468,13 +623,25 @@ func (m *MemoryVectorStore) matchesCondition(metadata map[string]any, cond vstor
 		if !exists {
 			return false
 		}
-		return fmt.Sprintf("%v", value) == fmt.Sprintf("%v", cond.Value)
+		return compareValues(value, cond.Value) == 0
 
 	case vstore.OpNotEqual:
 		if !exists {
 			return true
 		}
-		return fmt.Sprintf("%v", value) != fmt.Sprintf("%v", cond.Value)
+		return compareValues(value, cond.Value) != 0
+
+	case vstore.OpIn:
+		if !exists {
+			return false
+		}
+		return containsValue(cond.Value, value)
+
+	case vstore.OpNotIn:
+		if !exists {
+			return true
+		}
+		return !containsValue(cond.Value, value)
 
 	case vstore.OpGreaterThan:
 		if !exists {
@@ -508,11 +675,69 @@ func (m *MemoryVectorStore) matchesCondition(metadata map[string]any, cond vstor
 		substr := fmt.Sprintf("%v", cond.Value)
 		return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
 
+	case vstore.OpArrayContains:
+		return exists && isList(value) && containsValue(value, cond.Value)
+
+	case vstore.OpArrayContainsAny:
+		if !exists || !isList(value) {
+			return false
+		}
+		for _, want := range listValues(cond.Value) {
+			if containsValue(value, want) {
+				return true
+			}
+		}
+		return false
+
+	case vstore.OpArrayContainsAll:
+		if !exists || !isList(value) {
+			return false
+		}
+		for _, want := range listValues(cond.Value) {
+			if !containsValue(value, want) {
+				return false
+			}
+		}
+		return true
+
 	default:
 		return false
 	}
 }
 
+// isList reports whether v is a slice or array
+func isList(v any) bool {
+	kind := reflect.ValueOf(v).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// listValues returns the elements of a slice, or v itself when it isn't one
+func listValues(v any) []any {
+	if !isList(v) {
+		return []any{v}
+	}
+	rv := reflect.ValueOf(v)
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// containsValue reports whether list (a slice) holds a value equal to v
+func containsValue(list, v any) bool {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return compareValues(list, v) == 0
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if compareValues(rv.Index(i).Interface(), v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // compareValues compares two values
 func compareValues(a, b any) int {
 	// Try to convert to float64 for numeric comparison
@@ -615,6 +840,9 @@ func (m *MemoryVectorStore) Clear() {
 
 	m.vectors = make(map[string]*StoredVector)
 	m.namespaces = make(map[string][]string)
+	if m.indexes != nil {
+		m.indexes = make(map[string]*hnswIndex)
+	}
 }
 
 // Count returns the total number of vectors
@@ -0,0 +1,21 @@
+package vstmemory_test
+
+import (
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstmemory"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/vstoretest"
+)
+
+func TestConformance(t *testing.T) {
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		return vstmemory.NewMemoryVectorStore(dimension, metric)
+	})
+}
+
+func TestConformanceHNSW(t *testing.T) {
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		return vstmemory.NewMemoryVectorStore(dimension, metric, vstmemory.WithHNSW(vstmemory.HNSWConfig{}))
+	})
+}
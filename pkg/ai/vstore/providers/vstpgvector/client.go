@@ -16,6 +16,10 @@ type DBClient struct {
 	tableName          string
 	dimension          int
 	useNamespaceColumn bool
+
+	// Full-text search
+	textSearchLanguage string
+	textField          string
 }
 
 // NewDBClient creates a new database client
@@ -33,6 +37,19 @@ func NewDBClient(db *sqlx.DB, schema, tableName string, dimension int, useNamesp
 	}
 }
 
+// EnableTextSearch makes CreateTable maintain a tsvector column built from
+// a metadata field with the given text search configuration
+func (c *DBClient) EnableTextSearch(language, field string) *DBClient {
+	c.textSearchLanguage = language
+	c.textField = field
+	return c
+}
+
+// TextSearchEnabled reports whether the tsvector column is maintained
+func (c *DBClient) TextSearchEnabled() bool {
+	return c.textSearchLanguage != ""
+}
+
 // ConnectSqlx establishes a sqlx database connection
 func ConnectSqlx(ctx context.Context, connStr string, maxConns int, timeout time.Duration) (*sqlx.DB, error) {
 	dbx, err := sqlx.Open("postgres", connStr)
@@ -123,9 +140,37 @@ func (c *DBClient) CreateTable(ctx context.Context) error {
 		return fmt.Errorf("failed to create metadata index: %w", err)
 	}
 
+	if c.TextSearchEnabled() {
+		return c.CreateTextSearchColumn(ctx)
+	}
+
 	return nil
 }
 
+// CreateTextSearchColumn adds a generated tsvector column over the text
+// field and a GIN index on it. Existing rows are indexed by PostgreSQL when
+// the column is added, and new rows are kept up to date automatically.
+func (c *DBClient) CreateTextSearchColumn(ctx context.Context) error {
+	for _, query := range c.TextSearchMigration() {
+		if _, err := c.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to create text search column: %w", err)
+		}
+	}
+	return nil
+}
+
+// TextSearchMigration returns the statements CreateTextSearchColumn runs,
+// for applying them with a migration tool. Adding the column rewrites the
+// table and locks it while existing rows are indexed.
+func (c *DBClient) TextSearchMigration() []string {
+	return []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (to_tsvector('%s'::regconfig, coalesce(metadata->>'%s', ''))) STORED`,
+			c.FullTableName(), TextSearchColumn, c.textSearchLanguage, c.textField),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s USING GIN (%s)`,
+			c.tableName, TextSearchColumn, c.FullTableName(), TextSearchColumn),
+	}
+}
+
 // CreateVectorIndex creates a vector similarity index
 func (c *DBClient) CreateVectorIndex(ctx context.Context, config IndexConfig) error {
 	var query string
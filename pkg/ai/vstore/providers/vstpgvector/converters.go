@@ -59,8 +59,20 @@ func NewQueryResultBuilder(namespace string, includeValues, includeMetadata bool
 	}
 }
 
-// ScanRow scans a SQL row into a match
+// ScanRow scans a SQL row whose last column is a distance into a match
 func (b *QueryResultBuilder) ScanRow(rows *sql.Rows) error {
+	return b.scanRow(rows, func(distance float32) float32 {
+		return ConvertDistanceToScore(distance, b.metric)
+	})
+}
+
+// ScanScoredRow scans a SQL row whose last column is already a score
+// (e.g. a text search rank) into a match
+func (b *QueryResultBuilder) ScanScoredRow(rows *sql.Rows) error {
+	return b.scanRow(rows, func(score float32) float32 { return score })
+}
+
+func (b *QueryResultBuilder) scanRow(rows *sql.Rows, toScore func(float32) float32) error {
 	match := vstore.Match{}
 
 	scanArgs := []any{&match.ID}
@@ -75,8 +87,8 @@ func (b *QueryResultBuilder) ScanRow(rows *sql.Rows) error {
 		scanArgs = append(scanArgs, &metadata)
 	}
 
-	var distance float32
-	scanArgs = append(scanArgs, &distance)
+	var value float32
+	scanArgs = append(scanArgs, &value)
 
 	if err := rows.Scan(scanArgs...); err != nil {
 		return err
@@ -89,8 +101,7 @@ func (b *QueryResultBuilder) ScanRow(rows *sql.Rows) error {
 		match.Metadata = map[string]any(metadata)
 	}
 
-	// Convert distance to similarity score
-	match.Score = ConvertDistanceToScore(distance, b.metric)
+	match.Score = toScore(value)
 
 	b.matches = append(b.matches, match)
 	return nil
@@ -106,7 +117,7 @@ func (b *QueryResultBuilder) Build() *vstore.QueryResult {
 
 // FilterByMinScore filters matches by minimum score
 func (b *QueryResultBuilder) FilterByMinScore(minScore float32) {
-	if minScore <= 0 {
+	if minScore == 0 {
 		return
 	}
 
@@ -127,9 +138,8 @@ func (b *QueryResultBuilder) FilterByMinScore(minScore float32) {
 func ConvertDistanceToScore(distance float32, metric DistanceMetric) float32 {
 	switch metric {
 	case DistanceCosine:
-		// Cosine distance is in [0, 2], where 0 is identical
-		// Convert to similarity score in [0, 1]
-		return 1.0 - (distance / 2.0)
+		// Cosine distance is 1 - cosine similarity
+		return 1.0 - distance
 	case DistanceInnerProduct:
 		// Inner product: higher (less negative) is better
 		// Since pgvector returns negative inner product as distance
@@ -147,7 +157,7 @@ func ConvertDistanceToScore(distance float32, metric DistanceMetric) float32 {
 func ConvertScoreToDistance(score float32, metric DistanceMetric) float32 {
 	switch metric {
 	case DistanceCosine:
-		return (1.0 - score) * 2.0
+		return 1.0 - score
 	case DistanceInnerProduct:
 		return -score
 	case DistanceL2:
@@ -0,0 +1,215 @@
+package vstpgvector_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstpgvector"
+	"github.com/jmoiron/sqlx"
+)
+
+// ============================================================================
+// Fake database: records statements and answers queries with canned rows
+// ============================================================================
+
+type fakeDB struct {
+	mu       sync.Mutex
+	execs    []string
+	queries  []string
+	vector   [][]driver.Value // id, metadata, distance
+	keywords [][]driver.Value // id, metadata, rank
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("vstpgvector_fake", fakeDriver{})
+}
+
+// openFake returns a sqlx.DB backed by db
+func openFake(t *testing.T, db *fakeDB) *sqlx.DB {
+	t.Helper()
+	fakeDBsMu.Lock()
+	fakeDBs[t.Name()] = db
+	fakeDBsMu.Unlock()
+
+	conn, err := sql.Open("vstpgvector_fake", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return sqlx.NewDb(conn, "postgres")
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	return &fakeConn{db: fakeDBs[name]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("transactions not supported") }
+
+// CheckNamedValue accepts every argument as is
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.execs = append(c.db.execs, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.queries = append(c.db.queries, query)
+	if strings.Contains(query, "ts_rank_cd") {
+		return &fakeRows{rows: c.db.keywords}, nil
+	}
+	return &fakeRows{rows: c.db.vector}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id", "metadata", "score"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+func row(id, content string, score float64) []driver.Value {
+	return []driver.Value{id, []byte(fmt.Sprintf(`{"content":%q}`, content)), score}
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestTextSearchIsOptIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []vstpgvector.ProviderOption
+		wantTSV bool
+	}{
+		{"default", nil, false},
+		{"enabled", []vstpgvector.ProviderOption{vstpgvector.WithTextSearch(true)}, true},
+		{"enabled without auto creation", []vstpgvector.ProviderOption{
+			vstpgvector.WithTextSearch(true), vstpgvector.WithAutoCreateTable(false),
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{}
+			if _, err := vstpgvector.NewPgVectorProviderFromDB(openFake(t, db), 2, tt.opts...); err != nil {
+				t.Fatalf("NewPgVectorProviderFromDB: %v", err)
+			}
+			altered := false
+			for _, stmt := range db.execs {
+				if strings.Contains(stmt, vstpgvector.TextSearchColumn) {
+					altered = true
+				}
+			}
+			if altered != tt.wantTSV {
+				t.Errorf("text search column created = %v, want %v (statements: %q)", altered, tt.wantTSV, db.execs)
+			}
+		})
+	}
+}
+
+func TestHybridQueryDisabled(t *testing.T) {
+	provider, err := vstpgvector.NewPgVectorProviderFromDB(openFake(t, &fakeDB{}), 2)
+	if err != nil {
+		t.Fatalf("NewPgVectorProviderFromDB: %v", err)
+	}
+	if _, err := provider.HybridQuery(context.Background(), []float32{1, 0}, "cats"); err == nil {
+		t.Fatal("HybridQuery succeeded without text search")
+	}
+}
+
+func TestHybridQuery(t *testing.T) {
+	db := &fakeDB{
+		// Cosine distances: a is the closest vector
+		vector: [][]driver.Value{row("a", "alpha", 0.1), row("b", "beta", 0.2), row("c", "gamma", 0.3)},
+		// Keyword ranks: c is the best text match
+		keywords: [][]driver.Value{row("c", "gamma", 0.9), row("d", "delta", 0.5)},
+	}
+	provider, err := vstpgvector.NewPgVectorProviderFromDB(openFake(t, db), 2, vstpgvector.WithTextSearch(true))
+	if err != nil {
+		t.Fatalf("NewPgVectorProviderFromDB: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query string
+		opts  []vstore.Option
+		want  []string
+	}{
+		{"rrf", "gamma", nil, []string{"c", "a", "b", "d"}},
+		{"vector only", "gamma", []vstore.Option{vstore.WithHybridAlpha(1)}, []string{"a", "b", "c"}},
+		{"keyword only", "gamma", []vstore.Option{vstore.WithHybridAlpha(0)}, []string{"c", "d"}},
+		{"top k", "gamma", []vstore.Option{vstore.WithTopK(2)}, []string{"c", "a"}},
+		{"empty query", " ", nil, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.queries = nil
+			result, err := provider.HybridQuery(ctx, []float32{1, 0}, tt.query, tt.opts...)
+			if err != nil {
+				t.Fatalf("HybridQuery: %v", err)
+			}
+			var got []string
+			for _, m := range result.Matches {
+				got = append(got, m.ID)
+				if m.Metadata["content"] == nil {
+					t.Errorf("match %s has no metadata", m.ID)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Each side fetches HybridCandidates rows
+	db.queries = nil
+	if _, err := provider.HybridQuery(ctx, []float32{1, 0}, "gamma", vstore.WithHybridCandidates(7)); err != nil {
+		t.Fatalf("HybridQuery: %v", err)
+	}
+	if len(db.queries) != 2 {
+		t.Fatalf("ran %d queries, want 2", len(db.queries))
+	}
+	for _, query := range db.queries {
+		if !strings.HasSuffix(strings.TrimSpace(query), "LIMIT 7") {
+			t.Errorf("query %q does not fetch 7 candidates", query)
+		}
+	}
+}
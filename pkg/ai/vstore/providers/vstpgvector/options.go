@@ -76,3 +76,30 @@ func WithBatchSize(size int) ProviderOption {
 		p.batchSize = size
 	}
 }
+
+// WithTextSearch enables the full-text search column used by HybridQuery
+// (disabled by default). With WithAutoCreateTable the column and its GIN
+// index are added at startup; adding a stored generated column rewrites
+// the table, so on large tables disable auto creation and apply
+// DBClient.TextSearchMigration through your migration tool instead.
+func WithTextSearch(enabled bool) ProviderOption {
+	return func(p *PgVectorProvider) {
+		p.textSearch = enabled
+	}
+}
+
+// WithTextSearchLanguage sets the PostgreSQL text search configuration used
+// to build the tsvector column and parse queries (e.g. "english", "spanish", "simple")
+func WithTextSearchLanguage(language string) ProviderOption {
+	return func(p *PgVectorProvider) {
+		p.textSearchLanguage = language
+	}
+}
+
+// WithTextField sets the metadata field holding the text to index for
+// keyword search (default: "content")
+func WithTextField(field string) ProviderOption {
+	return func(p *PgVectorProvider) {
+		p.textField = field
+	}
+}
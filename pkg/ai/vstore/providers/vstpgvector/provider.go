@@ -2,14 +2,17 @@ package vstpgvector
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
 	"github.com/Abraxas-365/manifesto/pkg/errx"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 const (
@@ -18,6 +21,23 @@ const (
 	DefaultMaxConnections    = 25
 	DefaultConnectionTimeout = 10 * time.Second
 	DefaultBatchSize         = 100
+	DefaultTextSearchLang    = "english"
+	DefaultTextField         = "content"
+
+	// TextSearchColumn is the generated tsvector column used for keyword search
+	TextSearchColumn = "content_tsv"
+)
+
+// Compile-time interface checks
+var (
+	_ vstore.VectorStorer       = (*PgVectorProvider)(nil)
+	_ vstore.MetadataFilterer   = (*PgVectorProvider)(nil)
+	_ vstore.BatchProcessor     = (*PgVectorProvider)(nil)
+	_ vstore.NamespaceManager   = (*PgVectorProvider)(nil)
+	_ vstore.IndexManager       = (*PgVectorProvider)(nil)
+	_ vstore.HybridSearcher     = (*PgVectorProvider)(nil)
+	_ vstore.StatisticsProvider = (*PgVectorProvider)(nil)
+	_ vstore.VectorLister       = (*PgVectorProvider)(nil)
 )
 
 // PgVectorProvider implements vector store for PostgreSQL with pgvector
@@ -36,6 +56,9 @@ type PgVectorProvider struct {
 	autoCreateTable    bool
 	useNamespaceColumn bool
 	batchSize          int
+	textSearch         bool
+	textSearchLanguage string
+	textField          string
 
 	// Track if we own the connection (should close it)
 	ownsConnection bool
@@ -64,6 +87,8 @@ func NewPgVectorProvider(connStr string, dimension int, opts ...ProviderOption)
 		autoCreateTable:    true,
 		useNamespaceColumn: true,
 		batchSize:          DefaultBatchSize,
+		textSearchLanguage: DefaultTextSearchLang,
+		textField:          DefaultTextField,
 		ownsConnection:     true, // We created the connection
 	}
 
@@ -72,6 +97,10 @@ func NewPgVectorProvider(connStr string, dimension int, opts ...ProviderOption)
 		opt(provider)
 	}
 
+	if err := provider.validateTextSearch(); err != nil {
+		return nil, err
+	}
+
 	// Connect to database
 	ctx, cancel := context.WithTimeout(context.Background(), provider.connectionTimeout)
 	defer cancel()
@@ -83,6 +112,9 @@ func NewPgVectorProvider(connStr string, dimension int, opts ...ProviderOption)
 
 	provider.db = dbx
 	provider.client = NewDBClient(dbx, provider.schema, provider.tableName, provider.dimension, provider.useNamespaceColumn)
+	if provider.textSearch {
+		provider.client.EnableTextSearch(provider.textSearchLanguage, provider.textField)
+	}
 
 	// Ensure extension and table
 	if err := provider.initialize(ctx); err != nil {
@@ -114,6 +146,8 @@ func NewPgVectorProviderFromDB(dbx *sqlx.DB, dimension int, opts ...ProviderOpti
 		autoCreateTable:    true,
 		useNamespaceColumn: true,
 		batchSize:          DefaultBatchSize,
+		textSearchLanguage: DefaultTextSearchLang,
+		textField:          DefaultTextField,
 		ownsConnection:     false, // Connection is owned by caller
 	}
 
@@ -122,8 +156,15 @@ func NewPgVectorProviderFromDB(dbx *sqlx.DB, dimension int, opts ...ProviderOpti
 		opt(provider)
 	}
 
+	if err := provider.validateTextSearch(); err != nil {
+		return nil, err
+	}
+
 	provider.db = dbx
 	provider.client = NewDBClient(dbx, provider.schema, provider.tableName, provider.dimension, provider.useNamespaceColumn)
+	if provider.textSearch {
+		provider.client.EnableTextSearch(provider.textSearchLanguage, provider.textField)
+	}
 
 	// Ensure extension and table
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -178,7 +219,7 @@ func (p *PgVectorProvider) DB() *sqlx.DB {
 // ============================================================================
 
 // Upsert inserts or updates vectors
-func (p *PgVectorProvider) Upsert(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) *errx.Error {
+func (p *PgVectorProvider) Upsert(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) error {
 	if len(vectors) == 0 {
 		return nil
 	}
@@ -251,7 +292,7 @@ func (p *PgVectorProvider) Upsert(ctx context.Context, vectors []vstore.Vector,
 }
 
 // Query performs similarity search
-func (p *PgVectorProvider) Query(ctx context.Context, vector []float32, opts ...vstore.Option) (*vstore.QueryResult, *errx.Error) {
+func (p *PgVectorProvider) Query(ctx context.Context, vector []float32, opts ...vstore.Option) (*vstore.QueryResult, error) {
 	if len(vector) != p.dimension {
 		return nil, errorRegistry.New(ErrInvalidVectorDimension).
 			WithDetail("expected", p.dimension).
@@ -277,26 +318,10 @@ func (p *PgVectorProvider) Query(ctx context.Context, vector []float32, opts ...
 		FROM %s`,
 		selectFields, distanceOp, p.client.FullTableName())
 
-	// Add namespace filter
-	args := []any{Vector(vector)}
-	argNum := 2
-	if p.useNamespaceColumn && options.Namespace != "" {
-		query += fmt.Sprintf(" WHERE namespace = $%d", argNum)
-		args = append(args, options.Namespace)
-		argNum++
-	}
-
-	// Add metadata filter if provided
-	if options.Filter != nil {
-		filterClause, filterArgs := p.buildFilterClause(options.Filter, argNum)
-		if filterClause != "" {
-			if strings.Contains(query, "WHERE") {
-				query += " AND " + filterClause
-			} else {
-				query += " WHERE " + filterClause
-			}
-			args = append(args, filterArgs...)
-		}
+	// Add namespace and metadata filters
+	where, args := p.buildWhereClause(options, []any{Vector(vector)})
+	if where != "" {
+		query += " WHERE " + where
 	}
 
 	// Order by distance and limit
@@ -334,7 +359,7 @@ func (p *PgVectorProvider) Query(ctx context.Context, vector []float32, opts ...
 }
 
 // Delete removes vectors by IDs
-func (p *PgVectorProvider) Delete(ctx context.Context, ids []string, opts ...vstore.Option) *errx.Error {
+func (p *PgVectorProvider) Delete(ctx context.Context, ids []string, opts ...vstore.Option) error {
 	if len(ids) == 0 {
 		return nil
 	}
@@ -342,7 +367,7 @@ func (p *PgVectorProvider) Delete(ctx context.Context, ids []string, opts ...vst
 	options := vstore.ApplyOptions(opts...)
 
 	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1)`, p.client.FullTableName())
-	args := []any{ids}
+	args := []any{pq.Array(ids)}
 
 	if p.useNamespaceColumn && options.Namespace != "" {
 		query += " AND namespace = $2"
@@ -358,7 +383,7 @@ func (p *PgVectorProvider) Delete(ctx context.Context, ids []string, opts ...vst
 }
 
 // Fetch retrieves vectors by IDs
-func (p *PgVectorProvider) Fetch(ctx context.Context, ids []string, opts ...vstore.Option) ([]vstore.Vector, *errx.Error) {
+func (p *PgVectorProvider) Fetch(ctx context.Context, ids []string, opts ...vstore.Option) ([]vstore.Vector, error) {
 	if len(ids) == 0 {
 		return []vstore.Vector{}, nil
 	}
@@ -371,7 +396,7 @@ func (p *PgVectorProvider) Fetch(ctx context.Context, ids []string, opts ...vsto
 		WHERE id = ANY($1)`,
 		p.client.FullTableName())
 
-	args := []any{ids}
+	args := []any{pq.Array(ids)}
 
 	if p.useNamespaceColumn && options.Namespace != "" {
 		query += " AND namespace = $2"
@@ -405,18 +430,115 @@ func (p *PgVectorProvider) Fetch(ctx context.Context, ids []string, opts ...vsto
 // ============================================================================
 
 // QueryWithFilter performs filtered similarity search
-func (p *PgVectorProvider) QueryWithFilter(ctx context.Context, vector []float32, filter vstore.Filter, opts ...vstore.Option) (*vstore.QueryResult, *errx.Error) {
+func (p *PgVectorProvider) QueryWithFilter(ctx context.Context, vector []float32, filter vstore.Filter, opts ...vstore.Option) (*vstore.QueryResult, error) {
 	// Add filter to options and use regular Query
 	opts = append(opts, vstore.WithFilter(&filter))
 	return p.Query(ctx, vector, opts...)
 }
 
+// ============================================================================
+// HybridSearcher Implementation
+// ============================================================================
+
+// HybridQuery combines vector similarity with full-text search over the
+// tsvector column. Each side fetches HybridCandidates results, which are
+// merged with reciprocal rank fusion or weighted score blending depending
+// on the Fusion option; HybridAlpha weights the vector side. MinScore
+// applies to the fused score.
+//
+// The query text is parsed with websearch_to_tsquery, so quoted phrases,
+// "or" and "-term" are supported. An empty query falls back to Query.
+func (p *PgVectorProvider) HybridQuery(ctx context.Context, vector []float32, query string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if !p.textSearch {
+		return nil, errorRegistry.New(ErrFeatureNotSupported).
+			WithDetail("error", "text search not enabled, see WithTextSearch")
+	}
+	if strings.TrimSpace(query) == "" {
+		return p.Query(ctx, vector, opts...)
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	candidates := options.HybridCandidateCount()
+
+	var vectorMatches []vstore.Match
+	if options.HybridAlpha > 0 {
+		candidateOpts := append(slices.Clone(opts), vstore.WithTopK(candidates), vstore.WithMinScore(0))
+		result, err := p.Query(ctx, vector, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+		vectorMatches = result.Matches
+	}
+
+	var keywordMatches []vstore.Match
+	if options.HybridAlpha < 1 {
+		matches, err := p.keywordQuery(ctx, query, candidates, options)
+		if err != nil {
+			return nil, err
+		}
+		keywordMatches = matches
+	}
+
+	return vstore.NewQueryResultBuilder().
+		WithMatches(vstore.FuseResults(vectorMatches, keywordMatches, options)).
+		WithNamespace(options.Namespace).
+		Build(), nil
+}
+
+// keywordQuery ranks rows by ts_rank_cd against the tsvector column
+func (p *PgVectorProvider) keywordQuery(ctx context.Context, text string, limit int, options *vstore.Options) ([]vstore.Match, *errx.Error) {
+	selectFields := "id"
+	if options.IncludeValues {
+		selectFields += ", vector"
+	}
+	if options.IncludeMetadata {
+		selectFields += ", metadata"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, ts_rank_cd(%s, q) AS score
+		FROM %s, websearch_to_tsquery($1::regconfig, $2) q
+		WHERE %s @@ q`,
+		selectFields, TextSearchColumn, p.client.FullTableName(), TextSearchColumn)
+
+	where, args := p.buildWhereClause(options, []any{p.textSearchLanguage, text})
+	if where != "" {
+		query += " AND " + where
+	}
+	query += fmt.Sprintf(" ORDER BY score DESC LIMIT %d", limit)
+
+	rows, err := p.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, ParseDatabaseError(err, query, args...)
+	}
+	defer rows.Close()
+
+	builder := NewQueryResultBuilder(
+		options.Namespace,
+		options.IncludeValues,
+		options.IncludeMetadata,
+		p.defaultMetric,
+	)
+
+	for rows.Next() {
+		if err := builder.ScanScoredRow(rows.Rows); err != nil {
+			return nil, ParseDatabaseError(err, "scanning row")
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, ParseDatabaseError(err, "iterating rows")
+	}
+
+	return builder.Build().Matches, nil
+}
+
 // ============================================================================
 // BatchProcessor Implementation
 // ============================================================================
 
 // UpsertBatch upserts vectors in optimized batches
-func (p *PgVectorProvider) UpsertBatch(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) (*vstore.BatchResult, *errx.Error) {
+func (p *PgVectorProvider) UpsertBatch(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) (*vstore.BatchResult, error) {
 	result := &vstore.BatchResult{}
 
 	// Split into batches
@@ -440,7 +562,7 @@ func (p *PgVectorProvider) UpsertBatch(ctx context.Context, vectors []vstore.Vec
 }
 
 // DeleteBatch deletes multiple vectors efficiently
-func (p *PgVectorProvider) DeleteBatch(ctx context.Context, ids []string, opts ...vstore.Option) (*vstore.BatchResult, *errx.Error) {
+func (p *PgVectorProvider) DeleteBatch(ctx context.Context, ids []string, opts ...vstore.Option) (*vstore.BatchResult, error) {
 	if err := p.Delete(ctx, ids, opts...); err != nil {
 		return &vstore.BatchResult{
 			FailedCount: len(ids),
@@ -457,7 +579,7 @@ func (p *PgVectorProvider) DeleteBatch(ctx context.Context, ids []string, opts .
 // ============================================================================
 
 // ListNamespaces returns all namespaces
-func (p *PgVectorProvider) ListNamespaces(ctx context.Context) ([]string, *errx.Error) {
+func (p *PgVectorProvider) ListNamespaces(ctx context.Context) ([]string, error) {
 	if !p.useNamespaceColumn {
 		return nil, errorRegistry.New(ErrFeatureNotSupported).
 			WithDetail("error", "namespace column not enabled")
@@ -478,14 +600,14 @@ func (p *PgVectorProvider) ListNamespaces(ctx context.Context) ([]string, *errx.
 }
 
 // CreateNamespace creates a new namespace (no-op for column-based namespaces)
-func (p *PgVectorProvider) CreateNamespace(ctx context.Context, namespace string) *errx.Error {
+func (p *PgVectorProvider) CreateNamespace(ctx context.Context, namespace string) error {
 	// For column-based namespaces, this is a no-op
 	// Namespaces are created implicitly when vectors are inserted
 	return nil
 }
 
 // DeleteNamespace deletes a namespace and all its vectors
-func (p *PgVectorProvider) DeleteNamespace(ctx context.Context, namespace string) *errx.Error {
+func (p *PgVectorProvider) DeleteNamespace(ctx context.Context, namespace string) error {
 	if !p.useNamespaceColumn {
 		return errorRegistry.New(ErrFeatureNotSupported).
 			WithDetail("error", "namespace column not enabled")
@@ -506,7 +628,7 @@ func (p *PgVectorProvider) DeleteNamespace(ctx context.Context, namespace string
 // ============================================================================
 
 // CreateIndex creates a vector index
-func (p *PgVectorProvider) CreateIndex(ctx context.Context, config vstore.IndexConfig) *errx.Error {
+func (p *PgVectorProvider) CreateIndex(ctx context.Context, config vstore.IndexConfig) error {
 	indexConfig := IndexConfig{
 		IndexName:      config.Name,
 		TableName:      p.tableName,
@@ -526,7 +648,7 @@ func (p *PgVectorProvider) CreateIndex(ctx context.Context, config vstore.IndexC
 }
 
 // DeleteIndex deletes an index
-func (p *PgVectorProvider) DeleteIndex(ctx context.Context, indexName string) *errx.Error {
+func (p *PgVectorProvider) DeleteIndex(ctx context.Context, indexName string) error {
 	query := fmt.Sprintf(`DROP INDEX IF EXISTS %s.%s`, p.schema, indexName)
 
 	_, err := p.db.ExecContext(ctx, query)
@@ -538,7 +660,7 @@ func (p *PgVectorProvider) DeleteIndex(ctx context.Context, indexName string) *e
 }
 
 // DescribeIndex returns index metadata
-func (p *PgVectorProvider) DescribeIndex(ctx context.Context, indexName string) (*vstore.IndexInfo, *errx.Error) {
+func (p *PgVectorProvider) DescribeIndex(ctx context.Context, indexName string) (*vstore.IndexInfo, error) {
 	tableInfo, err := p.client.GetTableInfo(ctx)
 	if err != nil {
 		return nil, WrapError(err, ErrTableNotFound)
@@ -565,7 +687,7 @@ func (p *PgVectorProvider) DescribeIndex(ctx context.Context, indexName string)
 }
 
 // ListIndexes returns all indexes
-func (p *PgVectorProvider) ListIndexes(ctx context.Context) ([]vstore.IndexInfo, *errx.Error) {
+func (p *PgVectorProvider) ListIndexes(ctx context.Context) ([]vstore.IndexInfo, error) {
 	tableInfo, err := p.client.GetTableInfo(ctx)
 	if err != nil {
 		return nil, WrapError(err, ErrTableNotFound)
@@ -579,7 +701,7 @@ func (p *PgVectorProvider) ListIndexes(ctx context.Context) ([]vstore.IndexInfo,
 // ============================================================================
 
 // GetStatistics returns vector store statistics
-func (p *PgVectorProvider) GetStatistics(ctx context.Context, opts ...vstore.Option) (*vstore.Statistics, *errx.Error) {
+func (p *PgVectorProvider) GetStatistics(ctx context.Context, opts ...vstore.Option) (*vstore.Statistics, error) {
 	options := vstore.ApplyOptions(opts...)
 
 	var totalCount int64
@@ -616,11 +738,136 @@ func (p *PgVectorProvider) GetStatistics(ctx context.Context, opts ...vstore.Opt
 	return ToVstoreStatistics(totalCount, p.dimension, namespaceStats), nil
 }
 
+// ============================================================================
+// VectorLister Implementation
+// ============================================================================
+
+// List returns vectors in ID order using keyset pagination; the cursor is
+// the last ID of the previous page
+func (p *PgVectorProvider) List(ctx context.Context, cursor string, limit int, opts ...vstore.Option) (*vstore.ListResult, error) {
+	if limit <= 0 {
+		return nil, errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "limit must be positive").
+			WithDetail("limit", limit)
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	whereClause, args := p.buildWhereClause(options, []any{cursor})
+	if whereClause != "" {
+		whereClause = " AND " + whereClause
+	}
+
+	columns := "id, metadata"
+	if options.IncludeValues {
+		columns += ", vector"
+	}
+	// One extra row tells whether another page follows
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE id > $1%s
+		ORDER BY id
+		LIMIT %d`,
+		columns, p.client.FullTableName(), whereClause, limit+1)
+
+	rows, err := p.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, ParseDatabaseError(err, query, args...)
+	}
+	defer rows.Close()
+
+	result := &vstore.ListResult{Vectors: make([]vstore.Vector, 0, limit)}
+	for rows.Next() {
+		var id string
+		var metadata Metadata
+		var pgVector Vector
+
+		dest := []any{&id, &metadata}
+		if options.IncludeValues {
+			dest = append(dest, &pgVector)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, ParseDatabaseError(err, "scanning row")
+		}
+
+		if len(result.Vectors) == limit {
+			result.NextCursor = result.Vectors[limit-1].ID
+			break
+		}
+		v := ToVstoreVector(id, pgVector, metadata)
+		if !options.IncludeValues {
+			v.Values = nil
+		}
+		result.Vectors = append(result.Vectors, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ParseDatabaseError(err, "iterating rows")
+	}
+
+	return result, nil
+}
+
 // ============================================================================
 // Helper Methods
 // ============================================================================
 
-// buildFilterClause builds a WHERE clause from filter
+// validateTextSearch checks the text search settings, which are
+// interpolated into DDL
+func (p *PgVectorProvider) validateTextSearch() *errx.Error {
+	if !p.textSearch {
+		return nil
+	}
+	if !isIdentifier(p.textSearchLanguage) {
+		return errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "invalid text search language").
+			WithDetail("language", p.textSearchLanguage)
+	}
+	if !isIdentifier(p.textField) {
+		return errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "invalid text field").
+			WithDetail("field", p.textField)
+	}
+	return nil
+}
+
+// isIdentifier reports whether s only contains letters, digits and underscores
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// buildWhereClause builds the namespace and metadata filter conditions for
+// a query whose first len(args) parameters are already bound
+func (p *PgVectorProvider) buildWhereClause(options *vstore.Options, args []any) (string, []any) {
+	var clauses []string
+	argNum := len(args) + 1
+
+	if p.useNamespaceColumn && options.Namespace != "" {
+		clauses = append(clauses, fmt.Sprintf("namespace = $%d", argNum))
+		args = append(args, options.Namespace)
+		argNum++
+	}
+
+	if options.Filter != nil {
+		filterClause, filterArgs := p.buildFilterClause(options.Filter, argNum)
+		if filterClause != "" {
+			clauses = append(clauses, filterClause)
+			args = append(args, filterArgs...)
+		}
+	}
+
+	return strings.Join(clauses, " AND "), args
+}
+
+// buildFilterClause builds a WHERE clause from filter, recursing into
+// nested And/Or/Not sub-filters
 func (p *PgVectorProvider) buildFilterClause(filter *vstore.Filter, startArgNum int) (string, []any) {
 	if filter == nil {
 		return "", nil
@@ -630,40 +877,51 @@ func (p *PgVectorProvider) buildFilterClause(filter *vstore.Filter, startArgNum
 	var args []any
 	argNum := startArgNum
 
-	// Process Must conditions (AND)
-	for _, cond := range filter.Must {
+	condition := func(cond vstore.Condition) string {
 		clause, condArgs := p.buildCondition(cond, argNum)
-		if clause != "" {
+		args = append(args, condArgs...)
+		argNum += len(condArgs)
+		return clause
+	}
+	// An empty sub-filter matches every row
+	subFilter := func(sub *vstore.Filter) string {
+		clause, subArgs := p.buildFilterClause(sub, argNum)
+		if clause == "" {
+			return "TRUE"
+		}
+		args = append(args, subArgs...)
+		argNum += len(subArgs)
+		return "(" + clause + ")"
+	}
+
+	// Process Must conditions and And sub-filters (AND)
+	for _, cond := range filter.Must {
+		clauses = append(clauses, condition(cond))
+	}
+	for i := range filter.And {
+		if clause := subFilter(&filter.And[i]); clause != "TRUE" {
 			clauses = append(clauses, clause)
-			args = append(args, condArgs...)
-			argNum += len(condArgs)
 		}
 	}
 
-	// Process Should conditions (OR)
-	if len(filter.Should) > 0 {
+	// Process Should conditions and Or sub-filters (OR)
+	if len(filter.Should) > 0 || len(filter.Or) > 0 {
 		var shouldClauses []string
 		for _, cond := range filter.Should {
-			clause, condArgs := p.buildCondition(cond, argNum)
-			if clause != "" {
-				shouldClauses = append(shouldClauses, clause)
-				args = append(args, condArgs...)
-				argNum += len(condArgs)
-			}
+			shouldClauses = append(shouldClauses, condition(cond))
 		}
-		if len(shouldClauses) > 0 {
-			clauses = append(clauses, "("+strings.Join(shouldClauses, " OR ")+")")
+		for i := range filter.Or {
+			shouldClauses = append(shouldClauses, subFilter(&filter.Or[i]))
 		}
+		clauses = append(clauses, "("+strings.Join(shouldClauses, " OR ")+")")
 	}
 
-	// Process MustNot conditions (NOT)
+	// Process MustNot conditions and Not sub-filters (NOT)
 	for _, cond := range filter.MustNot {
-		clause, condArgs := p.buildCondition(cond, argNum)
-		if clause != "" {
-			clauses = append(clauses, "NOT ("+clause+")")
-			args = append(args, condArgs...)
-			argNum += len(condArgs)
-		}
+		clauses = append(clauses, "NOT ("+condition(cond)+")")
+	}
+	for i := range filter.Not {
+		clauses = append(clauses, "NOT "+subFilter(&filter.Not[i]))
 	}
 
 	if len(clauses) == 0 {
@@ -673,28 +931,93 @@ func (p *PgVectorProvider) buildFilterClause(filter *vstore.Filter, startArgNum
 	return strings.Join(clauses, " AND "), args
 }
 
-// buildCondition builds a single condition
+// buildCondition builds a single condition. The field path is always the
+// first argument; equality compares JSON values so numbers, strings and
+// booleans match by type, and ne/nin match rows missing the field. Unknown
+// operators match nothing.
 func (p *PgVectorProvider) buildCondition(cond vstore.Condition, argNum int) (string, []any) {
-	field := fmt.Sprintf("metadata->>'%s'", cond.Field)
+	field := fmt.Sprintf("(metadata #> $%d::text[])", argNum)
+	text := fmt.Sprintf("(metadata #>> $%d::text[])", argNum)
+	value := argNum + 1
+	args := []any{pq.Array(vstore.FieldPath(cond.Field))}
 
 	switch cond.Operator {
 	case vstore.OpEqual:
-		return fmt.Sprintf("%s = $%d", field, argNum), []any{cond.Value}
+		return fmt.Sprintf("%s = $%d::jsonb", field, value), append(args, jsonArg(cond.Value))
 	case vstore.OpNotEqual:
-		return fmt.Sprintf("%s != $%d", field, argNum), []any{cond.Value}
-	case vstore.OpGreaterThan:
-		return fmt.Sprintf("%s > $%d", field, argNum), []any{cond.Value}
-	case vstore.OpLessThan:
-		return fmt.Sprintf("%s < $%d", field, argNum), []any{cond.Value}
-	case vstore.OpGreaterThanOrEqual:
-		return fmt.Sprintf("%s >= $%d", field, argNum), []any{cond.Value}
-	case vstore.OpLessThanOrEqual:
-		return fmt.Sprintf("%s <= $%d", field, argNum), []any{cond.Value}
+		return fmt.Sprintf("%s IS DISTINCT FROM $%d::jsonb", field, value), append(args, jsonArg(cond.Value))
+	case vstore.OpGreaterThan, vstore.OpLessThan, vstore.OpGreaterThanOrEqual, vstore.OpLessThanOrEqual:
+		op := comparisonOperators[cond.Operator]
+		if n, ok := numericArg(cond.Value); ok {
+			// Only numbers are cast so string values can't break the query
+			return fmt.Sprintf("COALESCE(CASE WHEN jsonb_typeof(%s) = 'number' THEN %s::numeric %s $%d END, false)",
+				field, text, op, value), append(args, n)
+		}
+		return fmt.Sprintf("COALESCE(%s %s $%d, false)", text, op, value), append(args, fmt.Sprint(cond.Value))
+	case vstore.OpIn:
+		return fmt.Sprintf("$%d::jsonb @> jsonb_build_array(%s)", value, field), append(args, jsonArg(cond.Value))
+	case vstore.OpNotIn:
+		return fmt.Sprintf("NOT ($%d::jsonb @> jsonb_build_array(%s))", value, field), append(args, jsonArg(cond.Value))
 	case vstore.OpExists:
-		return fmt.Sprintf("metadata ? '%s'", cond.Field), nil
+		return fmt.Sprintf("%s IS NOT NULL", field), args
 	case vstore.OpContains:
-		return fmt.Sprintf("%s LIKE $%d", field, argNum), []any{fmt.Sprintf("%%%v%%", cond.Value)}
+		pattern := "%" + likeEscaper.Replace(fmt.Sprint(cond.Value)) + "%"
+		return fmt.Sprintf("COALESCE(%s ILIKE $%d, false)", text, value), append(args, pattern)
+	case vstore.OpArrayContains:
+		return fmt.Sprintf("COALESCE(jsonb_typeof(%s) = 'array' AND %s @> $%d::jsonb, false)", field, field, value),
+			append(args, jsonArg([]any{cond.Value}))
+	case vstore.OpArrayContainsAll:
+		return fmt.Sprintf("COALESCE(jsonb_typeof(%s) = 'array' AND %s @> $%d::jsonb, false)", field, field, value),
+			append(args, jsonArg(listArg(cond.Value)))
+	case vstore.OpArrayContainsAny:
+		return fmt.Sprintf("COALESCE(jsonb_typeof(%s) = 'array' AND EXISTS (SELECT 1 FROM jsonb_array_elements($%d::jsonb) AS e(value) WHERE %s @> jsonb_build_array(e.value)), false)",
+			field, value, field), append(args, jsonArg(listArg(cond.Value)))
 	default:
-		return "", nil
+		return "FALSE", nil
+	}
+}
+
+var comparisonOperators = map[vstore.FilterOperator]string{
+	vstore.OpGreaterThan:        ">",
+	vstore.OpLessThan:           "<",
+	vstore.OpGreaterThanOrEqual: ">=",
+	vstore.OpLessThanOrEqual:    "<=",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// jsonArg encodes a filter value as a jsonb argument
+func jsonArg(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
+
+// listArg returns v as a slice so it encodes as a JSON array
+func listArg(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return v
+	}
+	return []any{v}
+}
+
+// numericArg returns v as a float64 if it is a Go number
+func numericArg(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
 	}
 }
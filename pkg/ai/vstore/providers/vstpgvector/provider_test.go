@@ -0,0 +1,38 @@
+package vstpgvector_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstpgvector"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/vstoretest"
+)
+
+// TestConformance runs against the database in PGVECTOR_TEST_DSN
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("PGVECTOR_TEST_DSN")
+	if dsn == "" {
+		t.Skip("PGVECTOR_TEST_DSN not set")
+	}
+
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		table := fmt.Sprintf("vstoretest_%d", time.Now().UnixNano())
+		provider, err := vstpgvector.NewPgVectorProvider(dsn, dimension,
+			vstpgvector.WithTableName(table),
+			vstpgvector.WithDistanceMetric(vstpgvector.VstoreMetricToPg(metric)),
+		)
+		if err != nil {
+			t.Fatalf("NewPgVectorProvider: %v", err)
+		}
+		t.Cleanup(func() {
+			_, _ = provider.DB().ExecContext(context.Background(),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", vstpgvector.DefaultSchema, table))
+			_ = provider.Close()
+		})
+		return provider
+	})
+}
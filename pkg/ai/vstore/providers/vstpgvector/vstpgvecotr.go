@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -17,10 +18,10 @@ func (v Vector) Value() (driver.Value, error) {
 		return nil, nil
 	}
 
-	// Format as PostgreSQL vector literal: [1,2,3]
+	// Format as PostgreSQL vector literal: [1,2,3], keeping full float32 precision
 	parts := make([]string, len(v))
 	for i, val := range v {
-		parts[i] = fmt.Sprintf("%f", val)
+		parts[i] = strconv.FormatFloat(float64(val), 'g', -1, 32)
 	}
 	return "[" + strings.Join(parts, ",") + "]", nil
 }
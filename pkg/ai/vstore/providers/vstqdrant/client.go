@@ -0,0 +1,101 @@
+package vstqdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// HTTPClient handles all HTTP communication with the Qdrant REST API
+type HTTPClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPClient creates a new HTTP client for the Qdrant REST API
+func NewHTTPClient(baseURL, apiKey string, httpClient *http.Client) *HTTPClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &HTTPClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// apiResponse is the success envelope returned by Qdrant
+type apiResponse struct {
+	Result json.RawMessage `json:"result"`
+	Status any             `json:"status"`
+	Time   float64         `json:"time"`
+}
+
+// Do sends a request and decodes the "result" field of the response into
+// out (which may be nil)
+func (c *HTTPClient) Do(ctx context.Context, method, path string, payload, out any) *errx.Error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return WrapError(err, ErrInvalidInput).
+				WithDetail("error", "failed to marshal request payload")
+		}
+		body = bytes.NewReader(data)
+	}
+
+	url := c.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return WrapError(err, ErrAPIRequest).
+			WithDetail("error", "failed to create HTTP request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return WrapError(err, ErrAPIRequest).
+			WithDetail("error", "HTTP request failed").
+			WithDetail("url", url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return WrapError(err, ErrAPIResponse).
+			WithDetail("error", "failed to read response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ParseAPIError(resp.StatusCode, respBody).
+			WithDetail("method", method).
+			WithDetail("path", path)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	var envelope apiResponse
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return WrapError(err, ErrAPIResponse).
+			WithDetail("error", "failed to decode response")
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return WrapError(err, ErrAPIResponse).
+			WithDetail("error", "failed to decode response result")
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package vstqdrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Qdrant API Types
+// ============================================================================
+
+type point struct {
+	ID      string         `json:"id"`
+	Vector  map[string]any `json:"vector,omitempty"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+type retrievedPoint struct {
+	ID      any                        `json:"id"`
+	Score   float32                    `json:"score"`
+	Payload map[string]any             `json:"payload"`
+	Vector  map[string]json.RawMessage `json:"vector"`
+}
+
+type sparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+type queryRequest struct {
+	Query          any            `json:"query"`
+	Using          string         `json:"using,omitempty"`
+	Filter         map[string]any `json:"filter,omitempty"`
+	Limit          int            `json:"limit"`
+	WithPayload    bool           `json:"with_payload"`
+	WithVector     bool           `json:"with_vector"`
+	ScoreThreshold *float32       `json:"score_threshold,omitempty"`
+}
+
+type queryResponse struct {
+	Points []retrievedPoint `json:"points"`
+}
+
+type collectionInfo struct {
+	Status      string `json:"status"`
+	PointsCount int64  `json:"points_count"`
+	Config      struct {
+		Params struct {
+			Vectors map[string]struct {
+				Size     int    `json:"size"`
+				Distance string `json:"distance"`
+			} `json:"vectors"`
+		} `json:"params"`
+	} `json:"config"`
+}
+
+// ============================================================================
+// Point IDs
+// ============================================================================
+
+// pointIDNamespace seeds the UUIDs derived from vstore IDs
+var pointIDNamespace = uuid.MustParse("6f1c8f0e-7d8b-4c1e-9a57-3c0b8d2f4e61")
+
+// PointID maps a vstore ID to a Qdrant point ID. Qdrant only accepts
+// unsigned integers and UUIDs, so arbitrary IDs are hashed into a stable
+// UUID and the original ID is kept in the payload under IDPayloadKey.
+func PointID(id string) string {
+	return uuid.NewSHA1(pointIDNamespace, []byte(id)).String()
+}
+
+// ============================================================================
+// Vector Conversions
+// ============================================================================
+
+// toPoint converts a vstore.Vector to a Qdrant point
+func toPoint(v vstore.Vector) point {
+	payload := make(map[string]any, len(v.Metadata)+1)
+	maps.Copy(payload, v.Metadata)
+	payload[IDPayloadKey] = v.ID
+
+	vector := map[string]any{DenseVectorName: v.Values}
+	if v.SparseValues != nil {
+		vector[SparseVectorName] = toSparse(*v.SparseValues)
+	}
+
+	return point{ID: PointID(v.ID), Vector: vector, Payload: payload}
+}
+
+func toSparse(v vstore.SparseVector) sparseVector {
+	return sparseVector{Indices: v.Indices, Values: v.Values}
+}
+
+// toMatch converts a retrieved point to a vstore.Match
+func toMatch(p retrievedPoint, metric vstore.Metric, includeValues, includeMetadata bool) (vstore.Match, error) {
+	id, metadata := splitPayload(p)
+	match := vstore.Match{
+		ID:    id,
+		Score: ConvertScore(p.Score, metric),
+	}
+
+	if includeMetadata {
+		match.Metadata = metadata
+	}
+	if includeValues {
+		dense, sparse, err := decodeVectors(p.Vector)
+		if err != nil {
+			return vstore.Match{}, err
+		}
+		match.Values = dense
+		match.SparseValues = sparse
+	}
+
+	return match, nil
+}
+
+// toVector converts a retrieved point to a vstore.Vector
+func toVector(p retrievedPoint) (vstore.Vector, error) {
+	id, metadata := splitPayload(p)
+	dense, sparse, err := decodeVectors(p.Vector)
+	if err != nil {
+		return vstore.Vector{}, err
+	}
+
+	return vstore.Vector{
+		ID:           id,
+		Values:       dense,
+		SparseValues: sparse,
+		Metadata:     metadata,
+	}, nil
+}
+
+// splitPayload separates the original vstore ID from the metadata
+func splitPayload(p retrievedPoint) (string, map[string]any) {
+	metadata := make(map[string]any, len(p.Payload))
+	maps.Copy(metadata, p.Payload)
+
+	id, _ := metadata[IDPayloadKey].(string)
+	delete(metadata, IDPayloadKey)
+	if id == "" {
+		id = fmt.Sprint(p.ID)
+	}
+
+	return id, metadata
+}
+
+func decodeVectors(raw map[string]json.RawMessage) ([]float32, *vstore.SparseVector, error) {
+	var dense []float32
+	if data, ok := raw[DenseVectorName]; ok {
+		if err := json.Unmarshal(data, &dense); err != nil {
+			return nil, nil, fmt.Errorf("decode dense vector: %w", err)
+		}
+	}
+
+	var sparse *vstore.SparseVector
+	if data, ok := raw[SparseVectorName]; ok {
+		var sv sparseVector
+		if err := json.Unmarshal(data, &sv); err != nil {
+			return nil, nil, fmt.Errorf("decode sparse vector: %w", err)
+		}
+		sparse = &vstore.SparseVector{Indices: sv.Indices, Values: sv.Values}
+	}
+
+	return dense, sparse, nil
+}
+
+// ============================================================================
+// Metric Conversions
+// ============================================================================
+
+// QdrantDistance returns the Qdrant distance name for a vstore metric
+func QdrantDistance(metric vstore.Metric) string {
+	switch metric {
+	case vstore.MetricDotProduct:
+		return "Dot"
+	case vstore.MetricEuclidean:
+		return "Euclid"
+	default:
+		return "Cosine"
+	}
+}
+
+// VstoreMetric returns the vstore metric for a Qdrant distance name
+func VstoreMetric(distance string) vstore.Metric {
+	switch distance {
+	case "Dot":
+		return vstore.MetricDotProduct
+	case "Euclid":
+		return vstore.MetricEuclidean
+	default:
+		return vstore.MetricCosine
+	}
+}
+
+// ConvertScore converts a Qdrant score to a similarity where higher is
+// better. Cosine and Dot are already similarities; Euclid is a distance.
+func ConvertScore(score float32, metric vstore.Metric) float32 {
+	if metric == vstore.MetricEuclidean {
+		return 1.0 / (1.0 + score)
+	}
+	return score
+}
+
+// ============================================================================
+// Filter Conversions
+// ============================================================================
+
+// ToQdrantFilter converts a vstore.Filter to a Qdrant filter. Nested
+// And/Or/Not sub-filters become nested Qdrant filters, and dotted field
+// paths are passed through since Qdrant resolves them natively.
+func ToQdrantFilter(filter *vstore.Filter) (map[string]any, *errx.Error) {
+	if filter == nil || filter.IsEmpty() {
+		return nil, nil
+	}
+	return toQdrantFilter(filter)
+}
+
+// toQdrantFilter converts a filter node. An empty node converts to an empty
+// filter, which Qdrant matches against every point.
+func toQdrantFilter(filter *vstore.Filter) (map[string]any, *errx.Error) {
+	out := make(map[string]any)
+	for _, group := range []struct {
+		key        string
+		conditions []vstore.Condition
+		filters    []vstore.Filter
+	}{
+		{"must", filter.Must, filter.And},
+		{"should", filter.Should, filter.Or},
+		{"must_not", filter.MustNot, filter.Not},
+	} {
+		if len(group.conditions) == 0 && len(group.filters) == 0 {
+			continue
+		}
+		converted := make([]any, 0, len(group.conditions)+len(group.filters))
+		for _, cond := range group.conditions {
+			c, err := toQdrantCondition(cond)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, c)
+		}
+		for i := range group.filters {
+			f, err := toQdrantFilter(&group.filters[i])
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, f)
+		}
+		out[group.key] = converted
+	}
+	return out, nil
+}
+
+// toQdrantCondition converts a single condition. Negated operators become
+// nested filters, which Qdrant accepts anywhere a condition is expected, so
+// points missing the field match ne and nin like they do in other stores.
+// Qdrant matches a value against any element of a list field, which is what
+// the array operators rely on.
+func toQdrantCondition(cond vstore.Condition) (any, *errx.Error) {
+	match := func(m map[string]any) map[string]any {
+		return map[string]any{"key": cond.Field, "match": m}
+	}
+	rangeOf := func(op string) map[string]any {
+		return map[string]any{"key": cond.Field, "range": map[string]any{op: cond.Value}}
+	}
+	not := func(c any) map[string]any {
+		return map[string]any{"must_not": []any{c}}
+	}
+
+	// Match conditions only accept keywords, integers and booleans, so
+	// fractional numbers are compared with a closed range
+	equal := match(map[string]any{"value": cond.Value})
+	if f, ok := cond.Value.(float64); ok && f != math.Trunc(f) {
+		equal = map[string]any{"key": cond.Field, "range": map[string]any{"gte": f, "lte": f}}
+	}
+
+	switch cond.Operator {
+	case vstore.OpEqual:
+		return equal, nil
+	case vstore.OpNotEqual:
+		return not(equal), nil
+	case vstore.OpGreaterThan:
+		return rangeOf("gt"), nil
+	case vstore.OpLessThan:
+		return rangeOf("lt"), nil
+	case vstore.OpGreaterThanOrEqual:
+		return rangeOf("gte"), nil
+	case vstore.OpLessThanOrEqual:
+		return rangeOf("lte"), nil
+	case vstore.OpIn:
+		return match(map[string]any{"any": cond.Value}), nil
+	case vstore.OpNotIn:
+		return not(match(map[string]any{"any": cond.Value})), nil
+	case vstore.OpExists:
+		return not(map[string]any{"is_empty": map[string]any{"key": cond.Field}}), nil
+	case vstore.OpContains:
+		return match(map[string]any{"text": fmt.Sprint(cond.Value)}), nil
+	case vstore.OpArrayContains:
+		return match(map[string]any{"value": cond.Value}), nil
+	case vstore.OpArrayContainsAny:
+		return match(map[string]any{"any": listOf(cond.Value)}), nil
+	case vstore.OpArrayContainsAll:
+		values := listOf(cond.Value)
+		must := make([]any, len(values))
+		for i, v := range values {
+			must[i] = match(map[string]any{"value": v})
+		}
+		return map[string]any{"must": must}, nil
+	default:
+		return nil, errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "unsupported filter operator").
+			WithDetail("operator", string(cond.Operator))
+	}
+}
+
+// listOf returns the elements of a slice value, or v itself when it isn't
+// one
+func listOf(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{v}
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// withCondition returns filter with an extra must condition
+func withCondition(filter map[string]any, condition any) map[string]any {
+	out := make(map[string]any, len(filter)+1)
+	maps.Copy(out, filter)
+	must, _ := out["must"].([]any)
+	out["must"] = append(append([]any(nil), must...), condition)
+	return out
+}
@@ -0,0 +1,98 @@
+package vstqdrant_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstqdrant"
+)
+
+func TestToQdrantFilter(t *testing.T) {
+	where := func(op vstore.FilterOperator, value any) *vstore.Filter {
+		f := vstore.Where("year", op, value)
+		return &f
+	}
+
+	tests := []struct {
+		name   string
+		filter *vstore.Filter
+		want   string
+	}{
+		{"nil", nil, `null`},
+		{"empty", vstore.NewFilter(), `null`},
+		{"eq", where(vstore.OpEqual, 2020), `{"must":[{"key":"year","match":{"value":2020}}]}`},
+		{"eq fractional", where(vstore.OpEqual, 4.5), `{"must":[{"key":"year","range":{"gte":4.5,"lte":4.5}}]}`},
+		{"ne", where(vstore.OpNotEqual, 2020), `{"must":[{"must_not":[{"key":"year","match":{"value":2020}}]}]}`},
+		{"gt", where(vstore.OpGreaterThan, 2020), `{"must":[{"key":"year","range":{"gt":2020}}]}`},
+		{"gte", where(vstore.OpGreaterThanOrEqual, 2020), `{"must":[{"key":"year","range":{"gte":2020}}]}`},
+		{"lt", where(vstore.OpLessThan, 2020), `{"must":[{"key":"year","range":{"lt":2020}}]}`},
+		{"lte", where(vstore.OpLessThanOrEqual, 2020), `{"must":[{"key":"year","range":{"lte":2020}}]}`},
+		{"in", where(vstore.OpIn, []any{1, 2}), `{"must":[{"key":"year","match":{"any":[1,2]}}]}`},
+		{"nin", where(vstore.OpNotIn, []any{1, 2}), `{"must":[{"must_not":[{"key":"year","match":{"any":[1,2]}}]}]}`},
+		{"exists", where(vstore.OpExists, nil), `{"must":[{"must_not":[{"is_empty":{"key":"year"}}]}]}`},
+		{"contains", where(vstore.OpContains, "brown fox"), `{"must":[{"key":"year","match":{"text":"brown fox"}}]}`},
+		{"array contains", where(vstore.OpArrayContains, "go"), `{"must":[{"key":"year","match":{"value":"go"}}]}`},
+		{"array contains any", where(vstore.OpArrayContainsAny, []string{"go", "db"}), `{"must":[{"key":"year","match":{"any":["go","db"]}}]}`},
+		{"array contains any scalar", where(vstore.OpArrayContainsAny, "go"), `{"must":[{"key":"year","match":{"any":["go"]}}]}`},
+		{"array contains all", where(vstore.OpArrayContainsAll, []any{"go", "db"}),
+			`{"must":[{"must":[{"key":"year","match":{"value":"go"}},{"key":"year","match":{"value":"db"}}]}]}`},
+		{"clauses", &vstore.Filter{
+			Must:    []vstore.Condition{{Field: "a", Operator: vstore.OpEqual, Value: "x"}},
+			Should:  []vstore.Condition{{Field: "b", Operator: vstore.OpEqual, Value: "y"}},
+			MustNot: []vstore.Condition{{Field: "c.d", Operator: vstore.OpEqual, Value: "z"}},
+		}, `{"must":[{"key":"a","match":{"value":"x"}}],"must_not":[{"key":"c.d","match":{"value":"z"}}],"should":[{"key":"b","match":{"value":"y"}}]}`},
+		{"tree", func() *vstore.Filter {
+			f := vstore.And(
+				vstore.Or(vstore.Where("a", vstore.OpEqual, "x"), vstore.Where("b", vstore.OpEqual, "y")),
+				vstore.Not(vstore.Where("c", vstore.OpLessThan, 3)),
+			)
+			return &f
+		}(), `{"must":[{"should":[{"key":"a","match":{"value":"x"}},{"key":"b","match":{"value":"y"}}]},` +
+			`{"must_not":[{"key":"c","range":{"lt":3}}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := vstqdrant.ToQdrantFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ToQdrantFilter: %v", err)
+			}
+			got, _ := json.Marshal(filter)
+			if string(got) != tt.want {
+				t.Errorf("filter = %s\nwant     %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToQdrantFilterUnsupportedOperator(t *testing.T) {
+	_, err := vstqdrant.ToQdrantFilter(&vstore.Filter{
+		Must: []vstore.Condition{{Field: "a", Operator: "regex", Value: ".*"}},
+	})
+	if err == nil || err.Code != vstqdrant.ErrInvalidInput.Code {
+		t.Fatalf("ToQdrantFilter = %v, want %s", err, vstqdrant.ErrInvalidInput.Code)
+	}
+}
+
+func TestMetricConversions(t *testing.T) {
+	for _, metric := range []vstore.Metric{vstore.MetricCosine, vstore.MetricDotProduct, vstore.MetricEuclidean} {
+		if got := vstqdrant.VstoreMetric(vstqdrant.QdrantDistance(metric)); got != metric {
+			t.Errorf("round trip of %s = %s", metric, got)
+		}
+	}
+	if got := vstqdrant.ConvertScore(1, vstore.MetricEuclidean); got != 0.5 {
+		t.Errorf("euclidean distance 1 = %v, want 0.5", got)
+	}
+	if got := vstqdrant.ConvertScore(0.8, vstore.MetricCosine); got != 0.8 {
+		t.Errorf("cosine score = %v, want 0.8", got)
+	}
+}
+
+func TestPointID(t *testing.T) {
+	if vstqdrant.PointID("doc-1") != vstqdrant.PointID("doc-1") {
+		t.Error("PointID is not stable")
+	}
+	if vstqdrant.PointID("doc-1") == vstqdrant.PointID("doc-2") {
+		t.Error("PointID collides for different IDs")
+	}
+}
@@ -0,0 +1,133 @@
+package vstqdrant
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for Qdrant provider
+	errorRegistry = errx.NewRegistry("QDRANT")
+
+	// Configuration Errors
+	ErrMissingConfig = errorRegistry.Register(
+		"MISSING_CONFIG",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Required configuration is missing",
+	)
+
+	ErrInvalidConfig = errorRegistry.Register(
+		"INVALID_CONFIG",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid configuration",
+	)
+
+	// API Errors
+	ErrAPIRequest = errorRegistry.Register(
+		"API_REQUEST_FAILED",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Qdrant API request failed",
+	)
+
+	ErrAPIResponse = errorRegistry.Register(
+		"API_RESPONSE_INVALID",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Invalid response from Qdrant API",
+	)
+
+	ErrUnauthorized = errorRegistry.Register(
+		"UNAUTHORIZED",
+		errx.TypeAuthorization,
+		http.StatusUnauthorized,
+		"Qdrant API key is missing or invalid",
+	)
+
+	ErrCollectionNotFound = errorRegistry.Register(
+		"COLLECTION_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"Qdrant collection does not exist",
+	)
+
+	// Input Errors
+	ErrInvalidVectorDimension = errorRegistry.Register(
+		"INVALID_VECTOR_DIMENSION",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Vector dimension mismatch",
+	)
+
+	ErrEmptyVectorID = errorRegistry.Register(
+		"EMPTY_VECTOR_ID",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Vector ID cannot be empty",
+	)
+
+	ErrInvalidInput = errorRegistry.Register(
+		"INVALID_INPUT",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid input parameters",
+	)
+
+	// Feature Support Errors
+	ErrFeatureNotSupported = errorRegistry.Register(
+		"FEATURE_NOT_SUPPORTED",
+		errx.TypeValidation,
+		http.StatusNotImplemented,
+		"Feature not supported by this Qdrant configuration",
+	)
+)
+
+// apiErrorResponse is the error envelope returned by Qdrant
+type apiErrorResponse struct {
+	Status struct {
+		Error string `json:"error"`
+	} `json:"status"`
+}
+
+// ParseAPIError converts a non-2xx Qdrant response into an error
+func ParseAPIError(statusCode int, body []byte) *errx.Error {
+	message := string(body)
+	var apiErr apiErrorResponse
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Status.Error != "" {
+		message = apiErr.Status.Error
+	}
+
+	var code *errx.ErrorCode
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		code = ErrUnauthorized
+	case http.StatusNotFound:
+		code = ErrCollectionNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = ErrInvalidInput
+	default:
+		code = ErrAPIRequest
+	}
+
+	return errorRegistry.New(code).
+		WithDetail("status_code", statusCode).
+		WithDetail("error", message)
+}
+
+// WrapError wraps a standard error with a Qdrant error code
+func WrapError(err error, code *errx.ErrorCode) *errx.Error {
+	if err == nil {
+		return nil
+	}
+
+	var customErr *errx.Error
+	if errx.As(err, &customErr) {
+		return customErr
+	}
+
+	return errorRegistry.NewWithCause(code, err)
+}
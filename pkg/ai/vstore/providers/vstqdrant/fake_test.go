@@ -0,0 +1,556 @@
+package vstqdrant_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Fake Qdrant: the subset of the REST API used by the provider, in memory
+// ============================================================================
+
+type fakeQdrant struct {
+	mu          sync.Mutex
+	apiKey      string
+	collections map[string]*fakeCollection
+	requests    []string
+}
+
+type fakeCollection struct {
+	size     int
+	distance string
+	indexes  []string
+	points   map[string]*fakePoint
+}
+
+type fakePoint struct {
+	dense   []float64
+	sparse  *fakeSparse
+	payload map[string]any
+}
+
+type fakeSparse struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float64 `json:"values"`
+}
+
+// newFakeQdrant starts a fake server; requests must carry apiKey when set
+func newFakeQdrant(t *testing.T, apiKey string) (*fakeQdrant, *httptest.Server) {
+	t.Helper()
+	f := &fakeQdrant{apiKey: apiKey, collections: map[string]*fakeCollection{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /collections", f.listCollections)
+	mux.HandleFunc("GET /collections/{name}", f.withCollection(f.describeCollection))
+	mux.HandleFunc("PUT /collections/{name}", f.createCollection)
+	mux.HandleFunc("DELETE /collections/{name}", f.deleteCollection)
+	mux.HandleFunc("GET /collections/{name}/exists", f.collectionExists)
+	mux.HandleFunc("PUT /collections/{name}/index", f.withCollection(f.createIndex))
+	mux.HandleFunc("PUT /collections/{name}/points", f.withCollection(f.upsertPoints))
+	mux.HandleFunc("PUT /collections/{name}/points/vectors", f.withCollection(f.updateVectors))
+	mux.HandleFunc("POST /collections/{name}/points", f.withCollection(f.retrievePoints))
+	mux.HandleFunc("POST /collections/{name}/points/delete", f.withCollection(f.deletePoints))
+	mux.HandleFunc("POST /collections/{name}/points/query", f.withCollection(f.queryPoints))
+	mux.HandleFunc("POST /collections/{name}/points/scroll", f.withCollection(f.scrollPoints))
+	mux.HandleFunc("POST /collections/{name}/points/count", f.withCollection(f.countPoints))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.apiKey != "" && r.Header.Get("api-key") != f.apiKey {
+			writeError(w, http.StatusUnauthorized, "Must provide an API key or an Authorization bearer token")
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"result": result, "status": "ok", "time": 0})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"status": map[string]any{"error": message}, "time": 0})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Format error in JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (f *fakeQdrant) withCollection(h func(http.ResponseWriter, *http.Request, *fakeCollection)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		c, ok := f.collections[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Not found: Collection `%s` doesn't exist!", name))
+			return
+		}
+		h(w, r, c)
+	}
+}
+
+// ============================================================================
+// Collections
+// ============================================================================
+
+func (f *fakeQdrant) listCollections(w http.ResponseWriter, r *http.Request) {
+	names := make([]map[string]any, 0, len(f.collections))
+	for name := range f.collections {
+		names = append(names, map[string]any{"name": name})
+	}
+	writeResult(w, map[string]any{"collections": names})
+}
+
+func (f *fakeQdrant) describeCollection(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	writeResult(w, map[string]any{
+		"status":       "green",
+		"points_count": len(c.points),
+		"config": map[string]any{"params": map[string]any{"vectors": map[string]any{
+			"dense": map[string]any{"size": c.size, "distance": c.distance},
+		}}},
+	})
+}
+
+func (f *fakeQdrant) createCollection(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := f.collections[name]; ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Wrong input: Collection `%s` already exists!", name))
+		return
+	}
+	var req struct {
+		Vectors map[string]struct {
+			Size     int    `json:"size"`
+			Distance string `json:"distance"`
+		} `json:"vectors"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	dense, ok := req.Vectors["dense"]
+	if !ok || dense.Size <= 0 {
+		writeError(w, http.StatusBadRequest, "Wrong input: missing dense vector config")
+		return
+	}
+	f.collections[name] = &fakeCollection{size: dense.Size, distance: dense.Distance, points: map[string]*fakePoint{}}
+	writeResult(w, true)
+}
+
+func (f *fakeQdrant) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	_, ok := f.collections[name]
+	delete(f.collections, name)
+	writeResult(w, ok)
+}
+
+func (f *fakeQdrant) collectionExists(w http.ResponseWriter, r *http.Request) {
+	_, ok := f.collections[r.PathValue("name")]
+	writeResult(w, map[string]any{"exists": ok})
+}
+
+func (f *fakeQdrant) createIndex(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		FieldName string `json:"field_name"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	c.indexes = append(c.indexes, req.FieldName)
+	writeResult(w, map[string]any{"status": "completed"})
+}
+
+// ============================================================================
+// Points
+// ============================================================================
+
+type fakeVectors struct {
+	Dense  []float64   `json:"dense"`
+	Sparse *fakeSparse `json:"sparse"`
+}
+
+func parsePointID(w http.ResponseWriter, id string) bool {
+	if _, err := uuid.Parse(id); err != nil {
+		writeError(w, http.StatusBadRequest, "Wrong input: point id "+id+" is not a valid UUID")
+		return false
+	}
+	return true
+}
+
+func (f *fakeQdrant) upsertPoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		Points []struct {
+			ID      string         `json:"id"`
+			Vector  fakeVectors    `json:"vector"`
+			Payload map[string]any `json:"payload"`
+		} `json:"points"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, p := range req.Points {
+		if !parsePointID(w, p.ID) {
+			return
+		}
+		if len(p.Vector.Dense) != c.size {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Wrong input: Vector dimension error: expected dim: %d, got %d", c.size, len(p.Vector.Dense)))
+			return
+		}
+	}
+	for _, p := range req.Points {
+		dense := p.Vector.Dense
+		if c.distance == "Cosine" {
+			dense = normalize(dense)
+		}
+		c.points[p.ID] = &fakePoint{dense: dense, sparse: p.Vector.Sparse, payload: p.Payload}
+	}
+	writeResult(w, map[string]any{"status": "completed"})
+}
+
+func (f *fakeQdrant) updateVectors(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		Points []struct {
+			ID     string      `json:"id"`
+			Vector fakeVectors `json:"vector"`
+		} `json:"points"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, p := range req.Points {
+		if _, ok := c.points[p.ID]; !ok {
+			writeError(w, http.StatusNotFound, "Not found: No point with id "+p.ID+" found")
+			return
+		}
+	}
+	for _, p := range req.Points {
+		if p.Vector.Sparse != nil {
+			c.points[p.ID].sparse = p.Vector.Sparse
+		}
+	}
+	writeResult(w, map[string]any{"status": "completed"})
+}
+
+func (f *fakeQdrant) retrievePoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		IDs         []string `json:"ids"`
+		WithPayload bool     `json:"with_payload"`
+		WithVector  bool     `json:"with_vector"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	out := []map[string]any{}
+	for _, id := range req.IDs {
+		if p, ok := c.points[id]; ok {
+			out = append(out, p.render(id, 0, req.WithPayload, req.WithVector))
+		}
+	}
+	writeResult(w, out)
+}
+
+func (f *fakeQdrant) deletePoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		Points []string `json:"points"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, id := range req.Points {
+		delete(c.points, id)
+	}
+	writeResult(w, map[string]any{"status": "completed"})
+}
+
+func (f *fakeQdrant) queryPoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		Query       json.RawMessage `json:"query"`
+		Using       string          `json:"using"`
+		Filter      map[string]any  `json:"filter"`
+		Limit       int             `json:"limit"`
+		WithPayload bool            `json:"with_payload"`
+		WithVector  bool            `json:"with_vector"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	var hits []scored
+	switch req.Using {
+	case "dense":
+		var query []float64
+		if err := json.Unmarshal(req.Query, &query); err != nil || len(query) != c.size {
+			writeError(w, http.StatusBadRequest, "Wrong input: bad dense query")
+			return
+		}
+		if c.distance == "Cosine" {
+			query = normalize(query)
+		}
+		for id, p := range c.points {
+			if matchesFilter(p.payload, req.Filter) {
+				hits = append(hits, scored{id, score(c.distance, query, p.dense)})
+			}
+		}
+	case "sparse":
+		var query fakeSparse
+		if err := json.Unmarshal(req.Query, &query); err != nil {
+			writeError(w, http.StatusBadRequest, "Wrong input: bad sparse query")
+			return
+		}
+		for id, p := range c.points {
+			if p.sparse != nil && matchesFilter(p.payload, req.Filter) {
+				hits = append(hits, scored{id, sparseDot(query, *p.sparse)})
+			}
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "Wrong input: unknown vector "+req.Using)
+		return
+	}
+
+	// Euclid is a distance, the others similarities
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			if c.distance == "Euclid" && req.Using == "dense" {
+				return hits[i].score < hits[j].score
+			}
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].id < hits[j].id
+	})
+	if req.Limit > 0 && len(hits) > req.Limit {
+		hits = hits[:req.Limit]
+	}
+
+	points := make([]map[string]any, len(hits))
+	for i, h := range hits {
+		points[i] = c.points[h.id].render(h.id, h.score, req.WithPayload, req.WithVector)
+	}
+	writeResult(w, map[string]any{"points": points})
+}
+
+func (f *fakeQdrant) scrollPoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	var req struct {
+		Limit       int            `json:"limit"`
+		Offset      string         `json:"offset"`
+		Filter      map[string]any `json:"filter"`
+		WithPayload bool           `json:"with_payload"`
+		WithVector  bool           `json:"with_vector"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var ids []string
+	for id, p := range c.points {
+		if id >= req.Offset && matchesFilter(p.payload, req.Filter) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var next any
+	if len(ids) > req.Limit {
+		next = ids[req.Limit]
+		ids = ids[:req.Limit]
+	}
+	points := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		points[i] = c.points[id].render(id, 0, req.WithPayload, req.WithVector)
+	}
+	writeResult(w, map[string]any{"points": points, "next_page_offset": next})
+}
+
+func (f *fakeQdrant) countPoints(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	writeResult(w, map[string]any{"count": len(c.points)})
+}
+
+func (p *fakePoint) render(id string, score float64, withPayload, withVector bool) map[string]any {
+	out := map[string]any{"id": id, "score": score}
+	if withPayload {
+		out["payload"] = p.payload
+	}
+	if withVector {
+		vector := map[string]any{"dense": p.dense}
+		if p.sparse != nil {
+			vector["sparse"] = p.sparse
+		}
+		out["vector"] = vector
+	}
+	return out
+}
+
+// ============================================================================
+// Scoring
+// ============================================================================
+
+func normalize(v []float64) []float64 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+func score(distance string, a, b []float64) float64 {
+	var dot, dist float64
+	for i := range a {
+		dot += a[i] * b[i]
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	if distance == "Euclid" {
+		return math.Sqrt(dist)
+	}
+	return dot
+}
+
+func sparseDot(a, b fakeSparse) float64 {
+	var dot float64
+	for i, ai := range a.Indices {
+		for j, bj := range b.Indices {
+			if ai == bj {
+				dot += a.Values[i] * b.Values[j]
+			}
+		}
+	}
+	return dot
+}
+
+// ============================================================================
+// Filters: Qdrant semantics for the conditions the provider emits
+// ============================================================================
+
+// matchesFilter evaluates must (all), should (at least one) and must_not
+// (none) clauses
+func matchesFilter(payload map[string]any, filter map[string]any) bool {
+	if filter == nil {
+		return true
+	}
+	clause := func(key string) []any {
+		list, _ := filter[key].([]any)
+		return list
+	}
+	for _, c := range clause("must") {
+		if !matchesCondition(payload, c) {
+			return false
+		}
+	}
+	if should := clause("should"); len(should) > 0 && !slices.ContainsFunc(should, func(c any) bool {
+		return matchesCondition(payload, c)
+	}) {
+		return false
+	}
+	for _, c := range clause("must_not") {
+		if matchesCondition(payload, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesCondition(payload map[string]any, condition any) bool {
+	c, _ := condition.(map[string]any)
+	if empty, ok := c["is_empty"].(map[string]any); ok {
+		values, found := lookup(payload, empty["key"].(string))
+		return !found || len(values) == 0
+	}
+	key, ok := c["key"].(string)
+	if !ok {
+		return matchesFilter(payload, c) // Nested filter
+	}
+	values, _ := lookup(payload, key)
+
+	if m, ok := c["match"].(map[string]any); ok {
+		switch {
+		case m["value"] != nil:
+			return slices.ContainsFunc(values, func(v any) bool { return reflect.DeepEqual(v, m["value"]) })
+		case m["any"] != nil:
+			wanted, _ := m["any"].([]any)
+			return slices.ContainsFunc(values, func(v any) bool {
+				return slices.ContainsFunc(wanted, func(w any) bool { return reflect.DeepEqual(v, w) })
+			})
+		case m["text"] != nil:
+			return slices.ContainsFunc(values, func(v any) bool {
+				s, _ := v.(string)
+				words := strings.Fields(strings.ToLower(s))
+				for _, word := range strings.Fields(strings.ToLower(m["text"].(string))) {
+					if !slices.Contains(words, word) {
+						return false
+					}
+				}
+				return true
+			})
+		}
+		return false
+	}
+
+	if bounds, ok := c["range"].(map[string]any); ok {
+		return slices.ContainsFunc(values, func(v any) bool {
+			x, ok := v.(float64)
+			if !ok {
+				return false
+			}
+			for op, bound := range bounds {
+				b := bound.(float64)
+				if op == "gt" && !(x > b) || op == "gte" && !(x >= b) || op == "lt" && !(x < b) || op == "lte" && !(x <= b) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	return false
+}
+
+// lookup resolves a dotted key; list values are flattened so conditions
+// match any element
+func lookup(payload map[string]any, key string) ([]any, bool) {
+	var current any = payload
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	switch v := current.(type) {
+	case nil:
+		return nil, true
+	case []any:
+		return v, true
+	default:
+		return []any{v}, true
+	}
+}
@@ -0,0 +1,72 @@
+package vstqdrant
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// ProviderOption configures the Qdrant provider
+type ProviderOption func(*QdrantProvider)
+
+// WithAPIKey sets the API key sent in the api-key header
+func WithAPIKey(apiKey string) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(client *http.Client) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.httpClient = client
+	}
+}
+
+// WithTimeout sets the request timeout
+func WithTimeout(timeout time.Duration) ProviderOption {
+	return func(p *QdrantProvider) {
+		if p.httpClient == nil {
+			p.httpClient = &http.Client{}
+		}
+		p.httpClient.Timeout = timeout
+	}
+}
+
+// WithDefaultCollection sets the collection used when no namespace is given
+func WithDefaultCollection(name string) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.defaultCollection = name
+	}
+}
+
+// WithMetric sets the distance metric for collections created by the provider
+func WithMetric(metric vstore.Metric) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.metric = metric
+	}
+}
+
+// WithAutoCreateCollection creates missing collections on first upsert
+func WithAutoCreateCollection(auto bool) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.autoCreate = auto
+	}
+}
+
+// WithSparseVectors adds a sparse vector to created collections, enabling
+// UpsertSparse, QuerySparse and sparse hybrid search (enabled by default)
+func WithSparseVectors(enabled bool) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.sparseVectors = enabled
+	}
+}
+
+// WithTextField sets the payload field indexed for full-text matching
+// (default: "content")
+func WithTextField(field string) ProviderOption {
+	return func(p *QdrantProvider) {
+		p.textField = field
+	}
+}
@@ -0,0 +1,750 @@
+package vstqdrant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+const (
+	DefaultCollection = "vectors"
+	DefaultTimeout    = 30 * time.Second
+	DefaultBatchSize  = 100
+	DefaultTextField  = "content"
+
+	// DenseVectorName and SparseVectorName are the named vectors of
+	// collections created by the provider
+	DenseVectorName  = "dense"
+	SparseVectorName = "sparse"
+
+	// IDPayloadKey is the payload field holding the original vstore ID
+	IDPayloadKey = "vstore_id"
+)
+
+// Compile-time interface checks
+var (
+	_ vstore.VectorStorer        = (*QdrantProvider)(nil)
+	_ vstore.MetadataFilterer    = (*QdrantProvider)(nil)
+	_ vstore.BatchProcessor      = (*QdrantProvider)(nil)
+	_ vstore.NamespaceManager    = (*QdrantProvider)(nil)
+	_ vstore.IndexManager        = (*QdrantProvider)(nil)
+	_ vstore.HybridSearcher      = (*QdrantProvider)(nil)
+	_ vstore.SparseVectorSupport = (*QdrantProvider)(nil)
+	_ vstore.StatisticsProvider  = (*QdrantProvider)(nil)
+	_ vstore.VectorLister        = (*QdrantProvider)(nil)
+)
+
+// QdrantProvider implements vector store for Qdrant over its REST API.
+// Each namespace is a collection; the empty namespace maps to the default
+// collection.
+type QdrantProvider struct {
+	client *HTTPClient
+
+	// Configuration
+	baseURL           string
+	apiKey            string
+	httpClient        *http.Client
+	dimension         int
+	metric            vstore.Metric
+	defaultCollection string
+	autoCreate        bool
+	sparseVectors     bool
+	textField         string
+
+	// Collections known to exist
+	collections sync.Map
+}
+
+// NewQdrantProvider creates a new Qdrant provider
+//
+// Example:
+//
+//	provider, err := vstqdrant.NewQdrantProvider("http://localhost:6333", 1536,
+//	    vstqdrant.WithAPIKey(os.Getenv("QDRANT_API_KEY")),
+//	)
+//	client := vstore.NewClient(provider)
+func NewQdrantProvider(baseURL string, dimension int, opts ...ProviderOption) (*QdrantProvider, *errx.Error) {
+	if baseURL == "" {
+		return nil, errorRegistry.New(ErrMissingConfig).
+			WithDetail("error", "base URL is required")
+	}
+
+	if dimension <= 0 {
+		return nil, errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "dimension must be positive")
+	}
+
+	provider := &QdrantProvider{
+		baseURL:           baseURL,
+		dimension:         dimension,
+		metric:            vstore.MetricCosine,
+		defaultCollection: DefaultCollection,
+		autoCreate:        true,
+		sparseVectors:     true,
+		textField:         DefaultTextField,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	provider.client = NewHTTPClient(provider.baseURL, provider.apiKey, provider.httpClient)
+	return provider, nil
+}
+
+// ============================================================================
+// VectorStorer Implementation
+// ============================================================================
+
+// Upsert inserts or updates vectors
+func (p *QdrantProvider) Upsert(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	if err := p.validateVectors(vectors); err != nil {
+		return err
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	collection := p.collection(options.Namespace)
+	if err := p.ensureCollection(ctx, collection); err != nil {
+		return err
+	}
+
+	points := make([]point, len(vectors))
+	for i, v := range vectors {
+		points[i] = toPoint(v)
+	}
+
+	if err := p.client.Do(ctx, http.MethodPut, p.pointsPath(collection, "?wait=true"), map[string]any{"points": points}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Query performs similarity search
+func (p *QdrantProvider) Query(ctx context.Context, vector []float32, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if len(vector) != p.dimension {
+		return nil, errorRegistry.New(ErrInvalidVectorDimension).
+			WithDetail("expected", p.dimension).
+			WithDetail("got", len(vector))
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	filter, err := ToQdrantFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := p.query(ctx, options, queryRequest{
+		Query:  vector,
+		Using:  DenseVectorName,
+		Filter: filter,
+	}, p.metric)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vstore.QueryResult{Matches: matches, Namespace: options.Namespace}, nil
+}
+
+// Delete removes vectors by IDs
+func (p *QdrantProvider) Delete(ctx context.Context, ids []string, opts ...vstore.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	collection := p.collection(options.Namespace)
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = PointID(id)
+	}
+
+	err := p.client.Do(ctx, http.MethodPost, p.pointsPath(collection, "/delete?wait=true"), map[string]any{"points": pointIDs}, nil)
+	if isNotFound(err) {
+		return nil // Nothing to delete
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Fetch retrieves vectors by IDs
+func (p *QdrantProvider) Fetch(ctx context.Context, ids []string, opts ...vstore.Option) ([]vstore.Vector, error) {
+	if len(ids) == 0 {
+		return []vstore.Vector{}, nil
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	collection := p.collection(options.Namespace)
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = PointID(id)
+	}
+
+	var points []retrievedPoint
+	err := p.client.Do(ctx, http.MethodPost, p.pointsPath(collection, ""), map[string]any{
+		"ids":          pointIDs,
+		"with_payload": true,
+		"with_vector":  true,
+	}, &points)
+	if isNotFound(err) {
+		return []vstore.Vector{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([]vstore.Vector, 0, len(points))
+	for _, pt := range points {
+		v, convErr := toVector(pt)
+		if convErr != nil {
+			return nil, WrapError(convErr, ErrAPIResponse)
+		}
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
+
+// ============================================================================
+// MetadataFilterer Implementation
+// ============================================================================
+
+// QueryWithFilter performs filtered similarity search
+func (p *QdrantProvider) QueryWithFilter(ctx context.Context, vector []float32, filter vstore.Filter, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	opts = append(slices.Clone(opts), vstore.WithFilter(&filter))
+	return p.Query(ctx, vector, opts...)
+}
+
+// ============================================================================
+// BatchProcessor Implementation
+// ============================================================================
+
+// UpsertBatch upserts vectors in batches
+func (p *QdrantProvider) UpsertBatch(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) (*vstore.BatchResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	result := &vstore.BatchResult{}
+	for batch := range slices.Chunk(vectors, batchSize) {
+		if err := p.Upsert(ctx, batch, opts...); err != nil {
+			result.FailedCount += len(batch)
+			for _, v := range batch {
+				result.Errors = append(result.Errors, vstore.BatchError{
+					ID:    v.ID,
+					Error: err.Error(),
+				})
+			}
+		} else {
+			result.SuccessCount += len(batch)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteBatch deletes multiple vectors
+func (p *QdrantProvider) DeleteBatch(ctx context.Context, ids []string, opts ...vstore.Option) (*vstore.BatchResult, error) {
+	if err := p.Delete(ctx, ids, opts...); err != nil {
+		return &vstore.BatchResult{
+			FailedCount: len(ids),
+		}, err
+	}
+
+	return &vstore.BatchResult{
+		SuccessCount: len(ids),
+	}, nil
+}
+
+// ============================================================================
+// NamespaceManager Implementation
+// ============================================================================
+
+// ListNamespaces returns all collections
+func (p *QdrantProvider) ListNamespaces(ctx context.Context) ([]string, error) {
+	var result struct {
+		Collections []struct {
+			Name string `json:"name"`
+		} `json:"collections"`
+	}
+	if err := p.client.Do(ctx, http.MethodGet, "/collections", nil, &result); err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, len(result.Collections))
+	for i, c := range result.Collections {
+		namespaces[i] = c.Name
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
+}
+
+// CreateNamespace creates a collection with the provider's dimension and metric
+func (p *QdrantProvider) CreateNamespace(ctx context.Context, namespace string) error {
+	if err := p.createCollection(ctx, p.collection(namespace), p.dimension, p.metric); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteNamespace deletes a collection and all its vectors
+func (p *QdrantProvider) DeleteNamespace(ctx context.Context, namespace string) error {
+	return p.DeleteIndex(ctx, p.collection(namespace))
+}
+
+// ============================================================================
+// IndexManager Implementation
+// ============================================================================
+
+// CreateIndex creates a collection
+func (p *QdrantProvider) CreateIndex(ctx context.Context, config vstore.IndexConfig) error {
+	if config.Name == "" {
+		return errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "index name is required")
+	}
+
+	dimension := config.Dimension
+	if dimension <= 0 {
+		dimension = p.dimension
+	}
+	metric := config.Metric
+	if metric == "" {
+		metric = p.metric
+	}
+
+	if err := p.createCollection(ctx, config.Name, dimension, metric); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteIndex deletes a collection
+func (p *QdrantProvider) DeleteIndex(ctx context.Context, indexName string) error {
+	p.collections.Delete(indexName)
+
+	err := p.client.Do(ctx, http.MethodDelete, "/collections/"+url.PathEscape(indexName), nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DescribeIndex returns collection metadata
+func (p *QdrantProvider) DescribeIndex(ctx context.Context, indexName string) (*vstore.IndexInfo, error) {
+	var info collectionInfo
+	if err := p.client.Do(ctx, http.MethodGet, "/collections/"+url.PathEscape(indexName), nil, &info); err != nil {
+		return nil, err
+	}
+
+	dense := info.Config.Params.Vectors[DenseVectorName]
+	return &vstore.IndexInfo{
+		Name:             indexName,
+		Dimension:        dense.Size,
+		Metric:           VstoreMetric(dense.Distance),
+		TotalVectorCount: info.PointsCount,
+		Status:           info.Status,
+		Host:             p.baseURL,
+	}, nil
+}
+
+// ListIndexes returns all collections
+func (p *QdrantProvider) ListIndexes(ctx context.Context) ([]vstore.IndexInfo, error) {
+	names, err := p.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]vstore.IndexInfo, 0, len(names))
+	for _, name := range names {
+		info, err := p.DescribeIndex(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, *info)
+	}
+	return indexes, nil
+}
+
+// ============================================================================
+// HybridSearcher Implementation
+// ============================================================================
+
+// HybridQuery combines dense similarity with a keyword signal and fuses the
+// two rankings with vstore.FuseResults. The keyword side is a sparse vector
+// search when the SparseValues option is set; otherwise it is a dense
+// search restricted to points whose text field matches the query words.
+func (p *QdrantProvider) HybridQuery(ctx context.Context, vector []float32, query string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	candidateOpts := append(slices.Clone(opts),
+		vstore.WithTopK(options.HybridCandidateCount()),
+		vstore.WithMinScore(0),
+	)
+
+	var denseMatches []vstore.Match
+	if options.HybridAlpha > 0 {
+		result, err := p.Query(ctx, vector, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+		denseMatches = result.Matches
+	}
+
+	var keywordMatches []vstore.Match
+	if options.HybridAlpha < 1 {
+		var result *vstore.QueryResult
+		var err error
+		switch {
+		case options.SparseValues != nil:
+			result, err = p.QuerySparse(ctx, *options.SparseValues, candidateOpts...)
+		case strings.TrimSpace(query) != "":
+			result, err = p.textQuery(ctx, vector, query, candidateOpts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			keywordMatches = result.Matches
+		}
+	}
+
+	return &vstore.QueryResult{
+		Matches:   vstore.FuseResults(denseMatches, keywordMatches, options),
+		Namespace: options.Namespace,
+	}, nil
+}
+
+// textQuery ranks points matching the query words by dense similarity
+func (p *QdrantProvider) textQuery(ctx context.Context, vector []float32, text string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	filter, err := ToQdrantFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filter = withCondition(filter, map[string]any{
+		"key":   p.textField,
+		"match": map[string]any{"text": text},
+	})
+
+	matches, err := p.query(ctx, options, queryRequest{
+		Query:  vector,
+		Using:  DenseVectorName,
+		Filter: filter,
+	}, p.metric)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vstore.QueryResult{Matches: matches, Namespace: options.Namespace}, nil
+}
+
+// ============================================================================
+// SparseVectorSupport Implementation
+// ============================================================================
+
+// UpsertSparse sets the sparse vectors of existing points
+func (p *QdrantProvider) UpsertSparse(ctx context.Context, ids []string, vectors []vstore.SparseVector, opts ...vstore.Option) error {
+	if !p.sparseVectors {
+		return errorRegistry.New(ErrFeatureNotSupported).
+			WithDetail("error", "sparse vectors not enabled")
+	}
+	if len(ids) != len(vectors) {
+		return errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "ids and vectors length mismatch")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	collection := p.collection(options.Namespace)
+
+	points := make([]point, len(ids))
+	for i, id := range ids {
+		points[i] = point{
+			ID:     PointID(id),
+			Vector: map[string]any{SparseVectorName: toSparse(vectors[i])},
+		}
+	}
+
+	if err := p.client.Do(ctx, http.MethodPut, p.pointsPath(collection, "/vectors?wait=true"), map[string]any{"points": points}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// QuerySparse ranks points by the dot product of their sparse vectors
+func (p *QdrantProvider) QuerySparse(ctx context.Context, vector vstore.SparseVector, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if !p.sparseVectors {
+		return nil, errorRegistry.New(ErrFeatureNotSupported).
+			WithDetail("error", "sparse vectors not enabled")
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	filter, err := ToQdrantFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sparse scores are dot products regardless of the dense metric
+	matches, err := p.query(ctx, options, queryRequest{
+		Query:  toSparse(vector),
+		Using:  SparseVectorName,
+		Filter: filter,
+	}, vstore.MetricDotProduct)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vstore.QueryResult{Matches: matches, Namespace: options.Namespace}, nil
+}
+
+// ============================================================================
+// StatisticsProvider Implementation
+// ============================================================================
+
+// GetStatistics returns vector counts for the requested namespace, or for
+// every collection when no namespace is given
+func (p *QdrantProvider) GetStatistics(ctx context.Context, opts ...vstore.Option) (*vstore.Statistics, error) {
+	options := vstore.ApplyOptions(opts...)
+
+	names := []string{options.Namespace}
+	if options.Namespace == "" {
+		all, err := p.ListNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = all
+	}
+
+	stats := &vstore.Statistics{Dimension: p.dimension}
+	for _, name := range names {
+		var count struct {
+			Count int64 `json:"count"`
+		}
+		err := p.client.Do(ctx, http.MethodPost, p.pointsPath(name, "/count"), map[string]any{"exact": true}, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.TotalVectorCount += count.Count
+		stats.Namespaces = append(stats.Namespaces, vstore.NamespaceStats{
+			Name:        name,
+			VectorCount: count.Count,
+		})
+	}
+
+	return stats, nil
+}
+
+// ============================================================================
+// VectorLister Implementation
+// ============================================================================
+
+// List scrolls through the collection in point ID order; the cursor is the
+// point ID that starts the next page
+func (p *QdrantProvider) List(ctx context.Context, cursor string, limit int, opts ...vstore.Option) (*vstore.ListResult, error) {
+	if limit <= 0 {
+		return nil, errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "limit must be positive").
+			WithDetail("limit", limit)
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	filter, err := ToQdrantFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]any{
+		"limit":        limit,
+		"with_payload": true,
+		"with_vector":  options.IncludeValues,
+	}
+	if filter != nil {
+		req["filter"] = filter
+	}
+	if cursor != "" {
+		req["offset"] = cursor
+	}
+
+	var page struct {
+		Points         []retrievedPoint `json:"points"`
+		NextPageOffset any              `json:"next_page_offset"`
+	}
+	err = p.client.Do(ctx, http.MethodPost, p.pointsPath(p.collection(options.Namespace), "/scroll"), req, &page)
+	if isNotFound(err) {
+		return &vstore.ListResult{Vectors: []vstore.Vector{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &vstore.ListResult{Vectors: make([]vstore.Vector, 0, len(page.Points))}
+	for _, pt := range page.Points {
+		v, convErr := toVector(pt)
+		if convErr != nil {
+			return nil, WrapError(convErr, ErrAPIResponse)
+		}
+		result.Vectors = append(result.Vectors, v)
+	}
+	if page.NextPageOffset != nil {
+		result.NextCursor = fmt.Sprint(page.NextPageOffset)
+	}
+
+	return result, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// collection returns the collection backing a namespace
+func (p *QdrantProvider) collection(namespace string) string {
+	if namespace == "" {
+		return p.defaultCollection
+	}
+	return namespace
+}
+
+func (p *QdrantProvider) pointsPath(collection, suffix string) string {
+	return "/collections/" + url.PathEscape(collection) + "/points" + suffix
+}
+
+// query runs a points query and converts the results
+func (p *QdrantProvider) query(ctx context.Context, options *vstore.Options, req queryRequest, metric vstore.Metric) ([]vstore.Match, *errx.Error) {
+	req.Limit = options.TopK
+	req.WithPayload = true
+	req.WithVector = options.IncludeValues
+
+	var result queryResponse
+	err := p.client.Do(ctx, http.MethodPost, p.pointsPath(p.collection(options.Namespace), "/query"), req, &result)
+	if isNotFound(err) {
+		return []vstore.Match{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]vstore.Match, 0, len(result.Points))
+	for _, pt := range result.Points {
+		match, convErr := toMatch(pt, metric, options.IncludeValues, options.IncludeMetadata)
+		if convErr != nil {
+			return nil, WrapError(convErr, ErrAPIResponse)
+		}
+		if options.MeetsMinScore(match.Score) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+// ensureCollection creates the collection on first use when auto-create is enabled
+func (p *QdrantProvider) ensureCollection(ctx context.Context, name string) *errx.Error {
+	if _, ok := p.collections.Load(name); ok {
+		return nil
+	}
+
+	var result struct {
+		Exists bool `json:"exists"`
+	}
+	if err := p.client.Do(ctx, http.MethodGet, "/collections/"+url.PathEscape(name)+"/exists", nil, &result); err != nil {
+		return err
+	}
+
+	if !result.Exists {
+		if !p.autoCreate {
+			return errorRegistry.New(ErrCollectionNotFound).
+				WithDetail("collection", name)
+		}
+		if err := p.createCollection(ctx, name, p.dimension, p.metric); err != nil {
+			return err
+		}
+	}
+
+	p.collections.Store(name, true)
+	return nil
+}
+
+// createCollection creates a collection with a named dense vector, an
+// optional sparse vector and a full-text index on the text field
+func (p *QdrantProvider) createCollection(ctx context.Context, name string, dimension int, metric vstore.Metric) *errx.Error {
+	body := map[string]any{
+		"vectors": map[string]any{
+			DenseVectorName: map[string]any{
+				"size":     dimension,
+				"distance": QdrantDistance(metric),
+			},
+		},
+	}
+	if p.sparseVectors {
+		body["sparse_vectors"] = map[string]any{SparseVectorName: map[string]any{}}
+	}
+
+	path := "/collections/" + url.PathEscape(name)
+	if err := p.client.Do(ctx, http.MethodPut, path, body, nil); err != nil {
+		return err
+	}
+
+	if p.textField != "" {
+		index := map[string]any{
+			"field_name": p.textField,
+			"field_schema": map[string]any{
+				"type":      "text",
+				"tokenizer": "word",
+				"lowercase": true,
+			},
+		}
+		if err := p.client.Do(ctx, http.MethodPut, path+"/index?wait=true", index, nil); err != nil {
+			return err
+		}
+	}
+
+	p.collections.Store(name, true)
+	return nil
+}
+
+func (p *QdrantProvider) validateVectors(vectors []vstore.Vector) *errx.Error {
+	for i, v := range vectors {
+		if v.ID == "" {
+			return errorRegistry.New(ErrEmptyVectorID).WithDetail("index", i)
+		}
+		if len(v.Values) != p.dimension {
+			return errorRegistry.New(ErrInvalidVectorDimension).
+				WithDetail("expected", p.dimension).
+				WithDetail("got", len(v.Values)).
+				WithDetail("vector_id", v.ID)
+		}
+	}
+	return nil
+}
+
+func isNotFound(err *errx.Error) bool {
+	return err != nil && err.Code == ErrCollectionNotFound.Code
+}
+
+// String describes the provider
+func (p *QdrantProvider) String() string {
+	return fmt.Sprintf("qdrant(%s, dim=%d, metric=%s)", p.baseURL, p.dimension, p.metric)
+}
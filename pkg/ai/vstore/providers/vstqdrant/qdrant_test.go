@@ -0,0 +1,181 @@
+package vstqdrant_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstqdrant"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/vstoretest"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// TestConformance runs against the Qdrant server in QDRANT_TEST_URL
+func TestConformance(t *testing.T) {
+	baseURL := os.Getenv("QDRANT_TEST_URL")
+	if baseURL == "" {
+		t.Skip("QDRANT_TEST_URL not set")
+	}
+
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		collection := fmt.Sprintf("vstoretest_%d", time.Now().UnixNano())
+		provider, err := vstqdrant.NewQdrantProvider(baseURL, dimension,
+			vstqdrant.WithAPIKey(os.Getenv("QDRANT_TEST_API_KEY")),
+			vstqdrant.WithDefaultCollection(collection),
+			vstqdrant.WithMetric(metric),
+		)
+		if err != nil {
+			t.Fatalf("NewQdrantProvider: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = provider.DeleteIndex(context.Background(), collection)
+		})
+		return provider
+	})
+}
+
+// TestConformanceFake runs the suite against the in-memory fake of the REST
+// API, so the request shapes and converters are covered without a server
+func TestConformanceFake(t *testing.T) {
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		_, server := newFakeQdrant(t, "")
+		provider, err := vstqdrant.NewQdrantProvider(server.URL, dimension, vstqdrant.WithMetric(metric))
+		if err != nil {
+			t.Fatalf("NewQdrantProvider: %v", err)
+		}
+		return provider
+	})
+}
+
+func newFakeProvider(t *testing.T, opts ...vstqdrant.ProviderOption) (*fakeQdrant, *vstqdrant.QdrantProvider) {
+	t.Helper()
+	fake, server := newFakeQdrant(t, "secret")
+	opts = append([]vstqdrant.ProviderOption{vstqdrant.WithAPIKey("secret")}, opts...)
+	provider, err := vstqdrant.NewQdrantProvider(server.URL, 2, opts...)
+	if err != nil {
+		t.Fatalf("NewQdrantProvider: %v", err)
+	}
+	return fake, provider
+}
+
+func errorCode(err error) string {
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+func TestCollectionSetup(t *testing.T) {
+	ctx := context.Background()
+	fake, provider := newFakeProvider(t)
+
+	vectors := []vstore.Vector{{ID: "doc-1", Values: []float32{1, 0}, Metadata: map[string]any{"content": "hello"}}}
+	if err := provider.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := provider.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("second Upsert: %v", err)
+	}
+
+	collection := fake.collections[vstqdrant.DefaultCollection]
+	if collection == nil {
+		t.Fatal("default collection was not created")
+	}
+	if !slices.Equal(collection.indexes, []string{vstqdrant.DefaultTextField}) {
+		t.Errorf("indexes = %v, want a text index on %s", collection.indexes, vstqdrant.DefaultTextField)
+	}
+	if _, ok := collection.points[vstqdrant.PointID("doc-1")]; !ok {
+		t.Error("point was not stored under its derived UUID")
+	}
+
+	// The collection is only checked once
+	exists := 0
+	for _, req := range fake.requests {
+		if req == "GET /collections/"+vstqdrant.DefaultCollection+"/exists" {
+			exists++
+		}
+	}
+	if exists != 1 {
+		t.Errorf("checked the collection %d times, want 1", exists)
+	}
+}
+
+func TestAutoCreateDisabled(t *testing.T) {
+	_, provider := newFakeProvider(t, vstqdrant.WithAutoCreateCollection(false))
+	err := provider.Upsert(context.Background(), []vstore.Vector{{ID: "a", Values: []float32{1, 0}}})
+	if errorCode(err) != vstqdrant.ErrCollectionNotFound.Code {
+		t.Fatalf("Upsert = %v, want %s", err, vstqdrant.ErrCollectionNotFound.Code)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, server := newFakeQdrant(t, "secret")
+	provider, err := vstqdrant.NewQdrantProvider(server.URL, 2, vstqdrant.WithAPIKey("wrong"))
+	if err != nil {
+		t.Fatalf("NewQdrantProvider: %v", err)
+	}
+	if _, err := provider.ListNamespaces(context.Background()); errorCode(err) != vstqdrant.ErrUnauthorized.Code {
+		t.Fatalf("ListNamespaces = %v, want %s", err, vstqdrant.ErrUnauthorized.Code)
+	}
+}
+
+func TestHybridQueryFake(t *testing.T) {
+	ctx := context.Background()
+	_, provider := newFakeProvider(t)
+
+	vectors := []vstore.Vector{
+		{ID: "a", Values: []float32{1, 0}, Metadata: map[string]any{"content": "go channels"}},
+		{ID: "b", Values: []float32{0.8, 0.6}, Metadata: map[string]any{"content": "rust ownership"}},
+		{ID: "c", Values: []float32{0, 1}, Metadata: map[string]any{"content": "rust lifetimes"}},
+	}
+	if err := provider.Upsert(ctx, vectors); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	ids := func(result *vstore.QueryResult) []string {
+		var out []string
+		for _, m := range result.Matches {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	// The keyword side only ranks points whose content matches the words
+	result, err := provider.HybridQuery(ctx, []float32{1, 0}, "rust", vstore.WithHybridAlpha(0))
+	if err != nil {
+		t.Fatalf("HybridQuery: %v", err)
+	}
+	if got := ids(result); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("keyword matches = %v, want [b c]", got)
+	}
+
+	// With sparse values the keyword side is a sparse search
+	sparse := []vstore.SparseVector{
+		{Indices: []uint32{1}, Values: []float32{0.2}},
+		{Indices: []uint32{1, 2}, Values: []float32{0.5, 1}},
+	}
+	if err := provider.UpsertSparse(ctx, []string{"a", "c"}, sparse); err != nil {
+		t.Fatalf("UpsertSparse: %v", err)
+	}
+	query := vstore.SparseVector{Indices: []uint32{2}, Values: []float32{1}}
+	result, err = provider.HybridQuery(ctx, []float32{1, 0}, "", vstore.WithHybridAlpha(0), vstore.WithSparseValues(&query))
+	if err != nil {
+		t.Fatalf("HybridQuery sparse: %v", err)
+	}
+	if got := ids(result); !slices.Equal(got, []string{"c", "a"}) {
+		t.Errorf("sparse matches = %v, want [c a]", got)
+	}
+
+	result, err = provider.HybridQuery(ctx, []float32{1, 0}, "rust")
+	if err != nil {
+		t.Fatalf("HybridQuery fused: %v", err)
+	}
+	if got := ids(result); len(got) != 3 || got[0] != "b" {
+		t.Errorf("fused matches = %v, want b first", got)
+	}
+}
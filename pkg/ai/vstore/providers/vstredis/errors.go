@@ -0,0 +1,100 @@
+package vstredis
+
+import (
+	"net/http"
+
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+var (
+	// Error registry for Redis vector provider
+	errorRegistry = errx.NewRegistry("VSTREDIS")
+
+	// Configuration Errors
+	ErrMissingConfig = errorRegistry.Register(
+		"MISSING_CONFIG",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Required configuration is missing",
+	)
+
+	ErrInvalidConfig = errorRegistry.Register(
+		"INVALID_CONFIG",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid configuration",
+	)
+
+	// Redis Errors
+	ErrCommandFailed = errorRegistry.Register(
+		"COMMAND_FAILED",
+		errx.TypeExternal,
+		http.StatusInternalServerError,
+		"Redis command failed",
+	)
+
+	ErrIndexNotFound = errorRegistry.Register(
+		"INDEX_NOT_FOUND",
+		errx.TypeNotFound,
+		http.StatusNotFound,
+		"RediSearch index does not exist",
+	)
+
+	ErrInvalidReply = errorRegistry.Register(
+		"INVALID_REPLY",
+		errx.TypeExternal,
+		http.StatusBadGateway,
+		"Unexpected reply from Redis",
+	)
+
+	// Input Errors
+	ErrInvalidVectorDimension = errorRegistry.Register(
+		"INVALID_VECTOR_DIMENSION",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Vector dimension mismatch",
+	)
+
+	ErrEmptyVectorID = errorRegistry.Register(
+		"EMPTY_VECTOR_ID",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Vector ID cannot be empty",
+	)
+
+	ErrInvalidInput = errorRegistry.Register(
+		"INVALID_INPUT",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Invalid input parameters",
+	)
+
+	ErrUnsupportedFilter = errorRegistry.Register(
+		"UNSUPPORTED_FILTER",
+		errx.TypeValidation,
+		http.StatusBadRequest,
+		"Filter cannot be expressed on the RediSearch index",
+	)
+
+	// Feature Support Errors
+	ErrFeatureNotSupported = errorRegistry.Register(
+		"FEATURE_NOT_SUPPORTED",
+		errx.TypeValidation,
+		http.StatusNotImplemented,
+		"Feature not supported by this Redis configuration",
+	)
+)
+
+// WrapError wraps a standard error with a Redis vector error code
+func WrapError(err error, code *errx.ErrorCode) *errx.Error {
+	if err == nil {
+		return nil
+	}
+
+	var customErr *errx.Error
+	if errx.As(err, &customErr) {
+		return customErr
+	}
+
+	return errorRegistry.NewWithCause(code, err)
+}
@@ -0,0 +1,67 @@
+package vstredis
+
+import (
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// ProviderOption configures the Redis provider
+type ProviderOption func(*RedisProvider)
+
+// WithIndexName sets the RediSearch index name
+func WithIndexName(name string) ProviderOption {
+	return func(p *RedisProvider) {
+		p.indexName = name
+	}
+}
+
+// WithKeyPrefix sets the prefix of the hash keys holding vectors
+func WithKeyPrefix(prefix string) ProviderOption {
+	return func(p *RedisProvider) {
+		p.keyPrefix = prefix
+	}
+}
+
+// WithMetric sets the distance metric of the vector field
+func WithMetric(metric vstore.Metric) ProviderOption {
+	return func(p *RedisProvider) {
+		p.metric = metric
+	}
+}
+
+// WithAlgorithm sets the vector index algorithm (HNSW or FLAT)
+func WithAlgorithm(algorithm Algorithm) ProviderOption {
+	return func(p *RedisProvider) {
+		p.algorithm = algorithm
+	}
+}
+
+// WithIndexedFields declares the metadata fields that can be filtered on.
+// RediSearch only filters on fields in the index schema, so filters on any
+// other field are rejected.
+func WithIndexedFields(fields ...IndexedField) ProviderOption {
+	return func(p *RedisProvider) {
+		p.indexedFields = append(p.indexedFields, fields...)
+	}
+}
+
+// WithTextField sets the metadata field indexed for keyword search in
+// HybridQuery (default "content"). An empty field disables keyword search.
+func WithTextField(field string) ProviderOption {
+	return func(p *RedisProvider) {
+		p.textField = field
+	}
+}
+
+// WithAutoCreateIndex creates the index on first use if it doesn't exist
+func WithAutoCreateIndex(auto bool) ProviderOption {
+	return func(p *RedisProvider) {
+		p.autoCreate = auto
+	}
+}
+
+// WithBatchSize sets the default batch size for operations
+func WithBatchSize(size int) ProviderOption {
+	return func(p *RedisProvider) {
+		p.batchSize = size
+	}
+}
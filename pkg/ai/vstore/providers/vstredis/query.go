@@ -0,0 +1,459 @@
+package vstredis
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+)
+
+// ============================================================================
+// Vector Encoding
+// ============================================================================
+
+// encodeVector encodes a vector as little-endian FLOAT32 bytes
+func encodeVector(values []float32) []byte {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(data string) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("vector blob length %d is not a multiple of 4", len(data))
+	}
+	values := make([]float32, len(data)/4)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(data[4*i : 4*i+4])))
+	}
+	return values, nil
+}
+
+// ============================================================================
+// Score Conversion
+// ============================================================================
+
+// ConvertScore converts a RediSearch vector distance to a similarity where
+// higher is better. COSINE and IP report 1 - similarity; L2 reports the
+// squared euclidean distance.
+func ConvertScore(distance float64, metric vstore.Metric) float32 {
+	switch metric {
+	case vstore.MetricEuclidean:
+		return float32(1.0 / (1.0 + math.Sqrt(math.Max(distance, 0))))
+	default:
+		return float32(1.0 - distance)
+	}
+}
+
+// distanceMetric returns the RediSearch DISTANCE_METRIC for a vstore metric
+func distanceMetric(metric vstore.Metric) string {
+	switch metric {
+	case vstore.MetricDotProduct:
+		return "IP"
+	case vstore.MetricEuclidean:
+		return "L2"
+	default:
+		return "COSINE"
+	}
+}
+
+// ============================================================================
+// Query Syntax
+// ============================================================================
+
+// escapeTag escapes a value for use inside a TAG query {...}
+func escapeTag(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 127 {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteByte('\\')
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// tokenize splits text into lowercase terms that need no escaping
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || r > 127)
+	})
+}
+
+// namespaceClause restricts a query to a namespace; the empty namespace
+// matches every document
+func namespaceClause(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+	return fmt.Sprintf("@%s:{%s}", namespaceField, escapeTag(namespace))
+}
+
+// joinClauses ANDs non-empty clauses, returning "*" when there are none
+func joinClauses(clauses ...string) string {
+	parts := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildFilter converts a vstore.Filter to RediSearch query syntax,
+// recursing into nested And/Or/Not sub-filters. An empty filter converts to
+// an empty string.
+func (p *RedisProvider) buildFilter(filter *vstore.Filter) (string, *errx.Error) {
+	if filter == nil {
+		return "", nil
+	}
+
+	var parts []string
+	for _, cond := range filter.Must {
+		clause, err := p.buildCondition(cond)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, clause)
+	}
+	for i := range filter.And {
+		clause, err := p.buildFilter(&filter.And[i])
+		if err != nil {
+			return "", err
+		}
+		if clause != "" {
+			parts = append(parts, "("+clause+")")
+		}
+	}
+
+	if len(filter.Should) > 0 || len(filter.Or) > 0 {
+		should := make([]string, 0, len(filter.Should)+len(filter.Or))
+		matchAll := false
+		for _, cond := range filter.Should {
+			clause, err := p.buildCondition(cond)
+			if err != nil {
+				return "", err
+			}
+			should = append(should, clause)
+		}
+		for i := range filter.Or {
+			clause, err := p.buildFilter(&filter.Or[i])
+			if err != nil {
+				return "", err
+			}
+			if clause == "" {
+				// An empty alternative matches everything
+				matchAll = true
+				continue
+			}
+			should = append(should, "("+clause+")")
+		}
+		if !matchAll {
+			parts = append(parts, "("+strings.Join(should, " | ")+")")
+		}
+	}
+
+	for _, cond := range filter.MustNot {
+		clause, err := p.buildCondition(cond)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "-"+clause)
+	}
+	for i := range filter.Not {
+		clause, err := p.buildFilter(&filter.Not[i])
+		if err != nil {
+			return "", err
+		}
+		if clause == "" {
+			return "", errorRegistry.New(ErrUnsupportedFilter).
+				WithDetail("error", "negated sub-filter is empty")
+		}
+		parts = append(parts, "-("+clause+")")
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// buildCondition converts a single condition on an indexed field
+func (p *RedisProvider) buildCondition(cond vstore.Condition) (string, *errx.Error) {
+	field, ok := p.indexedField(cond.Field)
+	if !ok {
+		return "", errorRegistry.New(ErrUnsupportedFilter).
+			WithDetail("field", cond.Field).
+			WithDetail("error", "field is not indexed")
+	}
+	attr := "@" + field.attribute()
+
+	unsupported := func() (string, *errx.Error) {
+		return "", errorRegistry.New(ErrUnsupportedFilter).
+			WithDetail("field", cond.Field).
+			WithDetail("type", string(field.Type)).
+			WithDetail("operator", string(cond.Operator))
+	}
+
+	switch field.Type {
+	case FieldTag:
+		switch cond.Operator {
+		case vstore.OpEqual, vstore.OpContains, vstore.OpArrayContains:
+			// A tag matches any element of a list field
+			return fmt.Sprintf("(%s:{%s})", attr, escapeTag(tagString(cond.Value))), nil
+		case vstore.OpNotEqual:
+			return fmt.Sprintf("(-%s:{%s})", attr, escapeTag(tagString(cond.Value))), nil
+		case vstore.OpArrayContainsAll:
+			values := toSlice(cond.Value)
+			clauses := make([]string, len(values))
+			for i, v := range values {
+				clauses[i] = fmt.Sprintf("%s:{%s}", attr, escapeTag(tagString(v)))
+			}
+			return "(" + strings.Join(clauses, " ") + ")", nil
+		case vstore.OpIn, vstore.OpNotIn, vstore.OpArrayContainsAny:
+			values := toSlice(cond.Value)
+			escaped := make([]string, len(values))
+			for i, v := range values {
+				escaped[i] = escapeTag(tagString(v))
+			}
+			clause := fmt.Sprintf("%s:{%s}", attr, strings.Join(escaped, " | "))
+			if cond.Operator == vstore.OpNotIn {
+				return "(-" + clause + ")", nil
+			}
+			return "(" + clause + ")", nil
+		}
+
+	case FieldNumeric:
+		if cond.Operator == vstore.OpExists {
+			return fmt.Sprintf("(%s:[-inf +inf])", attr), nil
+		}
+		if cond.Operator == vstore.OpIn || cond.Operator == vstore.OpNotIn {
+			values := toSlice(cond.Value)
+			ranges := make([]string, 0, len(values))
+			for _, v := range values {
+				n, ok := toFloat(v)
+				if !ok {
+					return unsupported()
+				}
+				ranges = append(ranges, fmt.Sprintf("%s:[%s %s]", attr, formatNumber(n), formatNumber(n)))
+			}
+			clause := "(" + strings.Join(ranges, " | ") + ")"
+			if cond.Operator == vstore.OpNotIn {
+				return "(-" + clause + ")", nil
+			}
+			return clause, nil
+		}
+
+		n, ok := toFloat(cond.Value)
+		if !ok {
+			return unsupported()
+		}
+		v := formatNumber(n)
+		switch cond.Operator {
+		case vstore.OpEqual:
+			return fmt.Sprintf("(%s:[%s %s])", attr, v, v), nil
+		case vstore.OpNotEqual:
+			return fmt.Sprintf("(-%s:[%s %s])", attr, v, v), nil
+		case vstore.OpGreaterThan:
+			return fmt.Sprintf("(%s:[(%s +inf])", attr, v), nil
+		case vstore.OpGreaterThanOrEqual:
+			return fmt.Sprintf("(%s:[%s +inf])", attr, v), nil
+		case vstore.OpLessThan:
+			return fmt.Sprintf("(%s:[-inf (%s])", attr, v), nil
+		case vstore.OpLessThanOrEqual:
+			return fmt.Sprintf("(%s:[-inf %s])", attr, v), nil
+		}
+
+	case FieldText:
+		if cond.Operator == vstore.OpContains || cond.Operator == vstore.OpEqual {
+			terms := tokenize(fmt.Sprint(cond.Value))
+			if len(terms) == 0 {
+				return unsupported()
+			}
+			return fmt.Sprintf("(%s:(%s))", attr, strings.Join(terms, " ")), nil
+		}
+	}
+
+	return unsupported()
+}
+
+// ============================================================================
+// Field Values
+// ============================================================================
+
+// tagString renders a scalar as a tag value
+func tagString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return formatNumber(val)
+	case float32:
+		return formatNumber(float64(val))
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// tagValue renders a metadata value for a TAG field, joining lists with the
+// default tag separator
+func tagValue(v any) string {
+	values := toSlice(v)
+	parts := make([]string, len(values))
+	for i, item := range values {
+		parts[i] = strings.ReplaceAll(tagString(item), ",", " ")
+	}
+	return strings.Join(parts, ",")
+}
+
+func toSlice(v any) []any {
+	switch val := v.(type) {
+	case []any:
+		return val
+	case []string:
+		out := make([]any, len(val))
+		for i, s := range val {
+			out[i] = s
+		}
+		return out
+	case []int:
+		out := make([]any, len(val))
+		for i, n := range val {
+			out[i] = n
+		}
+		return out
+	case []float64:
+		out := make([]any, len(val))
+		for i, n := range val {
+			out[i] = n
+		}
+		return out
+	default:
+		return []any{v}
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// ============================================================================
+// Reply Parsing
+// ============================================================================
+
+// searchDoc is a single FT.SEARCH result
+type searchDoc struct {
+	Key    string
+	Score  float64
+	Fields map[string]string
+}
+
+// parseSearchReply decodes an FT.SEARCH reply. RESP2 replies are flat
+// arrays ([total, key, (score,) [field, value, ...], ...]); RESP3 replies
+// are maps with "total_results" and "results".
+func parseSearchReply(reply any, withScores bool) (int64, []searchDoc, error) {
+	switch r := reply.(type) {
+	case []any:
+		return parseRESP2(r, withScores)
+	case map[any]any:
+		return parseRESP3(r)
+	default:
+		return 0, nil, fmt.Errorf("unexpected FT.SEARCH reply type %T", reply)
+	}
+}
+
+func parseRESP2(reply []any, withScores bool) (int64, []searchDoc, error) {
+	if len(reply) == 0 {
+		return 0, nil, fmt.Errorf("empty FT.SEARCH reply")
+	}
+	total, ok := reply[0].(int64)
+	if !ok {
+		return 0, nil, fmt.Errorf("unexpected FT.SEARCH total %T", reply[0])
+	}
+
+	var docs []searchDoc
+	for i := 1; i < len(reply); {
+		doc := searchDoc{Key: fmt.Sprint(reply[i])}
+		i++
+		if withScores && i < len(reply) {
+			doc.Score = toScore(reply[i])
+			i++
+		}
+		if i < len(reply) {
+			if fields, ok := reply[i].([]any); ok {
+				doc.Fields = make(map[string]string, len(fields)/2)
+				for j := 0; j+1 < len(fields); j += 2 {
+					doc.Fields[fmt.Sprint(fields[j])] = fmt.Sprint(fields[j+1])
+				}
+				i++
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return total, docs, nil
+}
+
+func parseRESP3(reply map[any]any) (int64, []searchDoc, error) {
+	total, _ := reply["total_results"].(int64)
+	results, _ := reply["results"].([]any)
+
+	docs := make([]searchDoc, 0, len(results))
+	for _, item := range results {
+		entry, ok := item.(map[any]any)
+		if !ok {
+			return 0, nil, fmt.Errorf("unexpected FT.SEARCH result %T", item)
+		}
+		doc := searchDoc{Key: fmt.Sprint(entry["id"]), Score: toScore(entry["score"])}
+		if attrs, ok := entry["extra_attributes"].(map[any]any); ok {
+			doc.Fields = make(map[string]string, len(attrs))
+			for k, v := range attrs {
+				doc.Fields[fmt.Sprint(k)] = fmt.Sprint(v)
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return total, docs, nil
+}
+
+func toScore(v any) float64 {
+	switch s := v.(type) {
+	case float64:
+		return s
+	case int64:
+		return float64(s)
+	case string:
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	default:
+		return 0
+	}
+}
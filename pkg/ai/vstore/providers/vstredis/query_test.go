@@ -0,0 +1,247 @@
+package vstredis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestProvider builds a provider whose client is never dialed
+func newTestProvider(t *testing.T) *RedisProvider {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	t.Cleanup(func() { _ = rdb.Close() })
+	p, err := NewRedisProvider(rdb, 4, WithIndexedFields(
+		IndexedField{Name: "category", Type: FieldTag},
+		IndexedField{Name: "year", Type: FieldNumeric},
+		IndexedField{Name: "content", Type: FieldText},
+		IndexedField{Name: "author.name", Type: FieldTag},
+	))
+	if err != nil {
+		t.Fatalf("NewRedisProvider: %v", err)
+	}
+	return p
+}
+
+func TestEscapeTag(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"news", "news"},
+		{"snake_case9", "snake_case9"},
+		{"a-b c", `a\-b\ c`},
+		{"user@example.com", `user\@example\.com`},
+		{"{x}|y", `\{x\}\|y`},
+		{"café", "café"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeTag(tt.in); got != tt.want {
+			t.Errorf("escapeTag(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCondition(t *testing.T) {
+	p := newTestProvider(t)
+	tests := []struct {
+		name  string
+		field string
+		op    vstore.FilterOperator
+		value any
+		want  string
+	}{
+		{"tag eq", "category", vstore.OpEqual, "news", "(@m_category:{news})"},
+		{"tag eq escaped", "category", vstore.OpEqual, "a-b", `(@m_category:{a\-b})`},
+		{"tag eq number", "category", vstore.OpEqual, 2.0, "(@m_category:{2})"},
+		{"tag ne", "category", vstore.OpNotEqual, "news", "(-@m_category:{news})"},
+		{"tag in", "category", vstore.OpIn, []any{"blog", "docs"}, "(@m_category:{blog | docs})"},
+		{"tag nin", "category", vstore.OpNotIn, []string{"news"}, "(-@m_category:{news})"},
+		{"tag array contains", "category", vstore.OpArrayContains, "go", "(@m_category:{go})"},
+		{"tag array contains any", "category", vstore.OpArrayContainsAny, []any{"go", "db"}, "(@m_category:{go | db})"},
+		{"tag array contains all", "category", vstore.OpArrayContainsAll, []any{"go", "db"}, "(@m_category:{go} @m_category:{db})"},
+		{"nested tag", "author.name", vstore.OpEqual, "ann", "(@m_author__name:{ann})"},
+		{"numeric eq", "year", vstore.OpEqual, 2020, "(@m_year:[2020 2020])"},
+		{"numeric ne", "year", vstore.OpNotEqual, 2020, "(-@m_year:[2020 2020])"},
+		{"numeric gt", "year", vstore.OpGreaterThan, 2.5, "(@m_year:[(2.5 +inf])"},
+		{"numeric gte", "year", vstore.OpGreaterThanOrEqual, 2020, "(@m_year:[2020 +inf])"},
+		{"numeric lt", "year", vstore.OpLessThan, 2020, "(@m_year:[-inf (2020])"},
+		{"numeric lte", "year", vstore.OpLessThanOrEqual, int64(2020), "(@m_year:[-inf 2020])"},
+		{"numeric in", "year", vstore.OpIn, []int{2020, 2023}, "(@m_year:[2020 2020] | @m_year:[2023 2023])"},
+		{"numeric nin", "year", vstore.OpNotIn, []any{2020}, "(-(@m_year:[2020 2020]))"},
+		{"numeric exists", "year", vstore.OpExists, nil, "(@m_year:[-inf +inf])"},
+		{"text contains", "content", vstore.OpContains, "Brown, fox!", "(@m_content:(brown fox))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.buildCondition(vstore.Condition{Field: tt.field, Operator: tt.op, Value: tt.value})
+			if err != nil {
+				t.Fatalf("buildCondition: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildCondition = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildConditionUnsupported(t *testing.T) {
+	p := newTestProvider(t)
+	tests := []struct {
+		name  string
+		field string
+		op    vstore.FilterOperator
+		value any
+	}{
+		{"not indexed", "rating", vstore.OpEqual, 1},
+		{"tag range", "category", vstore.OpGreaterThan, "a"},
+		{"numeric string", "year", vstore.OpEqual, "2020"},
+		{"numeric in string", "year", vstore.OpIn, []any{"2020"}},
+		{"text without terms", "content", vstore.OpContains, "!!"},
+		{"text range", "content", vstore.OpLessThan, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.buildCondition(vstore.Condition{Field: tt.field, Operator: tt.op, Value: tt.value})
+			if err == nil || err.Code != ErrUnsupportedFilter.Code {
+				t.Errorf("buildCondition = %v, want %s", err, ErrUnsupportedFilter.Code)
+			}
+		})
+	}
+}
+
+func TestBuildFilter(t *testing.T) {
+	p := newTestProvider(t)
+	tests := []struct {
+		name   string
+		filter *vstore.Filter
+		want   string
+	}{
+		{"nil", nil, ""},
+		{"empty", vstore.NewFilter(), ""},
+		{"clauses", &vstore.Filter{
+			Must:    []vstore.Condition{{Field: "category", Operator: vstore.OpEqual, Value: "news"}},
+			Should:  []vstore.Condition{{Field: "year", Operator: vstore.OpEqual, Value: 2020}, {Field: "year", Operator: vstore.OpEqual, Value: 2021}},
+			MustNot: []vstore.Condition{{Field: "author.name", Operator: vstore.OpEqual, Value: "bob"}},
+		}, "(@m_category:{news}) ((@m_year:[2020 2020]) | (@m_year:[2021 2021])) -(@m_author__name:{bob})"},
+		{"tree", func() *vstore.Filter {
+			f := vstore.And(
+				vstore.Or(vstore.Where("category", vstore.OpEqual, "blog"), vstore.Where("category", vstore.OpEqual, "docs")),
+				vstore.Not(vstore.Where("year", vstore.OpLessThan, 2022)),
+			)
+			return &f
+		}(), "(((@m_category:{blog}) | (@m_category:{docs}))) (-(@m_year:[-inf (2022]))"},
+		{"empty alternative matches all", &vstore.Filter{
+			Or: []vstore.Filter{{}, vstore.Where("year", vstore.OpEqual, 2020)},
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.buildFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("buildFilter: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildFilter = %q\nwant          %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := p.buildFilter(&vstore.Filter{Not: []vstore.Filter{{}}}); err == nil || err.Code != ErrUnsupportedFilter.Code {
+		t.Errorf("empty negated sub-filter = %v, want %s", err, ErrUnsupportedFilter.Code)
+	}
+}
+
+func TestParseSearchReply(t *testing.T) {
+	want := []searchDoc{
+		{Key: "v:a", Score: 0.25, Fields: map[string]string{"text": "alpha", "__score": "0.25"}},
+		{Key: "v:b", Score: 0.5, Fields: map[string]string{"text": "beta", "__score": "0.5"}},
+	}
+
+	tests := []struct {
+		name       string
+		reply      any
+		withScores bool
+		want       []searchDoc
+	}{
+		{"resp2", []any{int64(2),
+			"v:a", []any{"text", "alpha", "__score", "0.25"},
+			"v:b", []any{"text", "beta", "__score", "0.5"},
+		}, false, []searchDoc{
+			{Key: "v:a", Fields: want[0].Fields},
+			{Key: "v:b", Fields: want[1].Fields},
+		}},
+		{"resp2 with scores", []any{int64(2),
+			"v:a", "0.25", []any{"text", "alpha", "__score", "0.25"},
+			"v:b", "0.5", []any{"text", "beta", "__score", "0.5"},
+		}, true, want},
+		{"resp2 keys only", []any{int64(2), "v:a", "v:b"}, false, []searchDoc{{Key: "v:a"}, {Key: "v:b"}}},
+		{"resp3", map[any]any{
+			"total_results": int64(2),
+			"results": []any{
+				map[any]any{"id": "v:a", "score": 0.25, "extra_attributes": map[any]any{"text": "alpha", "__score": "0.25"}},
+				map[any]any{"id": "v:b", "score": 0.5, "extra_attributes": map[any]any{"text": "beta", "__score": "0.5"}},
+			},
+		}, true, want},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, docs, err := parseSearchReply(tt.reply, tt.withScores)
+			if err != nil {
+				t.Fatalf("parseSearchReply: %v", err)
+			}
+			if total != 2 {
+				t.Errorf("total = %d, want 2", total)
+			}
+			if !reflect.DeepEqual(docs, tt.want) {
+				t.Errorf("docs = %+v\nwant   %+v", docs, tt.want)
+			}
+		})
+	}
+
+	for name, reply := range map[string]any{
+		"empty resp2":  []any{},
+		"bad total":    []any{"2"},
+		"bad resp3":    map[any]any{"results": []any{"v:a"}},
+		"unknown type": "OK",
+	} {
+		if _, _, err := parseSearchReply(reply, false); err == nil {
+			t.Errorf("%s: parseSearchReply accepted %v", name, reply)
+		}
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	values := []float32{1, -0.5, float32(math.Pi), 0}
+	decoded, err := decodeVector(string(encodeVector(values)))
+	if err != nil {
+		t.Fatalf("decodeVector: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, values) {
+		t.Errorf("round trip = %v, want %v", decoded, values)
+	}
+	if _, err := decodeVector("abc"); err == nil {
+		t.Error("decodeVector accepted a truncated blob")
+	}
+}
+
+func TestConvertScore(t *testing.T) {
+	tests := []struct {
+		distance float64
+		metric   vstore.Metric
+		want     float32
+	}{
+		{0.25, vstore.MetricCosine, 0.75},
+		{-0.5, vstore.MetricDotProduct, 1.5},
+		{4, vstore.MetricEuclidean, 1.0 / 3},
+		{-1e-9, vstore.MetricEuclidean, 1},
+	}
+	for _, tt := range tests {
+		if got := ConvertScore(tt.distance, tt.metric); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("ConvertScore(%v, %s) = %v, want %v", tt.distance, tt.metric, got, tt.want)
+		}
+	}
+}
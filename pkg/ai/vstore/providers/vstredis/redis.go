@@ -0,0 +1,971 @@
+package vstredis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/errx"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultIndexName = "vstore_idx"
+	DefaultKeyPrefix = "vstore:"
+	DefaultTextField = "content"
+	DefaultBatchSize = 500
+)
+
+// Hash fields written for every vector
+const (
+	idField        = "id"
+	vectorField    = "vector"
+	metadataField  = "metadata"
+	namespaceField = "namespace"
+	textAttribute  = "text"
+	scoreAttribute = "__score"
+)
+
+// Algorithm is the RediSearch vector index algorithm
+type Algorithm string
+
+const (
+	AlgorithmHNSW Algorithm = "HNSW"
+	AlgorithmFlat Algorithm = "FLAT"
+)
+
+// FieldType is the RediSearch type of an indexed metadata field
+type FieldType string
+
+const (
+	FieldTag     FieldType = "TAG"
+	FieldNumeric FieldType = "NUMERIC"
+	FieldText    FieldType = "TEXT"
+)
+
+// IndexedField is a metadata field added to the index schema so it can be
+// used in filters. Name may be a dotted path into nested metadata.
+type IndexedField struct {
+	Name string
+	Type FieldType
+}
+
+// attribute returns the hash field holding the metadata value, with path
+// dots replaced since schema attributes must be identifiers
+func (f IndexedField) attribute() string {
+	return "m_" + strings.ReplaceAll(f.Name, ".", "__")
+}
+
+// Compile-time interface checks
+var (
+	_ vstore.VectorStorer       = (*RedisProvider)(nil)
+	_ vstore.MetadataFilterer   = (*RedisProvider)(nil)
+	_ vstore.BatchProcessor     = (*RedisProvider)(nil)
+	_ vstore.NamespaceManager   = (*RedisProvider)(nil)
+	_ vstore.HybridSearcher     = (*RedisProvider)(nil)
+	_ vstore.StatisticsProvider = (*RedisProvider)(nil)
+	_ vstore.VectorLister       = (*RedisProvider)(nil)
+)
+
+// RedisProvider implements vector store on Redis Stack (RediSearch).
+// Vectors are stored as hashes under a key prefix and searched through a
+// single FT index; namespaces are a TAG field on each hash.
+//
+// Commands are sent with Do and both RESP2 and RESP3 replies are parsed,
+// so the provider works with clients created with default options.
+type RedisProvider struct {
+	rdb redis.UniversalClient
+
+	// Configuration
+	dimension     int
+	metric        vstore.Metric
+	algorithm     Algorithm
+	indexName     string
+	keyPrefix     string
+	textField     string
+	indexedFields []IndexedField
+	autoCreate    bool
+	batchSize     int
+
+	mu         sync.Mutex
+	indexReady bool
+}
+
+// NewRedisProvider creates a new Redis provider on an existing client, such
+// as the one the container builds from config.RedisConfig
+//
+// Example:
+//
+//	provider, err := vstredis.NewRedisProvider(container.Redis, 1536,
+//	    vstredis.WithIndexedFields(
+//	        vstredis.IndexedField{Name: "category", Type: vstredis.FieldTag},
+//	        vstredis.IndexedField{Name: "year", Type: vstredis.FieldNumeric},
+//	    ),
+//	)
+//	client := vstore.NewClient(provider)
+func NewRedisProvider(rdb redis.UniversalClient, dimension int, opts ...ProviderOption) (*RedisProvider, *errx.Error) {
+	if rdb == nil {
+		return nil, errorRegistry.New(ErrMissingConfig).
+			WithDetail("error", "redis client is required")
+	}
+
+	if dimension <= 0 {
+		return nil, errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "dimension must be positive")
+	}
+
+	provider := &RedisProvider{
+		rdb:        rdb,
+		dimension:  dimension,
+		metric:     vstore.MetricCosine,
+		algorithm:  AlgorithmHNSW,
+		indexName:  DefaultIndexName,
+		keyPrefix:  DefaultKeyPrefix,
+		textField:  DefaultTextField,
+		autoCreate: true,
+		batchSize:  DefaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	if err := provider.validateConfig(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// ============================================================================
+// VectorStorer Implementation
+// ============================================================================
+
+// Upsert inserts or updates vectors. Each hash is replaced atomically so
+// stale metadata fields don't survive an update.
+func (p *RedisProvider) Upsert(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	if err := p.validateVectors(vectors); err != nil {
+		return err
+	}
+
+	if err := p.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	for batch := range slices.Chunk(vectors, p.batchSize) {
+		_, err := p.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, v := range batch {
+				fields, err := p.toHash(v, options.Namespace)
+				if err != nil {
+					return err
+				}
+				key := p.key(v.ID)
+				pipe.Del(ctx, key)
+				pipe.HSet(ctx, key, fields)
+			}
+			return nil
+		})
+		if err != nil {
+			return WrapError(err, ErrCommandFailed).
+				WithDetail("operation", "upsert")
+		}
+	}
+
+	return nil
+}
+
+// Query performs similarity search
+func (p *RedisProvider) Query(ctx context.Context, vector []float32, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if len(vector) != p.dimension {
+		return nil, errorRegistry.New(ErrInvalidVectorDimension).
+			WithDetail("expected", p.dimension).
+			WithDetail("got", len(vector))
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	filter, err := p.buildFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	prefilter := joinClauses(namespaceClause(options.Namespace), filter)
+	if prefilter != "*" {
+		prefilter = "(" + prefilter + ")"
+	}
+	query := fmt.Sprintf("%s=>[KNN $K @%s $BLOB AS %s]", prefilter, vectorField, scoreAttribute)
+
+	returnFields := []any{idField, metadataField, scoreAttribute}
+	if options.IncludeValues {
+		returnFields = append(returnFields, vectorField)
+	}
+
+	args := []any{"FT.SEARCH", p.indexName, query,
+		"PARAMS", 4, "K", options.TopK, "BLOB", encodeVector(vector),
+		"SORTBY", scoreAttribute, "ASC",
+		"LIMIT", 0, options.TopK,
+		"RETURN", len(returnFields)}
+	args = append(args, returnFields...)
+	args = append(args, "DIALECT", 2)
+
+	docs, searchErr := p.search(ctx, false, args...)
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	matches := make([]vstore.Match, 0, len(docs))
+	for _, doc := range docs {
+		distance, parseErr := strconv.ParseFloat(doc.Fields[scoreAttribute], 64)
+		if parseErr != nil {
+			return nil, WrapError(parseErr, ErrInvalidReply).
+				WithDetail("key", doc.Key)
+		}
+
+		match, convErr := p.toMatch(doc, ConvertScore(distance, p.metric), options)
+		if convErr != nil {
+			return nil, convErr
+		}
+		if options.MeetsMinScore(match.Score) {
+			matches = append(matches, match)
+		}
+	}
+
+	return &vstore.QueryResult{Matches: matches, Namespace: options.Namespace}, nil
+}
+
+// Delete removes vectors by IDs. With a namespace, only vectors in that
+// namespace are removed.
+func (p *RedisProvider) Delete(ctx context.Context, ids []string, opts ...vstore.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	keys := make([]string, 0, len(ids))
+	if options.Namespace == "" {
+		for _, id := range ids {
+			keys = append(keys, p.key(id))
+		}
+	} else {
+		namespaces, err := p.namespacesOf(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if namespaces[i] == options.Namespace {
+				keys = append(keys, p.key(id))
+			}
+		}
+	}
+
+	for batch := range slices.Chunk(keys, p.batchSize) {
+		if err := p.rdb.Del(ctx, batch...).Err(); err != nil {
+			return WrapError(err, ErrCommandFailed).
+				WithDetail("operation", "delete")
+		}
+	}
+
+	return nil
+}
+
+// Fetch retrieves vectors by IDs
+func (p *RedisProvider) Fetch(ctx context.Context, ids []string, opts ...vstore.Option) ([]vstore.Vector, error) {
+	if len(ids) == 0 {
+		return []vstore.Vector{}, nil
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err := p.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, p.key(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "fetch")
+	}
+
+	vectors := make([]vstore.Vector, 0, len(ids))
+	for _, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		if options.Namespace != "" && fields[namespaceField] != options.Namespace {
+			continue
+		}
+
+		values, decodeErr := decodeVector(fields[vectorField])
+		if decodeErr != nil {
+			return nil, WrapError(decodeErr, ErrInvalidReply)
+		}
+		metadata, decodeErr := decodeMetadata(fields[metadataField])
+		if decodeErr != nil {
+			return nil, WrapError(decodeErr, ErrInvalidReply)
+		}
+
+		vectors = append(vectors, vstore.Vector{
+			ID:       fields[idField],
+			Values:   values,
+			Metadata: metadata,
+		})
+	}
+
+	return vectors, nil
+}
+
+// ============================================================================
+// MetadataFilterer Implementation
+// ============================================================================
+
+// QueryWithFilter performs filtered similarity search
+func (p *RedisProvider) QueryWithFilter(ctx context.Context, vector []float32, filter vstore.Filter, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	opts = append(slices.Clone(opts), vstore.WithFilter(&filter))
+	return p.Query(ctx, vector, opts...)
+}
+
+// ============================================================================
+// BatchProcessor Implementation
+// ============================================================================
+
+// UpsertBatch upserts vectors in batches
+func (p *RedisProvider) UpsertBatch(ctx context.Context, vectors []vstore.Vector, opts ...vstore.Option) (*vstore.BatchResult, error) {
+	options := vstore.ApplyOptions(opts...)
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = p.batchSize
+	}
+
+	result := &vstore.BatchResult{}
+	for batch := range slices.Chunk(vectors, batchSize) {
+		if err := p.Upsert(ctx, batch, opts...); err != nil {
+			result.FailedCount += len(batch)
+			for _, v := range batch {
+				result.Errors = append(result.Errors, vstore.BatchError{
+					ID:    v.ID,
+					Error: err.Error(),
+				})
+			}
+		} else {
+			result.SuccessCount += len(batch)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteBatch deletes multiple vectors
+func (p *RedisProvider) DeleteBatch(ctx context.Context, ids []string, opts ...vstore.Option) (*vstore.BatchResult, error) {
+	if err := p.Delete(ctx, ids, opts...); err != nil {
+		return &vstore.BatchResult{
+			FailedCount: len(ids),
+		}, err
+	}
+
+	return &vstore.BatchResult{
+		SuccessCount: len(ids),
+	}, nil
+}
+
+// ============================================================================
+// NamespaceManager Implementation
+// ============================================================================
+
+// ListNamespaces returns all namespaces holding vectors
+func (p *RedisProvider) ListNamespaces(ctx context.Context) ([]string, error) {
+	if err := p.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	reply, err := p.rdb.Do(ctx, "FT.TAGVALS", p.indexName, namespaceField).Result()
+	if err != nil {
+		return nil, WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "list_namespaces")
+	}
+
+	values, ok := reply.([]any)
+	if !ok {
+		return nil, errorRegistry.New(ErrInvalidReply).
+			WithDetail("reply_type", fmt.Sprintf("%T", reply))
+	}
+
+	// Tag values outlive their documents until RediSearch garbage collects
+	// them, so only namespaces that still hold vectors are returned
+	namespaces := make([]string, 0, len(values))
+	for _, v := range values {
+		ns := fmt.Sprint(v)
+		n, err := p.count(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
+}
+
+// CreateNamespace creates a namespace (no-op, namespaces are implicit)
+func (p *RedisProvider) CreateNamespace(ctx context.Context, namespace string) error {
+	return p.EnsureIndex(ctx)
+}
+
+// DeleteNamespace deletes a namespace and all its vectors
+func (p *RedisProvider) DeleteNamespace(ctx context.Context, namespace string) error {
+	if namespace == "" {
+		return errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "namespace is required")
+	}
+
+	if err := p.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	query := namespaceClause(namespace)
+	for {
+		docs, err := p.search(ctx, false, "FT.SEARCH", p.indexName, query,
+			"NOCONTENT", "LIMIT", 0, p.batchSize, "DIALECT", 2)
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		keys := make([]string, len(docs))
+		for i, doc := range docs {
+			keys[i] = doc.Key
+		}
+		if err := p.rdb.Del(ctx, keys...).Err(); err != nil {
+			return WrapError(err, ErrCommandFailed).
+				WithDetail("operation", "delete_namespace")
+		}
+	}
+}
+
+// ============================================================================
+// HybridSearcher Implementation
+// ============================================================================
+
+// HybridQuery combines KNN similarity with BM25 keyword search over the
+// text field and fuses the two rankings with vstore.FuseResults
+func (p *RedisProvider) HybridQuery(ctx context.Context, vector []float32, query string, opts ...vstore.Option) (*vstore.QueryResult, error) {
+	if p.textField == "" {
+		return nil, errorRegistry.New(ErrFeatureNotSupported).
+			WithDetail("error", "text field not configured")
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	if options.SparseValues != nil {
+		return nil, errorRegistry.New(ErrFeatureNotSupported).
+			WithDetail("error", "sparse vectors are not supported")
+	}
+
+	candidates := options.HybridCandidateCount()
+
+	var vectorMatches []vstore.Match
+	if options.HybridAlpha > 0 {
+		candidateOpts := append(slices.Clone(opts),
+			vstore.WithTopK(candidates),
+			vstore.WithMinScore(0),
+		)
+		result, err := p.Query(ctx, vector, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+		vectorMatches = result.Matches
+	}
+
+	var keywordMatches []vstore.Match
+	if options.HybridAlpha < 1 {
+		matches, err := p.keywordQuery(ctx, query, candidates, options)
+		if err != nil {
+			return nil, err
+		}
+		keywordMatches = matches
+	}
+
+	return &vstore.QueryResult{
+		Matches:   vstore.FuseResults(vectorMatches, keywordMatches, options),
+		Namespace: options.Namespace,
+	}, nil
+}
+
+// keywordQuery ranks documents matching any query term with BM25
+func (p *RedisProvider) keywordQuery(ctx context.Context, text string, limit int, options *vstore.Options) ([]vstore.Match, *errx.Error) {
+	terms := tokenize(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	filter, err := p.buildFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	query := joinClauses(
+		namespaceClause(options.Namespace),
+		filter,
+		fmt.Sprintf("@%s:(%s)", textAttribute, strings.Join(terms, " | ")),
+	)
+
+	returnFields := []any{idField, metadataField}
+	if options.IncludeValues {
+		returnFields = append(returnFields, vectorField)
+	}
+
+	args := []any{"FT.SEARCH", p.indexName, query,
+		"WITHSCORES", "SCORER", "BM25",
+		"LIMIT", 0, limit,
+		"RETURN", len(returnFields)}
+	args = append(args, returnFields...)
+	args = append(args, "DIALECT", 2)
+
+	docs, err := p.search(ctx, true, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]vstore.Match, 0, len(docs))
+	for _, doc := range docs {
+		match, err := p.toMatch(doc, float32(doc.Score), options)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// ============================================================================
+// StatisticsProvider Implementation
+// ============================================================================
+
+// GetStatistics returns vector counts for the requested namespace, or for
+// the whole index broken down by namespace
+func (p *RedisProvider) GetStatistics(ctx context.Context, opts ...vstore.Option) (*vstore.Statistics, error) {
+	options := vstore.ApplyOptions(opts...)
+	stats := &vstore.Statistics{Dimension: p.dimension}
+
+	total, err := p.count(ctx, options.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalVectorCount = total
+
+	if options.Namespace != "" {
+		stats.Namespaces = []vstore.NamespaceStats{{Name: options.Namespace, VectorCount: total}}
+		return stats, nil
+	}
+
+	namespaces, listErr := p.ListNamespaces(ctx)
+	if listErr != nil {
+		return nil, listErr
+	}
+	for _, ns := range namespaces {
+		n, err := p.count(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		stats.Namespaces = append(stats.Namespaces, vstore.NamespaceStats{Name: ns, VectorCount: n})
+	}
+
+	return stats, nil
+}
+
+// ============================================================================
+// VectorLister Implementation
+// ============================================================================
+
+// List pages through matching documents in index order. The cursor is a
+// result offset, so writes during listing can shift later pages.
+func (p *RedisProvider) List(ctx context.Context, cursor string, limit int, opts ...vstore.Option) (*vstore.ListResult, error) {
+	offset := 0
+	if cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil || n < 0 {
+			return nil, errorRegistry.New(ErrInvalidInput).
+				WithDetail("error", "invalid cursor").
+				WithDetail("cursor", cursor)
+		}
+		offset = n
+	}
+	if limit <= 0 {
+		return nil, errorRegistry.New(ErrInvalidInput).
+			WithDetail("error", "limit must be positive").
+			WithDetail("limit", limit)
+	}
+
+	options := vstore.ApplyOptions(opts...)
+	options.IncludeMetadata = true
+	filter, err := p.buildFilter(options.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	returnFields := []any{idField, metadataField}
+	if options.IncludeValues {
+		returnFields = append(returnFields, vectorField)
+	}
+	args := []any{"FT.SEARCH", p.indexName, joinClauses(namespaceClause(options.Namespace), filter),
+		"LIMIT", offset, limit,
+		"RETURN", len(returnFields)}
+	args = append(args, returnFields...)
+	args = append(args, "DIALECT", 2)
+
+	reply, doErr := p.rdb.Do(ctx, args...).Result()
+	if doErr != nil {
+		return nil, WrapError(doErr, ErrCommandFailed).
+			WithDetail("operation", "list")
+	}
+	total, docs, parseErr := parseSearchReply(reply, false)
+	if parseErr != nil {
+		return nil, WrapError(parseErr, ErrInvalidReply)
+	}
+
+	result := &vstore.ListResult{Vectors: make([]vstore.Vector, 0, len(docs))}
+	for _, doc := range docs {
+		match, err := p.toMatch(doc, 0, options)
+		if err != nil {
+			return nil, err
+		}
+		result.Vectors = append(result.Vectors, vstore.Vector{
+			ID:       match.ID,
+			Values:   match.Values,
+			Metadata: match.Metadata,
+		})
+	}
+	if next := offset + len(docs); len(docs) > 0 && int64(next) < total {
+		result.NextCursor = strconv.Itoa(next)
+	}
+
+	return result, nil
+}
+
+// ============================================================================
+// Index Management
+// ============================================================================
+
+// EnsureIndex creates the RediSearch index if it doesn't exist
+func (p *RedisProvider) EnsureIndex(ctx context.Context) error {
+	if err := p.ensureIndex(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DropIndex drops the RediSearch index, and the vector hashes when
+// deleteDocuments is true
+func (p *RedisProvider) DropIndex(ctx context.Context, deleteDocuments bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	args := []any{"FT.DROPINDEX", p.indexName}
+	if deleteDocuments {
+		args = append(args, "DD")
+	}
+	if err := p.rdb.Do(ctx, args...).Err(); err != nil && !isUnknownIndex(err) {
+		return WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "drop_index")
+	}
+
+	p.indexReady = false
+	return nil
+}
+
+// ensureIndex checks for the index once, creating it when auto-create is enabled
+func (p *RedisProvider) ensureIndex(ctx context.Context) *errx.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.indexReady {
+		return nil
+	}
+
+	err := p.rdb.Do(ctx, "FT.INFO", p.indexName).Err()
+	switch {
+	case err == nil:
+	case !isUnknownIndex(err):
+		return WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "index_info")
+	case !p.autoCreate:
+		return errorRegistry.New(ErrIndexNotFound).
+			WithDetail("index", p.indexName)
+	default:
+		if err := p.rdb.Do(ctx, p.createIndexArgs()...).Err(); err != nil &&
+			!strings.Contains(strings.ToLower(err.Error()), "index already exists") {
+			return WrapError(err, ErrCommandFailed).
+				WithDetail("operation", "create_index")
+		}
+	}
+
+	p.indexReady = true
+	return nil
+}
+
+// createIndexArgs builds the FT.CREATE command for the configured schema
+func (p *RedisProvider) createIndexArgs() []any {
+	args := []any{"FT.CREATE", p.indexName, "ON", "HASH", "PREFIX", 1, p.keyPrefix, "SCHEMA",
+		namespaceField, "TAG", "CASESENSITIVE",
+		vectorField, "VECTOR", string(p.algorithm), 6,
+		"TYPE", "FLOAT32",
+		"DIM", p.dimension,
+		"DISTANCE_METRIC", distanceMetric(p.metric),
+	}
+
+	if p.textField != "" {
+		args = append(args, textAttribute, "TEXT")
+	}
+
+	for _, f := range p.indexedFields {
+		args = append(args, f.attribute(), string(f.Type))
+		if f.Type == FieldTag {
+			args = append(args, "CASESENSITIVE")
+		}
+	}
+
+	return args
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (p *RedisProvider) key(id string) string {
+	return p.keyPrefix + id
+}
+
+func (p *RedisProvider) indexedField(name string) (IndexedField, bool) {
+	for _, f := range p.indexedFields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return IndexedField{}, false
+}
+
+// toHash converts a vector to hash fields
+func (p *RedisProvider) toHash(v vstore.Vector, namespace string) (map[string]any, error) {
+	metadata, err := json.Marshal(v.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata for %s: %w", v.ID, err)
+	}
+
+	fields := map[string]any{
+		idField:        v.ID,
+		vectorField:    encodeVector(v.Values),
+		metadataField:  string(metadata),
+		namespaceField: namespace,
+	}
+
+	if text, ok := v.Metadata[p.textField].(string); ok && p.textField != "" {
+		fields[textAttribute] = text
+	}
+
+	for _, f := range p.indexedFields {
+		value, ok := vstore.LookupField(v.Metadata, f.Name)
+		if !ok || value == nil {
+			continue
+		}
+		switch f.Type {
+		case FieldNumeric:
+			if n, ok := toFloat(value); ok {
+				fields[f.attribute()] = formatNumber(n)
+			}
+		case FieldText:
+			fields[f.attribute()] = fmt.Sprint(value)
+		default:
+			fields[f.attribute()] = tagValue(value)
+		}
+	}
+
+	return fields, nil
+}
+
+// toMatch converts a search document to a vstore.Match
+func (p *RedisProvider) toMatch(doc searchDoc, score float32, options *vstore.Options) (vstore.Match, *errx.Error) {
+	match := vstore.Match{
+		ID:    doc.Fields[idField],
+		Score: score,
+	}
+	if match.ID == "" {
+		match.ID = strings.TrimPrefix(doc.Key, p.keyPrefix)
+	}
+
+	if options.IncludeMetadata {
+		metadata, err := decodeMetadata(doc.Fields[metadataField])
+		if err != nil {
+			return vstore.Match{}, WrapError(err, ErrInvalidReply).
+				WithDetail("key", doc.Key)
+		}
+		match.Metadata = metadata
+	}
+
+	if options.IncludeValues {
+		values, err := decodeVector(doc.Fields[vectorField])
+		if err != nil {
+			return vstore.Match{}, WrapError(err, ErrInvalidReply).
+				WithDetail("key", doc.Key)
+		}
+		match.Values = values
+	}
+
+	return match, nil
+}
+
+// search runs an FT.SEARCH command and parses its reply
+func (p *RedisProvider) search(ctx context.Context, withScores bool, args ...any) ([]searchDoc, *errx.Error) {
+	reply, err := p.rdb.Do(ctx, args...).Result()
+	if err != nil {
+		return nil, WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "search")
+	}
+
+	_, docs, err := parseSearchReply(reply, withScores)
+	if err != nil {
+		return nil, WrapError(err, ErrInvalidReply)
+	}
+	return docs, nil
+}
+
+// count returns the number of vectors in a namespace, or in the whole
+// index for the empty namespace
+func (p *RedisProvider) count(ctx context.Context, namespace string) (int64, *errx.Error) {
+	if err := p.ensureIndex(ctx); err != nil {
+		return 0, err
+	}
+
+	reply, err := p.rdb.Do(ctx, "FT.SEARCH", p.indexName, joinClauses(namespaceClause(namespace)),
+		"LIMIT", 0, 0, "DIALECT", 2).Result()
+	if err != nil {
+		return 0, WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "count")
+	}
+
+	total, _, err := parseSearchReply(reply, false)
+	if err != nil {
+		return 0, WrapError(err, ErrInvalidReply)
+	}
+	return total, nil
+}
+
+// namespacesOf returns the stored namespace of each ID ("" when missing)
+func (p *RedisProvider) namespacesOf(ctx context.Context, ids []string) ([]string, *errx.Error) {
+	cmds := make([]*redis.StringCmd, len(ids))
+	_, err := p.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGet(ctx, p.key(id), namespaceField)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, WrapError(err, ErrCommandFailed).
+			WithDetail("operation", "namespace_lookup")
+	}
+
+	namespaces := make([]string, len(ids))
+	for i, cmd := range cmds {
+		namespaces[i] = cmd.Val()
+	}
+	return namespaces, nil
+}
+
+func (p *RedisProvider) validateConfig() *errx.Error {
+	if p.algorithm != AlgorithmHNSW && p.algorithm != AlgorithmFlat {
+		return errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "unsupported algorithm").
+			WithDetail("algorithm", string(p.algorithm))
+	}
+	if p.indexName == "" || p.keyPrefix == "" {
+		return errorRegistry.New(ErrInvalidConfig).
+			WithDetail("error", "index name and key prefix are required")
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = DefaultBatchSize
+	}
+
+	for _, f := range p.indexedFields {
+		if slices.ContainsFunc(vstore.FieldPath(f.Name), func(key string) bool { return !isIdentifier(key) }) {
+			return errorRegistry.New(ErrInvalidConfig).
+				WithDetail("error", "indexed field names must be identifiers or dotted paths of identifiers").
+				WithDetail("field", f.Name)
+		}
+		switch f.Type {
+		case FieldTag, FieldNumeric, FieldText:
+		default:
+			return errorRegistry.New(ErrInvalidConfig).
+				WithDetail("error", "unsupported field type").
+				WithDetail("field", f.Name).
+				WithDetail("type", string(f.Type))
+		}
+	}
+	return nil
+}
+
+func (p *RedisProvider) validateVectors(vectors []vstore.Vector) *errx.Error {
+	for i, v := range vectors {
+		if v.ID == "" {
+			return errorRegistry.New(ErrEmptyVectorID).WithDetail("index", i)
+		}
+		if len(v.Values) != p.dimension {
+			return errorRegistry.New(ErrInvalidVectorDimension).
+				WithDetail("expected", p.dimension).
+				WithDetail("got", len(v.Values)).
+				WithDetail("vector_id", v.ID)
+		}
+	}
+	return nil
+}
+
+// isIdentifier reports whether s is safe to use as a schema attribute
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isUnknownIndex(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown index") || strings.Contains(msg, "no such index") || strings.Contains(msg, "not found")
+}
+
+func decodeMetadata(data string) (map[string]any, error) {
+	metadata := make(map[string]any)
+	if data == "" || data == "null" {
+		return metadata, nil
+	}
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	return metadata, nil
+}
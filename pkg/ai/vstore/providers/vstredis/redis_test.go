@@ -0,0 +1,49 @@
+package vstredis_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/providers/vstredis"
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore/vstoretest"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestConformance runs against the Redis Stack server in REDIS_TEST_ADDR
+func TestConformance(t *testing.T) {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+		name := fmt.Sprintf("vstoretest_%d", time.Now().UnixNano())
+		provider, err := vstredis.NewRedisProvider(rdb, dimension,
+			vstredis.WithIndexName(name),
+			vstredis.WithKeyPrefix(name+":"),
+			vstredis.WithMetric(metric),
+			vstredis.WithIndexedFields(
+				vstredis.IndexedField{Name: vstoretest.FieldCategory, Type: vstredis.FieldTag},
+				vstredis.IndexedField{Name: vstoretest.FieldYear, Type: vstredis.FieldNumeric},
+				vstredis.IndexedField{Name: vstoretest.FieldRating, Type: vstredis.FieldNumeric},
+				vstredis.IndexedField{Name: vstoretest.FieldContent, Type: vstredis.FieldText},
+				vstredis.IndexedField{Name: vstoretest.FieldTags, Type: vstredis.FieldTag},
+				vstredis.IndexedField{Name: vstoretest.FieldAuthor, Type: vstredis.FieldTag},
+			),
+		)
+		if err != nil {
+			t.Fatalf("NewRedisProvider: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = provider.DropIndex(context.Background(), true)
+		})
+		return provider
+	})
+}
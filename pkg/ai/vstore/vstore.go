@@ -73,8 +73,9 @@ type HybridSearcher interface {
 
 // SparseVectorSupport supports sparse vector operations
 type SparseVectorSupport interface {
-	// UpsertSparse upserts sparse vectors
-	UpsertSparse(ctx context.Context, vectors []SparseVector, opts ...Option) error
+	// UpsertSparse sets the sparse values of existing vectors; ids[i] is
+	// the vector that vectors[i] belongs to
+	UpsertSparse(ctx context.Context, ids []string, vectors []SparseVector, opts ...Option) error
 
 	// QuerySparse queries with sparse vectors
 	QuerySparse(ctx context.Context, vector SparseVector, opts ...Option) (*QueryResult, error)
@@ -86,6 +87,15 @@ type StatisticsProvider interface {
 	GetStatistics(ctx context.Context, opts ...Option) (*Statistics, error)
 }
 
+// VectorLister supports paging through stored vectors, for exports and
+// re-indexing
+type VectorLister interface {
+	// List returns up to limit vectors after cursor ("" for the first
+	// page), honouring the Namespace, Filter and IncludeValues options.
+	// Cursors are opaque and provider specific.
+	List(ctx context.Context, cursor string, limit int, opts ...Option) (*ListResult, error)
+}
+
 // ============================================================================
 // LAYER 2: Core Data Models
 // ============================================================================
@@ -131,7 +141,9 @@ type Match struct {
 	// ID of the matched vector
 	ID string
 
-	// Score (similarity/distance)
+	// Score is a similarity where higher is better: cosine similarity for
+	// MetricCosine, the dot product for MetricDotProduct and 1/(1+distance)
+	// for MetricEuclidean
 	Score float32
 
 	// Values of the vector (if requested)
@@ -144,7 +156,8 @@ type Match struct {
 	Metadata map[string]any
 }
 
-// Filter represents metadata filtering
+// Filter represents metadata filtering. Filters nest through And, Or and
+// Not, so a Filter is a node of a boolean expression tree
 type Filter struct {
 	// Must conditions (AND)
 	Must []Condition
@@ -154,14 +167,25 @@ type Filter struct {
 
 	// MustNot conditions (NOT)
 	MustNot []Condition
+
+	// And sub-filters that must all match, alongside Must
+	And []Filter
+
+	// Or sub-filters, of which at least one of them or of Should must match
+	Or []Filter
+
+	// Not sub-filters that must not match, alongside MustNot
+	Not []Filter
 }
 
 // Condition represents a single filter condition
 type Condition struct {
-	// Field name in metadata
+	// Field name in metadata. Dots separate the keys of nested objects
+	// ("author.name")
 	Field string
 
-	// Operator (eq, ne, gt, lt, gte, lte, in, nin, exists)
+	// Operator (eq, ne, gt, lt, gte, lte, in, nin, exists, contains,
+	// array_contains, array_contains_any, array_contains_all)
 	Operator FilterOperator
 
 	// Value to compare against
@@ -182,6 +206,11 @@ const (
 	OpNotIn              FilterOperator = "nin"
 	OpExists             FilterOperator = "exists"
 	OpContains           FilterOperator = "contains"
+
+	// Array operators match metadata fields holding a list
+	OpArrayContains    FilterOperator = "array_contains"     // list contains the value
+	OpArrayContainsAny FilterOperator = "array_contains_any" // list contains any of the values
+	OpArrayContainsAll FilterOperator = "array_contains_all" // list contains all of the values
 )
 
 // IndexConfig represents index configuration
@@ -259,6 +288,15 @@ type BatchResult struct {
 	Errors []BatchError
 }
 
+// ListResult is a page of stored vectors
+type ListResult struct {
+	// Vectors in this page, with metadata
+	Vectors []Vector
+
+	// NextCursor resumes listing after this page ("" when there are no more)
+	NextCursor string
+}
+
 // BatchError represents an error in batch processing
 type BatchError struct {
 	// ID of the vector that failed
@@ -0,0 +1,199 @@
+package vstoretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// testCRUD covers Upsert, Fetch and Delete round trips
+func (s *suite) testCRUD(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	s.seed(t, store)
+
+	fetched, err := store.Fetch(ctx, append(fixtureIDs(), "missing"))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	s.assertIDs(t, "Fetch with a missing ID", vectorIDs(fetched), fixtureIDs())
+	for _, v := range fetched {
+		want := fixture(v.ID)
+		s.assertValues(t, "Fetch "+v.ID, v.Values, want.Values)
+		s.assertMetadata(t, "Fetch "+v.ID, v.Metadata, want.Metadata)
+	}
+
+	if fetched, err := store.Fetch(ctx, nil); err != nil || len(fetched) != 0 {
+		t.Errorf("Fetch(nil) = %d vectors, %v; want 0, nil", len(fetched), err)
+	}
+	if fetched, err := store.Fetch(ctx, []string{"missing"}); err != nil || len(fetched) != 0 {
+		t.Errorf("Fetch(missing) = %d vectors, %v; want 0, nil", len(fetched), err)
+	}
+
+	// Upsert replaces values and the whole metadata map
+	updated := vstore.Vector{
+		ID:       "a",
+		Values:   []float32{0, 0, 0.6, 0.8},
+		Metadata: map[string]any{FieldCategory: "archive"},
+	}
+	if err := store.Upsert(ctx, []vstore.Vector{updated}); err != nil {
+		t.Fatalf("Upsert update: %v", err)
+	}
+	fetched, err = store.Fetch(ctx, []string{"a"})
+	if err != nil {
+		t.Fatalf("Fetch updated: %v", err)
+	}
+	if len(fetched) != 1 {
+		t.Fatalf("Fetch updated: got %d vectors, want 1", len(fetched))
+	}
+	s.assertValues(t, "updated values", fetched[0].Values, updated.Values)
+	s.assertMetadata(t, "updated metadata", fetched[0].Metadata, updated.Metadata)
+
+	if err := store.Delete(ctx, []string{"b", "missing"}); err != nil {
+		t.Fatalf("Delete with a missing ID: %v", err)
+	}
+	if err := store.Delete(ctx, nil); err != nil {
+		t.Errorf("Delete(nil): %v", err)
+	}
+	fetched, err = store.Fetch(ctx, fixtureIDs())
+	if err != nil {
+		t.Fatalf("Fetch after delete: %v", err)
+	}
+	s.assertIDs(t, "Fetch after delete", vectorIDs(fetched), []string{"a", "c", "d", "e"})
+}
+
+// testValidation checks that invalid input is rejected without side effects
+func (s *suite) testValidation(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+
+	err := store.Upsert(ctx, []vstore.Vector{
+		fixture("a"),
+		{ID: "short", Values: []float32{1, 0}},
+	})
+	if err == nil {
+		t.Error("Upsert with a wrong dimension: want error")
+	}
+	if fetched, _ := store.Fetch(ctx, []string{"a", "short"}); len(fetched) != 0 {
+		t.Errorf("failed Upsert stored %v", vectorIDs(fetched))
+	}
+
+	if err := store.Upsert(ctx, []vstore.Vector{{ID: "", Values: []float32{1, 0, 0, 0}}}); err == nil {
+		t.Error("Upsert with an empty ID: want error")
+	}
+
+	if _, err := store.Query(ctx, []float32{1, 0}); err == nil {
+		t.Error("Query with a wrong dimension: want error")
+	}
+}
+
+// testQuery covers TopK, ordering, MinScore and the Include options
+func (s *suite) testQuery(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	s.seed(t, store)
+	query := fixture("a").Values
+
+	result, err := store.Query(ctx, query, vstore.WithTopK(2))
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(result.Matches) != 2 {
+		t.Fatalf("Query TopK 2: got %d matches", len(result.Matches))
+	}
+	if result.Matches[0].ID != "a" || !s.near(result.Matches[0].Score, 1) {
+		t.Errorf("Query: best match = %s (%v), want a (1)", result.Matches[0].ID, result.Matches[0].Score)
+	}
+	if result.Matches[1].ID != "b" {
+		t.Errorf("Query: second match = %s, want b", result.Matches[1].ID)
+	}
+	s.assertSorted(t, "Query", result)
+
+	result, err = store.Query(ctx, query, vstore.WithTopK(10))
+	if err != nil {
+		t.Fatalf("Query TopK 10: %v", err)
+	}
+	s.assertIDs(t, "Query TopK 10", matchIDs(result), fixtureIDs())
+	s.assertSorted(t, "Query TopK 10", result)
+
+	result, err = store.Query(ctx, query, vstore.WithTopK(10), vstore.WithMinScore(0.5))
+	if err != nil {
+		t.Fatalf("Query MinScore: %v", err)
+	}
+	s.assertIDs(t, "Query MinScore 0.5", matchIDs(result), []string{"a", "b"})
+
+	result, err = store.Query(ctx, query, vstore.WithTopK(1),
+		vstore.WithIncludeValues(true), vstore.WithIncludeMetadata(true))
+	if err != nil {
+		t.Fatalf("Query include: %v", err)
+	}
+	if len(result.Matches) == 1 {
+		s.assertValues(t, "IncludeValues", result.Matches[0].Values, fixture("a").Values)
+		s.assertMetadata(t, "IncludeMetadata", result.Matches[0].Metadata, fixture("a").Metadata)
+	}
+
+	result, err = store.Query(ctx, query, vstore.WithTopK(1),
+		vstore.WithIncludeValues(false), vstore.WithIncludeMetadata(false))
+	if err != nil {
+		t.Fatalf("Query exclude: %v", err)
+	}
+	if len(result.Matches) == 1 {
+		if len(result.Matches[0].Values) != 0 {
+			t.Errorf("IncludeValues(false): got values %v", result.Matches[0].Values)
+		}
+		if len(result.Matches[0].Metadata) != 0 {
+			t.Errorf("IncludeMetadata(false): got metadata %v", result.Matches[0].Metadata)
+		}
+	}
+}
+
+// testMetrics checks score values and ordering for each metric
+func (s *suite) testMetrics(t *testing.T) {
+	vectors := []vstore.Vector{
+		{ID: "same", Values: []float32{1, 0, 0, 0}},
+		{ID: "near", Values: []float32{0.6, 0.8, 0, 0}},
+		{ID: "orthogonal", Values: []float32{0, 1, 0, 0}},
+		{ID: "opposite", Values: []float32{-1, 0, 0, 0}},
+		{ID: "scaled", Values: []float32{2, 0, 0, 0}},
+	}
+
+	expected := map[vstore.Metric]map[string]float32{
+		vstore.MetricCosine: {
+			"same": 1, "near": 0.6, "orthogonal": 0, "opposite": -1, "scaled": 1,
+		},
+		vstore.MetricDotProduct: {
+			"same": 1, "near": 0.6, "orthogonal": 0, "opposite": -1, "scaled": 2,
+		},
+		vstore.MetricEuclidean: {
+			"same": 1, "near": 0.527864, "orthogonal": 0.414214, "opposite": 0.333333, "scaled": 0.5,
+		},
+	}
+
+	for _, metric := range s.cfg.metrics {
+		t.Run(string(metric), func(t *testing.T) {
+			ctx := context.Background()
+			store := s.store(t, metric)
+			if err := store.Upsert(ctx, vectors); err != nil {
+				t.Fatalf("Upsert: %v", err)
+			}
+
+			result, err := store.Query(ctx, []float32{1, 0, 0, 0}, vstore.WithTopK(len(vectors)))
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			s.assertSorted(t, string(metric), result)
+
+			want := expected[metric]
+			if len(result.Matches) != len(want) {
+				t.Errorf("got %d matches, want %d (a zero MinScore must keep negative scores)",
+					len(result.Matches), len(want))
+			}
+			for _, m := range result.Matches {
+				if !s.near(m.Score, want[m.ID]) {
+					t.Errorf("score for %s = %v, want %v", m.ID, m.Score, want[m.ID])
+				}
+			}
+		})
+	}
+}
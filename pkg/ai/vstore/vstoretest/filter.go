@@ -0,0 +1,123 @@
+package vstoretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+type filterCase struct {
+	name   string
+	filter vstore.Filter
+	want   []string
+}
+
+func must(field string, op vstore.FilterOperator, value any) vstore.Filter {
+	return vstore.Filter{Must: []vstore.Condition{{Field: field, Operator: op, Value: value}}}
+}
+
+// parse parses a filter expression the suite knows to be valid
+func parse(expr string) vstore.Filter {
+	filter, err := vstore.ParseFilter(expr)
+	if err != nil {
+		panic("vstoretest: " + err.Error())
+	}
+	return *filter
+}
+
+// filterCases covers every FilterOperator, Must/Should/MustNot combinations,
+// nested filter trees and the filter DSL. Fixture "e" has no year or rating.
+func filterCases() []filterCase {
+	return []filterCase{
+		{"eq/string", must(FieldCategory, vstore.OpEqual, "news"), []string{"a", "c"}},
+		{"eq/int", must(FieldYear, vstore.OpEqual, 2021), []string{"b"}},
+		{"eq/float", must(FieldRating, vstore.OpEqual, 4.5), []string{"a"}},
+		{"ne/string", must(FieldCategory, vstore.OpNotEqual, "news"), []string{"b", "d", "e"}},
+		{"ne/missing", must(FieldYear, vstore.OpNotEqual, 2021), []string{"a", "c", "d", "e"}},
+		{"gt/int", must(FieldYear, vstore.OpGreaterThan, 2021), []string{"c", "d"}},
+		{"gte/int", must(FieldYear, vstore.OpGreaterThanOrEqual, 2021), []string{"b", "c", "d"}},
+		{"lt/int", must(FieldYear, vstore.OpLessThan, 2022), []string{"a", "b"}},
+		{"lte/int", must(FieldYear, vstore.OpLessThanOrEqual, 2022), []string{"a", "b", "c"}},
+		{"gt/float", must(FieldRating, vstore.OpGreaterThan, 2.75), []string{"a", "b", "d"}},
+		{"lte/float", must(FieldRating, vstore.OpLessThanOrEqual, 2.5), []string{"c"}},
+		{"in/string", must(FieldCategory, vstore.OpIn, []any{"blog", "docs"}), []string{"b", "d", "e"}},
+		{"in/int", must(FieldYear, vstore.OpIn, []any{2020, 2023}), []string{"a", "d"}},
+		{"nin/string", must(FieldCategory, vstore.OpNotIn, []any{"news"}), []string{"b", "d", "e"}},
+		{"nin/missing", must(FieldYear, vstore.OpNotIn, []any{2020, 2021}), []string{"c", "d", "e"}},
+		{"exists", must(FieldYear, vstore.OpExists, nil), []string{"a", "b", "c", "d"}},
+		{"contains", must(FieldContent, vstore.OpContains, "brown"), []string{"a", "c"}},
+		{"must+must_not", vstore.Filter{
+			Must:    []vstore.Condition{{Field: FieldCategory, Operator: vstore.OpEqual, Value: "news"}},
+			MustNot: []vstore.Condition{{Field: FieldYear, Operator: vstore.OpEqual, Value: 2020}},
+		}, []string{"c"}},
+		{"should", vstore.Filter{
+			Should: []vstore.Condition{
+				{Field: FieldCategory, Operator: vstore.OpEqual, Value: "docs"},
+				{Field: FieldYear, Operator: vstore.OpEqual, Value: 2020},
+			},
+		}, []string{"a", "d"}},
+		{"must+should", vstore.Filter{
+			Must: []vstore.Condition{{Field: FieldCategory, Operator: vstore.OpEqual, Value: "blog"}},
+			Should: []vstore.Condition{
+				{Field: FieldContent, Operator: vstore.OpContains, Value: "quick"},
+				{Field: FieldYear, Operator: vstore.OpGreaterThan, Value: 2020},
+			},
+		}, []string{"b", "e"}},
+		{"path/eq", must(FieldAuthor, vstore.OpEqual, "ann"), []string{"a", "c"}},
+		{"path/ne", must(FieldAuthor, vstore.OpNotEqual, "ann"), []string{"b", "d", "e"}},
+		{"path/in", must(FieldAuthor, vstore.OpIn, []any{"bob", "cy"}), []string{"b", "d", "e"}},
+		{"array_contains", must(FieldTags, vstore.OpArrayContains, "go"), []string{"a", "b", "e"}},
+		{"array_contains_any", must(FieldTags, vstore.OpArrayContainsAny, []any{"rust", "docs"}), []string{"c", "d", "e"}},
+		{"array_contains_all", must(FieldTags, vstore.OpArrayContainsAll, []any{"go", "db"}), []string{"a"}},
+		{"tree/and+or+not", vstore.And(
+			vstore.Or(
+				vstore.Where(FieldCategory, vstore.OpEqual, "blog"),
+				vstore.Where(FieldCategory, vstore.OpEqual, "docs"),
+			),
+			vstore.Not(vstore.Where(FieldYear, vstore.OpLessThan, 2022)),
+		), []string{"d", "e"}},
+		{"tree/not-or", vstore.Not(vstore.Or(
+			vstore.Where(FieldCategory, vstore.OpEqual, "news"),
+			vstore.Where(FieldTags, vstore.OpArrayContains, "go"),
+		)), []string{"d"}},
+		{"tree/or-of-ands", vstore.Or(
+			vstore.And(
+				vstore.Where(FieldCategory, vstore.OpEqual, "news"),
+				vstore.Where(FieldYear, vstore.OpGreaterThan, 2020),
+			),
+			vstore.And(
+				vstore.Where(FieldCategory, vstore.OpEqual, "blog"),
+				vstore.Where(FieldTags, vstore.OpArrayContainsAll, []any{"go", "rust"}),
+			),
+		), []string{"c", "e"}},
+		{"dsl/nested", parse(`author.name = "ann" AND (tags HAS "rust" OR rating >= 4.5)`), []string{"a", "c"}},
+		{"dsl/not", parse(`category IN ["news", "blog"] AND NOT (year >= 2021 AND year <= 2022)`), []string{"a", "e"}},
+	}
+}
+
+// testFilters runs the operator matrix through QueryWithFilter when the
+// provider implements MetadataFilterer, and through the Filter option
+// otherwise
+func (s *suite) testFilters(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	s.seed(t, store)
+	query := []float32{0.5, 0.5, 0.5, 0.5}
+
+	for _, tc := range filterCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			var result *vstore.QueryResult
+			var err error
+			if filterer, ok := store.(vstore.MetadataFilterer); ok {
+				result, err = filterer.QueryWithFilter(ctx, query, tc.filter, vstore.WithTopK(10))
+			} else {
+				result, err = store.Query(ctx, query, vstore.WithTopK(10), vstore.WithFilter(&tc.filter))
+			}
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			s.assertIDs(t, tc.name, matchIDs(result), tc.want)
+		})
+	}
+}
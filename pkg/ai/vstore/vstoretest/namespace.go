@@ -0,0 +1,263 @@
+package vstoretest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// testBatch checks partial failure reporting in UpsertBatch and DeleteBatch
+func (s *suite) testBatch(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	batcher, ok := store.(vstore.BatchProcessor)
+	if !ok {
+		t.Skip("provider does not implement vstore.BatchProcessor")
+	}
+
+	vectors := append(fixtures(), vstore.Vector{ID: "bad", Values: []float32{1, 0}})
+	result, err := batcher.UpsertBatch(ctx, vectors, vstore.WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("UpsertBatch with an invalid vector: %v (want failures reported in the result)", err)
+	}
+	if result.SuccessCount+result.FailedCount != len(vectors) {
+		t.Errorf("UpsertBatch: success %d + failed %d != %d", result.SuccessCount, result.FailedCount, len(vectors))
+	}
+
+	failed := make([]string, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		failed = append(failed, e.ID)
+	}
+	if !slices.Contains(failed, "bad") {
+		t.Errorf("UpsertBatch: errors %v don't include the invalid vector", failed)
+	}
+	if len(failed) != result.FailedCount {
+		t.Errorf("UpsertBatch: %d errors for %d failures", len(failed), result.FailedCount)
+	}
+
+	ids := make([]string, len(vectors))
+	for i, v := range vectors {
+		ids[i] = v.ID
+	}
+	fetched, err := store.Fetch(ctx, ids)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	stored := vectorIDs(fetched)
+	if len(stored) != result.SuccessCount {
+		t.Errorf("UpsertBatch: %d vectors stored, %d reported successful", len(stored), result.SuccessCount)
+	}
+	for _, id := range failed {
+		if slices.Contains(stored, id) {
+			t.Errorf("UpsertBatch: %s reported failed but was stored", id)
+		}
+	}
+
+	deleted, err := batcher.DeleteBatch(ctx, stored)
+	if err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+	if deleted.SuccessCount != len(stored) || deleted.FailedCount != 0 {
+		t.Errorf("DeleteBatch: success %d, failed %d; want %d, 0", deleted.SuccessCount, deleted.FailedCount, len(stored))
+	}
+	if fetched, _ := store.Fetch(ctx, stored); len(fetched) != 0 {
+		t.Errorf("DeleteBatch left %v", vectorIDs(fetched))
+	}
+}
+
+// testNamespaces checks isolation between namespaces, namespace management
+// and per-namespace statistics
+func (s *suite) testNamespaces(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	nsA, nsB := uniqueNamespace("a"), uniqueNamespace("b")
+
+	manager, hasManager := store.(vstore.NamespaceManager)
+	if hasManager {
+		t.Cleanup(func() {
+			_ = manager.DeleteNamespace(context.Background(), nsA)
+			_ = manager.DeleteNamespace(context.Background(), nsB)
+		})
+	}
+
+	upsert := func(namespace string, ids ...string) {
+		t.Helper()
+		vectors := make([]vstore.Vector, len(ids))
+		for i, id := range ids {
+			vectors[i] = fixture(id)
+		}
+		if err := store.Upsert(ctx, vectors, vstore.WithNamespace(namespace)); err != nil {
+			t.Fatalf("Upsert into %s: %v", namespace, err)
+		}
+	}
+	upsert(nsA, "a", "b")
+	upsert(nsB, "c")
+	upsert("", "d")
+
+	query := []float32{0.5, 0.5, 0.5, 0.5}
+	queryIDs := func(namespace string) []string {
+		t.Helper()
+		result, err := store.Query(ctx, query, vstore.WithTopK(10), vstore.WithNamespace(namespace))
+		if err != nil {
+			t.Fatalf("Query %s: %v", namespace, err)
+		}
+		return matchIDs(result)
+	}
+	fetchIDs := func(namespace string, ids ...string) []string {
+		t.Helper()
+		fetched, err := store.Fetch(ctx, ids, vstore.WithNamespace(namespace))
+		if err != nil {
+			t.Fatalf("Fetch %s: %v", namespace, err)
+		}
+		return vectorIDs(fetched)
+	}
+
+	s.assertIDs(t, "Query namespace a", queryIDs(nsA), []string{"a", "b"})
+	s.assertIDs(t, "Query namespace b", queryIDs(nsB), []string{"c"})
+	s.assertIDs(t, "Fetch namespace a", fetchIDs(nsA, "a", "b", "c", "d"), []string{"a", "b"})
+
+	// Deleting through the wrong namespace is a no-op
+	if err := store.Delete(ctx, []string{"c"}, vstore.WithNamespace(nsA)); err != nil {
+		t.Fatalf("Delete through another namespace: %v", err)
+	}
+	s.assertIDs(t, "Delete through another namespace", fetchIDs(nsB, "c"), []string{"c"})
+
+	if stats, ok := store.(vstore.StatisticsProvider); ok {
+		s.checkStatistics(t, stats, map[string]int64{nsA: 2, nsB: 1})
+	}
+
+	if !hasManager {
+		return
+	}
+
+	namespaces, err := manager.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("ListNamespaces: %v", err)
+	}
+	if !slices.Contains(namespaces, nsA) || !slices.Contains(namespaces, nsB) {
+		t.Errorf("ListNamespaces = %v, want it to include %s and %s", namespaces, nsA, nsB)
+	}
+
+	if err := manager.DeleteNamespace(ctx, nsA); err != nil {
+		t.Fatalf("DeleteNamespace: %v", err)
+	}
+	s.assertIDs(t, "Query deleted namespace", queryIDs(nsA), nil)
+	s.assertIDs(t, "Fetch deleted namespace", fetchIDs(nsA, "a", "b"), nil)
+	s.assertIDs(t, "Fetch other namespace", fetchIDs(nsB, "c"), []string{"c"})
+
+	namespaces, err = manager.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("ListNamespaces after delete: %v", err)
+	}
+	if slices.Contains(namespaces, nsA) {
+		t.Errorf("ListNamespaces after delete = %v, still includes %s", namespaces, nsA)
+	}
+}
+
+// checkStatistics compares per-namespace vector counts
+func (s *suite) checkStatistics(t *testing.T, provider vstore.StatisticsProvider, want map[string]int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	for namespace, count := range want {
+		stats, err := provider.GetStatistics(ctx, vstore.WithNamespace(namespace))
+		if err != nil {
+			t.Fatalf("GetStatistics %s: %v", namespace, err)
+		}
+		if stats.TotalVectorCount != count {
+			t.Errorf("GetStatistics %s: total %d, want %d", namespace, stats.TotalVectorCount, count)
+		}
+		if stats.Dimension != Dimension {
+			t.Errorf("GetStatistics %s: dimension %d, want %d", namespace, stats.Dimension, Dimension)
+		}
+	}
+
+	stats, err := provider.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	for namespace, count := range want {
+		found := false
+		for _, ns := range stats.Namespaces {
+			if ns.Name == namespace {
+				found = true
+				if ns.VectorCount != count {
+					t.Errorf("GetStatistics: namespace %s has %d vectors, want %d", namespace, ns.VectorCount, count)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("GetStatistics: namespace %s missing from %v", namespace, stats.Namespaces)
+		}
+	}
+}
+
+// testList pages through a namespace and checks every vector is visited
+// exactly once, with filters and IncludeValues honoured
+func (s *suite) testList(t *testing.T) {
+	ctx := context.Background()
+	store := s.store(t, vstore.MetricCosine)
+	lister, ok := store.(vstore.VectorLister)
+	if !ok {
+		t.Skip("provider does not implement vstore.VectorLister")
+	}
+
+	namespace := uniqueNamespace("list")
+	if manager, ok := store.(vstore.NamespaceManager); ok {
+		t.Cleanup(func() { _ = manager.DeleteNamespace(context.Background(), namespace) })
+	}
+	s.seed(t, store, vstore.WithNamespace(namespace))
+	other := uniqueNamespace("other")
+	if manager, ok := store.(vstore.NamespaceManager); ok {
+		t.Cleanup(func() { _ = manager.DeleteNamespace(context.Background(), other) })
+	}
+	if err := store.Upsert(ctx, []vstore.Vector{{ID: "x", Values: []float32{1, 0, 0, 0}}}, vstore.WithNamespace(other)); err != nil {
+		t.Fatalf("Upsert into %s: %v", other, err)
+	}
+
+	listAll := func(opts ...vstore.Option) []vstore.Vector {
+		t.Helper()
+		opts = append([]vstore.Option{vstore.WithNamespace(namespace)}, opts...)
+		var all []vstore.Vector
+		cursor := ""
+		for page := 0; ; page++ {
+			if page > 10 {
+				t.Fatalf("List did not terminate after %d pages", page)
+			}
+			result, err := lister.List(ctx, cursor, 2, opts...)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(result.Vectors) > 2 {
+				t.Errorf("List: page of %d vectors exceeds the limit", len(result.Vectors))
+			}
+			all = append(all, result.Vectors...)
+			if result.NextCursor == "" {
+				return all
+			}
+			cursor = result.NextCursor
+		}
+	}
+
+	listed := listAll()
+	ids := vectorIDs(listed)
+	s.assertIDs(t, "List", ids, fixtureIDs())
+	if len(ids) != len(fixtureIDs()) {
+		t.Errorf("List: visited %v, want each fixture once", ids)
+	}
+	for _, v := range listed {
+		s.assertMetadata(t, "List "+v.ID, v.Metadata, fixture(v.ID).Metadata)
+		if len(v.Values) != 0 {
+			t.Errorf("List %s: got values without IncludeValues", v.ID)
+		}
+	}
+
+	for _, v := range listAll(vstore.WithIncludeValues(true)) {
+		s.assertValues(t, "List IncludeValues "+v.ID, v.Values, fixture(v.ID).Values)
+	}
+
+	filter := vstore.Where(FieldCategory, vstore.OpEqual, "blog")
+	s.assertIDs(t, "List with filter", vectorIDs(listAll(vstore.WithFilter(&filter))), []string{"b", "e"})
+}
@@ -0,0 +1,281 @@
+// Package vstoretest provides a conformance suite for vstore providers.
+//
+// Every provider runs the same spec so behaviour that callers rely on is
+// identical across stores:
+//
+//   - Fetch omits missing IDs instead of failing, and Upsert replaces the
+//     whole vector including its metadata
+//   - Upsert rejects a call containing an invalid vector without writing any
+//     of it
+//   - Scores are similarities where higher is better: cosine similarity,
+//     the dot product, or 1/(1+distance) for euclidean
+//   - A zero MinScore disables the threshold
+//   - ne and nin match vectors that lack the field; the other operators
+//     don't
+//   - Filters nest through And, Or and Not, dotted fields address nested
+//     metadata, and the array operators match list fields
+//   - Namespaces are isolated for Query, Fetch, Delete and statistics
+//   - UpsertBatch reports failed vectors in the result rather than failing
+//     the call, and never stores a vector it reports as failed
+//   - List pages visit every matching vector exactly once
+//
+// Optional capabilities (MetadataFilterer, BatchProcessor, NamespaceManager,
+// StatisticsProvider, VectorLister) are detected with type assertions, as vstore.NewClient
+// does, and their subtests are skipped when a provider doesn't implement
+// them.
+//
+// Example:
+//
+//	func TestConformance(t *testing.T) {
+//	    vstoretest.Run(t, func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer {
+//	        return vstmemory.NewMemoryVectorStore(dimension, metric)
+//	    })
+//	}
+package vstoretest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Abraxas-365/manifesto/pkg/ai/vstore"
+)
+
+// Dimension of every vector written by the suite
+const Dimension = 4
+
+// Metadata fields written by the suite. Providers that only filter on
+// declared fields (such as Redis) must index them with these types.
+const (
+	FieldCategory = "category"    // keyword
+	FieldYear     = "year"        // integer, missing on one fixture
+	FieldRating   = "rating"      // float, missing on one fixture
+	FieldContent  = "content"     // free text
+	FieldTags     = "tags"        // list of keywords
+	FieldAuthor   = "author.name" // keyword nested in an object
+)
+
+// Factory returns an empty store for one subtest. Stores backed by shared
+// infrastructure should use isolated tables, collections or indexes and
+// register cleanup with t.Cleanup.
+type Factory func(t *testing.T, dimension int, metric vstore.Metric) vstore.VectorStorer
+
+// config tunes the suite for a provider
+type config struct {
+	metrics   []vstore.Metric
+	tolerance float32
+}
+
+// Option configures Run
+type Option func(*config)
+
+// WithMetrics limits the metric subtests to the metrics a provider supports
+func WithMetrics(metrics ...vstore.Metric) Option {
+	return func(c *config) {
+		c.metrics = metrics
+	}
+}
+
+// WithTolerance sets the allowed error for score and value comparisons
+// (default 1e-3)
+func WithTolerance(tolerance float32) Option {
+	return func(c *config) {
+		c.tolerance = tolerance
+	}
+}
+
+// Run runs the conformance suite against stores created by newStore
+func Run(t *testing.T, newStore Factory, opts ...Option) {
+	cfg := &config{
+		metrics:   []vstore.Metric{vstore.MetricCosine, vstore.MetricDotProduct, vstore.MetricEuclidean},
+		tolerance: 1e-3,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s := &suite{newStore: newStore, cfg: cfg}
+	t.Run("CRUD", s.testCRUD)
+	t.Run("Validation", s.testValidation)
+	t.Run("Query", s.testQuery)
+	t.Run("Metrics", s.testMetrics)
+	t.Run("Filters", s.testFilters)
+	t.Run("Batch", s.testBatch)
+	t.Run("Namespaces", s.testNamespaces)
+	t.Run("List", s.testList)
+}
+
+type suite struct {
+	newStore Factory
+	cfg      *config
+}
+
+func (s *suite) store(t *testing.T, metric vstore.Metric) vstore.VectorStorer {
+	t.Helper()
+	store := s.newStore(t, Dimension, metric)
+	if store == nil {
+		t.Fatal("factory returned a nil store")
+	}
+	return store
+}
+
+// ============================================================================
+// Fixtures
+// ============================================================================
+
+// fixtures are unit vectors so stores that normalise cosine vectors return
+// the values they were given
+func fixtures() []vstore.Vector {
+	return []vstore.Vector{
+		{ID: "a", Values: []float32{1, 0, 0, 0}, Metadata: map[string]any{
+			FieldCategory: "news", FieldYear: 2020, FieldRating: 4.5, FieldContent: "the quick brown fox",
+			FieldTags: []any{"go", "db"}, "author": map[string]any{"name": "ann"},
+		}},
+		{ID: "b", Values: []float32{0.6, 0.8, 0, 0}, Metadata: map[string]any{
+			FieldCategory: "blog", FieldYear: 2021, FieldRating: 3.0, FieldContent: "a lazy dog sleeps",
+			FieldTags: []any{"go"}, "author": map[string]any{"name": "bob"},
+		}},
+		{ID: "c", Values: []float32{0, 1, 0, 0}, Metadata: map[string]any{
+			FieldCategory: "news", FieldYear: 2022, FieldRating: 2.5, FieldContent: "brown bear in the woods",
+			FieldTags: []any{"rust", "db"}, "author": map[string]any{"name": "ann"},
+		}},
+		{ID: "d", Values: []float32{0, 0, 1, 0}, Metadata: map[string]any{
+			FieldCategory: "docs", FieldYear: 2023, FieldRating: 4.0, FieldContent: "release notes",
+			FieldTags: []any{"docs"}, "author": map[string]any{"name": "bob"},
+		}},
+		{ID: "e", Values: []float32{0, 0, 0, 1}, Metadata: map[string]any{
+			FieldCategory: "blog", FieldContent: "quick start guide",
+			FieldTags: []any{"go", "rust"}, "author": map[string]any{"name": "cy"},
+		}},
+	}
+}
+
+func fixtureIDs() []string {
+	return []string{"a", "b", "c", "d", "e"}
+}
+
+func fixture(id string) vstore.Vector {
+	for _, v := range fixtures() {
+		if v.ID == id {
+			return v
+		}
+	}
+	panic("vstoretest: unknown fixture " + id)
+}
+
+func (s *suite) seed(t *testing.T, store vstore.VectorStorer, opts ...vstore.Option) {
+	t.Helper()
+	if err := store.Upsert(context.Background(), fixtures(), opts...); err != nil {
+		t.Fatalf("Upsert fixtures: %v", err)
+	}
+}
+
+// uniqueNamespace returns a namespace name that doesn't collide with other
+// runs against shared infrastructure
+func uniqueNamespace(suffix string) string {
+	return fmt.Sprintf("vstoretest_%x_%s", time.Now().UnixNano(), suffix)
+}
+
+// ============================================================================
+// Assertions
+// ============================================================================
+
+func matchIDs(result *vstore.QueryResult) []string {
+	ids := make([]string, len(result.Matches))
+	for i, m := range result.Matches {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func vectorIDs(vectors []vstore.Vector) []string {
+	ids := make([]string, len(vectors))
+	for i, v := range vectors {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+// sameIDs compares ID sets, ignoring order
+func sameIDs(got, want []string) bool {
+	got = slices.Clone(got)
+	want = slices.Clone(want)
+	sort.Strings(got)
+	sort.Strings(want)
+	return slices.Equal(got, want)
+}
+
+func (s *suite) assertIDs(t *testing.T, label string, got, want []string) {
+	t.Helper()
+	if !sameIDs(got, want) {
+		sort.Strings(got)
+		sort.Strings(want)
+		t.Errorf("%s: got IDs %v, want %v", label, got, want)
+	}
+}
+
+func (s *suite) assertSorted(t *testing.T, label string, result *vstore.QueryResult) {
+	t.Helper()
+	for i := 1; i < len(result.Matches); i++ {
+		if result.Matches[i].Score > result.Matches[i-1].Score {
+			t.Errorf("%s: matches not sorted by score: %v", label, scores(result))
+			return
+		}
+	}
+}
+
+func (s *suite) assertValues(t *testing.T, label string, got, want []float32) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d values, want %d", label, len(got), len(want))
+		return
+	}
+	for i := range want {
+		if !s.near(got[i], want[i]) {
+			t.Errorf("%s: got values %v, want %v", label, got, want)
+			return
+		}
+	}
+}
+
+// assertMetadata compares metadata after a JSON round trip, since most
+// stores return numbers as float64
+func (s *suite) assertMetadata(t *testing.T, label string, got, want map[string]any) {
+	t.Helper()
+	if !reflect.DeepEqual(normalize(got), normalize(want)) {
+		t.Errorf("%s: got metadata %v, want %v", label, got, want)
+	}
+}
+
+func (s *suite) near(got, want float32) bool {
+	return float32(math.Abs(float64(got-want))) <= s.cfg.tolerance
+}
+
+func normalize(metadata map[string]any) map[string]any {
+	out := map[string]any{}
+	if len(metadata) == 0 {
+		return out
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return metadata
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return metadata
+	}
+	return out
+}
+
+func scores(result *vstore.QueryResult) []float32 {
+	out := make([]float32, len(result.Matches))
+	for i, m := range result.Matches {
+		out[i] = m.Score
+	}
+	return out
+}
@@ -13,6 +13,7 @@ type FileInfo struct {
 	ModTime     time.Time         // Modification time
 	IsDir       bool              // Is a directory
 	ContentType string            // MIME type (when available)
+	ETag        string            // Content version tag (when available)
 	Metadata    map[string]string // Additional metadata
 }
 
@@ -25,6 +26,13 @@ type FileReader interface {
 	Exists(ctx context.Context, path string) (bool, error)
 }
 
+// DirLister is implemented by file systems whose List may return more than
+// one level, like S3 at the bucket root. ListDir returns only the direct
+// children of path.
+type DirLister interface {
+	ListDir(ctx context.Context, path string) ([]FileInfo, error)
+}
+
 // FileWriter provides write operations
 type FileWriter interface {
 	WriteFile(ctx context.Context, path string, data []byte) error
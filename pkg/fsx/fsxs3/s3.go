@@ -205,12 +205,25 @@ func (fs *S3FileSystem) Stat(ctx context.Context, path string) (fsx.FileInfo, er
 		ModTime:     *headOutput.LastModified,
 		IsDir:       isDir,
 		ContentType: aws.ToString(headOutput.ContentType),
+		ETag:        aws.ToString(headOutput.ETag),
 		Metadata:    metadata,
 	}, nil
 }
 
-// List returns a listing of files and directories in the specified path
+// List returns a listing of files and directories in the specified path.
+// Listing the bucket root returns every object in the bucket; use ListDir
+// to list a single level there too.
 func (fs *S3FileSystem) List(ctx context.Context, path string) ([]fsx.FileInfo, error) {
+	return fs.list(ctx, path, false)
+}
+
+// ListDir returns only the direct children of the specified path, including
+// at the bucket root
+func (fs *S3FileSystem) ListDir(ctx context.Context, path string) ([]fsx.FileInfo, error) {
+	return fs.list(ctx, path, true)
+}
+
+func (fs *S3FileSystem) list(ctx context.Context, path string, singleLevel bool) ([]fsx.FileInfo, error) {
 	if fs.bucket == "" {
 		return nil, s3Errors.New(ErrEmptyBucketName)
 	}
@@ -222,52 +235,55 @@ func (fs *S3FileSystem) List(ctx context.Context, path string) ([]fsx.FileInfo,
 	key := fs.s3Key(path)
 
 	var delimiter *string
-	if key != "" {
+	if key != "" || singleLevel {
 		delimiter = aws.String("/")
 	}
 
-	listOutput, err := fs.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+	files := make([]fsx.FileInfo, 0)
+	paginator := s3.NewListObjectsV2Paginator(fs.client, &s3.ListObjectsV2Input{
 		Bucket:    aws.String(fs.bucket),
 		Prefix:    aws.String(key),
 		Delimiter: delimiter,
 	})
 
-	if err != nil {
-		return nil, s3Errors.NewWithCause(ErrFailedList, err).
-			WithDetail("path", path).
-			WithDetail("bucket", fs.bucket).
-			WithDetail("key", key)
-	}
-
-	files := make([]fsx.FileInfo, 0)
-
-	// Add directories (common prefixes)
-	for _, prefix := range listOutput.CommonPrefixes {
-		dirName := filepath.Base(strings.TrimSuffix(aws.ToString(prefix.Prefix), "/"))
-		files = append(files, fsx.FileInfo{
-			Name:     dirName,
-			IsDir:    true,
-			ModTime:  time.Time{},
-			Metadata: make(map[string]string),
-		})
-	}
+	for paginator.HasMorePages() {
+		listOutput, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, s3Errors.NewWithCause(ErrFailedList, err).
+				WithDetail("path", path).
+				WithDetail("bucket", fs.bucket).
+				WithDetail("key", key)
+		}
 
-	// Add files
-	for _, obj := range listOutput.Contents {
-		if aws.ToString(obj.Key) == key {
-			continue
+		// Add directories (common prefixes)
+		for _, prefix := range listOutput.CommonPrefixes {
+			dirName := filepath.Base(strings.TrimSuffix(aws.ToString(prefix.Prefix), "/"))
+			files = append(files, fsx.FileInfo{
+				Name:     dirName,
+				IsDir:    true,
+				ModTime:  time.Time{},
+				Metadata: make(map[string]string),
+			})
 		}
 
-		name := filepath.Base(aws.ToString(obj.Key))
-		isDir := strings.HasSuffix(aws.ToString(obj.Key), "/")
+		// Add files
+		for _, obj := range listOutput.Contents {
+			if aws.ToString(obj.Key) == key {
+				continue
+			}
 
-		files = append(files, fsx.FileInfo{
-			Name:     name,
-			Size:     *obj.Size,
-			ModTime:  *obj.LastModified,
-			IsDir:    isDir,
-			Metadata: make(map[string]string),
-		})
+			name := filepath.Base(aws.ToString(obj.Key))
+			isDir := strings.HasSuffix(aws.ToString(obj.Key), "/")
+
+			files = append(files, fsx.FileInfo{
+				Name:     name,
+				Size:     aws.ToInt64(obj.Size),
+				ModTime:  aws.ToTime(obj.LastModified),
+				IsDir:    isDir,
+				ETag:     aws.ToString(obj.ETag),
+				Metadata: make(map[string]string),
+			})
+		}
 	}
 
 	return files, nil
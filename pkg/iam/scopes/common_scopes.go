@@ -75,6 +75,11 @@ const (
 	ScopeTemplatesRead   = "templates:read"
 	ScopeTemplatesWrite  = "templates:write"
 	ScopeTemplatesDelete = "templates:delete"
+
+	// MCP scopes (Model Context Protocol server)
+	ScopeMCPAll     = "mcp:*"
+	ScopeMCPRead    = "mcp:read"
+	ScopeMCPExecute = "mcp:execute"
 )
 
 // CommonScopeCategories organizes common scopes by domain
@@ -148,6 +153,11 @@ var CommonScopeCategories = map[string][]string{
 		ScopeTemplatesWrite,
 		ScopeTemplatesDelete,
 	},
+	"MCP": {
+		ScopeMCPAll,
+		ScopeMCPRead,
+		ScopeMCPExecute,
+	},
 }
 
 // CommonScopeDescriptions provides human-readable descriptions
@@ -222,6 +232,11 @@ var CommonScopeDescriptions = map[string]string{
 	ScopeTemplatesRead:   "View templates",
 	ScopeTemplatesWrite:  "Create and edit templates",
 	ScopeTemplatesDelete: "Delete templates",
+
+	// MCP
+	ScopeMCPAll:     "Full access to the MCP server",
+	ScopeMCPRead:    "List MCP tools",
+	ScopeMCPExecute: "Call MCP tools and search the knowledge base",
 }
 
 // CommonScopeGroups defines common role groupings
@@ -260,6 +275,7 @@ var CommonScopeGroups = map[string][]string{
 	"api_admin": {
 		ScopeAPIKeysAll,
 		ScopeIntegrationsAll,
+		ScopeMCPAll,
 	},
 	"settings_admin": {
 		ScopeSettingsAll,